@@ -3,75 +3,128 @@ package istio
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/coreos/go-semver/semver"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
 	"go.uber.org/zap"
 
-	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/manifest"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
 	"github.com/pkg/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 const (
+	// istioNamespace is the namespace Istio is installed in if the task does not configure one.
 	istioNamespace = "istio-system"
 )
 
-type bootstrapIstioPerformer func(logger *zap.SugaredLogger) (actions.IstioPerformer, error)
+// errIstioctlNotAvailable is returned when the istioctl client version is missing, which means no usable istioctl binary was resolved.
+var errIstioctlNotAvailable = errors.New("Could not determine istioctl client version, istioctl binary is not available")
+
+type bootstrapIstioPerformer func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error)
 
 type StatusPreAction struct {
+	lastErrorRecorder
 	getIstioPerformer bootstrapIstioPerformer
 }
 
 func NewStatusPreAction(getIstioPerformer bootstrapIstioPerformer) *StatusPreAction {
-	return &StatusPreAction{getIstioPerformer}
+	return &StatusPreAction{getIstioPerformer: getIstioPerformer}
 }
 
 func (a *StatusPreAction) Run(context *service.ActionContext) error {
+	err := a.run(withLogFields(context))
+	a.recordError(err)
+	return err
+}
+
+func (a *StatusPreAction) run(context *service.ActionContext) error {
 	context.Logger.Debug("Pre reconcile action of istio triggered")
 
-	performer, err := a.getIstioPerformer(context.Logger)
+	performer, err := a.getIstioPerformer(context.Task, context.Logger)
 	if err != nil {
 		return err
 	}
 
-	istioStatus, err := getInstalledVersion(context, performer)
+	err = checkClientVersionOffline(context, performer)
 	if err != nil {
 		return err
 	}
 
-	if !isClientCompatibleWithTargetVersion(istioStatus) {
-		return fmt.Errorf("Istio could not be updated since the binary version: %s is not compatible with the target version: %s - the difference between versions exceeds one minor version", istioStatus.ClientVersion, istioStatus.TargetVersion)
+	istioStatus, err := getInstalledVersion(context, performer)
+	if err != nil {
+		return unsupportedVersionOr(err)
+	}
+
+	err = checkClientVersion(istioStatus)
+	if err != nil {
+		return err
 	}
 	context.Logger.Debug("Pre version check successful")
 
-	return nil
+	return ensureNoConflictingRevisions(context, performer)
 }
 
 type MainReconcileAction struct {
+	lastErrorRecorder
+	reconcileStatusRecorder
 	getIstioPerformer bootstrapIstioPerformer
+	metrics           ActionMetrics
 }
 
 func NewIstioMainReconcileAction(getIstioPerformer bootstrapIstioPerformer) *MainReconcileAction {
-	return &MainReconcileAction{getIstioPerformer}
+	return &MainReconcileAction{getIstioPerformer: getIstioPerformer, metrics: noopActionMetrics{}}
+}
+
+// WithMetrics makes the action record its installs, updates, skips and durations to the given metrics.
+func (a *MainReconcileAction) WithMetrics(metrics ActionMetrics) *MainReconcileAction {
+	a.metrics = metrics
+	return a
 }
 
 func (a *MainReconcileAction) Run(context *service.ActionContext) error {
+	observation := newActionObservation(a.metrics, reconcileActionName)
+	context = withLogFields(context)
+	timeout := actionTimeout(context.Task, context.Logger)
+	context, cancel := withActionTimeout(context, timeout)
+	defer cancel()
+	err := deadlineExceededError(context, timeout, a.run(context, observation))
+	observation.observeDuration()
+	a.recordError(err)
+	a.recordStatus(observation.status)
+	return err
+}
+
+func (a *MainReconcileAction) run(context *service.ActionContext, observation *actionObservation) error {
 	context.Logger.Debug("Reconcile action of istio triggered")
 
-	performer, err := a.getIstioPerformer(context.Logger)
+	if boolConfig(context.Task, labelNamespacesOnlyConfigKey, context.Logger) {
+		return NewLabelNamespacesAction(a.getIstioPerformer).run(context)
+	}
+
+	performer, err := a.getIstioPerformer(context.Task, context.Logger)
 	if err != nil {
 		return err
 	}
 
-	err = deployIstio(context, performer)
+	if boolConfig(context.Task, dryRunConfigKey, context.Logger) {
+		_, err = dryRunDeployIstio(context, performer)
+		return err
+	}
+
+	err = deployIstio(context, performer, observation)
 
 	errLabelNamespaces := performer.LabelNamespaces(context.Context, context.KubeClient,
-		context.WorkspaceFactory, context.Task.Version, context.Task.Component, context.Logger)
-	if errLabelNamespaces != nil {
+		context.WorkspaceFactory, context.Task.Version, context.Task.Component, istioRevision(context.Task), context.Logger)
+	if errLabelNamespaces != nil && boolConfig(context.Task, labelNamespacesFailureAsWarningConfigKey, context.Logger) {
+		context.Logger.Warnf("Could not label namespaces: %v", errLabelNamespaces)
+	} else if errLabelNamespaces != nil {
 		errLabelNamespaces = errors.Wrap(errLabelNamespaces, "Could not label namespaces")
 		if err != nil {
 			err = errors.Wrap(err, errLabelNamespaces.Error())
@@ -83,55 +136,188 @@ func (a *MainReconcileAction) Run(context *service.ActionContext) error {
 	return err
 }
 
-func deployIstio(context *service.ActionContext, performer actions.IstioPerformer) error {
-	component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
-		WithNamespace(istioNamespace).
-		WithProfile(context.Task.Profile).
-		WithConfiguration(context.Task.Configuration).Build()
-	istioManifest, err := context.ChartProvider.RenderManifest(component)
+// deploymentPlan is the deployment of Istio decided for the task, together with the rendered manifest and the
+// versions found on the cluster the decision is based on.
+type deploymentPlan struct {
+	manifest    string
+	istioStatus actions.IstioStatus
+	actionKind  ActionKind
+	// forced updates Istio although it is already at the target version
+	forced bool
+	// reinstall uninstalls Istio before installing it again instead of updating it in place
+	reinstall bool
+	// err is the reason why the deployment is blocked
+	err error
+}
+
+// planDeployment renders and validates the Istio manifest and decides how Istio is deployed, without changing
+// anything on the cluster.
+func planDeployment(context *service.ActionContext, performer actions.IstioPerformer) (deploymentPlan, error) {
+	istioManifest, err := renderIstioManifest(context)
 	if err != nil {
-		return err
+		return deploymentPlan{}, err
+	}
+
+	err = manifest.ValidateSingleIstioOperator(istioManifest.Manifest)
+	if err != nil {
+		return deploymentPlan{}, errors.Wrap(err, "Rendered Istio manifest is invalid")
+	}
+
+	istioOperator, err := manifest.ExtractIstioOperatorContextFrom(istioManifest.Manifest)
+	if err == nil {
+		err = manifest.ValidateIstioOperatorSchema(istioOperator, false)
+	}
+	if err != nil {
+		return deploymentPlan{}, errors.Wrap(err, "Rendered Istio manifest is invalid")
 	}
 
 	istioStatus, err := getInstalledVersion(context, performer)
+	if err != nil {
+		return deploymentPlan{}, err
+	}
+
+	plan := decideDeployment(context.Task, istioStatus, context.Logger)
+	plan.manifest = istioManifest.Manifest
+	if plan.reinstall {
+		context.Logger.Info("Forced clean reinstall of Istio was requested, reinstalling Istio although it is already at target version")
+	} else if plan.forced {
+		context.Logger.Info("Forced reconcile of Istio was requested, updating Istio although it is already at target version")
+	}
+	var remediationErr *RetryAfterRemediationError
+	if errors.As(plan.err, &remediationErr) {
+		remediationErr.RequeueAfter = requeueAfterRemediation(context.Task, context.Logger)
+	}
+
+	return plan, nil
+}
+
+// decideDeployment decides how Istio is deployed for the task given the versions found on the cluster. The deployment
+// and the planned actions of the task are based on the same decision.
+func decideDeployment(task *reconciler.Task, istioStatus actions.IstioStatus, logger *zap.SugaredLogger) deploymentPlan {
+	actionKind, err := PlanAction(istioStatus)
+	plan := deploymentPlan{istioStatus: istioStatus, actionKind: actionKind, err: err}
+	if actionKind == ActionKindSkip && boolConfig(task, forceReinstallConfigKey, logger) {
+		plan.actionKind = ActionKindUpdate
+		plan.forced = true
+		plan.reinstall = boolConfig(task, forceReinstallCleanConfigKey, logger)
+	}
+	return plan
+}
+
+func deployIstio(context *service.ActionContext, performer actions.IstioPerformer, observation *actionObservation) error {
+	plan, err := planDeployment(context, performer)
 	if err != nil {
 		return err
 	}
+	istioStatus := plan.istioStatus
+	observation.targetVersion = istioStatus.TargetVersion
 
-	if canInstall(istioStatus) {
+	switch plan.actionKind {
+	case ActionKindInstall:
 		context.Logger.Info("No Istio version was detected on the cluster, performing installation...")
+		err = ensureIstioctlAvailable(istioStatus)
+		if err != nil {
+			return err
+		}
 
-		err = performer.Install(context.Context, context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, context.Logger)
+		rollbackOnFailure := boolConfig(context.Task, installRollbackOnFailureConfigKey, context.Logger)
+		err = performer.Install(context.Context, context.KubeClient.Kubeconfig(), plan.manifest, istioStatus.TargetVersion, istioRevision(context.Task), rollbackOnFailure, context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not install Istio")
 		}
+		observation.metrics.IncInstall(istioStatus.TargetVersion)
+		observation.status = newReconcileStatus(ReconcileOutcomeInstall, istioStatus)
+	case ActionKindSkip:
+		context.Logger.Infof("Istio pilot and data plane are already at target version %s, skipping update", istioStatus.TargetVersion)
+		observation.metrics.IncSkip(istioStatus.TargetVersion)
+		observation.status = newReconcileStatus(ReconcileOutcomeSkipped, istioStatus)
+	case ActionKindUpdate:
+		if isDowngrade(istioStatus) {
+			context.Logger.Warnf("Downgrading Istio from pilot version %s to target version %s", istioStatus.PilotVersion, istioStatus.TargetVersion)
+		}
+		context.Logger.Debugw("Istio version was detected on the cluster, updating pilot and data plane...", "dataPlaneVersions", dataPlaneVersionsString(istioStatus, ","))
+		err = ensureIstioctlAvailable(istioStatus)
+		if err != nil {
+			return err
+		}
 
-	} else if canUpdateResult, err := canUpdate(istioStatus); canUpdateResult {
-		context.Logger.Debugf("Istio version was detected on the cluster, updating pilot from %s and data plane from %s to version %s...", istioStatus.PilotVersion, dataPlaneVersionsString(istioStatus, ","), istioStatus.TargetVersion)
+		if plan.reinstall {
+			err = performer.Reinstall(context.Context, context.KubeClient, plan.manifest, istioStatus.TargetVersion, istioRevision(context.Task), istioSystemNamespace(context.Task), context.Logger)
+			if err != nil {
+				return errors.Wrap(err, "Could not reinstall Istio")
+			}
+			observation.metrics.IncUpdate(istioStatus.TargetVersion)
+			observation.status = newReconcileStatus(ReconcileOutcomeUpdate, istioStatus)
+			return nil
+		}
 
-		err = performer.Update(context.Context, context.KubeClient.Kubeconfig(), istioManifest.Manifest, istioStatus.TargetVersion, context.Logger)
-		if err != nil {
+		skipIngressGatewayRestart := boolConfig(context.Task, ingressGatewaySkipRestartConfigKey, context.Logger)
+		err = performer.Update(context.Context, context.KubeClient.Kubeconfig(), plan.manifest, istioStatus.TargetVersion, istioRevision(context.Task), skipIngressGatewayRestart, context.Logger)
+		var restartPendingErr *actions.IngressGatewayRestartPendingError
+		restartPending := errors.As(err, &restartPendingErr)
+		if restartPending {
+			context.Logger.Warnf("Ingress gateway restart was skipped, it has to be restarted manually")
+		} else if err != nil {
 			return errors.Wrap(err, "Could not update Istio")
 		}
-	} else {
-		return err
+		observation.metrics.IncUpdate(istioStatus.TargetVersion)
+		observation.status = newReconcileStatus(ReconcileOutcomeUpdate, istioStatus)
+		observation.status.IngressGatewayRestartPending = restartPending
+	default:
+		observation.metrics.IncBlocked(istioStatus.TargetVersion)
+		observation.status = newReconcileStatus(ReconcileOutcomeBlocked, istioStatus)
+		return plan.err
 	}
 
 	return nil
 }
 
+// withLogFields returns a copy of the action context whose logger carries the component and namespace of the task as structured fields.
+// The copy keeps fields added during the run from leaking into the context of other actions.
+func withLogFields(context *service.ActionContext) *service.ActionContext {
+	fieldsContext := *context
+	fieldsContext.Logger = context.Logger.With("component", context.Task.Component, "namespace", context.Task.Namespace)
+	return &fieldsContext
+}
+
 type ProxyResetPostAction struct {
+	lastErrorRecorder
 	getIstioPerformer bootstrapIstioPerformer
+	metrics           ActionMetrics
 }
 
 func NewProxyResetPostAction(getIstioPerformer bootstrapIstioPerformer) *ProxyResetPostAction {
-	return &ProxyResetPostAction{getIstioPerformer}
+	return &ProxyResetPostAction{getIstioPerformer: getIstioPerformer, metrics: noopActionMetrics{}}
+}
+
+// WithMetrics makes the action record its durations to the given metrics.
+func (a *ProxyResetPostAction) WithMetrics(metrics ActionMetrics) *ProxyResetPostAction {
+	a.metrics = metrics
+	return a
 }
 
 func (a *ProxyResetPostAction) Run(context *service.ActionContext) error {
+	observation := newActionObservation(a.metrics, proxyResetActionName)
+	err := a.run(withLogFields(context), observation)
+	observation.observeDuration()
+	a.recordError(err)
+	return err
+}
+
+func (a *ProxyResetPostAction) run(context *service.ActionContext, observation *actionObservation) error {
 	context.Logger.Debug("Proxy reset post action of istio triggered")
 
-	performer, err := a.getIstioPerformer(context.Logger)
+	if boolConfig(context.Task, labelNamespacesOnlyConfigKey, context.Logger) {
+		context.Logger.Debug("Skipping proxy reset as only the namespaces are labeled")
+		return nil
+	}
+
+	if boolConfig(context.Task, dryRunConfigKey, context.Logger) {
+		context.Logger.Info("Istio reconciliation dry-run, skipping proxy reset")
+		return nil
+	}
+
+	performer, err := a.getIstioPerformer(context.Task, context.Logger)
 	if err != nil {
 		return err
 	}
@@ -140,35 +326,129 @@ func (a *ProxyResetPostAction) Run(context *service.ActionContext) error {
 	if err != nil {
 		return err
 	}
+	observation.targetVersion = istioStatus.TargetVersion
+
+	failOnError := boolConfig(context.Task, proxyResetFailOnErrorConfigKey, context.Logger)
 
-	err = ensureCanResetProxies(istioStatus)
+	err = ensureProxyTargetCompatibleWithPilot(istioStatus, istioStatus.TargetVersion)
+	if err == nil {
+		err = ensureCanResetProxies(istioStatus)
+	}
 	if err != nil {
+		if failOnError {
+			return errors.Wrap(err, "Can not perform ResetProxy action")
+		}
 		context.Logger.Warnf("Can not perform ResetProxy action: %v", err)
 		return nil
 	}
 
-	err = performer.ResetProxy(context.Context, context.KubeClient.Kubeconfig(), context.WorkspaceFactory, context.Task.Version, context.Task.Component, istioStatus.TargetVersion, istioStatus.TargetPrefix, context.Logger)
+	namespace := proxyResetNamespace(context.Task)
+	if namespace != "" {
+		context.Logger.Infof("Proxy reset limited to namespace %s", namespace)
+	}
+	namespaces := proxyResetNamespaces(context.Task)
+	if len(namespaces) > 0 {
+		context.Logger.Infof("Proxy reset limited to namespaces %s", strings.Join(namespaces, ","))
+	}
+
+	imagePrefix := proxyImagePrefix(context.Task, istioStatus.TargetPrefix, context.Logger)
+
+	proxyVersion := istioStatus.TargetVersion
+	if boolConfig(context.Task, proxyVersionFromIstiodConfigKey, context.Logger) {
+		proxyVersion, err = performer.GetProxyImageVersion(context.Context, context.KubeClient.Kubeconfig(), istioRevision(context.Task), context.Logger)
+		if err != nil {
+			if failOnError {
+				return errors.Wrap(err, "Could not get istio proxy image version from istiod")
+			}
+			context.Logger.Warnf("Can not perform ResetProxy action, could not get istio proxy image version from istiod: %v", err)
+			return nil
+		}
+		err = ensureProxyTargetCompatibleWithPilot(istioStatus, proxyVersion)
+		if err != nil {
+			if failOnError {
+				return errors.Wrap(err, "Can not perform ResetProxy action")
+			}
+			context.Logger.Warnf("Can not perform ResetProxy action: %v", err)
+			return nil
+		}
+		context.Logger.Infof("Resetting proxies to version %s configured in istiod instead of target version %s", proxyVersion, istioStatus.TargetVersion)
+	}
+
+	options := proxyResetOptions(context.Task, context.Logger)
+	summary, err := performer.ResetProxy(context.Context, context.KubeClient.Kubeconfig(), context.WorkspaceFactory, context.Task.Version, context.Task.Component, proxyVersion, imagePrefix, namespace, namespaces, options, context.Logger)
+	context.Logger.Infow("Istio proxy reset summary", "podsConsidered", summary.PodsConsidered, "podsRestarted", summary.PodsRestarted, "podsFailed", summary.PodsFailed, "podsSkipped", summary.PodsSkipped)
 	if err != nil {
+		if failOnError {
+			return errors.Wrap(err, "ResetProxy action failed")
+		}
 		context.Logger.Warnf("ResetProxy action failed: %v", err)
 		return nil
 	}
 
+	return checkProxyResetCompletion(context, performer, proxyVersion, imagePrefix, namespace, namespaces, options, failOnError)
+}
+
+// checkProxyResetCompletion verifies that no pods in the scope of the proxy reset still run an old istio proxy image after the
+// proxy reset. Remaining pods are logged, with failOnError they fail the action.
+func checkProxyResetCompletion(context *service.ActionContext, performer actions.IstioPerformer, targetVersion, imagePrefix, namespace string, namespaces []string, options actions.ProxyResetOptions, failOnError bool) error {
+	remainingPods, err := performer.CheckProxyResetCompletion(context.Context, context.KubeClient.Kubeconfig(), targetVersion, imagePrefix, namespace, namespaces, options, context.Logger)
+	if err != nil {
+		if failOnError {
+			return errors.Wrap(err, "Could not verify the completion of the proxy reset")
+		}
+		context.Logger.Warnf("Could not verify the completion of the proxy reset: %v", err)
+		return nil
+	}
+	if len(remainingPods.Items) == 0 {
+		context.Logger.Debugf("All istio proxies run version %s after the proxy reset", targetVersion)
+		return nil
+	}
+
+	podNames := make([]string, 0, len(remainingPods.Items))
+	for _, pod := range remainingPods.Items {
+		podNames = append(podNames, pod.Namespace+"/"+pod.Name)
+	}
+	if failOnError {
+		return fmt.Errorf("%d pods still run an istio proxy image other than version %s after the proxy reset: %s", len(podNames), targetVersion, strings.Join(podNames, ", "))
+	}
+	context.Logger.Warnf("%d pods still run an istio proxy image other than version %s after the proxy reset: %s", len(podNames), targetVersion, strings.Join(podNames, ", "))
 	return nil
 }
 
 type UninstallAction struct {
+	lastErrorRecorder
 	getIstioPerformer bootstrapIstioPerformer
+	metrics           ActionMetrics
 }
 
 // NewUninstallAction returns an instance of UninstallAction
 func NewUninstallAction(getIstioPerformer bootstrapIstioPerformer) *UninstallAction {
-	return &UninstallAction{getIstioPerformer}
+	return &UninstallAction{getIstioPerformer: getIstioPerformer, metrics: noopActionMetrics{}}
+}
+
+// WithMetrics makes the action record its durations to the given metrics.
+func (a *UninstallAction) WithMetrics(metrics ActionMetrics) *UninstallAction {
+	a.metrics = metrics
+	return a
 }
 
 func (a *UninstallAction) Run(context *service.ActionContext) error {
+	observation := newActionObservation(a.metrics, uninstallActionName)
+	err := a.run(withLogFields(context), observation)
+	observation.observeDuration()
+	a.recordError(err)
+	return err
+}
+
+func (a *UninstallAction) run(context *service.ActionContext, observation *actionObservation) error {
 	context.Logger.Debug("Uninstall action of istio triggered")
 
-	performer, err := a.getIstioPerformer(context.Logger)
+	if boolConfig(context.Task, dryRunConfigKey, context.Logger) {
+		context.Logger.Info("Istio reconciliation dry-run, skipping istio uninstall")
+		return nil
+	}
+
+	performer, err := a.getIstioPerformer(context.Task, context.Logger)
 	if err != nil {
 		return err
 	}
@@ -177,21 +457,32 @@ func (a *UninstallAction) Run(context *service.ActionContext) error {
 	if err != nil {
 		return err
 	}
+	observation.targetVersion = istioStatus.TargetVersion
 	if canUninstall(istioStatus) {
-		component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
-			WithNamespace(istioNamespace).
-			WithProfile(context.Task.Profile).
-			WithConfiguration(context.Task.Configuration).Build()
-		istioManifest, err := context.ChartProvider.RenderManifest(component)
+		err = ensureIstioctlAvailable(istioStatus)
+		if err != nil {
+			return err
+		}
+		if boolConfig(context.Task, uninstallBackupIstioOperatorConfigKey, context.Logger) {
+			err = backupIstioOperatorBeforeUninstall(context, performer)
+			if err != nil {
+				return err
+			}
+		}
+		namespace := istioSystemNamespace(context.Task)
+		istioManifest, err := renderIstioManifest(context)
 		if err != nil {
 			return err
 		}
-		// Before removing istio himself, undeploy all related objects like dashboards
-		err = unDeployIstioRelatedResources(context.Context, istioManifest.Manifest, context.KubeClient, context.Logger)
+		logIstioCRDsBeforeUninstall(context, performer)
+		// Before removing istio himself, undeploy all related objects like dashboards. With continueOnCleanupError the manifest is
+		// deleted document by document, failing documents do not abort the cleanup but are reported together at the end.
+		continueOnCleanupErr := boolConfig(context.Task, continueOnCleanupErrorConfigKey, context.Logger)
+		err = unDeployIstioRelatedResources(context.Context, istioManifest.Manifest, context.KubeClient, cleanupNamespaces(context.Task), continueOnCleanupErr, context.Logger)
 		if err != nil {
 			return err
 		}
-		err = performer.Uninstall(context.KubeClient, istioStatus.TargetVersion, context.Logger)
+		err = performer.Uninstall(context.Context, context.KubeClient, istioStatus.TargetVersion, istioRevision(context.Task), namespace, context.Logger)
 		if err != nil {
 			return errors.Wrap(err, "Could not uninstall istio")
 		}
@@ -202,6 +493,37 @@ func (a *UninstallAction) Run(context *service.ActionContext) error {
 	return nil
 }
 
+// logIstioCRDsBeforeUninstall logs the Istio CRDs with the number of their custom resources, to assess what the uninstall
+// removes. The CRDs are only listed with debug logging enabled, a failure to list them does not stop the uninstall.
+func logIstioCRDsBeforeUninstall(context *service.ActionContext, performer actions.IstioPerformer) {
+	if !context.Logger.Desugar().Core().Enabled(zap.DebugLevel) {
+		return
+	}
+
+	crds, err := performer.GetIstioCRDs(context.Context, context.KubeClient.Kubeconfig(), context.Logger)
+	if err != nil {
+		context.Logger.Warnf("Could not list Istio CRDs before uninstall: %v", err)
+		return
+	}
+	for _, crd := range crds {
+		context.Logger.Debugw("Istio CRD before uninstall", "crd", crd.Name, "kind", crd.Kind, "customResources", crd.CustomResources)
+	}
+}
+
+// backupIstioOperatorBeforeUninstall logs the YAML of the installed IstioOperator. It is skipped if there is none.
+func backupIstioOperatorBeforeUninstall(context *service.ActionContext, performer actions.IstioPerformer) error {
+	operatorYaml, err := performer.GetIstioOperator(context.Context, context.KubeClient.Kubeconfig(), context.Logger)
+	if err != nil {
+		return errors.Wrap(err, "Could not back up Istio Operator before uninstall")
+	}
+	if operatorYaml == "" {
+		context.Logger.Info("No Istio Operator found on the cluster, skipping backup before uninstall")
+		return nil
+	}
+	context.Logger.Infow("Backup of the Istio Operator before uninstall", "istioOperator", operatorYaml)
+	return nil
+}
+
 type helperVersion struct {
 	ver semver.Version
 }
@@ -216,7 +538,8 @@ func (h helperVersion) compare(second helperVersion) int {
 			if h.ver.Patch > second.ver.Patch {
 				return 1
 			} else if h.ver.Patch == second.ver.Patch {
-				return 0
+				// Major, Minor and Patch are equal, so only the pre-release identifiers are compared following semver precedence
+				return h.ver.Compare(second.ver)
 			} else {
 				return -1
 			}
@@ -228,12 +551,9 @@ func (h helperVersion) compare(second helperVersion) int {
 	}
 }
 
+// newHelperVersionFrom parses the version, every version string is parsed only once.
 func newHelperVersionFrom(versionInString string) (helperVersion, error) {
-	version, err := semver.NewVersion(versionInString)
-	if err != nil {
-		return helperVersion{}, err
-	}
-	return helperVersion{ver: *version}, err
+	return helperVersions.get(versionInString)
 }
 
 func canInstall(istioStatus actions.IstioStatus) bool {
@@ -245,18 +565,85 @@ func isInstalled(istioStatus actions.IstioStatus) bool {
 }
 
 func canUninstall(istioStatus actions.IstioStatus) bool {
-	return isInstalled(istioStatus) && istioStatus.ClientVersion != ""
+	return isInstalled(istioStatus)
 }
 
+// ensureIstioctlAvailable checks that an istioctl client version was detected, it is missing if no usable istioctl binary was resolved.
+// It has to be called before every step that runs istioctl.
+func ensureIstioctlAvailable(istioStatus actions.IstioStatus) error {
+	if istioStatus.ClientVersion == "" {
+		return errIstioctlNotAvailable
+	}
+	return nil
+}
+
+// getInstalledVersion returns the status of the Istio installation and attaches its target and pilot version to the logger of the context.
 func getInstalledVersion(context *service.ActionContext, performer actions.IstioPerformer) (actions.IstioStatus, error) {
-	istioStatus, err := performer.Version(context.WorkspaceFactory, context.Task.Version, context.Task.Component, context.KubeClient.Kubeconfig(), context.Logger)
+	istioStatus, err := performer.Version(context.Context, context.WorkspaceFactory, context.Task.Version, context.Task.Component, context.KubeClient.Kubeconfig(), context.Logger)
 	if err != nil {
 		return actions.IstioStatus{}, errors.Wrap(err, "Could not fetch Istio version")
 	}
-	context.Logger.Debugf("Detected: istioctl version %s, target Istio version: %s", istioStatus.ClientVersion, istioStatus.TargetVersion)
+	context.Logger = context.Logger.With("targetVersion", istioStatus.TargetVersion, "pilotVersion", istioStatus.PilotVersion)
+	context.Logger.Debugw("Detected Istio version", "clientVersion", istioStatus.ClientVersion)
 	return istioStatus, nil
 }
 
+// checkClientVersionOffline fails fast if the istioctl binary resolved for the target version of the chart is missing or not compatible
+// with this version, before the cluster is contacted. If the versions cannot be determined without the cluster, e.g. because the chart
+// does not define a target version, the check is left to the full version query.
+func checkClientVersionOffline(context *service.ActionContext, performer actions.IstioPerformer) error {
+	istioStatus, err := performer.ClientVersion(context.WorkspaceFactory, context.Task.Version, context.Task.Component, context.Logger)
+	var unsupportedVersionErr *actions.UnsupportedVersionError
+	if errors.As(err, &unsupportedVersionErr) {
+		return unsupportedVersionOr(err)
+	}
+	if err != nil {
+		context.Logger.Debugf("Could not check the istioctl version without the cluster, checking it with the cluster: %v", err)
+		return nil
+	}
+	return checkClientVersion(istioStatus)
+}
+
+// checkClientVersion checks that an istioctl binary is available and differs from the target version by at most one minor version.
+func checkClientVersion(istioStatus actions.IstioStatus) error {
+	err := ensureIstioctlAvailable(istioStatus)
+	if err != nil {
+		return err
+	}
+	if !isClientCompatibleWithTargetVersion(istioStatus) {
+		return &IncompatibleVersionError{
+			Component:   "istioctl",
+			FromVersion: istioStatus.ClientVersion,
+			ToVersion:   istioStatus.TargetVersion,
+			Action:      "update",
+			Violation:   ViolationClientVersionSkew,
+		}
+	}
+	return nil
+}
+
+// unsupportedVersionOr lists the supported istioctl versions if err is an UnsupportedVersionError, other errors are returned as they are.
+func unsupportedVersionOr(err error) error {
+	var unsupportedVersionErr *actions.UnsupportedVersionError
+	if errors.As(err, &unsupportedVersionErr) {
+		return fmt.Errorf("Istio target version %s is not supported by the available istioctl binaries, supported versions: %s",
+			unsupportedVersionErr.Version, supportedVersionsString(unsupportedVersionErr.SupportedVersions))
+	}
+	return err
+}
+
+// supportedVersionsString lists the supported istioctl versions separated by commas, or "none" if there are none.
+func supportedVersionsString(versions []istioctl.Version) string {
+	if len(versions) == 0 {
+		return "none"
+	}
+	values := make([]string, 0, len(versions))
+	for _, version := range versions {
+		values = append(values, version.String())
+	}
+	return strings.Join(values, ", ")
+}
+
 func isClientCompatibleWithTargetVersion(istioStatus actions.IstioStatus) bool {
 
 	clientHelperVersion, err := newHelperVersionFrom(istioStatus.ClientVersion)
@@ -271,38 +658,130 @@ func isClientCompatibleWithTargetVersion(istioStatus actions.IstioStatus) bool {
 	return amongOneMinor(clientHelperVersion, targetHelperVersion)
 }
 
+// canUpdate checks whether the pilots and the data plane can be updated to the target version. A negative result is always
+// accompanied by an error explaining why the update is not possible.
 func canUpdate(istioStatus actions.IstioStatus) (bool, error) {
-	if isPilotCompatible, err := isComponentCompatible(istioStatus.PilotVersion, istioStatus.TargetVersion, "Pilot"); !isPilotCompatible {
-		return false, err
+	for _, pilotVersion := range pilotVersions(istioStatus) {
+		if isPilotCompatible, err := isComponentCompatible(pilotVersion, istioStatus.TargetVersion, "Pilot"); !isPilotCompatible {
+			return false, updateRejectionReason(err, istioStatus)
+		}
 	}
 
-	for dpVersion := range istioStatus.DataPlaneVersions {
-		if isDataplaneCompatible, err := isComponentCompatible(dpVersion, istioStatus.TargetVersion, "Data plane"); !isDataplaneCompatible {
-			return false, err
+	if err := checkDataPlaneVersions(istioStatus); err != nil {
+		if canBeRemediatedByProxyReset(istioStatus) {
+			return false, newProxyResetRemediationError(err, istioStatus.PilotVersion)
 		}
+		return false, err
 	}
 
 	return true, nil
 }
 
-func ensureCanResetProxies(istioStatus actions.IstioStatus) error {
-	pilotVersion, err := istioctl.VersionFromString(istioStatus.PilotVersion)
+// updateRejectionReason makes sure a rejected update never goes unexplained: if no reason was given, a generic one is
+// created from the versions of the status.
+func updateRejectionReason(err error, istioStatus actions.IstioStatus) error {
 	if err != nil {
-		return errors.Wrap(err, "Error parsing pilot version")
+		return err
 	}
+	return fmt.Errorf("Istio can not be updated from pilot version %s to target version %s", istioStatus.PilotVersion, istioStatus.TargetVersion)
+}
 
-	targetVersion, err := istioctl.VersionFromString(istioStatus.TargetVersion)
+// ensureCanResetProxies checks that all pilots run the target version and the data plane is compatible with it. Pilot and
+// target versions are compared without their pre-release suffix, as a suffix like -distroless or -debug only selects a
+// variant of the same Istio version. The proxy image prefix of the variant is not validated here, the proxy reset compares
+// the whole image of the sidecars with the expected prefix separately.
+func ensureCanResetProxies(istioStatus actions.IstioStatus) error {
+	targetVersion, err := proxyResetVersion(istioStatus.TargetVersion)
 	if err != nil {
 		return errors.Wrap(err, "Error parsing target version")
 	}
 
-	if pilotVersion.MajorMinorPatch() != targetVersion.MajorMinorPatch() {
-		return fmt.Errorf("Istio pilot version %s do not match target version %s", istioStatus.PilotVersion, istioStatus.TargetVersion)
+	for _, pilotVersionString := range pilotVersions(istioStatus) {
+		pilotVersion, err := proxyResetVersion(pilotVersionString)
+		if err != nil {
+			return errors.Wrap(err, "Error parsing pilot version")
+		}
+
+		if pilotVersion != targetVersion {
+			return &IncompatibleVersionError{
+				Component:   "pilot",
+				FromVersion: pilotVersionString,
+				ToVersion:   istioStatus.TargetVersion,
+				Action:      "proxy reset",
+				Violation:   ViolationVersionMismatch,
+			}
+		}
+	}
+
+	return checkDataPlaneVersions(istioStatus)
+}
+
+// proxyResetVersion returns the major, minor and patch version of an Istio image tag, a pre-release suffix selecting the
+// image variant (e.g. 1.2.0-distroless) and build metadata are stripped.
+func proxyResetVersion(version string) (string, error) {
+	release, _, _ := strings.Cut(strings.TrimSpace(version), "+")
+	release, _, _ = strings.Cut(release, "-")
+	parsed, err := istioctl.VersionFromString(release)
+	if err != nil {
+		return "", err
 	}
+	return parsed.MajorMinorPatch(), nil
+}
 
+// pilotVersions returns the sorted versions of all pilots in the mesh. If the status carries no set of pilot versions, only
+// the single pilot version is returned.
+func pilotVersions(istioStatus actions.IstioStatus) []string {
+	if len(istioStatus.PilotVersions) == 0 {
+		return []string{istioStatus.PilotVersion}
+	}
+
+	versions := make([]string, 0, len(istioStatus.PilotVersions))
+	for version := range istioStatus.PilotVersions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// checkDataPlaneVersions checks all data plane versions against the target version. Every incompatible version is reported,
+// several incompatibilities are aggregated into an IncompatibleVersionsError.
+func checkDataPlaneVersions(istioStatus actions.IstioStatus) error {
+	dpVersions := make([]string, 0, len(istioStatus.DataPlaneVersions))
 	for dpVersion := range istioStatus.DataPlaneVersions {
+		dpVersions = append(dpVersions, dpVersion)
+	}
+	sort.Strings(dpVersions)
+
+	var incompatibilities []*IncompatibleVersionError
+	for _, dpVersion := range dpVersions {
 		if isDataplaneCompatible, err := isComponentCompatible(dpVersion, istioStatus.TargetVersion, "Data plane"); !isDataplaneCompatible {
-			return err
+			var incompatibleErr *IncompatibleVersionError
+			if !errors.As(err, &incompatibleErr) {
+				return err
+			}
+			incompatibilities = append(incompatibilities, incompatibleErr)
+		}
+	}
+
+	switch len(incompatibilities) {
+	case 0:
+		return nil
+	case 1:
+		return incompatibilities[0]
+	default:
+		return &IncompatibleVersionsError{Errors: incompatibilities}
+	}
+}
+
+// ensureProxyTargetCompatibleWithPilot checks that istiod running in every pilot version accepts proxies in the given version.
+func ensureProxyTargetCompatibleWithPilot(istioStatus actions.IstioStatus, proxyVersion string) error {
+	if istioStatus.PilotVersion == "" && len(istioStatus.PilotVersions) == 0 {
+		return errors.New("Istio pilot is not installed, proxies can not be reset")
+	}
+
+	for _, pilotVersion := range pilotVersions(istioStatus) {
+		if isCompatible, err := isComponentCompatible(proxyVersion, pilotVersion, "Istio proxy"); !isCompatible {
+			return errors.Wrapf(err, "Target proxy image %s:%s is not compatible with Istio pilot version %s", istioStatus.TargetPrefix, proxyVersion, pilotVersion)
 		}
 	}
 	return nil
@@ -331,13 +810,61 @@ func isComponentCompatible(componentVersion, targetVersion, componentName string
 
 	componentVsTargetComparison := targetHelperVersion.compare(componentHelperVersion)
 	if !amongOneMinor(componentHelperVersion, targetHelperVersion) {
-		return false, fmt.Errorf("Could not perform %s for %s from version: %s to version: %s - the difference between versions exceed one minor version",
-			getActionTypeFrom(componentVsTargetComparison), componentName, componentVersion, targetVersion)
+		return false, &IncompatibleVersionError{
+			Component:   componentName,
+			FromVersion: componentVersion,
+			ToVersion:   targetVersion,
+			Action:      getActionTypeFrom(componentVsTargetComparison),
+			Violation:   ViolationMinorVersionSkew,
+		}
 	}
 
 	return true, nil
 }
 
+// isAtTargetVersion checks if all pilots and the whole data plane already run the target release, in which case
+// an update would only cause needless rollouts. Suffixes like -distroless are ignored in the comparison.
+func isAtTargetVersion(istioStatus actions.IstioStatus) bool {
+	targetVersion, err := proxyResetVersion(istioStatus.TargetVersion)
+	if err != nil {
+		return false
+	}
+
+	versions := pilotVersions(istioStatus)
+	for dpVersion := range istioStatus.DataPlaneVersions {
+		versions = append(versions, dpVersion)
+	}
+	for _, version := range versions {
+		normalisedVersion, err := proxyResetVersion(version)
+		if err != nil || normalisedVersion != targetVersion {
+			return false
+		}
+	}
+	return true
+}
+
+// isDowngrade checks if the target version is lower than the pilot version installed on the cluster. Versions are compared
+// without their suffix, so a distroless pilot is not a downgrade of the same release.
+func isDowngrade(istioStatus actions.IstioStatus) bool {
+	pilotVersion, err := proxyResetVersion(istioStatus.PilotVersion)
+	if err != nil {
+		return false
+	}
+	targetVersion, err := proxyResetVersion(istioStatus.TargetVersion)
+	if err != nil {
+		return false
+	}
+	pilotHelperVersion, err := newHelperVersionFrom(pilotVersion)
+	if err != nil {
+		return false
+	}
+	targetHelperVersion, err := newHelperVersionFrom(targetVersion)
+	if err != nil {
+		return false
+	}
+	return targetHelperVersion.compare(pilotHelperVersion) < 0
+}
+
 func getActionTypeFrom(comparison int) string {
 	switch comparison {
 	case 1:
@@ -352,21 +879,66 @@ func getActionTypeFrom(comparison int) string {
 }
 
 func amongOneMinor(first, second helperVersion) bool {
-	return first.ver.Major == second.ver.Major && (first.ver.Minor == second.ver.Minor || first.ver.Minor-second.ver.Minor == -1 || first.ver.Minor-second.ver.Minor == 1)
+	if first.ver.Major == second.ver.Major {
+		return first.ver.Minor == second.ver.Minor || first.ver.Minor-second.ver.Minor == -1 || first.ver.Minor-second.ver.Minor == 1
+	}
+	return isMajorBoundaryStep(first, second) || isMajorBoundaryStep(second, first)
 }
 
-func unDeployIstioRelatedResources(context context.Context, manifest string, client kubernetes.Client, logger *zap.SugaredLogger) error {
-	logger.Debugf("Undeploying istio related dashboards")
-	// multiple calls necessary, please see: https://github.com/kyma-incubator/reconciler/issues/367
-	_, err := client.Delete(context, manifest, "kyma-system")
-	if err != nil {
-		return err
+// isMajorBoundaryStep checks if lower is the highest minor version of its major and higher is the .0 minor version of the next major.
+func isMajorBoundaryStep(lower, higher helperVersion) bool {
+	highestMinor, ok := highestMinorPerMajor[lower.ver.Major]
+	return ok && higher.ver.Major == lower.ver.Major+1 && lower.ver.Minor == highestMinor && higher.ver.Minor == 0
+}
+
+// unDeployIstioRelatedResources deletes the manifest from each of the namespaces. A failing namespace does not skip the
+// remaining ones, the errors of all namespaces are aggregated.
+func unDeployIstioRelatedResources(context context.Context, manifest string, client kubernetes.Client, namespaces []string, continueOnError bool, logger *zap.SugaredLogger) error {
+	if continueOnError {
+		return unDeployIstioRelatedResourcesPerDocument(context, manifest, client, namespaces, logger)
 	}
-	logger.Debugf("Undeploying other istio related resources")
-	_, err = client.Delete(context, manifest, istioNamespace)
+
+	var errs []error
+	// multiple namespaces necessary, please see: https://github.com/kyma-incubator/reconciler/issues/367
+	for _, namespace := range namespaces {
+		logger.Debugf("Undeploying istio related resources from namespace %s", namespace)
+		_, err := client.Delete(context, manifest, namespace)
+		if err != nil {
+			logger.Warnf("Could not undeploy istio related resources from namespace %s: %v", namespace, err)
+			errs = append(errs, errors.Wrapf(err, "Could not undeploy istio related resources from namespace %s", namespace))
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func unDeployIstioRelatedResourcesPerDocument(context context.Context, manifest string, client kubernetes.Client, namespaces []string, logger *zap.SugaredLogger) error {
+	unstructs, err := kubernetes.ToUnstructured([]byte(manifest), true)
 	if err != nil {
 		return err
 	}
 
+	var failedDocuments []string
+	// multiple namespaces necessary, please see: https://github.com/kyma-incubator/reconciler/issues/367
+	for _, namespace := range namespaces {
+		logger.Debugf("Undeploying istio related resources from namespace %s document by document", namespace)
+		for _, unstruct := range unstructs {
+			document, err := unstruct.MarshalJSON()
+			if err != nil {
+				return err
+			}
+
+			_, err = client.Delete(context, string(document), namespace)
+			if err != nil {
+				logger.Warnf("Could not undeploy %s %s in namespace %s: %v", unstruct.GetKind(), unstruct.GetName(), namespace, err)
+				failedDocuments = append(failedDocuments, fmt.Sprintf("%s/%s in namespace %s: %v", unstruct.GetKind(), unstruct.GetName(), namespace, err))
+			}
+		}
+	}
+
+	if len(failedDocuments) > 0 {
+		return fmt.Errorf("Could not undeploy %d istio related documents: %s", len(failedDocuments), strings.Join(failedDocuments, "; "))
+	}
+
 	return nil
 }
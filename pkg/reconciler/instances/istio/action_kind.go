@@ -0,0 +1,30 @@
+package istio
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+)
+
+// ActionKind is the deployment MainReconcileAction decides on for the Istio versions found on the cluster.
+type ActionKind string
+
+const (
+	ActionKindInstall ActionKind = "install"
+	ActionKindUpdate  ActionKind = "update"
+	ActionKindSkip    ActionKind = "skip"
+	ActionKindBlocked ActionKind = "blocked"
+)
+
+// PlanAction decides whether Istio has to be installed, updated or can be skipped for the given status, without any side
+// effects. If the update is blocked by incompatible versions, the returned error explains why.
+func PlanAction(istioStatus actions.IstioStatus) (ActionKind, error) {
+	if canInstall(istioStatus) {
+		return ActionKindInstall, nil
+	}
+	if isAtTargetVersion(istioStatus) {
+		return ActionKindSkip, nil
+	}
+	if canUpdateResult, err := canUpdate(istioStatus); !canUpdateResult {
+		return ActionKindBlocked, err
+	}
+	return ActionKindUpdate, nil
+}
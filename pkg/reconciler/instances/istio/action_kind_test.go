@@ -0,0 +1,145 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PlanAction(t *testing.T) {
+
+	t.Run("should plan install when neither pilot nor data plane is on the cluster", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", DataPlaneVersions: map[string]bool{}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindInstall, actionKind)
+	})
+
+	t.Run("should plan update when only the data plane is left on the cluster", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindUpdate, actionKind)
+	})
+
+	t.Run("should plan skip when pilot and data plane are at the target version", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindSkip, actionKind)
+	})
+
+	t.Run("should plan skip when pilot and data plane run the plain release of the distroless target version", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0-distroless", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindSkip, actionKind)
+	})
+
+	t.Run("should plan skip when pilot and data plane run the distroless release of the target version", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0-distroless", DataPlaneVersions: map[string]bool{"1.2.0-distroless": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindSkip, actionKind)
+	})
+
+	t.Run("should plan update when the data plane is not at the target version yet", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.1", TargetVersion: "1.2.1", PilotVersion: "1.2.1", DataPlaneVersions: map[string]bool{"1.2.1": true, "1.2.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindUpdate, actionKind)
+	})
+
+	t.Run("should plan update when pilot and data plane are one minor version behind", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindUpdate, actionKind)
+	})
+
+	t.Run("should plan update when downgrading within one minor version", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.11.1", PilotVersion: "1.11.2", DataPlaneVersions: map[string]bool{"1.11.2": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindUpdate, actionKind)
+	})
+
+	t.Run("should plan blocked with reason when the pilot is more than one minor version behind", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.3.0", TargetVersion: "1.3.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, ActionKindBlocked, actionKind)
+	})
+
+	t.Run("should plan blocked with remediation hint when the data plane skew can be fixed by a proxy reset", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.0.0": true, "1.1.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.Equal(t, ActionKindBlocked, actionKind)
+		var remediationErr *RetryAfterRemediationError
+		require.True(t, errors.As(err, &remediationErr))
+	})
+
+	t.Run("should plan blocked with reason when the target version is invalid", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "invalid", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}
+
+		// when
+		actionKind, err := PlanAction(istioStatus)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, ActionKindBlocked, actionKind)
+	})
+}
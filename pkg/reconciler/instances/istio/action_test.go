@@ -2,11 +2,15 @@ package istio
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	actionsmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions/mocks"
@@ -17,9 +21,16 @@ import (
 	log "github.com/kyma-incubator/reconciler/pkg/logger"
 	chartmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	k8smocks "github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -37,6 +48,8 @@ kind: IstioOperator
 metadata:
   namespace: namespace
   name: name
+spec:
+  profile: default
 ---
 apiVersion: version/v2
 kind: Kind2
@@ -150,9 +163,9 @@ func Test_helperVersion_compare(t *testing.T) {
 		require.Zero(t, result)
 	})
 
-	t.Run("should return true when helper versions are of equal numbers and one has suffix", func(t *testing.T) {
+	t.Run("should return lower precedence for pre-release version than for the release", func(t *testing.T) {
 		// given
-		v1, err := newHelperVersionFrom("1.2.3-suffix")
+		v1, err := newHelperVersionFrom("1.2.3-alpha")
 		require.NoError(t, err)
 		v2, err := newHelperVersionFrom("1.2.3")
 		require.NoError(t, err)
@@ -161,14 +174,71 @@ func Test_helperVersion_compare(t *testing.T) {
 		result := v1.compare(v2)
 
 		// then
-		require.Zero(t, result)
+		require.Equal(t, -1, result)
+		require.Equal(t, 1, v2.compare(v1))
+	})
+
+	t.Run("should compare alphanumeric pre-release identifiers lexically", func(t *testing.T) {
+		// given
+		v1, err := newHelperVersionFrom("1.2.3-rc1")
+		require.NoError(t, err)
+		v2, err := newHelperVersionFrom("1.2.3-rc2")
+		require.NoError(t, err)
+
+		// when
+		result := v1.compare(v2)
+
+		// then
+		require.Equal(t, -1, result)
+	})
+
+	t.Run("should compare numeric pre-release identifiers numerically", func(t *testing.T) {
+		// given
+		v1, err := newHelperVersionFrom("1.2.3-rc.10")
+		require.NoError(t, err)
+		v2, err := newHelperVersionFrom("1.2.3-rc.2")
+		require.NoError(t, err)
+
+		// when
+		result := v1.compare(v2)
+
+		// then
+		require.Equal(t, 1, result)
+	})
+
+	t.Run("should return lower precedence for numeric than for alphanumeric pre-release identifiers", func(t *testing.T) {
+		// given
+		v1, err := newHelperVersionFrom("1.2.3-1")
+		require.NoError(t, err)
+		v2, err := newHelperVersionFrom("1.2.3-alpha")
+		require.NoError(t, err)
+
+		// when
+		result := v1.compare(v2)
+
+		// then
+		require.Equal(t, -1, result)
+	})
+
+	t.Run("should return higher precedence for larger set of pre-release identifiers", func(t *testing.T) {
+		// given
+		v1, err := newHelperVersionFrom("1.2.3-alpha.1")
+		require.NoError(t, err)
+		v2, err := newHelperVersionFrom("1.2.3-alpha")
+		require.NoError(t, err)
+
+		// when
+		result := v1.compare(v2)
+
+		// then
+		require.Equal(t, 1, result)
 	})
 
-	t.Run("should return true when helper versions are of equal numbers and both have different suffixes", func(t *testing.T) {
+	t.Run("should return zero when pre-release identifiers are equal", func(t *testing.T) {
 		// given
-		v1, err := newHelperVersionFrom("1.2.3-suffix1")
+		v1, err := newHelperVersionFrom("1.2.3-rc.1")
 		require.NoError(t, err)
-		v2, err := newHelperVersionFrom("1.2.3-suffix2")
+		v2, err := newHelperVersionFrom("1.2.3-rc.1")
 		require.NoError(t, err)
 
 		// when
@@ -181,12 +251,6 @@ func Test_helperVersion_compare(t *testing.T) {
 }
 
 func TestStatusPreAction_Run(t *testing.T) {
-	performerCreatorFn := func(p actions.IstioPerformer) bootstrapIstioPerformer {
-		return func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-			return p, nil
-		}
-	}
-
 	t.Run("should not perform istio actions when istio was detected on the cluster and client version is lower than target version", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
@@ -194,7 +258,7 @@ func TestStatusPreAction_Run(t *testing.T) {
 			ResourceDir: "./test_files/resources/",
 		}, nil)
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		performer := actionsmocks.IstioPerformer{}
@@ -204,31 +268,101 @@ func TestStatusPreAction_Run(t *testing.T) {
 			PilotVersion:      "1.1",
 			DataPlaneVersions: map[string]bool{"1.1": true},
 		}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(tooLowClientVersion, nil)
-		performer.On("Install", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(nil)
+		// the chart does not define the target version, so the client version can only be checked with the cluster
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(actions.IstioStatus{}, errors.New("Target Version could not be found"))
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(tooLowClientVersion, nil)
+		performer.On("Install", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 
-		action := StatusPreAction{performerCreatorFn(&performer)}
+		action := StatusPreAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
 
 		// then
 		require.EqualError(t, err, "Istio could not be updated since the binary version: 1.0 is not compatible with the target version: 1.2 - the difference between versions exceeds one minor version")
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should list the supported versions when the target version is not supported", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		targetVersion, err := istioctl.VersionFromString("1.3.0")
+		require.NoError(t, err)
+		firstSupportedVersion, err := istioctl.VersionFromString("1.1.0")
+		require.NoError(t, err)
+		secondSupportedVersion, err := istioctl.VersionFromString("1.2.4")
+		require.NoError(t, err)
+		unsupportedVersionErr := &actions.UnsupportedVersionError{
+			Version:           targetVersion,
+			SupportedVersions: []istioctl.Version{firstSupportedVersion, secondSupportedVersion},
+			Err:               errors.New("No matching 'istioctl' binary found"),
+		}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(actions.IstioStatus{}, unsupportedVersionErr)
+		action := NewStatusPreAction(performerCreatorFn(&performer))
+
+		// when
+		err = action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "Istio target version 1.3.0 is not supported by the available istioctl binaries, supported versions: 1.1.0, 1.2.4")
+		performer.AssertNotCalled(t, "Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should report that no versions are supported when there are no istioctl binaries", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		targetVersion, err := istioctl.VersionFromString("1.3.0")
+		require.NoError(t, err)
+		unsupportedVersionErr := &actions.UnsupportedVersionError{Version: targetVersion, Err: errors.New("No matching 'istioctl' binary found")}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(actions.IstioStatus{}, unsupportedVersionErr)
+		action := NewStatusPreAction(performerCreatorFn(&performer))
+
+		// when
+		err = action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "Istio target version 1.3.0 is not supported by the available istioctl binaries, supported versions: none")
+	})
+
+	t.Run("should fail on an incompatible istioctl binary before the cluster is contacted", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.0.0", TargetVersion: "1.2.0"}, nil)
+		action := NewStatusPreAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "Istio could not be updated since the binary version: 1.0.0 is not compatible with the target version: 1.2.0 - the difference between versions exceeds one minor version")
+		performer.AssertNotCalled(t, "Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
+	t.Run("should fail when no istioctl binary is available before the cluster is contacted", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{TargetVersion: "1.2.0"}, nil)
+		action := NewStatusPreAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.ErrorIs(t, err, errIstioctlNotAvailable)
+		performer.AssertNotCalled(t, "Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
 
 func Test_ReconcileAction_Run(t *testing.T) {
 
-	performerCreatorFn := func(p actions.IstioPerformer) bootstrapIstioPerformer {
-		return func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-			return p, nil
-		}
-	}
-
 	performerCreatorErrorFn := func(p actions.IstioPerformer) bootstrapIstioPerformer {
-		return func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+		return func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
 			return p, errors.New("Performer error")
 		}
 	}
@@ -237,11 +371,11 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		performer := actionsmocks.IstioPerformer{}
-		action := MainReconcileAction{performerCreatorErrorFn(&performer)}
+		action := MainReconcileAction{getIstioPerformer: performerCreatorErrorFn(&performer)}
 
 		//when
 		err := action.Run(actionContext)
@@ -251,16 +385,58 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Contains(t, err.Error(), "Performer error")
 		provider.AssertNotCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
 		performer.AssertNotCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Install", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"), mock.AnythingOfType("string"))
-		performer.AssertNotCalled(t, "LabelNamespaces", mock.AnythingOfType("context.Context"), mock.AnythingOfType("kubernetes.Client"), mock.AnythingOfType("chart.Factory"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Update", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("string"))
+		performer.AssertNotCalled(t, "LabelNamespaces", mock.AnythingOfType("context.Context"), mock.AnythingOfType("kubernetes.Client"), mock.AnythingOfType("chart.Factory"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), "", mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Update", mock.AnythingOfType("context.Context"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not install istio when the rendered manifest does not contain an istio operator", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifestWithoutIstioOperator}, nil)
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Rendered Istio manifest is invalid: Istio Operator definition could not be found in manifest")
+		performer.AssertNotCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not install istio when the istio operator of the rendered manifest has no spec", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		operatorWithoutSpec := "apiVersion: install.istio.io/v1alpha1\nkind: IstioOperator\nmetadata:\n  name: name\n"
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: operatorWithoutSpec}, nil)
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Rendered Istio manifest is invalid: Istio Operator is invalid: spec is missing")
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should not return error when istio install and label namespaces were successful", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		noIstioOnTheCluster := actions.IstioStatus{
@@ -270,10 +446,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			DataPlaneVersions: map[string]bool{},
 		}
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
-		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(nil)
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(nil)
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -281,23 +457,23 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Update", actionContext.Context, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Update", mock.AnythingOfType("*context.timerCtx"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should return an error when istio installation and label namespaces failed", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(actions.IstioStatus{}, errors.New("Version error"))
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(errors.New("LabelNamespaces error"))
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(actions.IstioStatus{}, errors.New("Version error"))
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("LabelNamespaces error"))
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -307,16 +483,16 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Contains(t, err.Error(), "Version error")
 		require.Contains(t, err.Error(), "LabelNamespaces error")
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"))
-		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("string"))
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should return an error when istio installation failed but label namespaces were successful", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		performer := actionsmocks.IstioPerformer{}
@@ -326,10 +502,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			PilotVersion:      "",
 			DataPlaneVersions: map[string]bool{},
 		}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(noIstioOnTheCluster, nil)
-		performer.On("Install", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(errors.New("Istio Install error"))
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(nil)
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("Istio Install error"))
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -338,17 +514,17 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Istio Install error")
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should return an error when istio installation but namespaces label failed", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		performer := actionsmocks.IstioPerformer{}
@@ -358,10 +534,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			PilotVersion:      "",
 			DataPlaneVersions: map[string]bool{},
 		}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(noIstioOnTheCluster, nil)
-		performer.On("Install", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(nil)
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(errors.New("LabelNamespaces error"))
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("LabelNamespaces error"))
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -370,17 +546,17 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "LabelNamespaces error")
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should not return an error when istio update and label namespaces were successful", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		istioOnTheCluster := actions.IstioStatus{
@@ -390,10 +566,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			DataPlaneVersions: map[string]bool{"1.0.0": true},
 		}
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
-		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(nil)
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(nil)
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -401,17 +577,17 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should not return an error when istio update and label namespaces failed", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		istioOnTheCluster := actions.IstioStatus{
@@ -421,10 +597,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			DataPlaneVersions: map[string]bool{"1.0.0": true},
 		}
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
-		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(errors.New("Istio Update error"))
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(errors.New("LabelNamespaces error"))
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("Istio Update error"))
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("LabelNamespaces error"))
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -434,17 +610,17 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Contains(t, err.Error(), "Istio Update error")
 		require.Contains(t, err.Error(), "LabelNamespaces error")
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should return an error when istio update failed but label namespaces were successful", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		istioOnTheCluster := actions.IstioStatus{
@@ -454,10 +630,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			DataPlaneVersions: map[string]bool{"1.0.0": true},
 		}
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
-		performer.On("Update", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(errors.New("Istio Update error"))
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(nil)
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("Update", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("Istio Update error"))
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -466,16 +642,16 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Istio Update error")
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should return an error when istio update was successful but label namespaces failed", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
 		performer := actionsmocks.IstioPerformer{}
@@ -485,10 +661,10 @@ func Test_ReconcileAction_Run(t *testing.T) {
 			PilotVersion:      "1.0.0",
 			DataPlaneVersions: map[string]bool{"1.0.0": true},
 		}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(istioOnTheCluster, nil)
-		performer.On("Update", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), actionContext.Logger).Return(nil)
-		performer.On("LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger).Return(errors.New("LabelNamespaces error"))
-		action := MainReconcileAction{performerCreatorFn(&performer)}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("Update", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("LabelNamespaces error"))
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
 
 		// when
 		err := action.Run(actionContext)
@@ -497,908 +673,3134 @@ func Test_ReconcileAction_Run(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "LabelNamespaces error")
 		provider.AssertCalled(t, "RenderManifest", mock.AnythingOfType("*chart.Component"))
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "LabelNamespaces", actionContext.Context, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", actionContext.Logger)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
-}
-
-func newFakeServiceContext(factory chart.Factory, provider chart.Provider, client kubernetes.Client) *service.ActionContext {
-	logger := log.NewLogger(true)
-	model := reconciler.Task{
-		Component: "component",
-		Namespace: "namespace",
-		Version:   "version",
-		Profile:   "profile",
-	}
-
-	return &service.ActionContext{
-		KubeClient:       client,
-		Context:          context.Background(),
-		WorkspaceFactory: factory,
-		Logger:           logger,
-		ChartProvider:    provider,
-		Task:             &model,
-	}
-}
-
-func newFakeKubeClient() *k8smocks.Client {
-	mockClient := &k8smocks.Client{}
-	mockClient.On("Clientset").Return(fake.NewSimpleClientset(), nil)
-	mockClient.On("Kubeconfig").Return("kubeconfig")
-	mockClient.On("Deploy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
-
-	return mockClient
-}
-
-func Test_UninstallAction(t *testing.T) {
-	performerCreatorFn := func(p actions.IstioPerformer) bootstrapIstioPerformer {
-		return func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-			return p, nil
-		}
-	}
-
-	noIstioOnTheCluster := actions.IstioStatus{
-		ClientVersion:     "1.0",
-		PilotVersion:      "",
-		DataPlaneVersions: map[string]bool{},
-	}
 
-	istioAvailable := actions.IstioStatus{
-		ClientVersion:     "1.0",
-		PilotVersion:      "1.0",
-		DataPlaneVersions: map[string]bool{"1.0": true},
-	}
-
-	t.Run("should perform istio uninstall action when istio is available", func(t *testing.T) {
+	t.Run("should not return an error when istio install was successful and label namespaces failure is treated as warning", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
-		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		actionContext.Task.Configuration = map[string]interface{}{"istio.labelNamespaces.failureAsWarning": true}
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
-			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
-			Return(istioAvailable, nil)
-		performer.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
-		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{}, nil)
-
-		action := UninstallAction{performerCreatorFn(&performer)}
+		noIstioOnTheCluster := actions.IstioStatus{
+			ClientVersion:     "1.0.0",
+			TargetVersion:     "1.0.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("LabelNamespaces error"))
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
 
 		// when
 		err := action.Run(actionContext)
 
 		// then
 		require.NoError(t, err)
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.
-			AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should not perform istio uninstall action when istio was not detected on the cluster", func(t *testing.T) {
+	t.Run("should return only the install error when label namespaces failure is treated as warning", func(t *testing.T) {
 		// given
 		factory := chartmocks.Factory{}
 		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
 		kubeClient := newFakeKubeClient()
 		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		actionContext.Task.Configuration = map[string]interface{}{"istio.labelNamespaces.failureAsWarning": true}
 		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
-			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
-			Return(noIstioOnTheCluster, nil)
-
-		action := UninstallAction{performerCreatorFn(&performer)}
+		noIstioOnTheCluster := actions.IstioStatus{
+			ClientVersion:     "1.0.0",
+			TargetVersion:     "1.0.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, actionContext.KubeClient.Kubeconfig(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("Istio Install error"))
+		performer.On("LabelNamespaces", mock.AnythingOfType("*context.timerCtx"), actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("LabelNamespaces error"))
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
 
 		// when
 		err := action.Run(actionContext)
 
 		// then
-		require.NoError(t, err)
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("string"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Install error")
+		require.NotContains(t, err.Error(), "LabelNamespaces error")
 	})
+}
 
-	t.Run("should not perform istio uninstall action when there is an error detecting istio version", func(t *testing.T) {
-		// given
-		factory := chartmocks.Factory{}
+func Test_deployIstio_AtTargetVersion(t *testing.T) {
+
+	newActionContext := func() *service.ActionContext {
 		provider := chartmocks.Provider{}
-		kubeClient := newFakeKubeClient()
-		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
-		performer := actionsmocks.IstioPerformer{}
-		performer.On("Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
-			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
-			Return(noIstioOnTheCluster, errors.New("error in detecting istio version"))
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		return newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+	}
 
-		action := UninstallAction{performerCreatorFn(&performer)}
+	t.Run("should skip update when pilot and data plane are exactly at the target version", func(t *testing.T) {
+		// given
+		actionContext := newActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
 
 		// when
-		err := action.Run(actionContext)
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
 
 		// then
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "Could not fetch Istio version: error in detecting istio version")
-		performer.AssertCalled(t, "Version", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
-		performer.AssertNotCalled(t, "Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("string"))
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("bool"), mock.Anything)
 	})
 
-}
-func Test_canUnInstall(t *testing.T) {
-
-	t.Run("should uninstall when istio is installed", func(t *testing.T) {
+	t.Run("should update when a data plane version differs from the target version in the patch version", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.9.2",
-			TargetVersion:     "",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{"1.9.2": true},
-		}
+		actionContext := newActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.1", TargetVersion: "1.2.1", PilotVersion: "1.2.1", DataPlaneVersions: map[string]bool{"1.2.1": true, "1.2.0": true}}, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 
 		// when
-		got := canUninstall(istioVersion)
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
 
 		// then
-		require.True(t, got)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should not uninstall when istio is not installed", func(t *testing.T) {
+	t.Run("should update when the pilot differs from the target version in the patch version", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		actionContext := newActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.1", TargetVersion: "1.2.1", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.1": true}}, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 
 		// when
-		got := canUninstall(istioVersion)
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
 
 		// then
-		require.False(t, got)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should not uninstall when istio ctl is not installed", func(t *testing.T) {
+	t.Run("should update when already at the target version but a forced reinstall is requested", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.11.2",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 
 		// when
-		got := canUninstall(istioVersion)
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
 
 		// then
-		require.False(t, got)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Reinstall", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
-	t.Run("should not matter to uninstall if client version and data plane diverge", func(t *testing.T) {
+
+	t.Run("should reinstall in the configured namespace when a forced clean reinstall is requested", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.9.0",
-			TargetVersion:     "1.20.2",
-			PilotVersion:      "1.11.2",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true, "istio.forceReinstall.clean": true, "istio.namespace": "custom-istio"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+		performer.On("Reinstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "1.2.0", "", "custom-istio", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		observation := newActionObservation(noopActionMetrics{}, reconcileActionName)
 
 		// when
-		got := canUninstall(istioVersion)
+		err := deployIstio(actionContext, &performer, observation)
 
 		// then
-		require.True(t, got)
+		require.NoError(t, err)
+		require.Equal(t, ReconcileOutcomeUpdate, observation.status.Outcome)
+		performer.AssertCalled(t, "Reinstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "1.2.0", "", "custom-istio", mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
-}
 
-func Test_canInstall(t *testing.T) {
-	t.Run("should install when client and pilot versions are empty", func(t *testing.T) {
+	t.Run("should reinstall the configured revision when a forced clean reinstall is requested", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.9.2",
-			TargetVersion:     "",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true, "istio.forceReinstall.clean": true, "istio.revision": "canary"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+		performer.On("Reinstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "1.2.0", "canary", "istio-system", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Reinstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "1.2.0", "canary", "istio-system", mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should return error when the forced clean reinstall failed", func(t *testing.T) {
+		// given
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true, "istio.forceReinstall.clean": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+		performer.On("Reinstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), "1.2.0", "", "istio-system", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("Old istiod pods did not terminate"))
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not reinstall Istio")
+	})
+}
+
+func Test_deployIstio_DowngradeWarning(t *testing.T) {
+
+	newActionContext := func(logger *zap.SugaredLogger) *service.ActionContext {
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		actionContext.Logger = logger
+		return actionContext
+	}
+
+	t.Run("should warn when the target version is lower than the pilot version", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.WarnLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.11.1", PilotVersion: "1.11.2", DataPlaneVersions: map[string]bool{"1.11.2": true}}, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.NoError(t, err)
+		warnings := logs.FilterMessage("Downgrading Istio from pilot version 1.11.2 to target version 1.11.1").All()
+		require.Len(t, warnings, 1)
+		require.Equal(t, zap.WarnLevel, warnings[0].Level)
+	})
+
+	t.Run("should not warn when the target version is higher than the pilot version", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.WarnLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.11.2", PilotVersion: "1.11.1", DataPlaneVersions: map[string]bool{"1.11.1": true}}, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, logs.FilterMessageSnippet("Downgrading Istio").All())
+	})
+}
+
+func Test_ActionLogFields(t *testing.T) {
+
+	t.Run("should log component, namespace, target and pilot version as structured fields", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.DebugLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.17.0", TargetVersion: "1.17.1", PilotVersion: "1.16.0"}, nil)
+
+		// when
+		_, err := getInstalledVersion(withLogFields(actionContext), &performer)
+
+		// then
+		require.NoError(t, err)
+		detected := logs.FilterMessage("Detected Istio version").All()
+		require.Len(t, detected, 1)
+		require.Equal(t, map[string]interface{}{
+			"component":     "component",
+			"namespace":     "namespace",
+			"targetVersion": "1.17.1",
+			"pilotVersion":  "1.16.0",
+			"clientVersion": "1.17.0",
+		}, detected[0].ContextMap())
+	})
+
+	t.Run("should not attach fields to the logger of the original context", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		logger := actionContext.Logger
+
+		// when
+		fieldsContext := withLogFields(actionContext)
+
+		// then
+		require.Same(t, logger, actionContext.Logger)
+		require.NotSame(t, logger, fieldsContext.Logger)
+	})
+}
+
+func newFakeServiceContext(factory chart.Factory, provider chart.Provider, client kubernetes.Client) *service.ActionContext {
+	logger := log.NewLogger(true)
+	model := reconciler.Task{
+		Component: "component",
+		Namespace: "namespace",
+		Version:   "version",
+		Profile:   "profile",
+	}
+
+	return &service.ActionContext{
+		KubeClient:       client,
+		Context:          context.Background(),
+		WorkspaceFactory: factory,
+		Logger:           logger,
+		ChartProvider:    provider,
+		Task:             &model,
+	}
+}
+
+func newFakeKubeClient() *k8smocks.Client {
+	mockClient := &k8smocks.Client{}
+	mockClient.On("Clientset").Return(fake.NewSimpleClientset(), nil)
+	mockClient.On("Kubeconfig").Return("kubeconfig")
+	mockClient.On("Deploy", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	return mockClient
+}
+
+func newFakeActionContext() *service.ActionContext {
+	return newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, newFakeKubeClient())
+}
+
+// newFakeRenderingActionContext returns a fake action context whose chart provider renders istioManifest.
+func newFakeRenderingActionContext() *service.ActionContext {
+	provider := chartmocks.Provider{}
+	provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+	return newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+}
+
+func performerCreatorFn(p actions.IstioPerformer) bootstrapIstioPerformer {
+	return func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+		return p, nil
+	}
+}
+
+// newProxyResetPerformer returns a performer mock reporting istioStatus, on which the proxy reset and the check of its
+// completion succeed.
+func newProxyResetPerformer(istioStatus actions.IstioStatus) *actionsmocks.IstioPerformer {
+	performer := actionsmocks.IstioPerformer{}
+	performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(istioStatus, nil)
+	performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{}, nil)
+	performer.On("CheckProxyResetCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+	return &performer
+}
+
+// newReconcilePerformer returns a performer mock reporting istioStatus, on which install, update and the labeling of the
+// namespaces succeed.
+func newReconcilePerformer(istioStatus actions.IstioStatus) *actionsmocks.IstioPerformer {
+	performer := actionsmocks.IstioPerformer{}
+	performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(istioStatus, nil)
+	performer.On("Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("bool"), mock.Anything).Return(nil)
+	performer.On("Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("bool"), mock.Anything).Return(nil)
+	performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return &performer
+}
+
+func Test_UninstallAction(t *testing.T) {
+	noIstioOnTheCluster := actions.IstioStatus{
+		ClientVersion:     "1.0",
+		PilotVersion:      "",
+		DataPlaneVersions: map[string]bool{},
+	}
+
+	istioAvailable := actions.IstioStatus{
+		ClientVersion:     "1.0",
+		PilotVersion:      "1.0",
+		DataPlaneVersions: map[string]bool{"1.0": true},
+	}
+
+	t.Run("should perform istio uninstall action when istio is available", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioAvailable, nil)
+		performer.On("GetIstioCRDs", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]actions.IstioCRD{}, nil)
+		performer.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.
+			AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should stop the cleanup of istio related resources at the first error by default", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("delete error"))
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioAvailable, nil)
+		performer.On("GetIstioCRDs", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]actions.IstioCRD{}, nil)
+		performer.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "istio related documents")
+	})
+
+	t.Run("should continue the cleanup of istio related resources on errors when it is configured for the task", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("delete error"))
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		actionContext.Task.Configuration = map[string]interface{}{"istio.uninstall.continueOnCleanupError": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioAvailable, nil)
+		performer.On("GetIstioCRDs", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]actions.IstioCRD{}, nil)
+		performer.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istio related documents")
+	})
+
+	t.Run("should uninstall istio from the configured namespace", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		actionContext.Task.Configuration = map[string]interface{}{"istio.namespace": "custom-istio"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioAvailable, nil)
+		performer.On("GetIstioCRDs", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]actions.IstioCRD{}, nil)
+		performer.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "custom-istio", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "custom-istio", mock.AnythingOfType("*zap.SugaredLogger"))
+		kubeClient.AssertCalled(t, "Delete", mock.Anything, mock.Anything, "custom-istio")
+		kubeClient.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, "istio-system")
+	})
+
+	t.Run("should not perform istio uninstall action when istio was not detected on the cluster", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(noIstioOnTheCluster, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"))
+	})
+
+	t.Run("should not perform istio uninstall action when there is an error detecting istio version", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(noIstioOnTheCluster, errors.New("error in detecting istio version"))
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not fetch Istio version: error in detecting istio version")
+		performer.AssertCalled(t, "Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		performer.AssertNotCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"))
+	})
+
+	t.Run("should return an error when istio is available but istioctl is not", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		istioWithoutClient := actions.IstioStatus{
+			PilotVersion:      "1.0",
+			DataPlaneVersions: map[string]bool{"1.0": true},
+		}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioWithoutClient, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.True(t, errors.Is(err, errIstioctlNotAvailable))
+		performer.AssertNotCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not return an error when istioctl is not available and istio was not detected on the cluster", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&factory, &provider, kubeClient)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType(
+			"string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{DataPlaneVersions: map[string]bool{}}, nil)
+
+		action := UninstallAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_UninstallAction_IstioOperatorBackup(t *testing.T) {
+	istioAvailable := actions.IstioStatus{
+		ClientVersion:     "1.0",
+		PilotVersion:      "1.0",
+		DataPlaneVersions: map[string]bool{"1.0": true},
+	}
+
+	newActionContext := func(logger *zap.SugaredLogger) *service.ActionContext {
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, kubeClient)
+		actionContext.Logger = logger
+		return actionContext
+	}
+
+	newPerformer := func(operatorYaml string, err error) *actionsmocks.IstioPerformer {
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioAvailable, nil)
+		performer.On("GetIstioOperator", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(operatorYaml, err)
+		performer.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		return &performer
+	}
+
+	t.Run("should log the installed Istio Operator before uninstalling istio", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.InfoLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.uninstall.backupIstioOperator": true}
+		performer := newPerformer("kind: IstioOperator\n", nil)
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		backups := logs.FilterMessage("Backup of the Istio Operator before uninstall").All()
+		require.Len(t, backups, 1)
+		require.Equal(t, "kind: IstioOperator\n", backups[0].ContextMap()["istioOperator"])
+		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should uninstall istio when there is no Istio Operator to back up", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.InfoLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.uninstall.backupIstioOperator": true}
+		performer := newPerformer("", nil)
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, logs.FilterMessage("Backup of the Istio Operator before uninstall").All())
+		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not uninstall istio when the Istio Operator could not be backed up", func(t *testing.T) {
+		// given
+		actionContext := newActionContext(log.NewLogger(true))
+		actionContext.Task.Configuration = map[string]interface{}{"istio.uninstall.backupIstioOperator": true}
+		performer := newPerformer("", errors.New("dynamic client error"))
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "Could not back up Istio Operator before uninstall: dynamic client error")
+		performer.AssertNotCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not back up the Istio Operator by default", func(t *testing.T) {
+		// given
+		actionContext := newActionContext(log.NewLogger(true))
+		performer := newPerformer("kind: IstioOperator\n", nil)
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "GetIstioOperator", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_UninstallAction_IstioCRDs(t *testing.T) {
+	istioAvailable := actions.IstioStatus{
+		ClientVersion:     "1.0",
+		PilotVersion:      "1.0",
+		DataPlaneVersions: map[string]bool{"1.0": true},
+	}
+
+	newActionContext := func(logger *zap.SugaredLogger) *service.ActionContext {
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, kubeClient)
+		actionContext.Logger = logger
+		return actionContext
+	}
+
+	newPerformer := func(crds []actions.IstioCRD, err error) *actionsmocks.IstioPerformer {
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(istioAvailable, nil)
+		performer.On("GetIstioCRDs", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(crds, err)
+		performer.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		return &performer
+	}
+
+	t.Run("should log the Istio CRDs before uninstalling istio", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.DebugLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		performer := newPerformer([]actions.IstioCRD{
+			{Name: "gateways.networking.istio.io", Group: "networking.istio.io", Kind: "Gateway", CustomResources: 1},
+			{Name: "virtualservices.networking.istio.io", Group: "networking.istio.io", Kind: "VirtualService", CustomResources: 3},
+		}, nil)
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		crdLogs := logs.FilterMessage("Istio CRD before uninstall").All()
+		require.Len(t, crdLogs, 2)
+		require.Equal(t, "virtualservices.networking.istio.io", crdLogs[1].ContextMap()["crd"])
+		require.Equal(t, int64(3), crdLogs[1].ContextMap()["customResources"])
+		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should uninstall istio when the Istio CRDs could not be listed", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.DebugLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		performer := newPerformer(nil, errors.New("list error"))
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, logs.FilterMessage("Could not list Istio CRDs before uninstall: list error").All(), 1)
+		performer.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not list the Istio CRDs without debug logging", func(t *testing.T) {
+		// given
+		core, _ := observer.New(zap.InfoLevel)
+		actionContext := newActionContext(zap.New(core).Sugar())
+		performer := newPerformer(nil, nil)
+		action := NewUninstallAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "GetIstioCRDs", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_unDeployIstioRelatedResources(t *testing.T) {
+
+	defaultNamespaces := []string{"kyma-system", istioNamespace}
+
+	t.Run("should delete from the remaining namespaces when one fails and continue on error is disabled", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, "kyma-system").Return(nil, errors.New("delete error"))
+		kubeClient.On("Delete", mock.Anything, mock.Anything, istioNamespace).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, defaultNamespaces, false, actionContext.Logger)
+
+		// then
+		require.EqualError(t, err, "Could not undeploy istio related resources from namespace kyma-system: delete error")
+		kubeClient.AssertNumberOfCalls(t, "Delete", 2)
+		kubeClient.AssertCalled(t, "Delete", mock.Anything, istioManifest, istioNamespace)
+	})
+
+	t.Run("should aggregate the errors of all failing namespaces", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("delete error"))
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, []string{"ns1", "ns2", "ns3"}, false, actionContext.Logger)
+
+		// then
+		var aggregate utilerrors.Aggregate
+		require.ErrorAs(t, err, &aggregate)
+		require.Len(t, aggregate.Errors(), 3)
+		kubeClient.AssertNumberOfCalls(t, "Delete", 3)
+	})
+
+	t.Run("should delete from each configured namespace", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, []string{"team-a", "team-b", "istio-system"}, false, actionContext.Logger)
+
+		// then
+		require.NoError(t, err)
+		kubeClient.AssertNumberOfCalls(t, "Delete", 3)
+		for _, namespace := range []string{"team-a", "team-b", "istio-system"} {
+			kubeClient.AssertCalled(t, "Delete", mock.Anything, istioManifest, namespace)
+		}
+		kubeClient.AssertNotCalled(t, "Delete", mock.Anything, istioManifest, "kyma-system")
+	})
+
+	t.Run("should delete every document from each configured namespace when continue on error is enabled", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, []string{"team-a"}, true, actionContext.Logger)
+
+		// then
+		require.NoError(t, err)
+		kubeClient.AssertNumberOfCalls(t, "Delete", 3)
+		kubeClient.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, "kyma-system")
+	})
+
+	t.Run("should delete every document and return aggregated error when continue on error is enabled", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.MatchedBy(func(document string) bool {
+			return strings.Contains(document, "Kind1")
+		}), istioNamespace).Return(nil, errors.New("delete error"))
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, defaultNamespaces, true, actionContext.Logger)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not undeploy 1 istio related documents")
+		require.Contains(t, err.Error(), "Kind1/name in namespace istio-system: delete error")
+		require.NotContains(t, err.Error(), "Kind2")
+		kubeClient.AssertNumberOfCalls(t, "Delete", 6)
+	})
+
+	t.Run("should not return error when all documents were deleted and continue on error is enabled", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, defaultNamespaces, true, actionContext.Logger)
+
+		// then
+		require.NoError(t, err)
+		kubeClient.AssertNumberOfCalls(t, "Delete", 6)
+	})
+
+	t.Run("should delete istio related resources from the given istio namespace", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		kubeClient.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+
+		// when
+		err := unDeployIstioRelatedResources(actionContext.Context, istioManifest, kubeClient, []string{"kyma-system", "custom-istio"}, false, actionContext.Logger)
+
+		// then
+		require.NoError(t, err)
+		kubeClient.AssertCalled(t, "Delete", mock.Anything, istioManifest, "kyma-system")
+		kubeClient.AssertCalled(t, "Delete", mock.Anything, istioManifest, "custom-istio")
+		kubeClient.AssertNotCalled(t, "Delete", mock.Anything, istioManifest, "istio-system")
+	})
+}
+
+func Test_canUnInstall(t *testing.T) {
+
+	t.Run("should uninstall when istio is installed", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.9.2",
+			TargetVersion:     "",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{"1.9.2": true},
+		}
+
+		// when
+		got := canUninstall(istioVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should not uninstall when istio is not installed", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := canUninstall(istioVersion)
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("should uninstall when istio is installed although istio ctl is not", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.11.2",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+
+		// when
+		got := canUninstall(istioVersion)
+
+		// then
+		require.True(t, got)
+	})
+	t.Run("should not matter to uninstall if client version and data plane diverge", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.9.0",
+			TargetVersion:     "1.20.2",
+			PilotVersion:      "1.11.2",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+
+		// when
+		got := canUninstall(istioVersion)
+
+		// then
+		require.True(t, got)
+	})
+}
+
+func Test_canInstall(t *testing.T) {
+	t.Run("should install when client and pilot versions are empty", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.9.2",
+			TargetVersion:     "",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := canInstall(istioVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should update when client and pilot versions values are not empty", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "",
+			PilotVersion:      "1.11.1",
+			DataPlaneVersions: map[string]bool{"1.11.1": true},
+		}
+
+		// when
+		got := canInstall(istioVersion)
+
+		// then
+		require.False(t, got)
+	})
+}
+
+func Test_canUpdate(t *testing.T) {
+	t.Run("should not allow update when client version is more than one minor behind the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.0.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.0.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.False(t, result)
+	})
+
+	t.Run("should allow update when permissible downgrade scenario is detected for pilot", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.True(t, result)
+	})
+
+	t.Run("should not allow update when downgrade scenario is detected for pilot", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "1.3.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.False(t, result)
+	})
+
+	t.Run("should allow update when permissible downgrade scenario is detected for data plane", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.5": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.True(t, result)
+	})
+
+	t.Run("should not allow update when downgrade scenario is detected for data plane", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.3.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.False(t, result)
+	})
+
+	t.Run("should not allow update when more than one minor upgrade is detected for pilot", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.0.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.False(t, result)
+	})
+
+	t.Run("should not allow update when more than one minor upgrade is detected for data plane", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.False(t, result)
+	})
+
+	t.Run("should allow update when less than one minor upgrade is detected for pilot and data plane ", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.True(t, result)
+	})
+
+	t.Run("should allow update when all versions match", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.True(t, result)
+	})
+
+	t.Run("should allow update when control plane is consistent and not in the same version as data plane", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.1",
+			TargetVersion:     "1.2.1",
+			PilotVersion:      "1.2.1",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		result, _ := canUpdate(version)
+
+		// then
+		require.True(t, result)
+	})
+
+	t.Run("should report all incompatible data plane versions in one error", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.4.0",
+			TargetVersion:     "1.4.0",
+			PilotVersion:      "1.4.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true, "1.1.0": true, "1.2.0": true, "1.4.0": true},
+		}
+
+		// when
+		result, err := canUpdate(version)
+
+		// then
+		require.False(t, result)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Found 3 incompatible data plane versions")
+		require.Contains(t, err.Error(), "from version: 1.0.0 to version: 1.4.0")
+		require.Contains(t, err.Error(), "from version: 1.1.0 to version: 1.4.0")
+		require.Contains(t, err.Error(), "from version: 1.2.0 to version: 1.4.0")
+	})
+
+	t.Run("should not allow update when one of multiple pilot versions is more than one minor behind the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			PilotVersions:     map[string]bool{"1.2.0": true, "1.0.0": true},
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		result, err := canUpdate(version)
+
+		// then
+		require.False(t, result)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not perform upgrade for Pilot from version: 1.0.0 to version: 1.2.0")
+	})
+
+	t.Run("should allow update when all of multiple pilot versions are compatible with the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			PilotVersions:     map[string]bool{"1.1.0": true, "1.2.0": true},
+			DataPlaneVersions: map[string]bool{"1.1.0": true, "1.2.0": true},
+		}
+
+		// when
+		result, err := canUpdate(version)
+
+		// then
+		require.True(t, result)
+		require.NoError(t, err)
+	})
+}
+
+func Test_updateRejectionReason(t *testing.T) {
+	version := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.0.0",
+		DataPlaneVersions: map[string]bool{"1.0.0": true},
+	}
+
+	t.Run("should keep the given reason", func(t *testing.T) {
+		// when
+		err := updateRejectionReason(errors.New("pilot is incompatible"), version)
+
+		// then
+		require.EqualError(t, err, "pilot is incompatible")
+	})
+
+	t.Run("should explain a rejected update without a reason", func(t *testing.T) {
+		// when
+		err := updateRejectionReason(nil, version)
+
+		// then
+		require.EqualError(t, err, "Istio can not be updated from pilot version 1.0.0 to target version 1.2.0")
+	})
+}
+
+func Test_canUpdate_AlwaysExplainsRejection(t *testing.T) {
+	rejected := []actions.IstioStatus{
+		{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.0.0", DataPlaneVersions: map[string]bool{"1.2.0": true}},
+		{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.0.0": true}},
+		{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "abc", DataPlaneVersions: map[string]bool{"1.2.0": true}},
+		{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersions: map[string]bool{"1.2.0": true, "1.0.0": true}, DataPlaneVersions: map[string]bool{"1.2.0": true}},
+	}
+
+	for _, version := range rejected {
+		t.Run(fmt.Sprintf("should return an error when pilot %s and data plane %v are rejected", version.PilotVersion, version.DataPlaneVersions), func(t *testing.T) {
+			// when
+			result, err := canUpdate(version)
+
+			// then
+			require.False(t, result)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_ensureCanResetProxies(t *testing.T) {
+	t.Run("should not allow proxy reset when pilot version do not match the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.0.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.0.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NotNil(t, err)
+		require.Equal(t, err.Error(), "Istio pilot version 1.0.0 do not match target version 1.2.0")
+	})
+
+	t.Run("should not allow proxy reset when one of dataplane versions is more than one minor behind the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true, "1.0.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NotNil(t, err)
+		require.Equal(t, err.Error(), "Could not perform upgrade for Data plane from version: 1.0.0 to version: 1.2.0 - the difference between versions exceed one minor version")
+	})
+
+	t.Run("should report all dataplane versions which are more than one minor away from the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.3.0",
+			TargetVersion:     "1.3.0",
+			PilotVersion:      "1.3.0",
+			DataPlaneVersions: map[string]bool{"1.3.0": true, "1.1.0": true, "1.0.0": true, "1.5.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NotNil(t, err)
+		require.Equal(t, "Found 3 incompatible data plane versions: "+
+			"Could not perform upgrade for Data plane from version: 1.0.0 to version: 1.3.0 - the difference between versions exceed one minor version; "+
+			"Could not perform upgrade for Data plane from version: 1.1.0 to version: 1.3.0 - the difference between versions exceed one minor version; "+
+			"Could not perform downgrade for Data plane from version: 1.5.0 to version: 1.3.0 - the difference between versions exceed one minor version", err.Error())
+	})
+
+	t.Run("should allow proxy reset when all versions match", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.Nil(t, err)
+	})
+
+	t.Run("should allow proxy reset when one of dataplane versions is one minor behind the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true, "1.1.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.Nil(t, err)
+	})
+
+	t.Run("should allow proxy reset when no dataplane versions found", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.Nil(t, err)
+	})
+
+	t.Run("should allow proxy reset when target version has a pre-release specified", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0-distroless",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.Nil(t, err)
+	})
+
+	t.Run("should report all incompatible data plane versions in one error", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.4.0",
+			TargetVersion:     "1.4.0",
+			PilotVersion:      "1.4.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true, "1.1.0": true, "1.2.0": true, "1.3.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Found 3 incompatible data plane versions")
+		require.Contains(t, err.Error(), "from version: 1.0.0 to version: 1.4.0")
+		require.Contains(t, err.Error(), "from version: 1.1.0 to version: 1.4.0")
+		require.Contains(t, err.Error(), "from version: 1.2.0 to version: 1.4.0")
+		require.NotContains(t, err.Error(), "1.3.0")
+	})
+
+	t.Run("should not allow proxy reset when one of multiple pilot versions do not match the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			PilotVersions:     map[string]bool{"1.2.0": true, "1.1.0": true},
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NotNil(t, err)
+		require.Equal(t, err.Error(), "Istio pilot version 1.1.0 do not match target version 1.2.0")
+	})
+
+	t.Run("should allow proxy reset when target version is a debug variant", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0-debug",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should allow proxy reset when pilot runs a distroless variant of the plain target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0-distroless",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should allow proxy reset when target version has several suffixes", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0-solo-fips-distroless",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should not allow proxy reset when distroless pilot version do not match the distroless target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0-distroless",
+			PilotVersion:      "1.1.0-distroless",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.EqualError(t, err, "Istio pilot version 1.1.0-distroless do not match target version 1.2.0-distroless")
+	})
+
+	t.Run("should return an error when the target version is invalid", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion: "1.2.0",
+			TargetVersion: "distroless",
+			PilotVersion:  "1.2.0",
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Error parsing target version")
+	})
+}
+
+func Test_proxyResetVersion(t *testing.T) {
+
+	t.Run("should keep a plain version", func(t *testing.T) {
+		// when
+		version, err := proxyResetVersion("1.2.0")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+	})
+
+	t.Run("should strip the distroless suffix", func(t *testing.T) {
+		// when
+		version, err := proxyResetVersion("1.2.0-distroless")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+	})
+
+	t.Run("should strip the debug suffix", func(t *testing.T) {
+		// when
+		version, err := proxyResetVersion("1.2.0-debug")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+	})
+
+	t.Run("should strip several suffixes and build metadata", func(t *testing.T) {
+		// when
+		version, err := proxyResetVersion("1.2.0-solo-fips-distroless+build.1")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.0", version)
+	})
+
+	t.Run("should return an error when the version is empty", func(t *testing.T) {
+		// when
+		_, err := proxyResetVersion("")
+
+		// then
+		require.Error(t, err)
+	})
+}
+
+func Test_ensureProxyTargetCompatibleWithPilot(t *testing.T) {
+	t.Run("should allow proxy target matching the pilot version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			TargetVersion: "1.2.0-distroless",
+			TargetPrefix:  "eu.gcr.io/kyma-project/external/istio/proxyv2",
+			PilotVersion:  "1.2.0",
+		}
+
+		// when
+		err := ensureProxyTargetCompatibleWithPilot(version, version.TargetVersion)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should allow proxy target one minor away from the pilot version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			TargetVersion: "1.3.0",
+			PilotVersion:  "1.2.5",
+		}
+
+		// when
+		err := ensureProxyTargetCompatibleWithPilot(version, version.TargetVersion)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should not allow proxy target more than one minor away from the pilot version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			TargetVersion: "1.4.0",
+			TargetPrefix:  "eu.gcr.io/kyma-project/external/istio/proxyv2",
+			PilotVersion:  "1.2.0",
+		}
+
+		// when
+		err := ensureProxyTargetCompatibleWithPilot(version, version.TargetVersion)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Target proxy image eu.gcr.io/kyma-project/external/istio/proxyv2:1.4.0 is not compatible with Istio pilot version 1.2.0")
+	})
+
+	t.Run("should not allow proxy target more than one minor away from any of the pilot versions", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			TargetVersion: "1.4.0",
+			TargetPrefix:  "eu.gcr.io/kyma-project/external/istio/proxyv2",
+			PilotVersion:  "1.4.0",
+			PilotVersions: map[string]bool{"1.2.0": true, "1.4.0": true},
+		}
+
+		// when
+		err := ensureProxyTargetCompatibleWithPilot(version, version.TargetVersion)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Target proxy image eu.gcr.io/kyma-project/external/istio/proxyv2:1.4.0 is not compatible with Istio pilot version 1.2.0")
+	})
+
+	t.Run("should not allow proxy reset when pilot is not installed", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			TargetVersion: "1.2.0",
+		}
+
+		// when
+		err := ensureProxyTargetCompatibleWithPilot(version, version.TargetVersion)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio pilot is not installed")
+	})
+}
+
+func Test_isClientCompatible(t *testing.T) {
+	t.Run("should return false if version string is semver incompatible", func(t *testing.T) {
+		// given
+		badVersions := actions.IstioStatus{
+			ClientVersion:     "version1",
+			PilotVersion:      "version2",
+			DataPlaneVersions: map[string]bool{"version3": true},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(badVersions)
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("should return true when client and target versions are the same", func(t *testing.T) {
+		// given
+		exactSameClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(exactSameClientVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should return true when client and target versions are of the same minor and different patch and client version is higher than target", func(t *testing.T) {
+		// given
+		sameMinorClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.1.1",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(sameMinorClientVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should return true when client and target versions are of the same minor and different patch and target version is higher than client", func(t *testing.T) {
+		// given
+		sameMinorClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.1",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(sameMinorClientVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should return true when client and target versions are among one minor and of the same patch and client version is higher than target", func(t *testing.T) {
+		// given
+		oneHigherMinorClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(oneHigherMinorClientVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should return true when client and target versions are among one minor and of the same patch and target version is higher than client", func(t *testing.T) {
+		// given
+		oneLowerMinorClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(oneLowerMinorClientVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("should return false when client and target versions are not among one minor and target version is higher than client", func(t *testing.T) {
+		// given
+		twoLowerMinorClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.0.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(twoLowerMinorClientVersion)
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("should return false when client and target versions are not among one minor and client version is higher than target", func(t *testing.T) {
+		// given
+		greaterThanOneMinorClientVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.0.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		got := isClientCompatibleWithTargetVersion(greaterThanOneMinorClientVersion)
+
+		// then
+		require.False(t, got)
+	})
+}
+
+func Test_isComponentCompatible(t *testing.T) {
+	componentName := "component"
+
+	t.Run("should return false when version string is semver incompatible", func(t *testing.T) {
+		// given
+		badVersions := actions.IstioStatus{
+			ClientVersion:     "version1",
+			PilotVersion:      "version2",
+			DataPlaneVersions: map[string]bool{"version3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(badVersions.PilotVersion, badVersions.TargetVersion, componentName)
+
+		// then
+		require.Error(t, err)
+		require.False(t, got)
+	})
+
+	t.Run("should return true when pilot and target versions are equal", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.3",
+			TargetVersion:     "1.2.3",
+			PilotVersion:      "1.2.3",
+			DataPlaneVersions: map[string]bool{"1.2.3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, got)
+	})
+
+	t.Run("should return true when pilot and targets version are vary only in patch", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.3",
+			TargetVersion:     "1.2.3",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, got)
+	})
+
+	t.Run("should return true when pilot version is one minor lower than target", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.3",
+			TargetVersion:     "1.2.3",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.2.3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, got)
+	})
+
+	t.Run("should return true when pilot version is one minor higher than target", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.3",
+			TargetVersion:     "1.2.3",
+			PilotVersion:      "1.3.0",
+			DataPlaneVersions: map[string]bool{"1.2.3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, got)
+	})
+
+	t.Run("should return false when pilot version is more than one minor lower than target", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.3",
+			TargetVersion:     "1.2.3",
+			PilotVersion:      "1.0.0",
+			DataPlaneVersions: map[string]bool{"1.2.3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+
+		// then
+		require.Error(t, err)
+		require.False(t, got)
+	})
+
+	t.Run("should return false when pilot version is more than one minor higher than target", func(t *testing.T) {
+		// given
+		istioVersion := actions.IstioStatus{
+			ClientVersion:     "1.2.3",
+			TargetVersion:     "1.2.3",
+			PilotVersion:      "1.4.0",
+			DataPlaneVersions: map[string]bool{"1.2.3": true},
+		}
+
+		// when
+		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+
+		// then
+		require.Error(t, err)
+		require.False(t, got)
+	})
+}
+
+func Test_amongOneMinor(t *testing.T) {
+	t.Run("Downgrade of PilotVersion with same minor version is permitted", func(t *testing.T) {
+		// given
+		sameMinorPilotVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.11.6",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+		pilotHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.PilotVersion)
+		require.NoError(t, err)
+		targetHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.TargetVersion)
+		require.NoError(t, err)
+
+		// when
+		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("Upgrade of PilotVersion with same minor version is permitted", func(t *testing.T) {
+		// given
+		sameMinorPilotVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.11.1",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+		pilotHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.PilotVersion)
+		require.NoError(t, err)
+		targetHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.TargetVersion)
+		require.NoError(t, err)
+
+		// when
+		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("Downgrade of PilotVersion with one minor version is permitted", func(t *testing.T) {
+		// given
+		oneMinorPilotVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.12.6",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+		pilotHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.PilotVersion)
+		require.NoError(t, err)
+		targetHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.TargetVersion)
+		require.NoError(t, err)
+
+		// when
+		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("Upgrade of PilotVersion with one minor version is permitted", func(t *testing.T) {
+		// given
+		oneMinorPilotVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.10.1",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+		pilotHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.PilotVersion)
+		require.NoError(t, err)
+		targetHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.TargetVersion)
+		require.NoError(t, err)
+
+		// when
+		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+
+		// then
+		require.True(t, got)
+	})
+
+	t.Run("Downgrade of PilotVersion with more than one minor version is NOT permitted", func(t *testing.T) {
+		// given
+		greaterThanOneMinorPilotVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.13.6",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+		pilotHelperVersion, err := newHelperVersionFrom(greaterThanOneMinorPilotVersion.PilotVersion)
+		require.NoError(t, err)
+		targetHelperVersion, err := newHelperVersionFrom(greaterThanOneMinorPilotVersion.TargetVersion)
+		require.NoError(t, err)
+
+		// when
+		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("Upgrade of PilotVersion with more than one minor version is NOT permitted", func(t *testing.T) {
+		// given
+		lesserThanOneMinorPilotVersion := actions.IstioStatus{
+			ClientVersion:     "1.11.2",
+			TargetVersion:     "1.11.2",
+			PilotVersion:      "1.9.1",
+			DataPlaneVersions: map[string]bool{"1.11.2": true},
+		}
+		pilotHelperVersion, err := newHelperVersionFrom(lesserThanOneMinorPilotVersion.PilotVersion)
+		require.NoError(t, err)
+		targetHelperVersion, err := newHelperVersionFrom(lesserThanOneMinorPilotVersion.TargetVersion)
+		require.NoError(t, err)
 
 		// when
-		got := canInstall(istioVersion)
+		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+
+		// then
+		require.False(t, got)
+	})
+}
+
+func Test_amongOneMinor_MajorBoundary(t *testing.T) {
+	highestMinors := highestMinorPerMajor
+	highestMinorPerMajor = map[int64]int64{1: 30}
+	defer func() { highestMinorPerMajor = highestMinors }()
+
+	amongOneMinorOf := func(first, second string) bool {
+		firstHelperVersion, err := newHelperVersionFrom(first)
+		require.NoError(t, err)
+		secondHelperVersion, err := newHelperVersionFrom(second)
+		require.NoError(t, err)
+		return amongOneMinor(firstHelperVersion, secondHelperVersion)
+	}
+
+	t.Run("Upgrade from the highest minor version of a major to the first minor version of the next major is permitted", func(t *testing.T) {
+		// when
+		got := amongOneMinorOf("1.30.4", "2.0.1")
 
 		// then
 		require.True(t, got)
 	})
 
-	t.Run("should update when client and pilot versions values are not empty", func(t *testing.T) {
-		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "",
-			PilotVersion:      "1.11.1",
-			DataPlaneVersions: map[string]bool{"1.11.1": true},
-		}
+	t.Run("Downgrade from the first minor version of a major to the highest minor version of the previous major is permitted", func(t *testing.T) {
+		// when
+		got := amongOneMinorOf("2.0.0", "1.30.1")
+
+		// then
+		require.True(t, got)
+	})
 
+	t.Run("Upgrade from a minor version lower than the highest minor version of a major to the next major is NOT permitted", func(t *testing.T) {
 		// when
-		got := canInstall(istioVersion)
+		got := amongOneMinorOf("1.29.0", "2.0.0")
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("Upgrade from the highest minor version of a major beyond the first minor version of the next major is NOT permitted", func(t *testing.T) {
+		// when
+		got := amongOneMinorOf("1.30.0", "2.1.0")
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("Upgrade skipping a major version is NOT permitted", func(t *testing.T) {
+		// when
+		got := amongOneMinorOf("1.30.0", "3.0.0")
+
+		// then
+		require.False(t, got)
+	})
+
+	t.Run("Upgrade across a major version without a configured highest minor version is NOT permitted", func(t *testing.T) {
+		// when
+		got := amongOneMinorOf("2.5.0", "3.0.0")
 
 		// then
 		require.False(t, got)
 	})
+
+	t.Run("Upgrade within the same major version is not affected by the highest minor version", func(t *testing.T) {
+		// when
+		got := amongOneMinorOf("1.30.0", "1.31.0")
+
+		// then
+		require.True(t, got)
+	})
 }
 
-func Test_canUpdate(t *testing.T) {
-	t.Run("should not allow update when client version is more than one minor behind the target version", func(t *testing.T) {
+func Test_generateNewManifestWithoutIstioOperatorFrom(t *testing.T) {
+
+	t.Run("should generate empty manifest from empty input manifest", func(t *testing.T) {
+		// when
+		result, err := manifest.GenerateNewManifestWithoutIstioOperatorFrom("")
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("should return manifest without IstioOperator kind if it was not present ", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.0.0",
+		require.Contains(t, istioManifestWithoutIstioOperator, "Kind1")
+		require.Contains(t, istioManifestWithoutIstioOperator, "Kind2")
+		require.NotContains(t, istioManifestWithoutIstioOperator, "IstioOperator")
+
+		// when
+		result, err := manifest.GenerateNewManifestWithoutIstioOperatorFrom(istioManifestWithoutIstioOperator)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, "Kind1")
+		require.Contains(t, result, "Kind2")
+		require.NotContains(t, result, "IstioOperator")
+	})
+
+	t.Run("should return manifest without IstioOperator kind if it was present", func(t *testing.T) {
+		// given
+		require.Contains(t, istioManifest, "Kind1")
+		require.Contains(t, istioManifest, "Kind2")
+		require.Contains(t, istioManifest, "IstioOperator")
+
+		// when
+		result, err := manifest.GenerateNewManifestWithoutIstioOperatorFrom(istioManifest)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, "Kind1")
+		require.Contains(t, result, "Kind2")
+		require.NotContains(t, result, "IstioOperator")
+	})
+
+}
+
+func Test_deployIstio_ForceReinstall(t *testing.T) {
+
+	dataPlaneSkew := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.2.0",
+		DataPlaneVersions: map[string]bool{"1.0.0": true},
+	}
+
+	t.Run("should not force update when the data plane is two minor versions behind the target version", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dataPlaneSkew, nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.Error(t, err)
+		var incompatibleErr *IncompatibleVersionError
+		require.ErrorAs(t, err, &incompatibleErr)
+		require.Equal(t, "Data plane", incompatibleErr.Component)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not update istio when the data plane blocks the update and it is not forced", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(dataPlaneSkew, nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.Error(t, err)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not force update across more than one minor version of the pilot", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true}
+		pilotSkew := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
 			TargetVersion:     "1.2.0",
 			PilotVersion:      "1.0.0",
 			DataPlaneVersions: map[string]bool{"1.0.0": true},
 		}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(pilotSkew, nil)
 
 		// when
-		result, _ := canUpdate(version)
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.Error(t, err)
+		var incompatibleErr *IncompatibleVersionError
+		require.ErrorAs(t, err, &incompatibleErr)
+		require.Equal(t, "Pilot", incompatibleErr.Component)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should keep the pre action compatibility guard when forced", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.0.0", TargetVersion: "1.2.0"}, nil)
+		action := NewStatusPreAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		var incompatibleErr *IncompatibleVersionError
+		require.ErrorAs(t, err, &incompatibleErr)
+		require.Equal(t, ViolationClientVersionSkew, incompatibleErr.Violation)
+	})
+}
+
+func Test_deployIstio_IngressGatewaySkipRestart(t *testing.T) {
+
+	updatableStatus := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.1.0",
+		DataPlaneVersions: map[string]bool{"1.1.0": true},
+	}
+
+	t.Run("should report a pending ingress gateway restart when the update skipped it", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.ingressGateway.skipRestart": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(updatableStatus, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), true, mock.AnythingOfType("*zap.SugaredLogger")).Return(&actions.IngressGatewayRestartPendingError{})
+		observation := newActionObservation(noopActionMetrics{}, reconcileActionName)
+
+		// when
+		err := deployIstio(actionContext, &performer, observation)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ReconcileOutcomeUpdate, observation.status.Outcome)
+		require.True(t, observation.status.IngressGatewayRestartPending)
+	})
+
+	t.Run("should not report a pending ingress gateway restart when the update restarted it", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(updatableStatus, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), false, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		observation := newActionObservation(noopActionMetrics{}, reconcileActionName)
+
+		// when
+		err := deployIstio(actionContext, &performer, observation)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, observation.status.IngressGatewayRestartPending)
+	})
+
+	t.Run("should fail when the update failed", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.ingressGateway.skipRestart": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(updatableStatus, nil)
+		performer.On("Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("upgrade error"))
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.EqualError(t, err, "Could not update Istio: upgrade error")
+	})
+}
+
+func Test_isDowngrade(t *testing.T) {
+
+	t.Run("should detect downgrade when the target version is lower than the pilot version", func(t *testing.T) {
+		require.True(t, isDowngrade(actions.IstioStatus{TargetVersion: "1.1.0", PilotVersion: "1.2.0"}))
+	})
+
+	t.Run("should not detect downgrade when the pilot runs the distroless release of the target version", func(t *testing.T) {
+		require.False(t, isDowngrade(actions.IstioStatus{TargetVersion: "1.2.0", PilotVersion: "1.2.0-distroless"}))
+	})
+
+	t.Run("should not detect downgrade when the target version is the distroless release of the pilot version", func(t *testing.T) {
+		require.False(t, isDowngrade(actions.IstioStatus{TargetVersion: "1.2.0-distroless", PilotVersion: "1.2.0"}))
+	})
+}
+
+func Test_deployIstio_InstallRollback(t *testing.T) {
+
+	noIstioOnTheCluster := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", DataPlaneVersions: map[string]bool{}}
+
+	newActionContext := func(configuration map[string]interface{}) *service.ActionContext {
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = configuration
+		return actionContext
+	}
+
+	t.Run("should request the rollback of a failed installation when it is configured", func(t *testing.T) {
+		// given
+		actionContext := newActionContext(map[string]interface{}{"istio.install.rollbackOnFailure": true})
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), true, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), true, mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should not request the rollback of a failed installation by default", func(t *testing.T) {
+		// given
+		actionContext := newActionContext(nil)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), false, mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), "1.2.0", mock.AnythingOfType("string"), false, mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_deployIstio_IstioctlNotAvailable(t *testing.T) {
+
+	newActionContext := func() *service.ActionContext {
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		return newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+	}
+
+	t.Run("should return an error when istioctl is not available and istio has to be installed", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{TargetVersion: "1.2.0"}, nil)
+
+		// when
+		err := deployIstio(newActionContext(), &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.True(t, errors.Is(err, errIstioctlNotAvailable))
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should return an error when istioctl is not available and istio has to be updated", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}, nil)
+
+		// when
+		err := deployIstio(newActionContext(), &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.True(t, errors.Is(err, errIstioctlNotAvailable))
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should skip without istioctl when istio is already at target version", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+
+		// when
+		err := deployIstio(newActionContext(), &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_ProxyResetPostAction_Options(t *testing.T) {
+	istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+	t.Run("should pass the configured options to the proxy reset", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.imageComparison": "digest", "istio.proxyReset.imageDigest": "sha256:abc",
+			"istio.proxyReset.jobPodsHandling": "skip", "istio.proxyReset.namespacePriority": "kyma-system",
+			"istio.proxyReset.includeTerminatingPods": true}
+		performer := newProxyResetPerformer(istioStatus)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+			actions.ProxyResetOptions{ImageComparison: data.ImageComparisonDigest, ImageDigest: "sha256:abc", JobPodsHandling: data.JobPodsHandlingSkip,
+				NamespacePriority: []string{"kyma-system"}, IncludeTerminatingPods: true}, mock.Anything)
+	})
+}
+
+func Test_Action_LastError(t *testing.T) {
+	compatibleStatus := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.2.0",
+		DataPlaneVersions: map[string]bool{"1.2.0": true},
+	}
+
+	t.Run("should not report any error before the first run", func(t *testing.T) {
+		// given
+		action := NewStatusPreAction(performerCreatorFn(&actionsmocks.IstioPerformer{}))
 
 		// then
-		require.False(t, result)
+		require.Nil(t, action.LastError())
 	})
 
-	t.Run("should allow update when permissible downgrade scenario is detected for pilot", func(t *testing.T) {
+	t.Run("should record the error of a failed run and clear it on the next successful run", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.1.0": true},
-		}
+		factory := chartmocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "./test_files/resources/"}, nil)
+		provider := chartmocks.Provider{}
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(compatibleStatus, nil)
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{}, errors.New("version error")).Once()
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(compatibleStatus, nil).Once()
+		performer.On("GetIstiodRevisions", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]string{"default"}, nil)
+		action := NewStatusPreAction(performerCreatorFn(&performer))
+		before := time.Now()
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, result)
+		require.Error(t, err)
+		lastError := action.LastError()
+		require.NotNil(t, lastError)
+		require.Equal(t, err, lastError.Err)
+		require.False(t, lastError.Timestamp.Before(before))
+
+		// when
+		err = action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Nil(t, action.LastError())
 	})
 
-	t.Run("should not allow update when downgrade scenario is detected for pilot", func(t *testing.T) {
+	t.Run("should record errors per action", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "1.3.0",
-			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		actionContext := newFakeActionContext()
+		failingCreatorFn := func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+			return nil, errors.New("performer error")
 		}
+		preAction := NewStatusPreAction(failingCreatorFn)
+		postAction := NewProxyResetPostAction(performerCreatorFn(&actionsmocks.IstioPerformer{}))
 
 		// when
-		result, _ := canUpdate(version)
+		err := preAction.Run(actionContext)
 
 		// then
-		require.False(t, result)
+		require.EqualError(t, err, "performer error")
+		require.EqualError(t, preAction.LastError().Err, "performer error")
+		require.Nil(t, postAction.LastError())
 	})
+}
 
-	t.Run("should allow update when permissible downgrade scenario is detected for data plane", func(t *testing.T) {
-		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "1.1.0",
-			DataPlaneVersions: map[string]bool{"1.1.5": true},
+func Test_StatusPreAction_ConflictingRevisions(t *testing.T) {
+	compatibleStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}
+	inPlaceIstiod := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}}
+	canaryIstiod := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod-canary", Namespace: "istio-system", Labels: map[string]string{"app": "istiod", "istio.io/rev": "canary"}}}
+
+	newKubeClient := func(deployments ...*appsv1.Deployment) *k8smocks.Client {
+		clientSet := fake.NewSimpleClientset()
+		for _, deployment := range deployments {
+			require.NoError(t, clientSet.Tracker().Add(deployment))
 		}
+		kubeClient := k8smocks.Client{}
+		kubeClient.On("Clientset").Return(clientSet, nil)
+		kubeClient.On("Kubeconfig").Return("kubeconfig")
+		return &kubeClient
+	}
+	// the revisions are detected on the fake deployments by the default performer, everything else is mocked
+	newPerformer := func() *actionsmocks.IstioPerformer {
+		defaultPerformer := actions.NewDefaultIstioPerformer(nil, nil, nil, nil)
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(compatibleStatus, nil)
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(compatibleStatus, nil)
+		performer.On("GetIstiodRevisions", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(func(ctx context.Context, kubeClient kubernetes.Client, namespace string, logger *zap.SugaredLogger) []string {
+				revisions, err := defaultPerformer.GetIstiodRevisions(ctx, kubeClient, namespace, logger)
+				require.NoError(t, err)
+				return revisions
+			}, nil)
+		return &performer
+	}
+
+	t.Run("should fail when an in-place and a canary revision are installed but no revision was requested", func(t *testing.T) {
+		// given
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, newKubeClient(inPlaceIstiod, canaryIstiod))
+		action := NewStatusPreAction(performerCreatorFn(newPerformer()))
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, result)
+		require.EqualError(t, err, "Detected conflicting Istio revisions on the cluster: canary, default, set istio.revision to select the revision to reconcile")
 	})
 
-	t.Run("should not allow update when downgrade scenario is detected for data plane", func(t *testing.T) {
+	t.Run("should succeed when an in-place and a canary revision are installed and a revision was requested", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "1.1.0",
-			DataPlaneVersions: map[string]bool{"1.3.0": true},
-		}
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, newKubeClient(inPlaceIstiod, canaryIstiod))
+		actionContext.Task.Configuration = map[string]interface{}{"istio.revision": "canary"}
+		performer := newPerformer()
+		action := NewStatusPreAction(performerCreatorFn(performer))
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, result)
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "GetIstiodRevisions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should not allow update when more than one minor upgrade is detected for pilot", func(t *testing.T) {
+	t.Run("should succeed when only a single revision is installed", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.0.0",
-			DataPlaneVersions: map[string]bool{"1.1.0": true},
-		}
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, newKubeClient(inPlaceIstiod))
+		action := NewStatusPreAction(performerCreatorFn(newPerformer()))
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, result)
+		require.NoError(t, err)
 	})
 
-	t.Run("should not allow update when more than one minor upgrade is detected for data plane", func(t *testing.T) {
+	t.Run("should fail when the revisions could not be detected", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.1.0",
-			DataPlaneVersions: map[string]bool{"1.0.0": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("ClientVersion", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(compatibleStatus, nil)
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(compatibleStatus, nil)
+		performer.On("GetIstiodRevisions", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("list error"))
+		action := NewStatusPreAction(performerCreatorFn(&performer))
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, result)
+		require.EqualError(t, err, "Could not detect the Istio revisions on the cluster: list error")
 	})
+}
 
-	t.Run("should allow update when less than one minor upgrade is detected for pilot and data plane ", func(t *testing.T) {
+func Test_ProxyResetPostAction_Namespaces(t *testing.T) {
+	istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+	t.Run("should reset proxies only in the allowlisted namespaces", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.1.0",
-			DataPlaneVersions: map[string]bool{"1.1.0": true},
-		}
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.namespaces": "team-a,team-b", "istio.proxyReset.failOnError": true}
+		performer := newProxyResetPerformer(istioStatus)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, result)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", []string{"team-a", "team-b"}, mock.Anything, mock.Anything)
+		performer.AssertCalled(t, "CheckProxyResetCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", []string{"team-a", "team-b"}, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should allow update when all versions match", func(t *testing.T) {
+	t.Run("should reset proxies in all namespaces when no allowlist is configured", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.2.0": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := newProxyResetPerformer(istioStatus)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, result)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", []string(nil), mock.Anything, mock.Anything)
 	})
+}
 
-	t.Run("should allow update when control plane is consistent and not in the same version as data plane", func(t *testing.T) {
+type fakeActionMetrics struct {
+	installs  []string
+	updates   []string
+	skips     []string
+	blocked   []string
+	durations map[string]string
+}
+
+func newFakeActionMetrics() *fakeActionMetrics {
+	return &fakeActionMetrics{durations: map[string]string{}}
+}
+
+func (m *fakeActionMetrics) IncInstall(targetVersion string) {
+	m.installs = append(m.installs, targetVersion)
+}
+
+func (m *fakeActionMetrics) IncUpdate(targetVersion string) {
+	m.updates = append(m.updates, targetVersion)
+}
+
+func (m *fakeActionMetrics) IncSkip(targetVersion string) {
+	m.skips = append(m.skips, targetVersion)
+}
+
+func (m *fakeActionMetrics) IncBlocked(targetVersion string) {
+	m.blocked = append(m.blocked, targetVersion)
+}
+
+func (m *fakeActionMetrics) ObserveDuration(action, targetVersion string, _ time.Duration) {
+	m.durations[action] = targetVersion
+}
+
+func Test_ActionMetrics(t *testing.T) {
+
+	t.Run("should record install and duration of the reconcile action", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.1",
-			TargetVersion:     "1.2.1",
-			PilotVersion:      "1.2.1",
-			DataPlaneVersions: map[string]bool{"1.2.0": true},
-		}
+		actionContext := newFakeRenderingActionContext()
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", DataPlaneVersions: map[string]bool{}})
+		metrics := newFakeActionMetrics()
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer)).WithMetrics(metrics)
 
 		// when
-		result, _ := canUpdate(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, result)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1.2.0"}, metrics.installs)
+		require.Empty(t, metrics.updates)
+		require.Empty(t, metrics.skips)
+		require.Equal(t, map[string]string{reconcileActionName: "1.2.0"}, metrics.durations)
 	})
-}
 
-func Test_ensureCanResetProxies(t *testing.T) {
-	t.Run("should not allow proxy reset when pilot version do not match the target version", func(t *testing.T) {
+	t.Run("should record update of the reconcile action", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.0.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.0.0",
-			DataPlaneVersions: map[string]bool{"1.0.0": true},
-		}
+		actionContext := newFakeRenderingActionContext()
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}})
+		metrics := newFakeActionMetrics()
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer)).WithMetrics(metrics)
 
 		// when
-		err := ensureCanResetProxies(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.NotNil(t, err)
-		require.Equal(t, err.Error(), "Istio pilot version 1.0.0 do not match target version 1.2.0")
+		require.NoError(t, err)
+		require.Empty(t, metrics.installs)
+		require.Equal(t, []string{"1.2.0"}, metrics.updates)
+		require.Empty(t, metrics.skips)
 	})
 
-	t.Run("should not allow proxy reset when one of dataplane versions is more than one minor behind the target version", func(t *testing.T) {
+	t.Run("should record blocked update of the reconcile action when versions are not compatible", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.2.0": true, "1.0.0": true},
-		}
+		actionContext := newFakeRenderingActionContext()
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.4.0", TargetVersion: "1.4.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}})
+		metrics := newFakeActionMetrics()
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer)).WithMetrics(metrics)
 
 		// when
-		err := ensureCanResetProxies(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.NotNil(t, err)
-		require.Equal(t, err.Error(), "Could not perform upgrade for Data plane from version: 1.0.0 to version: 1.2.0 - the difference between versions exceed one minor version")
+		require.Error(t, err)
+		require.Empty(t, metrics.installs)
+		require.Empty(t, metrics.updates)
+		require.Empty(t, metrics.skips)
+		require.Equal(t, []string{"1.4.0"}, metrics.blocked)
 	})
 
-	t.Run("should allow proxy reset when all versions match", func(t *testing.T) {
+	t.Run("should record duration without target version when the version could not be resolved", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.2.0": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(actions.IstioStatus{}, errors.New("Version error"))
+		metrics := newFakeActionMetrics()
+		action := NewUninstallAction(performerCreatorFn(&performer)).WithMetrics(metrics)
 
 		// when
-		err := ensureCanResetProxies(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.Nil(t, err)
+		require.Error(t, err)
+		require.Equal(t, map[string]string{uninstallActionName: ""}, metrics.durations)
 	})
 
-	t.Run("should allow proxy reset when one of dataplane versions is one minor behind the target version", func(t *testing.T) {
+	t.Run("should record duration of the proxy reset action", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.2.0": true, "1.1.0": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := newProxyResetPerformer(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}})
+		metrics := newFakeActionMetrics()
+		action := NewProxyResetPostAction(performerCreatorFn(performer)).WithMetrics(metrics)
 
 		// when
-		err := ensureCanResetProxies(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.Nil(t, err)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{proxyResetActionName: "1.2.0"}, metrics.durations)
 	})
+}
 
-	t.Run("should allow proxy reset when no dataplane versions found", func(t *testing.T) {
+func Test_ProxyResetPostAction_ImagePrefix(t *testing.T) {
+	istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", TargetPrefix: "eu.gcr.io/kyma-project/external/istio", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+	t.Run("should reset proxies with the configured image prefix", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{},
-		}
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxy.imagePrefix": "registry.local/istio"}
+		performer := newProxyResetPerformer(istioStatus)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		err := ensureCanResetProxies(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.Nil(t, err)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "1.2.0", "registry.local/istio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should allow proxy reset when target version has a pre-release specified", func(t *testing.T) {
+	t.Run("should reset proxies with the image prefix of the Istio chart when no override is configured", func(t *testing.T) {
 		// given
-		version := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.2.0-distroless",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.2.0": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := newProxyResetPerformer(istioStatus)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		err := ensureCanResetProxies(version)
+		err := action.Run(actionContext)
 
 		// then
-		require.Nil(t, err)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "1.2.0", "eu.gcr.io/kyma-project/external/istio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
-func Test_isClientCompatible(t *testing.T) {
-	t.Run("should return false if version string is semver incompatible", func(t *testing.T) {
+func Test_MainReconcileAction_LastStatus(t *testing.T) {
+	t.Run("should not report any status before the first run", func(t *testing.T) {
 		// given
-		badVersions := actions.IstioStatus{
-			ClientVersion:     "version1",
-			PilotVersion:      "version2",
-			DataPlaneVersions: map[string]bool{"version3": true},
-		}
+		action := NewIstioMainReconcileAction(performerCreatorFn(&actionsmocks.IstioPerformer{}))
+
+		// then
+		require.Nil(t, action.LastStatus())
+	})
+
+	t.Run("should report install when Istio was installed", func(t *testing.T) {
+		// given
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", DataPlaneVersions: map[string]bool{}})
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(badVersions)
+		err := action.Run(newFakeRenderingActionContext())
 
 		// then
-		require.False(t, got)
+		require.NoError(t, err)
+		status := action.LastStatus()
+		require.NotNil(t, status)
+		require.Equal(t, ReconcileOutcomeInstall, status.Outcome)
+		require.Empty(t, status.SourcePilotVersions)
+		require.Empty(t, status.SourceDataPlaneVersions)
+		require.Equal(t, "1.2.0", status.TargetVersion)
 	})
 
-	t.Run("should return true when client and target versions are the same", func(t *testing.T) {
+	t.Run("should report update with the source versions when Istio was updated", func(t *testing.T) {
 		// given
-		exactSameClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true, "1.2.0": true}})
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(exactSameClientVersion)
+		err := action.Run(newFakeRenderingActionContext())
 
 		// then
-		require.True(t, got)
+		require.NoError(t, err)
+		status := action.LastStatus()
+		require.Equal(t, ReconcileOutcomeUpdate, status.Outcome)
+		require.Equal(t, []string{"1.1.0"}, status.SourcePilotVersions)
+		require.Equal(t, []string{"1.1.0", "1.2.0"}, status.SourceDataPlaneVersions)
+		require.Equal(t, "1.2.0", status.TargetVersion)
 	})
 
-	t.Run("should return true when client and target versions are of the same minor and different patch and client version is higher than target", func(t *testing.T) {
+	t.Run("should report skipped when Istio is already at the target version", func(t *testing.T) {
 		// given
-		sameMinorClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.1.1",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}})
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(sameMinorClientVersion)
+		err := action.Run(newFakeRenderingActionContext())
 
 		// then
-		require.True(t, got)
+		require.NoError(t, err)
+		require.Equal(t, ReconcileOutcomeSkipped, action.LastStatus().Outcome)
+		require.Equal(t, "1.2.0", action.LastStatus().TargetVersion)
 	})
 
-	t.Run("should return true when client and target versions are of the same minor and different patch and target version is higher than client", func(t *testing.T) {
+	t.Run("should report blocked with the source versions when the versions are not compatible", func(t *testing.T) {
 		// given
-		sameMinorClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.1.1",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		performer := newReconcilePerformer(actions.IstioStatus{ClientVersion: "1.4.0", TargetVersion: "1.4.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}})
+		action := NewIstioMainReconcileAction(performerCreatorFn(performer))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(sameMinorClientVersion)
+		err := action.Run(newFakeRenderingActionContext())
 
 		// then
-		require.True(t, got)
+		require.Error(t, err)
+		status := action.LastStatus()
+		require.Equal(t, ReconcileOutcomeBlocked, status.Outcome)
+		require.Equal(t, []string{"1.1.0"}, status.SourcePilotVersions)
+		require.Equal(t, []string{"1.1.0"}, status.SourceDataPlaneVersions)
+		require.Equal(t, "1.4.0", status.TargetVersion)
 	})
 
-	t.Run("should return true when client and target versions are among one minor and of the same patch and client version is higher than target", func(t *testing.T) {
+	t.Run("should report skipped and keep the error when the versions could not be resolved", func(t *testing.T) {
 		// given
-		oneHigherMinorClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.1.0",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(actions.IstioStatus{}, errors.New("Version error"))
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(oneHigherMinorClientVersion)
+		err := action.Run(newFakeRenderingActionContext())
 
 		// then
-		require.True(t, got)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Version error")
+		require.Equal(t, ReconcileOutcomeSkipped, action.LastStatus().Outcome)
+		require.Empty(t, action.LastStatus().TargetVersion)
 	})
+}
 
-	t.Run("should return true when client and target versions are among one minor and of the same patch and target version is higher than client", func(t *testing.T) {
+func Test_ProxyResetPostAction_CheckProxyResetCompletion(t *testing.T) {
+	istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", TargetPrefix: "istio", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+	remainingPods := v1.PodList{Items: []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns2"}},
+	}}
+
+	newPerformer := func(remaining v1.PodList, err error) *actionsmocks.IstioPerformer {
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(istioStatus, nil)
+		performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{}, nil)
+		performer.On("CheckProxyResetCompletion", mock.Anything, mock.Anything, "1.2.0", "istio", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(remaining, err)
+		return &performer
+	}
+
+	t.Run("should succeed when all proxies converged to the target version", func(t *testing.T) {
 		// given
-		oneLowerMinorClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.1.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		core, logs := observer.New(zap.WarnLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		performer := newPerformer(v1.PodList{}, nil)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(oneLowerMinorClientVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, got)
+		require.NoError(t, err)
+		require.Empty(t, logs.FilterMessageSnippet("after the proxy reset").All())
+		performer.AssertCalled(t, "CheckProxyResetCompletion", mock.Anything, mock.Anything, "1.2.0", "istio", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should return false when client and target versions are not among one minor and target version is higher than client", func(t *testing.T) {
+	t.Run("should only warn when proxies did not converge in silent mode", func(t *testing.T) {
 		// given
-		twoLowerMinorClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.0.0",
-			TargetVersion:     "1.2.0",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		core, logs := observer.New(zap.WarnLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		action := NewProxyResetPostAction(performerCreatorFn(newPerformer(remainingPods, nil)))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(twoLowerMinorClientVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, got)
+		require.NoError(t, err)
+		warnings := logs.FilterMessage("2 pods still run an istio proxy image other than version 1.2.0 after the proxy reset: ns1/pod1, ns2/pod2").All()
+		require.Len(t, warnings, 1)
 	})
 
-	t.Run("should return false when client and target versions are not among one minor and client version is higher than target", func(t *testing.T) {
+	t.Run("should fail when proxies did not converge and failOnError is enabled", func(t *testing.T) {
 		// given
-		greaterThanOneMinorClientVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.0",
-			TargetVersion:     "1.0.0",
-			PilotVersion:      "",
-			DataPlaneVersions: map[string]bool{},
-		}
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.failOnError": true}
+		action := NewProxyResetPostAction(performerCreatorFn(newPerformer(remainingPods, nil)))
 
 		// when
-		got := isClientCompatibleWithTargetVersion(greaterThanOneMinorClientVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, got)
+		require.EqualError(t, err, "2 pods still run an istio proxy image other than version 1.2.0 after the proxy reset: ns1/pod1, ns2/pod2")
 	})
-}
-
-func Test_isComponentCompatible(t *testing.T) {
-	componentName := "component"
 
-	t.Run("should return false when version string is semver incompatible", func(t *testing.T) {
+	t.Run("should only warn when the completion could not be verified in silent mode", func(t *testing.T) {
 		// given
-		badVersions := actions.IstioStatus{
-			ClientVersion:     "version1",
-			PilotVersion:      "version2",
-			DataPlaneVersions: map[string]bool{"version3": true},
-		}
+		core, logs := observer.New(zap.WarnLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		action := NewProxyResetPostAction(performerCreatorFn(newPerformer(v1.PodList{}, errors.New("list error"))))
 
 		// when
-		got, err := isComponentCompatible(badVersions.PilotVersion, badVersions.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
-		require.Error(t, err)
-		require.False(t, got)
+		require.NoError(t, err)
+		require.Len(t, logs.FilterMessageSnippet("Could not verify the completion of the proxy reset").All(), 1)
 	})
 
-	t.Run("should return true when pilot and target versions are equal", func(t *testing.T) {
+	t.Run("should fail when the completion could not be verified and failOnError is enabled", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.3",
-			TargetVersion:     "1.2.3",
-			PilotVersion:      "1.2.3",
-			DataPlaneVersions: map[string]bool{"1.2.3": true},
-		}
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.failOnError": true}
+		action := NewProxyResetPostAction(performerCreatorFn(newPerformer(v1.PodList{}, errors.New("list error"))))
 
 		// when
-		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
-		require.NoError(t, err)
-		require.True(t, got)
+		require.EqualError(t, err, "Could not verify the completion of the proxy reset: list error")
 	})
+}
 
-	t.Run("should return true when pilot and targets version are vary only in patch", func(t *testing.T) {
+func Test_ProxyResetPostAction_ResetSummary(t *testing.T) {
+	istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", TargetPrefix: "istio", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+	t.Run("should log the summary of a successful proxy reset", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.3",
-			TargetVersion:     "1.2.3",
-			PilotVersion:      "1.2.0",
-			DataPlaneVersions: map[string]bool{"1.2.3": true},
-		}
+		core, logs := observer.New(zap.InfoLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(istioStatus, nil)
+		performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 3}, nil)
+		performer.On("CheckProxyResetCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
 		require.NoError(t, err)
-		require.True(t, got)
+		summaries := logs.FilterMessage("Istio proxy reset summary").All()
+		require.Len(t, summaries, 1)
+		require.Equal(t, map[string]interface{}{"podsConsidered": int64(3), "podsRestarted": int64(3), "podsFailed": int64(0), "podsSkipped": int64(0)}, summaries[0].ContextMap())
 	})
 
-	t.Run("should return true when pilot version is one minor lower than target", func(t *testing.T) {
+	t.Run("should log the skipped pods in the summary", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.3",
-			TargetVersion:     "1.2.3",
-			PilotVersion:      "1.1.0",
-			DataPlaneVersions: map[string]bool{"1.2.3": true},
-		}
+		core, logs := observer.New(zap.InfoLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(istioStatus, nil)
+		performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 1, PodsSkipped: 2}, nil)
+		performer.On("CheckProxyResetCompletion", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
 		require.NoError(t, err)
-		require.True(t, got)
+		summaries := logs.FilterMessage("Istio proxy reset summary").All()
+		require.Len(t, summaries, 1)
+		require.Equal(t, map[string]interface{}{"podsConsidered": int64(3), "podsRestarted": int64(1), "podsFailed": int64(0), "podsSkipped": int64(2)}, summaries[0].ContextMap())
 	})
 
-	t.Run("should return true when pilot version is one minor higher than target", func(t *testing.T) {
+	t.Run("should log the summary of a partially failed proxy reset", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.3",
-			TargetVersion:     "1.2.3",
-			PilotVersion:      "1.3.0",
-			DataPlaneVersions: map[string]bool{"1.2.3": true},
-		}
+		core, logs := observer.New(zap.InfoLevel)
+		actionContext := newFakeActionContext()
+		actionContext.Logger = zap.New(core).Sugar()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(istioStatus, nil)
+		performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 2, PodsFailed: 1}, errors.New("reset error"))
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
 		require.NoError(t, err)
-		require.True(t, got)
+		summaries := logs.FilterMessage("Istio proxy reset summary").All()
+		require.Len(t, summaries, 1)
+		require.Equal(t, map[string]interface{}{"podsConsidered": int64(3), "podsRestarted": int64(2), "podsFailed": int64(1), "podsSkipped": int64(0)}, summaries[0].ContextMap())
 	})
+}
 
-	t.Run("should return false when pilot version is more than one minor lower than target", func(t *testing.T) {
+func Test_ProxyResetPostAction_FailOnError(t *testing.T) {
+	pilotBehindTarget := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+	pilotOnTarget := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+	pilotNotInstalled := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+	t.Run("should only warn when proxies can not be reset in silent mode", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.3",
-			TargetVersion:     "1.2.3",
-			PilotVersion:      "1.0.0",
-			DataPlaneVersions: map[string]bool{"1.2.3": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pilotBehindTarget, nil)
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
-		require.Error(t, err)
-		require.False(t, got)
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should return false when pilot version is more than one minor higher than target", func(t *testing.T) {
+	t.Run("should only warn when proxy reset fails in silent mode", func(t *testing.T) {
 		// given
-		istioVersion := actions.IstioStatus{
-			ClientVersion:     "1.2.3",
-			TargetVersion:     "1.2.3",
-			PilotVersion:      "1.4.0",
-			DataPlaneVersions: map[string]bool{"1.2.3": true},
-		}
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pilotOnTarget, nil)
+		performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{}, errors.New("reset error"))
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got, err := isComponentCompatible(istioVersion.PilotVersion, istioVersion.TargetVersion, componentName)
+		err := action.Run(actionContext)
 
 		// then
-		require.Error(t, err)
-		require.False(t, got)
+		require.NoError(t, err)
 	})
-}
 
-func Test_amongOneMinor(t *testing.T) {
-	t.Run("Downgrade of PilotVersion with same minor version is permitted", func(t *testing.T) {
+	t.Run("should return error when proxies can not be reset in strict mode", func(t *testing.T) {
 		// given
-		sameMinorPilotVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.11.6",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
-		pilotHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.PilotVersion)
-		require.NoError(t, err)
-		targetHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.TargetVersion)
-		require.NoError(t, err)
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.failOnError": "true"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pilotBehindTarget, nil)
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, got)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Can not perform ResetProxy action")
+		performer.AssertNotCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("Upgrade of PilotVersion with same minor version is permitted", func(t *testing.T) {
+	t.Run("should return error when the target proxy is not compatible with the pilot in strict mode", func(t *testing.T) {
 		// given
-		sameMinorPilotVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.11.1",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
-		pilotHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.PilotVersion)
-		require.NoError(t, err)
-		targetHelperVersion, err := newHelperVersionFrom(sameMinorPilotVersion.TargetVersion)
-		require.NoError(t, err)
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.failOnError": "true"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pilotNotInstalled, nil)
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, got)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Can not perform ResetProxy action: Istio pilot is not installed")
+		performer.AssertNotCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("Downgrade of PilotVersion with one minor version is permitted", func(t *testing.T) {
+	t.Run("should return error when proxy reset fails in strict mode", func(t *testing.T) {
 		// given
-		oneMinorPilotVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.12.6",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
-		pilotHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.PilotVersion)
-		require.NoError(t, err)
-		targetHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.TargetVersion)
-		require.NoError(t, err)
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.failOnError": "true"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(pilotOnTarget, nil)
+		performer.On("ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(proxy.ResetSummary{}, errors.New("reset error"))
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
 
 		// when
-		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, got)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ResetProxy action failed: reset error")
 	})
+}
 
-	t.Run("Upgrade of PilotVersion with one minor version is permitted", func(t *testing.T) {
+func Test_ProxyResetPostAction_ProxyVersionSource(t *testing.T) {
+	istioStatus := actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", TargetPrefix: "istio", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}
+
+	t.Run("should reset proxies to the target version of the chart by default", func(t *testing.T) {
 		// given
-		oneMinorPilotVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.10.1",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
-		pilotHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.PilotVersion)
-		require.NoError(t, err)
-		targetHelperVersion, err := newHelperVersionFrom(oneMinorPilotVersion.TargetVersion)
-		require.NoError(t, err)
+		actionContext := newFakeActionContext()
+		performer := newProxyResetPerformer(istioStatus)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.True(t, got)
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "GetProxyImageVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "1.2.0", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		performer.AssertCalled(t, "CheckProxyResetCompletion", mock.Anything, mock.Anything, "1.2.0", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("Downgrade of PilotVersion with more than one minor version is NOT permitted", func(t *testing.T) {
+	t.Run("should reset proxies to the version configured in istiod when enabled", func(t *testing.T) {
 		// given
-		greaterThanOneMinorPilotVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.13.6",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
-		pilotHelperVersion, err := newHelperVersionFrom(greaterThanOneMinorPilotVersion.PilotVersion)
-		require.NoError(t, err)
-		targetHelperVersion, err := newHelperVersionFrom(greaterThanOneMinorPilotVersion.TargetVersion)
-		require.NoError(t, err)
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.versionFromIstiod": true, "istio.revision": "canary"}
+		performer := newProxyResetPerformer(istioStatus)
+		performer.On("GetProxyImageVersion", mock.Anything, mock.Anything, "canary", mock.Anything).Return("1.1.5", nil)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, got)
+		require.NoError(t, err)
+		performer.AssertCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "1.1.5", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		performer.AssertCalled(t, "CheckProxyResetCompletion", mock.Anything, mock.Anything, "1.1.5", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("Upgrade of PilotVersion with more than one minor version is NOT permitted", func(t *testing.T) {
+	t.Run("should not reset proxies when the version could not be derived from istiod", func(t *testing.T) {
 		// given
-		lesserThanOneMinorPilotVersion := actions.IstioStatus{
-			ClientVersion:     "1.11.2",
-			TargetVersion:     "1.11.2",
-			PilotVersion:      "1.9.1",
-			DataPlaneVersions: map[string]bool{"1.11.2": true},
-		}
-		pilotHelperVersion, err := newHelperVersionFrom(lesserThanOneMinorPilotVersion.PilotVersion)
-		require.NoError(t, err)
-		targetHelperVersion, err := newHelperVersionFrom(lesserThanOneMinorPilotVersion.TargetVersion)
-		require.NoError(t, err)
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.versionFromIstiod": true}
+		performer := newProxyResetPerformer(istioStatus)
+		performer.On("GetProxyImageVersion", mock.Anything, mock.Anything, "", mock.Anything).Return("", errors.New("invalid version"))
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		got := amongOneMinor(pilotHelperVersion, targetHelperVersion)
+		err := action.Run(actionContext)
 
 		// then
-		require.False(t, got)
+		require.NoError(t, err)
+		performer.AssertNotCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
-}
 
-func Test_generateNewManifestWithoutIstioOperatorFrom(t *testing.T) {
+	t.Run("should not reset proxies when the version configured in istiod is not compatible with the pilot", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.versionFromIstiod": true}
+		performer := newProxyResetPerformer(istioStatus)
+		performer.On("GetProxyImageVersion", mock.Anything, mock.Anything, "", mock.Anything).Return("1.0.0", nil)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
-	t.Run("should generate empty manifest from empty input manifest", func(t *testing.T) {
 		// when
-		result, err := manifest.GenerateNewManifestWithoutIstioOperatorFrom("")
+		err := action.Run(actionContext)
 
 		// then
 		require.NoError(t, err)
-		require.Empty(t, result)
+		performer.AssertNotCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should return manifest without IstioOperator kind if it was not present ", func(t *testing.T) {
+	t.Run("should fail when the version configured in istiod is not compatible with the pilot and failOnError is enabled", func(t *testing.T) {
 		// given
-		require.Contains(t, istioManifestWithoutIstioOperator, "Kind1")
-		require.Contains(t, istioManifestWithoutIstioOperator, "Kind2")
-		require.NotContains(t, istioManifestWithoutIstioOperator, "IstioOperator")
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.versionFromIstiod": true, "istio.proxyReset.failOnError": true}
+		performer := newProxyResetPerformer(istioStatus)
+		performer.On("GetProxyImageVersion", mock.Anything, mock.Anything, "", mock.Anything).Return("1.0.0", nil)
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		result, err := manifest.GenerateNewManifestWithoutIstioOperatorFrom(istioManifestWithoutIstioOperator)
+		err := action.Run(actionContext)
 
 		// then
-		require.NoError(t, err)
-		require.Contains(t, result, "Kind1")
-		require.Contains(t, result, "Kind2")
-		require.NotContains(t, result, "IstioOperator")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Target proxy image istio:1.0.0 is not compatible with Istio pilot version 1.2.0")
+		performer.AssertNotCalled(t, "ResetProxy", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("should return manifest without IstioOperator kind if it was present", func(t *testing.T) {
+	t.Run("should fail when the version could not be derived from istiod and failOnError is enabled", func(t *testing.T) {
 		// given
-		require.Contains(t, istioManifest, "Kind1")
-		require.Contains(t, istioManifest, "Kind2")
-		require.Contains(t, istioManifest, "IstioOperator")
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.proxyReset.versionFromIstiod": true, "istio.proxyReset.failOnError": true}
+		performer := newProxyResetPerformer(istioStatus)
+		performer.On("GetProxyImageVersion", mock.Anything, mock.Anything, "", mock.Anything).Return("", errors.New("invalid version"))
+		action := NewProxyResetPostAction(performerCreatorFn(performer))
 
 		// when
-		result, err := manifest.GenerateNewManifestWithoutIstioOperatorFrom(istioManifest)
+		err := action.Run(actionContext)
 
 		// then
-		require.NoError(t, err)
-		require.Contains(t, result, "Kind1")
-		require.Contains(t, result, "Kind2")
-		require.NotContains(t, result, "IstioOperator")
+		require.EqualError(t, err, "Could not get istio proxy image version from istiod: invalid version")
 	})
-
 }
@@ -0,0 +1,48 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	actionTimeoutConfigKey = "istio.actionTimeout"
+	defaultActionTimeout   = 20 * time.Minute
+)
+
+// actionTimeout returns the deadline of the reconcile action configured for the task, falling back to the default one.
+func actionTimeout(task *reconciler.Task, logger *zap.SugaredLogger) time.Duration {
+	value, ok := task.Configuration[actionTimeoutConfigKey]
+	if !ok {
+		return defaultActionTimeout
+	}
+	timeout, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil || timeout <= 0 {
+		logger.Warnf("Invalid %s value %v, using default %s", actionTimeoutConfigKey, value, defaultActionTimeout)
+		return defaultActionTimeout
+	}
+	return timeout
+}
+
+// withActionTimeout returns a copy of the action context whose context is cancelled after timeout.
+func withActionTimeout(actionContext *service.ActionContext, timeout time.Duration) (*service.ActionContext, context.CancelFunc) {
+	timeoutContext := *actionContext
+	ctx, cancel := context.WithTimeout(actionContext.Context, timeout)
+	timeoutContext.Context = ctx
+	return &timeoutContext, cancel
+}
+
+// deadlineExceededError returns a deadline exceeded error if the action failed after the context of the action ran out of time,
+// otherwise err is returned unchanged.
+func deadlineExceededError(actionContext *service.ActionContext, timeout time.Duration, err error) error {
+	if err == nil || !errors.Is(actionContext.Context.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return errors.Wrapf(context.DeadlineExceeded, "Istio reconcile action did not complete within %s, last error: %v", timeout, err)
+}
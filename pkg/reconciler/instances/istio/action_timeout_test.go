@@ -0,0 +1,111 @@
+package istio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	actionsmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_actionTimeout(t *testing.T) {
+	logger := log.NewLogger(true)
+
+	t.Run("should return default timeout when it is not configured", func(t *testing.T) {
+		// when
+		timeout := actionTimeout(&reconciler.Task{}, logger)
+
+		// then
+		require.Equal(t, defaultActionTimeout, timeout)
+	})
+
+	t.Run("should return configured timeout", func(t *testing.T) {
+		// when
+		timeout := actionTimeout(&reconciler.Task{Configuration: map[string]interface{}{"istio.actionTimeout": "5m"}}, logger)
+
+		// then
+		require.Equal(t, 5*time.Minute, timeout)
+	})
+
+	t.Run("should return default timeout when the configured one is invalid", func(t *testing.T) {
+		// when
+		timeout := actionTimeout(&reconciler.Task{Configuration: map[string]interface{}{"istio.actionTimeout": "-1s"}}, logger)
+
+		// then
+		require.Equal(t, defaultActionTimeout, timeout)
+	})
+}
+
+func Test_MainReconcileAction_Timeout(t *testing.T) {
+	noIstioOnTheCluster := actions.IstioStatus{ClientVersion: "1.0", TargetVersion: "1.0", DataPlaneVersions: map[string]bool{}}
+
+	t.Run("should install Istio with the context of the action carrying the deadline", func(t *testing.T) {
+		// given
+		actionContext := newFakeRenderingActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.actionTimeout": "1m"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		var deadline time.Time
+		var hasDeadline bool
+		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil).
+			Run(func(args mock.Arguments) { deadline, hasDeadline = args.Get(0).(context.Context).Deadline() })
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.True(t, hasDeadline)
+		require.WithinDuration(t, time.Now().Add(time.Minute), deadline, 10*time.Second)
+	})
+
+	t.Run("should return deadline exceeded error when the action does not complete in time", func(t *testing.T) {
+		// given
+		actionContext := newFakeRenderingActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.actionTimeout": "10ms"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(func(ctx context.Context, kubeConfig, istioChart, version, revision string, logger *zap.SugaredLogger) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Contains(t, err.Error(), "Istio reconcile action did not complete within 10ms")
+	})
+
+	t.Run("should not change the error when the action fails within the deadline", func(t *testing.T) {
+		// given
+		actionContext := newFakeRenderingActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		performer.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("Istio Install error"))
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.False(t, errors.Is(err, context.DeadlineExceeded))
+		require.Contains(t, err.Error(), "Istio Install error")
+	})
+}
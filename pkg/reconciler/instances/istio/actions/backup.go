@@ -0,0 +1,110 @@
+package actions
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	backupLabel           = "reconciler.kyma-project.io/istio-operator-backup"
+	backupTimestampLabel  = "reconciler.kyma-project.io/backup-timestamp"
+	backupNamePrefix      = "istio-operator-backup-"
+	backupDataKey         = "istio-operator"
+	backupTimestampFormat = "20060102-150405"
+)
+
+// backupIstioOperator stores the IstioOperator installed on the cluster in a timestamped ConfigMap and prunes the backups
+// exceeding the configured retention. Nothing is stored if there is no IstioOperator on the cluster.
+func (c *DefaultIstioPerformer) backupIstioOperator(context context.Context, kubeConfig string, logger *zap.SugaredLogger) error {
+	operatorYaml, err := c.GetIstioOperator(context, kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+	if operatorYaml == "" {
+		logger.Debug("No Istio Operator found on the cluster, skipping backup")
+		return nil
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return err
+	}
+
+	err = writeIstioOperatorBackup(context, kubeClient, c.namespace, operatorYaml, time.Now(), logger)
+	if err != nil {
+		return err
+	}
+
+	return pruneIstioOperatorBackups(context, kubeClient, c.namespace, c.backupRetention, logger)
+}
+
+// GetIstioOperator returns the YAML of the IstioOperator installed on the cluster or an empty string if there is none.
+func (c *DefaultIstioPerformer) GetIstioOperator(context context.Context, kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	operator, err := c.getCurrentOperator(context, kubeConfig)
+	if err != nil || operator == nil {
+		return "", err
+	}
+
+	operatorYaml, err := yaml.Marshal(operator.Object)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not marshal Istio Operator")
+	}
+
+	return string(operatorYaml), nil
+}
+
+func writeIstioOperatorBackup(context context.Context, kubeClient kubernetes.Interface, namespace, operatorYaml string, timestamp time.Time, logger *zap.SugaredLogger) error {
+	formattedTimestamp := timestamp.UTC().Format(backupTimestampFormat)
+	backup := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupNamePrefix + formattedTimestamp,
+			Namespace: namespace,
+			Labels: map[string]string{
+				backupLabel:          "true",
+				backupTimestampLabel: formattedTimestamp,
+			},
+		},
+		Data: map[string]string{backupDataKey: operatorYaml},
+	}
+
+	_, err := kubeClient.CoreV1().ConfigMaps(namespace).Create(context, backup, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Could not create Istio Operator backup")
+	}
+	logger.Infof("Istio Operator backed up in ConfigMap %s/%s", namespace, backup.Name)
+
+	return nil
+}
+
+// pruneIstioOperatorBackups deletes the oldest Istio Operator backups so that at most retention backups remain.
+func pruneIstioOperatorBackups(context context.Context, kubeClient kubernetes.Interface, namespace string, retention int, logger *zap.SugaredLogger) error {
+	backups, err := kubeClient.CoreV1().ConfigMaps(namespace).List(context, metav1.ListOptions{LabelSelector: backupLabel + "=true"})
+	if err != nil {
+		return errors.Wrap(err, "Could not list Istio Operator backups")
+	}
+	if len(backups.Items) <= retention {
+		return nil
+	}
+
+	sort.Slice(backups.Items, func(i, j int) bool {
+		return backups.Items[i].Labels[backupTimestampLabel] > backups.Items[j].Labels[backupTimestampLabel]
+	})
+	for _, backup := range backups.Items[retention:] {
+		err = kubeClient.CoreV1().ConfigMaps(namespace).Delete(context, backup.Name, metav1.DeleteOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "Could not delete Istio Operator backup %s", backup.Name)
+		}
+		logger.Debugf("Pruned Istio Operator backup %s/%s", namespace, backup.Name)
+	}
+
+	return nil
+}
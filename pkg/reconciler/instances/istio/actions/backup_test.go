@@ -0,0 +1,174 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	istioOperator "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_pruneIstioOperatorBackups(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	start := time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should keep exactly the configured number of most recent backups", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		for i := 0; i < 5; i++ {
+			err := writeIstioOperatorBackup(context.TODO(), kubeClient, istioNamespace, "operator", start.Add(time.Duration(i)*time.Hour), log)
+			require.NoError(t, err)
+		}
+
+		// when
+		err := pruneIstioOperatorBackups(context.TODO(), kubeClient, istioNamespace, 2, log)
+
+		// then
+		require.NoError(t, err)
+		backups, err := kubeClient.CoreV1().ConfigMaps("istio-system").List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, backups.Items, 2)
+		var names []string
+		for _, backup := range backups.Items {
+			names = append(names, backup.Name)
+		}
+		require.ElementsMatch(t, []string{"istio-operator-backup-20221101-140000", "istio-operator-backup-20221101-130000"}, names)
+	})
+
+	t.Run("should not prune anything when there are less backups than retention", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		err := writeIstioOperatorBackup(context.TODO(), kubeClient, istioNamespace, "operator", start, log)
+		require.NoError(t, err)
+
+		// when
+		err = pruneIstioOperatorBackups(context.TODO(), kubeClient, istioNamespace, 3, log)
+
+		// then
+		require.NoError(t, err)
+		backups, err := kubeClient.CoreV1().ConfigMaps("istio-system").List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, backups.Items, 1)
+	})
+}
+
+func Test_DefaultIstioPerformer_backupIstioOperator(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	newProvider := func(t *testing.T, kubeClient *fake.Clientset, objects ...runtime.Object) *clientsetmocks.Provider {
+		dynamicScheme := runtime.NewScheme()
+		err := istioOperator.SchemeBuilder.AddToScheme(dynamicScheme)
+		require.NoError(t, err)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicfake.NewSimpleDynamicClient(dynamicScheme, objects...), nil)
+		return &provider
+	}
+
+	t.Run("should store labeled backup of the installed Istio Operator", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		iop := istioOperator.IstioOperator{ObjectMeta: metav1.ObjectMeta{Name: "installed-state-default-operator", Namespace: "istio-system"}}
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t, kubeClient, &iop), &datamocks.Gatherer{}).
+			WithIstioOperatorBackup(1)
+
+		// when
+		err := wrapper.backupIstioOperator(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		backups, err := kubeClient.CoreV1().ConfigMaps("istio-system").List(context.TODO(), metav1.ListOptions{LabelSelector: "reconciler.kyma-project.io/istio-operator-backup=true"})
+		require.NoError(t, err)
+		require.Len(t, backups.Items, 1)
+		require.NotEmpty(t, backups.Items[0].Labels["reconciler.kyma-project.io/backup-timestamp"])
+		require.Contains(t, backups.Items[0].Data["istio-operator"], "name: installed-state-default-operator")
+	})
+
+	t.Run("should store the backup in the configured namespace", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		iop := istioOperator.IstioOperator{ObjectMeta: metav1.ObjectMeta{Name: "installed-state-default-operator", Namespace: "custom-istio"}}
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t, kubeClient, &iop), &datamocks.Gatherer{}).
+			WithIstioOperatorBackup(1).
+			WithNamespace("custom-istio")
+
+		// when
+		err := wrapper.backupIstioOperator(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		backups, err := kubeClient.CoreV1().ConfigMaps("custom-istio").List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, backups.Items, 1)
+	})
+
+	t.Run("should skip backup when there is no Istio Operator on the cluster", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t, kubeClient), &datamocks.Gatherer{}).
+			WithIstioOperatorBackup(1)
+
+		// when
+		err := wrapper.backupIstioOperator(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		backups, err := kubeClient.CoreV1().ConfigMaps("istio-system").List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Empty(t, backups.Items)
+	})
+}
+
+func Test_DefaultIstioPerformer_GetIstioOperator(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	newProvider := func(t *testing.T, objects ...runtime.Object) *clientsetmocks.Provider {
+		dynamicScheme := runtime.NewScheme()
+		err := istioOperator.SchemeBuilder.AddToScheme(dynamicScheme)
+		require.NoError(t, err)
+		provider := clientsetmocks.Provider{}
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicfake.NewSimpleDynamicClient(dynamicScheme, objects...), nil)
+		return &provider
+	}
+
+	t.Run("should return the YAML of the installed Istio Operator", func(t *testing.T) {
+		// given
+		iop := istioOperator.IstioOperator{ObjectMeta: metav1.ObjectMeta{Name: "installed-state-default-operator", Namespace: "istio-system"}}
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t, &iop), &datamocks.Gatherer{})
+
+		// when
+		operatorYaml, err := wrapper.GetIstioOperator(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, operatorYaml, "name: installed-state-default-operator")
+		require.Contains(t, operatorYaml, "namespace: istio-system")
+	})
+
+	t.Run("should return empty YAML when there is no Istio Operator on the cluster", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t), &datamocks.Gatherer{})
+
+		// when
+		operatorYaml, err := wrapper.GetIstioOperator(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, operatorYaml)
+	})
+}
@@ -0,0 +1,91 @@
+package actions
+
+import (
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	helmChart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// istioChartLoader loads the Istio chart of a workspace at most once. It is meant to live for a single performer call, so
+// that several chart values can be read without repeated disk access and helm parsing.
+type istioChartLoader struct {
+	workspace  chart.Factory
+	branch     string
+	istioChart string
+
+	chart     *helmChart.Chart
+	valuesRaw []byte
+	values    *chartValues
+}
+
+func newIstioChartLoader(workspace chart.Factory, branch string, istioChart string) *istioChartLoader {
+	return &istioChartLoader{
+		workspace:  workspace,
+		branch:     branch,
+		istioChart: istioChart,
+	}
+}
+
+// load returns the Istio helm chart, it is only read from the workspace on the first call.
+func (l *istioChartLoader) load() (*helmChart.Chart, error) {
+	if l.chart != nil {
+		return l.chart, nil
+	}
+
+	ws, err := l.workspace.Get(l.branch)
+	if err != nil {
+		return nil, err
+	}
+
+	istioHelmChart, err := loader.Load(filepath.Join(ws.ResourceDir, l.istioChart))
+	if err != nil {
+		return nil, err
+	}
+	l.chart = istioHelmChart
+
+	return l.chart, nil
+}
+
+// rawValues returns the values of the Istio helm chart as JSON.
+func (l *istioChartLoader) rawValues() ([]byte, error) {
+	if l.valuesRaw != nil {
+		return l.valuesRaw, nil
+	}
+
+	istioHelmChart, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+
+	mapAsJSON, err := json.Marshal(istioHelmChart.Values)
+	if err != nil {
+		return nil, err
+	}
+	l.valuesRaw = mapAsJSON
+
+	return l.valuesRaw, nil
+}
+
+// chartValues returns the values of the Istio helm chart parsed into chartValues.
+func (l *istioChartLoader) chartValues() (chartValues, error) {
+	if l.values != nil {
+		return *l.values, nil
+	}
+
+	mapAsJSON, err := l.rawValues()
+	if err != nil {
+		return chartValues{}, err
+	}
+
+	var values chartValues
+	err = json.Unmarshal(mapAsJSON, &values)
+	if err != nil {
+		return chartValues{}, err
+	}
+	l.values = &values
+
+	return values, nil
+}
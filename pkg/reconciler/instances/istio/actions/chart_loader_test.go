@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_istioChartLoader(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should load the chart only once for several chart values", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files/path-tests"}, nil)
+		chartLoader := newIstioChartLoader(factory, "branch", "istio")
+
+		// when
+		targetVersion, versionErr := getTargetVersionFromIstioChart(chartLoader, log)
+		targetPrefix, prefixErr := getTargetProxyV2PrefixFromIstioChart(chartLoader, log)
+		_, injectionErr := isSidecarInjectionNamespacesByDefaultEnabled(chartLoader)
+
+		// then
+		require.NoError(t, versionErr)
+		require.NoError(t, prefixErr)
+		require.NoError(t, injectionErr)
+		require.Equal(t, "1.13.2-distroless", targetVersion)
+		require.Equal(t, "istio-proxy-path/istio-proxy-dir", targetPrefix)
+		factory.AssertNumberOfCalls(t, "Get", 1)
+	})
+
+	t.Run("should retry loading the chart when the workspace could not be retrieved", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(nil, errors.New("workspace error")).Once()
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files/path-tests"}, nil)
+		chartLoader := newIstioChartLoader(factory, "branch", "istio")
+
+		// when
+		_, firstErr := chartLoader.load()
+		loadedChart, secondErr := chartLoader.load()
+
+		// then
+		require.EqualError(t, firstErr, "workspace error")
+		require.NoError(t, secondErr)
+		require.NotNil(t, loadedChart)
+		factory.AssertNumberOfCalls(t, "Get", 2)
+	})
+
+	t.Run("should load the chart only once when labeling namespaces", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(createNamespace("test")), nil)
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", "istio-sidecar-enabled-namespace-labels", "", log)
+
+		// then
+		require.NoError(t, err)
+		factory.AssertNumberOfCalls(t, "Get", 1)
+	})
+}
+
+func Benchmark_istioChartLoader(b *testing.B) {
+	log := logger.NewLogger(false)
+	factory := &workspacemocks.Factory{}
+	factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files/path-tests"}, nil)
+
+	b.Run("shared loader", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			chartLoader := newIstioChartLoader(factory, "branch", "istio")
+			_, _ = getTargetVersionFromIstioChart(chartLoader, log)
+			_, _ = getTargetProxyV2PrefixFromIstioChart(chartLoader, log)
+		}
+	})
+
+	b.Run("loader per lookup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _ = getTargetVersionFromIstioChart(newIstioChartLoader(factory, "branch", "istio"), log)
+			_, _ = getTargetProxyV2PrefixFromIstioChart(newIstioChartLoader(factory, "branch", "istio"), log)
+		}
+	})
+}
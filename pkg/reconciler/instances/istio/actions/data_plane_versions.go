@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodReference identifies a pod running an istio proxy.
+type PodReference struct {
+	Name      string
+	Namespace string
+}
+
+func (c *DefaultIstioPerformer) GetDataPlaneVersionsDetailed(context context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (map[string][]PodReference, error) {
+	targetVersion, err := getTargetVersionFromIstioChart(newIstioChartLoader(workspace, branchVersion, istioChart), logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "Target Version could not be found")
+	}
+
+	version, err := istioctl.VersionFromString(targetVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing version")
+	}
+
+	commander, err := c.resolver.GetCommander(version)
+	if err != nil {
+		return nil, err
+	}
+
+	versionOutput, err := commander.Version(kubeConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	dataPlane, err := mapDataPlaneVersions(versionOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	pods, err := kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list pods")
+	}
+	existingPods := make(map[PodReference]bool, len(pods.Items))
+	for _, pod := range pods.Items {
+		existingPods[PodReference{Name: pod.Name, Namespace: pod.Namespace}] = true
+	}
+
+	versions := map[string][]PodReference{}
+	for _, proxy := range dataPlane {
+		pod, ok := podReferenceFromProxyID(proxy.ID)
+		if !ok {
+			logger.Debugf("Could not determine the pod of istio proxy %s", proxy.ID)
+			continue
+		}
+		if !existingPods[pod] {
+			logger.Debugf("Skipping istio proxy %s as its pod no longer exists", proxy.ID)
+			continue
+		}
+		versions[proxy.IstioVersion] = append(versions[proxy.IstioVersion], pod)
+	}
+
+	for _, refs := range versions {
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].Namespace != refs[j].Namespace {
+				return refs[i].Namespace < refs[j].Namespace
+			}
+			return refs[i].Name < refs[j].Name
+		})
+	}
+
+	return versions, nil
+}
+
+// mapDataPlaneVersions returns the data plane proxies reported by the istioctl version command.
+func mapDataPlaneVersions(versionOutput []byte) ([]*DataPlaneVersion, error) {
+	index := bytes.IndexRune(versionOutput, '{')
+	if index < 0 {
+		return nil, errors.New("the result of the version command does not contain a version")
+	}
+
+	var version IstioVersionOutput
+	err := json.Unmarshal(versionOutput[index:], &version)
+	if err != nil {
+		return nil, err
+	}
+
+	return version.DataPlaneVersion, nil
+}
+
+// podReferenceFromProxyID returns the pod of an istio proxy ID, which istioctl reports as <pod name>.<namespace>.
+func podReferenceFromProxyID(id string) (PodReference, bool) {
+	index := strings.LastIndex(id, ".")
+	if index <= 0 || index == len(id)-1 {
+		return PodReference{}, false
+	}
+	return PodReference{Name: id[:index], Namespace: id[index+1:]}, true
+}
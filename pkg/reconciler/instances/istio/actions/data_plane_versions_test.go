@@ -0,0 +1,157 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const istioctlMockDataPlaneVersions = `{
+	"clientVersion": {
+		"version": "1.11.2"
+	},
+	"meshVersion": [
+		{
+			"Component": "pilot",
+			"Info": {
+				"version": "1.11.2"
+			}
+		}
+	],
+	"dataPlaneVersion": [
+		{
+			"ID": "httpbin-74fb669cc6-2jxgd.default",
+			"IstioVersion": "1.11.1"
+		},
+		{
+			"ID": "istio-ingressgateway-5b8f7c8f47-x2tbq.istio-system",
+			"IstioVersion": "1.11.2"
+		},
+		{
+			"ID": "reviews-v1-545db77b95-6gz7s.bookinfo",
+			"IstioVersion": "1.11.1"
+		},
+		{
+			"ID": "deleted-pod.default",
+			"IstioVersion": "1.11.1"
+		}
+	]
+}`
+
+func Test_DefaultIstioPerformer_GetDataPlaneVersionsDetailed(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	kubeConfig := "kubeConfig"
+	factory := &workspacemocks.Factory{}
+	factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+	fixPod := func(name, namespace string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	t.Run("should return the pods running each data plane version", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockDataPlaneVersions), nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(
+			fixPod("httpbin-74fb669cc6-2jxgd", "default"),
+			fixPod("istio-ingressgateway-5b8f7c8f47-x2tbq", "istio-system"),
+			fixPod("reviews-v1-545db77b95-6gz7s", "bookinfo"),
+		), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		versions, err := wrapper.GetDataPlaneVersionsDetailed(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, map[string][]PodReference{
+			"1.11.1": {
+				{Name: "reviews-v1-545db77b95-6gz7s", Namespace: "bookinfo"},
+				{Name: "httpbin-74fb669cc6-2jxgd", Namespace: "default"},
+			},
+			"1.11.2": {
+				{Name: "istio-ingressgateway-5b8f7c8f47-x2tbq", Namespace: "istio-system"},
+			},
+		}, versions)
+	})
+
+	t.Run("should return no versions when there is no data plane", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockSimpleVersion), nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		versions, err := wrapper.GetDataPlaneVersionsDetailed(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, versions)
+	})
+
+	t.Run("should return error when the version command output is empty", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		versions, err := wrapper.GetDataPlaneVersionsDetailed(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.Nil(t, versions)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain a version")
+	})
+
+	t.Run("should return error when istioctl version command failed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{})
+
+		// when
+		versions, err := wrapper.GetDataPlaneVersionsDetailed(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		require.Nil(t, versions)
+		require.EqualError(t, err, "istioctl error")
+	})
+}
+
+func Test_podReferenceFromProxyID(t *testing.T) {
+
+	t.Run("should split the proxy ID at the last dot", func(t *testing.T) {
+		// when
+		pod, ok := podReferenceFromProxyID("my.pod.default")
+
+		// then
+		require.True(t, ok)
+		require.Equal(t, PodReference{Name: "my.pod", Namespace: "default"}, pod)
+	})
+
+	t.Run("should not return a pod for a proxy ID without namespace", func(t *testing.T) {
+		// when
+		_, ok := podReferenceFromProxyID("id")
+
+		// then
+		require.False(t, ok)
+	})
+}
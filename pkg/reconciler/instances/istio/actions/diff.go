@@ -0,0 +1,107 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	installedIstioOperatorName = "installed-state-default-operator"
+	diffContextLines           = 3
+)
+
+var istioOperatorResource = schema.GroupVersionResource{Group: "install.istio.io", Version: "v1alpha1", Resource: "istiooperators"}
+
+// GetIstioOperatorDiff returns the unified diff between the spec of the IstioOperator installed on the cluster and the
+// spec which would be applied for the given chart. An empty string is returned if there are no differences.
+func (c *DefaultIstioPerformer) GetIstioOperatorDiff(context context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) (string, error) {
+	current, err := c.getCurrentOperatorSpec(context, kubeConfig)
+	if err != nil {
+		return "", err
+	}
+
+	mergedConfig, err := c.computeMergedConfig(context, kubeConfig, istioChart, logger)
+	if err != nil {
+		return "", err
+	}
+	target, err := operatorSpecToYaml([]byte(mergedConfig))
+	if err != nil {
+		return "", errors.Wrap(err, "Could not parse target Istio Operator")
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(current),
+		B:        difflib.SplitLines(target),
+		FromFile: "current",
+		ToFile:   "target",
+		Context:  diffContextLines,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Could not compute Istio Operator diff")
+	}
+
+	return diff, nil
+}
+
+func (c *DefaultIstioPerformer) getCurrentOperatorSpec(context context.Context, kubeConfig string) (string, error) {
+	operator, err := c.getCurrentOperator(context, kubeConfig)
+	if err != nil || operator == nil {
+		return "", err
+	}
+
+	objJSON, err := operator.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	spec, err := operatorSpecToYaml(objJSON)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not parse current Istio Operator")
+	}
+
+	return spec, nil
+}
+
+// getCurrentOperator returns the IstioOperator installed on the cluster or nil if there is none.
+func (c *DefaultIstioPerformer) getCurrentOperator(context context.Context, kubeConfig string) (*unstructured.Unstructured, error) {
+	dynamicClient, err := c.provider.GetDynamicClient(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	operator, err := dynamicClient.Resource(istioOperatorResource).Namespace(c.namespace).Get(context, installedIstioOperatorName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "Could not get current Istio Operator")
+	}
+
+	return operator, nil
+}
+
+func operatorSpecToYaml(operatorManifest []byte) (string, error) {
+	var operator struct {
+		Spec map[string]interface{} `json:"spec"`
+	}
+	if err := yaml.Unmarshal(operatorManifest, &operator); err != nil {
+		return "", err
+	}
+	if len(operator.Spec) == 0 {
+		return "", nil
+	}
+
+	specYaml, err := yaml.Marshal(operator.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	return string(specYaml), nil
+}
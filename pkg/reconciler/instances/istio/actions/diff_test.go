@@ -0,0 +1,115 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/kyma-project/istio/operator/api/v1alpha1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	operatorv1alpha1 "istio.io/api/operator/v1alpha1"
+	istioOperator "istio.io/istio/operator/pkg/apis/istio/v1alpha1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	controllerfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_DefaultIstioPerformer_GetIstioOperatorDiff(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	err := v1alpha1.AddToScheme(scheme.Scheme)
+	require.NoError(t, err)
+	ctrlClient := controllerfake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	newProvider := func(t *testing.T, objects ...runtime.Object) *clientsetmocks.Provider {
+		dynamicScheme := runtime.NewScheme()
+		err := istioOperator.SchemeBuilder.AddToScheme(dynamicScheme)
+		require.NoError(t, err)
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicfake.NewSimpleDynamicClient(dynamicScheme, objects...), nil)
+		return &provider
+	}
+
+	t.Run("should return unified diff of the changed field", func(t *testing.T) {
+		// given
+		iop := istioOperator.IstioOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: "installed-state-default-operator", Namespace: "istio-system"},
+			Spec: &operatorv1alpha1.IstioOperatorSpec{
+				Components: &operatorv1alpha1.IstioComponentSetSpec{
+					Cni: &operatorv1alpha1.ComponentSpec{
+						Enabled: wrapperspb.Bool(true),
+					},
+				},
+			},
+		}
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t, &iop), &datamocks.Gatherer{})
+
+		// when
+		diff, err := wrapper.GetIstioOperatorDiff(context.TODO(), kubeConfig, istioManifestCniDisabled, log)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, diff, "--- current")
+		require.Contains(t, diff, "+++ target")
+		require.Contains(t, diff, "-    enabled: true")
+		require.Contains(t, diff, "+    enabled: false")
+	})
+
+	t.Run("should return empty diff when the cluster Istio Operator matches the target", func(t *testing.T) {
+		// given
+		iop := istioOperator.IstioOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: "installed-state-default-operator", Namespace: "istio-system"},
+			Spec: &operatorv1alpha1.IstioOperatorSpec{
+				Components: &operatorv1alpha1.IstioComponentSetSpec{
+					Cni: &operatorv1alpha1.ComponentSpec{
+						Enabled: wrapperspb.Bool(false),
+					},
+				},
+			},
+		}
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t, &iop), &datamocks.Gatherer{})
+
+		// when
+		diff, err := wrapper.GetIstioOperatorDiff(context.TODO(), kubeConfig, istioManifestCniDisabled, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, diff)
+	})
+
+	t.Run("should show the whole target spec as added when there is no Istio Operator on the cluster", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t), &datamocks.Gatherer{})
+
+		// when
+		diff, err := wrapper.GetIstioOperatorDiff(context.TODO(), kubeConfig, istioManifestCniDisabled, log)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, diff, "+components:")
+		require.Contains(t, diff, "+    enabled: false")
+	})
+
+	t.Run("should return error when istio operator could not be found in manifest", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, newProvider(t), &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetIstioOperatorDiff(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Operator definition could not be found in manifest")
+	})
+}
@@ -0,0 +1,9 @@
+package actions
+
+// IngressGatewayRestartPendingError is returned by Update when Istio was updated successfully but the required restart
+// of the ingress gateway was skipped and has to be done manually.
+type IngressGatewayRestartPendingError struct{}
+
+func (e *IngressGatewayRestartPendingError) Error() string {
+	return "Istio was updated but the ingress gateway restart was skipped, a manual restart is pending"
+}
@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// istioCRDGroups are the API groups of the CRDs owned by Istio.
+var istioCRDGroups = map[string]bool{
+	"install.istio.io":    true,
+	"networking.istio.io": true,
+	"security.istio.io":   true,
+}
+
+// IstioCRD is a CRD owned by Istio together with the number of its custom resources on the cluster.
+type IstioCRD struct {
+	Name            string
+	Group           string
+	Kind            string
+	CustomResources int
+}
+
+// GetIstioCRDs lists the CRDs of the Istio API groups sorted by name and counts the custom resources of each of them in all namespaces.
+func (c *DefaultIstioPerformer) GetIstioCRDs(context context.Context, kubeConfig string, logger *zap.SugaredLogger) ([]IstioCRD, error) {
+	dynamicClient, err := c.provider.GetDynamicClient(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	crds, err := dynamicClient.Resource(crdResource).List(context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list CRDs")
+	}
+
+	var istioCRDs []IstioCRD
+	for _, crd := range crds.Items {
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		if !istioCRDGroups[group] {
+			continue
+		}
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		version := servedCRDVersion(crd)
+		if version == "" {
+			logger.Debugf("Skipping CRD %s as it serves no version", crd.GetName())
+			continue
+		}
+
+		resource := schema.GroupVersionResource{Group: group, Version: version, Resource: plural}
+		customResources, err := dynamicClient.Resource(resource).List(context, metav1.ListOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Could not list custom resources of CRD %s", crd.GetName())
+		}
+
+		istioCRDs = append(istioCRDs, IstioCRD{
+			Name:            crd.GetName(),
+			Group:           group,
+			Kind:            kind,
+			CustomResources: len(customResources.Items),
+		})
+	}
+
+	sort.Slice(istioCRDs, func(i, j int) bool {
+		return istioCRDs[i].Name < istioCRDs[j].Name
+	})
+	return istioCRDs, nil
+}
+
+// servedCRDVersion returns the storage version of the CRD if it is served, otherwise the first served version.
+func servedCRDVersion(crd unstructured.Unstructured) string {
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	served := ""
+	for _, version := range versions {
+		versionMap, ok := version.(map[string]interface{})
+		if !ok || versionMap["served"] != true {
+			continue
+		}
+		name, _ := versionMap["name"].(string)
+		if versionMap["storage"] == true {
+			return name
+		}
+		if served == "" {
+			served = name
+		}
+	}
+	return served
+}
@@ -0,0 +1,144 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func Test_DefaultIstioPerformer_GetIstioCRDs(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	newCRD := func(group, kind, plural string, versions ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apiextensions.k8s.io/v1",
+			"kind":       "CustomResourceDefinition",
+			"metadata":   map[string]interface{}{"name": plural + "." + group},
+			"spec": map[string]interface{}{
+				"group":    group,
+				"names":    map[string]interface{}{"kind": kind, "plural": plural},
+				"versions": versions,
+			},
+		}}
+	}
+	newVersion := func(name string, served, storage bool) interface{} {
+		return map[string]interface{}{"name": name, "served": served, "storage": storage}
+	}
+	newCR := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		}}
+	}
+	listKinds := map[schema.GroupVersionResource]string{
+		crdResource: "CustomResourceDefinitionList",
+		{Group: "networking.istio.io", Version: "v1beta1", Resource: "gateways"}:          "GatewayList",
+		{Group: "networking.istio.io", Version: "v1beta1", Resource: "virtualservices"}:   "VirtualServiceList",
+		{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}: "PeerAuthenticationList",
+		{Group: "install.istio.io", Version: "v1alpha1", Resource: "istiooperators"}:      "IstioOperatorList",
+		{Group: "networking.istio.io", Version: "v1beta1", Resource: "destinationrules"}:  "DestinationRuleList",
+		{Group: "networking.istio.io", Version: "v1beta1", Resource: "sidecars"}:          "SidecarList",
+	}
+	newProvider := func(objects ...runtime.Object) *clientsetmocks.Provider {
+		provider := clientsetmocks.Provider{}
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...), nil)
+		return &provider
+	}
+
+	t.Run("should list the Istio CRDs with the number of their custom resources", func(t *testing.T) {
+		// given
+		provider := newProvider(
+			newCRD("networking.istio.io", "VirtualService", "virtualservices", newVersion("v1beta1", true, true)),
+			newCRD("networking.istio.io", "Gateway", "gateways", newVersion("v1beta1", true, true)),
+			newCRD("security.istio.io", "PeerAuthentication", "peerauthentications", newVersion("v1beta1", true, true)),
+			newCRD("install.istio.io", "IstioOperator", "istiooperators", newVersion("v1alpha1", true, true)),
+			newCR("networking.istio.io/v1beta1", "VirtualService", "team-a", "reviews"),
+			newCR("networking.istio.io/v1beta1", "VirtualService", "team-b", "ratings"),
+			newCR("networking.istio.io/v1beta1", "Gateway", "istio-system", "kyma-gateway"),
+			newCR("install.istio.io/v1alpha1", "IstioOperator", "istio-system", "installed-state-default-operator"),
+		)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, nil)
+
+		// when
+		crds, err := wrapper.GetIstioCRDs(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []IstioCRD{
+			{Name: "gateways.networking.istio.io", Group: "networking.istio.io", Kind: "Gateway", CustomResources: 1},
+			{Name: "istiooperators.install.istio.io", Group: "install.istio.io", Kind: "IstioOperator", CustomResources: 1},
+			{Name: "peerauthentications.security.istio.io", Group: "security.istio.io", Kind: "PeerAuthentication", CustomResources: 0},
+			{Name: "virtualservices.networking.istio.io", Group: "networking.istio.io", Kind: "VirtualService", CustomResources: 2},
+		}, crds)
+	})
+
+	t.Run("should ignore CRDs of other groups", func(t *testing.T) {
+		// given
+		provider := newProvider(
+			newCRD("serving.knative.dev", "Service", "services", newVersion("v1", true, true)),
+			newCRD("telemetry.istio.io", "Telemetry", "telemetries", newVersion("v1alpha1", true, true)),
+			newCRD("networking.istio.io", "Sidecar", "sidecars", newVersion("v1beta1", true, true)),
+		)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, nil)
+
+		// when
+		crds, err := wrapper.GetIstioCRDs(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []IstioCRD{{Name: "sidecars.networking.istio.io", Group: "networking.istio.io", Kind: "Sidecar", CustomResources: 0}}, crds)
+	})
+
+	t.Run("should count the custom resources of the storage version", func(t *testing.T) {
+		// given
+		provider := newProvider(
+			newCRD("networking.istio.io", "DestinationRule", "destinationrules", newVersion("v1alpha3", true, false), newVersion("v1beta1", true, true)),
+			newCR("networking.istio.io/v1beta1", "DestinationRule", "team-a", "reviews"),
+		)
+		wrapper := NewDefaultIstioPerformer(nil, nil, provider, nil)
+
+		// when
+		crds, err := wrapper.GetIstioCRDs(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []IstioCRD{{Name: "destinationrules.networking.istio.io", Group: "networking.istio.io", Kind: "DestinationRule", CustomResources: 1}}, crds)
+	})
+
+	t.Run("should return no CRDs when Istio CRDs are not installed", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(nil, nil, newProvider(), nil)
+
+		// when
+		crds, err := wrapper.GetIstioCRDs(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, crds)
+	})
+
+	t.Run("should return an error when the dynamic client could not be created", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(nil, errors.New("dynamic client error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, nil)
+
+		// when
+		_, err := wrapper.GetIstioCRDs(context.TODO(), kubeConfig, log)
+
+		// then
+		require.EqualError(t, err, "dynamic client error")
+	})
+}
@@ -0,0 +1,158 @@
+package actions
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/manifest"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	istiodContainerName                     = "discovery"
+	defaultIstiodLogOutputLevel             = "default:info"
+	defaultIstiodClusterDomain              = "cluster.local"
+	defaultIstiodKeepaliveMaxServerConnTime = "30m"
+)
+
+// IstiodArgDifference describes a single istiod argument which differs between the chart and the live istiod deployment.
+// An empty Desired or Actual value means the argument is not present on the respective side.
+type IstiodArgDifference struct {
+	Arg     string
+	Desired string
+	Actual  string
+}
+
+func (c *DefaultIstioPerformer) GetIstiodArgsDrift(context context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) ([]IstiodArgDifference, error) {
+	desiredArgs, err := getDesiredIstiodArgs(istioChart)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context, istiodDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not get istiod deployment")
+	}
+
+	var actualArgs []string
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == istiodContainerName {
+			actualArgs = container.Args
+		}
+	}
+
+	differences := diffIstiodArgs(parseIstiodArgs(desiredArgs), parseIstiodArgs(actualArgs))
+	logger.Debugf("Found %d differences between desired and actual istiod args", len(differences))
+
+	return differences, nil
+}
+
+// getDesiredIstiodArgs renders the istiod container args the same way the istio-discovery chart does, using the values
+// of the IstioOperator in istioChart and the chart defaults for values which are not set.
+func getDesiredIstiodArgs(istioChart string) ([]string, error) {
+	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	if err != nil {
+		return nil, err
+	}
+
+	var istioOperator struct {
+		Spec struct {
+			Values struct {
+				Global struct {
+					Logging struct {
+						Level string `json:"level"`
+					} `json:"logging"`
+					Proxy struct {
+						ClusterDomain string `json:"clusterDomain"`
+					} `json:"proxy"`
+				} `json:"global"`
+				Pilot struct {
+					KeepaliveMaxServerConnectionAge string `json:"keepaliveMaxServerConnectionAge"`
+				} `json:"pilot"`
+			} `json:"values"`
+		} `json:"spec"`
+	}
+	err = yaml.Unmarshal([]byte(istioOperatorManifest), &istioOperator)
+	if err != nil {
+		return nil, err
+	}
+	values := istioOperator.Spec.Values
+
+	return []string{
+		"discovery",
+		"--monitoringAddr=:15014",
+		"--log_output_level=" + valueOrDefault(values.Global.Logging.Level, defaultIstiodLogOutputLevel),
+		"--domain", valueOrDefault(values.Global.Proxy.ClusterDomain, defaultIstiodClusterDomain),
+		"--keepaliveMaxServerConnectionAge", valueOrDefault(values.Pilot.KeepaliveMaxServerConnectionAge, defaultIstiodKeepaliveMaxServerConnTime),
+	}, nil
+}
+
+// parseIstiodArgs maps every argument to its value, supporting both `--flag=value` and `--flag value` notations.
+// Positional arguments and flags without a value are mapped to an empty value.
+func parseIstiodArgs(args []string) map[string]string {
+	parsed := make(map[string]string, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			parsed[arg] = ""
+			continue
+		}
+		if flag, value, found := strings.Cut(arg, "="); found {
+			parsed[flag] = value
+			continue
+		}
+		if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			parsed[arg] = args[i+1]
+			i++
+			continue
+		}
+		parsed[arg] = ""
+	}
+	return parsed
+}
+
+func diffIstiodArgs(desired, actual map[string]string) []IstiodArgDifference {
+	var differences []IstiodArgDifference
+	for arg, desiredValue := range desired {
+		actualValue, ok := actual[arg]
+		if !ok {
+			differences = append(differences, IstiodArgDifference{Arg: arg, Desired: argString(arg, desiredValue)})
+		} else if actualValue != desiredValue {
+			differences = append(differences, IstiodArgDifference{Arg: arg, Desired: argString(arg, desiredValue), Actual: argString(arg, actualValue)})
+		}
+	}
+	for arg, actualValue := range actual {
+		if _, ok := desired[arg]; !ok {
+			differences = append(differences, IstiodArgDifference{Arg: arg, Actual: argString(arg, actualValue)})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Arg < differences[j].Arg
+	})
+	return differences
+}
+
+func argString(arg, value string) string {
+	if value == "" {
+		return arg
+	}
+	return arg + "=" + value
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const istioManifestWithIstiodValues = `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: installed-state-default-operator
+spec:
+  values:
+    global:
+      logging:
+        level: all:warn
+`
+
+func Test_parseIstiodArgs(t *testing.T) {
+
+	t.Run("should parse positional args and flags in both notations", func(t *testing.T) {
+		// when
+		parsed := parseIstiodArgs([]string{"discovery", "--monitoringAddr=:15014", "--domain", "cluster.local", "--verbose"})
+
+		// then
+		require.Equal(t, map[string]string{
+			"discovery":        "",
+			"--monitoringAddr": ":15014",
+			"--domain":         "cluster.local",
+			"--verbose":        "",
+		}, parsed)
+	})
+}
+
+func Test_DefaultIstioPerformer_GetIstiodArgsDrift(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	newIstiod := func(args ...string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"},
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "discovery", Args: args}},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("should not report differences when istiod runs with the desired args", func(t *testing.T) {
+		// given
+		istiod := newIstiod("discovery", "--monitoringAddr=:15014", "--log_output_level=all:warn", "--domain", "cluster.local", "--keepaliveMaxServerConnectionAge", "30m")
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(istiod), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		differences, err := wrapper.GetIstiodArgsDrift(context.TODO(), kubeConfig, istioManifestWithIstiodValues, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, differences)
+	})
+
+	t.Run("should report changed, missing and unexpected args", func(t *testing.T) {
+		// given
+		istiod := newIstiod("discovery", "--monitoringAddr=:15014", "--log_output_level=default:debug", "--domain", "cluster.local", "--profiling")
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(istiod), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		differences, err := wrapper.GetIstiodArgsDrift(context.TODO(), kubeConfig, istioManifestWithIstiodValues, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []IstiodArgDifference{
+			{Arg: "--keepaliveMaxServerConnectionAge", Desired: "--keepaliveMaxServerConnectionAge=30m"},
+			{Arg: "--log_output_level", Desired: "--log_output_level=all:warn", Actual: "--log_output_level=default:debug"},
+			{Arg: "--profiling", Actual: "--profiling"},
+		}, differences)
+	})
+
+	t.Run("should return error when istiod deployment does not exist", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetIstiodArgsDrift(context.TODO(), kubeConfig, istioManifestWithIstiodValues, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not get istiod deployment")
+	})
+}
@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// IstiodNotAvailableError is returned when the istiod deployment did not become available after install or update.
+type IstiodNotAvailableError struct {
+	Namespace         string
+	Name              string
+	Timeout           time.Duration
+	AvailableReplicas int32
+	DesiredReplicas   int32
+}
+
+func (e *IstiodNotAvailableError) Error() string {
+	return fmt.Sprintf("Istiod deployment %s/%s did not become available within %s, %d of %d replicas available",
+		e.Namespace, e.Name, e.Timeout, e.AvailableReplicas, e.DesiredReplicas)
+}
+
+// waitForIstiodAvailable polls the istiod deployment of the revision until all its desired replicas are available, so
+// that labeling the namespaces and resetting the proxies do not race with a starting control plane.
+func (c *DefaultIstioPerformer) waitForIstiodAvailable(context context.Context, kubeConfig, revision string, logger *zap.SugaredLogger) error {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	name := istiodDeploymentNameFor(revision)
+	var available, desired int32
+	err = wait.PollImmediate(c.config.Interval, c.config.Timeout, func() (bool, error) {
+		istiod, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context, name, metav1.GetOptions{})
+		if err != nil {
+			logger.Debugf("Waiting for istiod deployment: %v", err)
+			return false, nil
+		}
+		available, desired = istiod.Status.AvailableReplicas, desiredReplicas(istiod)
+		if available < desired {
+			logger.Debugf("Waiting for istiod to be available, %d of %d replicas available", available, desired)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return &IstiodNotAvailableError{
+			Namespace:         c.namespace,
+			Name:              name,
+			Timeout:           c.config.Timeout,
+			AvailableReplicas: available,
+			DesiredReplicas:   desired,
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to wait for istiod to become available")
+	}
+
+	logger.Debugf("Istiod deployment %s/%s is available", c.namespace, name)
+	return nil
+}
+
+func desiredReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newIstiodWithAvailableReplicas(revision string, desired, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: istiodDeploymentNameFor(revision), Namespace: istioNamespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: &desired},
+		Status:     appsv1.DeploymentStatus{Replicas: desired, AvailableReplicas: available},
+	}
+}
+
+func newAvailableIstiod(revision string) *appsv1.Deployment {
+	return newIstiodWithAvailableReplicas(revision, 1, 1)
+}
+
+func Test_DefaultIstioPerformer_waitForIstiodAvailable(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	config := PerformerConfig{Timeout: 200 * time.Millisecond, Interval: 10 * time.Millisecond}
+
+	t.Run("should return when all istiod replicas are available", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newIstiodWithAvailableReplicas("", 2, 2)), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, nil).WithPerformerConfig(config)
+
+		// when
+		err := wrapper.waitForIstiodAvailable(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should look up istiod in the configured namespace", func(t *testing.T) {
+		// given
+		istiod := newAvailableIstiod("")
+		istiod.Namespace = "custom-istio"
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(istiod), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, nil).WithPerformerConfig(config).WithNamespace("custom-istio")
+
+		// when
+		err := wrapper.waitForIstiodAvailable(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should wait until istiod replicas become available", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(newIstiodWithAvailableReplicas("canary", 2, 0))
+		getCalls := 0
+		kubeClient.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			getCalls++
+			if getCalls < 3 {
+				return false, nil, nil
+			}
+			return true, newIstiodWithAvailableReplicas("canary", 2, 2), nil
+		})
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, nil).WithPerformerConfig(config)
+
+		// when
+		err := wrapper.waitForIstiodAvailable(context.TODO(), kubeConfig, "canary", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 3, getCalls)
+	})
+
+	t.Run("should return timeout error when istiod replicas do not become available in time", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newIstiodWithAvailableReplicas("", 2, 1)), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, nil).WithPerformerConfig(config)
+
+		// when
+		err := wrapper.waitForIstiodAvailable(context.TODO(), kubeConfig, "", log)
+
+		// then
+		var notAvailableErr *IstiodNotAvailableError
+		require.ErrorAs(t, err, &notAvailableErr)
+		require.Equal(t, int32(1), notAvailableErr.AvailableReplicas)
+		require.Equal(t, int32(2), notAvailableErr.DesiredReplicas)
+		require.EqualError(t, err, "Istiod deployment istio-system/istiod did not become available within 200ms, 1 of 2 replicas available")
+	})
+
+	t.Run("should return timeout error when istiod deployment does not exist", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, &provider, nil).WithPerformerConfig(config)
+
+		// when
+		err := wrapper.waitForIstiodAvailable(context.TODO(), kubeConfig, "", log)
+
+		// then
+		var notAvailableErr *IstiodNotAvailableError
+		require.ErrorAs(t, err, &notAvailableErr)
+		require.Equal(t, "istiod", notAvailableErr.Name)
+	})
+}
@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultRevision = "default"
+
+// GetIstiodRevisions returns the sorted revisions of the istiod deployments installed in namespace, read from their
+// istio.io/rev label. An in-place installation without the label is reported as the default revision.
+func (c *DefaultIstioPerformer) GetIstiodRevisions(context context.Context, kubeClient kubernetes.Client, namespace string, logger *zap.SugaredLogger) ([]string, error) {
+	clientSet, err := kubeClient.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := clientSet.AppsV1().Deployments(namespace).List(context, metav1.ListOptions{LabelSelector: istiodLabelSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list istiod deployments")
+	}
+	logger.Debugf("Found %d istiod deployments in namespace %s", len(deployments.Items), namespace)
+
+	revisionSet := make(map[string]bool)
+	for _, deployment := range deployments.Items {
+		revision := deployment.Labels[istioRevisionLabel]
+		if revision == "" {
+			revision = defaultRevision
+		}
+		revisionSet[revision] = true
+	}
+
+	revisions := make([]string, 0, len(revisionSet))
+	for revision := range revisionSet {
+		revisions = append(revisions, revision)
+	}
+	sort.Strings(revisions)
+	return revisions, nil
+}
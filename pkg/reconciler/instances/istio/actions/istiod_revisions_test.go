@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_GetIstiodRevisions(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	newKubeClient := func(deployments ...*appsv1.Deployment) *mocks.Client {
+		clientSet := fake.NewSimpleClientset()
+		for _, deployment := range deployments {
+			require.NoError(t, clientSet.Tracker().Add(deployment))
+		}
+		kubeClient := mocks.Client{}
+		kubeClient.On("Clientset").Return(clientSet, nil)
+		return &kubeClient
+	}
+	newIstiod := func(name string, labels map[string]string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: istioNamespace, Labels: labels}}
+	}
+	wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+
+	t.Run("should report the default revision of an in-place installation", func(t *testing.T) {
+		// given
+		kubeClient := newKubeClient(newIstiod("istiod", map[string]string{"app": "istiod"}))
+
+		// when
+		revisions, err := wrapper.GetIstiodRevisions(context.TODO(), kubeClient, istioNamespace, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, revisions)
+	})
+
+	t.Run("should report both revisions of an in-place and a canary installation", func(t *testing.T) {
+		// given
+		kubeClient := newKubeClient(
+			newIstiod("istiod", map[string]string{"app": "istiod", "istio.io/rev": "default"}),
+			newIstiod("istiod-canary", map[string]string{"app": "istiod", "istio.io/rev": "canary"}),
+		)
+
+		// when
+		revisions, err := wrapper.GetIstiodRevisions(context.TODO(), kubeClient, istioNamespace, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"canary", "default"}, revisions)
+	})
+
+	t.Run("should report a revision once for several istiod deployments", func(t *testing.T) {
+		// given
+		kubeClient := newKubeClient(
+			newIstiod("istiod", map[string]string{"app": "istiod"}),
+			newIstiod("istiod-default", map[string]string{"app": "istiod", "istio.io/rev": "default"}),
+		)
+
+		// when
+		revisions, err := wrapper.GetIstiodRevisions(context.TODO(), kubeClient, istioNamespace, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, revisions)
+	})
+
+	t.Run("should ignore deployments which are not istiod", func(t *testing.T) {
+		// given
+		kubeClient := newKubeClient(
+			newIstiod("istiod", map[string]string{"app": "istiod"}),
+			newIstiod("istio-ingressgateway", map[string]string{"app": "istio-ingressgateway", "istio.io/rev": "canary"}),
+		)
+
+		// when
+		revisions, err := wrapper.GetIstiodRevisions(context.TODO(), kubeClient, istioNamespace, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"default"}, revisions)
+	})
+
+	t.Run("should report no revision when istiod is not installed", func(t *testing.T) {
+		// given
+		kubeClient := newKubeClient()
+
+		// when
+		revisions, err := wrapper.GetIstiodRevisions(context.TODO(), kubeClient, istioNamespace, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, revisions)
+	})
+
+	t.Run("should fail when the clientset could not be retrieved", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Clientset").Return(nil, errors.New("clientset error"))
+
+		// when
+		_, err := wrapper.GetIstiodRevisions(context.TODO(), &kubeClient, istioNamespace, log)
+
+		// then
+		require.EqualError(t, err, "clientset error")
+	})
+}
@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// errTargetVersionNotFound is returned when neither the helm values nor Chart.yaml of the Istio chart define a version.
+var errTargetVersionNotFound = errors.New("Target Istio version could not be found neither in Chart.yaml nor in helm values")
+
+// getTargetVersionFromIstiodDeployment derives the target version from the image tag of the installed istiod deployment.
+// It is used as a fallback for installations which were not done by the reconciler and whose chart does not define a version.
+func (c *DefaultIstioPerformer) getTargetVersionFromIstiodDeployment(context context.Context, kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return "", err
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context, istiodDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "Could not get istiod deployment")
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name != istiodContainerName {
+			continue
+		}
+		tag := imageTag(container.Image)
+		if tag == "" {
+			return "", errors.Errorf("Image %s of istiod deployment has no tag", container.Image)
+		}
+		logger.Debugf("Resolved target Istio version: %s from istiod deployment", tag)
+		return tag, nil
+	}
+
+	return "", errors.Errorf("Container %s not found in istiod deployment", istiodContainerName)
+}
+
+// imageTag returns the tag of the image reference, ignoring the digest and a registry port.
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	lastSlash := strings.LastIndex(image, "/")
+	lastColon := strings.LastIndex(image, ":")
+	if lastColon <= lastSlash {
+		return ""
+	}
+	return image[lastColon+1:]
+}
@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_getTargetVersionFromIstiodDeployment(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	newPerformer := func(kubeClient *fake.Clientset) *DefaultIstioPerformer {
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		return NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+	}
+	newIstiod := func(image string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: istiodDeploymentName, Namespace: istioNamespace},
+			Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: istiodContainerName, Image: image}},
+			}}},
+		}
+	}
+
+	t.Run("should return the image tag of the istiod deployment", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fake.NewSimpleClientset(newIstiod("eu.gcr.io/kyma-project/external/istio/pilot:1.11.4-distroless")))
+
+		// when
+		version, err := wrapper.getTargetVersionFromIstiodDeployment(context.TODO(), "kubeConfig", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.4-distroless", version)
+	})
+
+	t.Run("should fail when the istiod image has no tag", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fake.NewSimpleClientset(newIstiod("localhost:5000/istio/pilot@sha256:abc")))
+
+		// when
+		_, err := wrapper.getTargetVersionFromIstiodDeployment(context.TODO(), "kubeConfig", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "has no tag")
+	})
+
+	t.Run("should fail when istiod is not installed", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fake.NewSimpleClientset())
+
+		// when
+		_, err := wrapper.getTargetVersionFromIstiodDeployment(context.TODO(), "kubeConfig", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not get istiod deployment")
+	})
+}
+
+func Test_imageTag(t *testing.T) {
+	require.Equal(t, "1.11.4", imageTag("istio/pilot:1.11.4"))
+	require.Equal(t, "1.11.4", imageTag("localhost:5000/istio/pilot:1.11.4@sha256:abc"))
+	require.Equal(t, "", imageTag("localhost:5000/istio/pilot"))
+}
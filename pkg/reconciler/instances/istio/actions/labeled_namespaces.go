@@ -0,0 +1,37 @@
+package actions
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (c *DefaultIstioPerformer) ListLabeledNamespaces(context context.Context, kubeClient kubernetes.Client, logger *zap.SugaredLogger) ([]string, error) {
+	clientSet, err := kubeClient.Clientset()
+	if err != nil {
+		return nil, err
+	}
+
+	labeled := map[string]bool{}
+	for _, selector := range []string{injectionEnabledLabelSelector, istioRevisionLabel} {
+		namespaces, err := clientSet.CoreV1().Namespaces().List(context, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, err
+		}
+		for _, namespace := range namespaces.Items {
+			labeled[namespace.Name] = true
+		}
+	}
+
+	result := make([]string, 0, len(labeled))
+	for namespace := range labeled {
+		result = append(result, namespace)
+	}
+	sort.Strings(result)
+	logger.Debugf("Found %d namespaces labeled for sidecar injection", len(result))
+
+	return result, nil
+}
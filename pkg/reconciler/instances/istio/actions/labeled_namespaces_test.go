@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_ListLabeledNamespaces(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should list namespaces labeled with istio-injection enabled or a revision", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(
+			createNamespaceWithLabel("injection-enabled", map[string]string{"istio-injection": "enabled"}),
+			createNamespaceWithLabel("injection-disabled", map[string]string{"istio-injection": "disabled"}),
+			createNamespaceWithLabel("revision", map[string]string{"istio.io/rev": "canary"}),
+			createNamespaceWithLabel("both", map[string]string{"istio-injection": "enabled", "istio.io/rev": "canary"}),
+			createNamespace("unlabeled"),
+		), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+
+		// when
+		namespaces, err := wrapper.ListLabeledNamespaces(context.TODO(), &kubeClient, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"both", "injection-enabled", "revision"}, namespaces)
+	})
+
+	t.Run("should return empty list when no namespace is labeled", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Clientset").Return(fake.NewSimpleClientset(createNamespace("unlabeled")), nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+
+		// when
+		namespaces, err := wrapper.ListLabeledNamespaces(context.TODO(), &kubeClient, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, namespaces)
+	})
+
+	t.Run("should return error when clientset could not be retrieved", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		kubeClient.On("Clientset").Return(nil, errors.New("clientset error"))
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+
+		// when
+		_, err := wrapper.ListLabeledNamespaces(context.TODO(), &kubeClient, log)
+
+		// then
+		require.EqualError(t, err, "clientset error")
+	})
+}
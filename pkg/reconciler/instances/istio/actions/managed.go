@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	istiodDeploymentName = "istiod"
+	managedByLabel       = "reconciler.kyma-project.io/managed-by"
+	managedByValue       = "reconciler"
+)
+
+// markAsManaged labels the istiod deployment so that the installation can be recognized as managed by this reconciler.
+func (c *DefaultIstioPerformer) markAsManaged(context context.Context, kubeConfig string, logger *zap.SugaredLogger) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Warnf("Could not retrieve KubeClient from Kubeconfig to mark Istio as managed: %v", err)
+		return
+	}
+
+	labelPatch := fmt.Sprintf(`{"metadata":{"labels":{"%s":"%s"}}}`, managedByLabel, managedByValue)
+	_, err = kubeClient.AppsV1().Deployments(c.namespace).Patch(context, istiodDeploymentName, types.MergePatchType, []byte(labelPatch), metav1.PatchOptions{})
+	if err != nil {
+		logger.Warnf("Could not mark istiod deployment as managed by reconciler: %v", err)
+		return
+	}
+	logger.Debugf("Istiod deployment marked as managed by reconciler")
+}
+
+// isManagedByReconciler checks if the istiod deployment carries the label written during installation.
+// Failures are only logged, as the installation is then treated as not managed.
+func (c *DefaultIstioPerformer) isManagedByReconciler(kubeConfig string, logger *zap.SugaredLogger) bool {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Warnf("Could not retrieve KubeClient from Kubeconfig to check if Istio is managed: %v", err)
+		return false
+	}
+
+	deployment, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context.TODO(), istiodDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			logger.Warnf("Could not check if istiod deployment is managed by reconciler: %v", err)
+		}
+		return false
+	}
+
+	return deployment.Labels[managedByLabel] == managedByValue
+}
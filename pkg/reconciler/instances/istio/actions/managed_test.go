@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_ManagedByReconciler(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	newPerformer := func(kubeClient *fake.Clientset) *DefaultIstioPerformer {
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		return NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+	}
+
+	t.Run("should report installation marked during install as managed", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"},
+		})
+		wrapper := newPerformer(kubeClient)
+
+		// when
+		wrapper.markAsManaged(context.TODO(), "kubeconfig", log)
+		managed := wrapper.isManagedByReconciler("kubeconfig", log)
+
+		// then
+		require.True(t, managed)
+		deployment, err := kubeClient.AppsV1().Deployments("istio-system").Get(context.TODO(), "istiod", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "reconciler", deployment.Labels["reconciler.kyma-project.io/managed-by"])
+	})
+
+	t.Run("should report installation without the marker as not managed", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}},
+		})
+		wrapper := newPerformer(kubeClient)
+
+		// when
+		managed := wrapper.isManagedByReconciler("kubeconfig", log)
+
+		// then
+		require.False(t, managed)
+	})
+
+	t.Run("should report installation as not managed when istiod deployment does not exist", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fake.NewSimpleClientset())
+
+		// when
+		wrapper.markAsManaged(context.TODO(), "kubeconfig", log)
+		managed := wrapper.isManagedByReconciler("kubeconfig", log)
+
+		// then
+		require.False(t, managed)
+	})
+}
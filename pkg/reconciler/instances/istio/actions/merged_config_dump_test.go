@@ -0,0 +1,85 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/kyma-project/istio/operator/api/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	controllerfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_DefaultIstioPerformer_MergedConfigDump(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	require.NoError(t, v1alpha1.AddToScheme(scheme.Scheme))
+
+	newProvider := func() *clientsetmocks.Provider {
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(controllerfake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		return &provider
+	}
+
+	t.Run("should log the merged Istio Operator passed to istioctl install", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.DebugLevel)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, newProvider(), &datamocks.Gatherer{}).
+			WithMergedConfigDump(true)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, zap.New(core).Sugar())
+
+		// then
+		require.Error(t, err)
+		dumps := logs.FilterMessage("Merged Istio Operator").All()
+		require.Len(t, dumps, 1)
+		require.Equal(t, zap.DebugLevel, dumps[0].Level)
+		require.Equal(t, cmder.Calls[0].Arguments.String(1), dumps[0].ContextMap()["istioOperator"])
+	})
+
+	t.Run("should log the merged Istio Operator passed to istioctl upgrade", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.DebugLevel)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, newProvider(), &datamocks.Gatherer{}).
+			WithMergedConfigDump(true)
+
+		// when
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, zap.New(core).Sugar())
+
+		// then
+		require.Error(t, err)
+		dumps := logs.FilterMessage("Merged Istio Operator").All()
+		require.Len(t, dumps, 1)
+		require.Equal(t, cmder.Calls[0].Arguments.String(1), dumps[0].ContextMap()["istioOperator"])
+	})
+
+	t.Run("should not log the merged Istio Operator by default", func(t *testing.T) {
+		// given
+		core, logs := observer.New(zap.DebugLevel)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, newProvider(), &datamocks.Gatherer{})
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, zap.New(core).Sugar())
+
+		// then
+		require.Error(t, err)
+		require.Empty(t, logs.FilterMessage("Merged Istio Operator").All())
+	})
+}
@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/manifest"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	meshConfigMapName = "istio"
+	meshConfigMapKey  = "mesh"
+)
+
+// MeshConfigDifference describes a single MeshConfig field which differs between the chart and the cluster.
+type MeshConfigDifference struct {
+	Path    string
+	Desired interface{}
+	Actual  interface{}
+}
+
+func (c *DefaultIstioPerformer) GetMeshConfigDrift(context context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) ([]MeshConfigDifference, error) {
+	desired, err := getDesiredMeshConfig(istioChart)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	actual := map[string]interface{}{}
+	cm, err := kubeClient.CoreV1().ConfigMaps(c.namespace).Get(context, meshConfigMapName, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "Could not get istio mesh config")
+	}
+	if err == nil {
+		err = yaml.Unmarshal([]byte(cm.Data[meshConfigMapKey]), &actual)
+		if err != nil {
+			return nil, errors.Wrap(err, "Could not parse istio mesh config")
+		}
+	}
+
+	differences := diffMeshConfig(desired, actual, "")
+	logger.Debugf("Found %d differences between desired and actual mesh config", len(differences))
+
+	return differences, nil
+}
+
+func getDesiredMeshConfig(istioChart string) (map[string]interface{}, error) {
+	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	if err != nil {
+		return nil, err
+	}
+
+	var istioOperator struct {
+		Spec struct {
+			MeshConfig map[string]interface{} `json:"meshConfig"`
+		} `json:"spec"`
+	}
+	err = json.Unmarshal([]byte(istioOperatorManifest), &istioOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	return istioOperator.Spec.MeshConfig, nil
+}
+
+// diffMeshConfig compares only the fields set in the desired mesh config, as the live one contains all Istio defaults as well.
+func diffMeshConfig(desired, actual map[string]interface{}, path string) []MeshConfigDifference {
+	var differences []MeshConfigDifference
+	for key, desiredValue := range desired {
+		fieldPath := key
+		if path != "" {
+			fieldPath = fmt.Sprintf("%s.%s", path, key)
+		}
+
+		actualValue, ok := actual[key]
+		desiredMap, desiredIsMap := desiredValue.(map[string]interface{})
+		actualMap, actualIsMap := actualValue.(map[string]interface{})
+		if ok && desiredIsMap && actualIsMap {
+			differences = append(differences, diffMeshConfig(desiredMap, actualMap, fieldPath)...)
+			continue
+		}
+
+		if !ok || !reflect.DeepEqual(desiredValue, actualValue) {
+			differences = append(differences, MeshConfigDifference{Path: fieldPath, Desired: desiredValue, Actual: actualValue})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Path < differences[j].Path
+	})
+	return differences
+}
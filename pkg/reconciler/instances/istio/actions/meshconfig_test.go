@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const istioManifestWithMeshConfig = `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: installed-state-default-operator
+spec:
+  meshConfig:
+    accessLogEncoding: JSON
+    enableTracing: true
+    defaultConfig:
+      holdApplicationUntilProxyStarts: true
+      gatewayTopology:
+        numTrustedProxies: 1
+`
+
+func Test_diffMeshConfig(t *testing.T) {
+
+	t.Run("should not report differences when the actual config contains all desired values", func(t *testing.T) {
+		// given
+		desired := map[string]interface{}{"enableTracing": true, "defaultConfig": map[string]interface{}{"holdApplicationUntilProxyStarts": true}}
+		actual := map[string]interface{}{"enableTracing": true, "rootNamespace": "istio-system", "defaultConfig": map[string]interface{}{"holdApplicationUntilProxyStarts": true, "discoveryAddress": "istiod"}}
+
+		// when
+		differences := diffMeshConfig(desired, actual, "")
+
+		// then
+		require.Empty(t, differences)
+	})
+
+	t.Run("should report changed and missing values with their path", func(t *testing.T) {
+		// given
+		desired := map[string]interface{}{"enableTracing": true, "accessLogEncoding": "JSON", "defaultConfig": map[string]interface{}{"holdApplicationUntilProxyStarts": true}}
+		actual := map[string]interface{}{"enableTracing": false, "defaultConfig": map[string]interface{}{"holdApplicationUntilProxyStarts": false}}
+
+		// when
+		differences := diffMeshConfig(desired, actual, "")
+
+		// then
+		require.Equal(t, []MeshConfigDifference{
+			{Path: "accessLogEncoding", Desired: "JSON", Actual: nil},
+			{Path: "defaultConfig.holdApplicationUntilProxyStarts", Desired: true, Actual: false},
+			{Path: "enableTracing", Desired: true, Actual: false},
+		}, differences)
+	})
+}
+
+func Test_DefaultIstioPerformer_GetMeshConfigDrift(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should report drift between chart and istio ConfigMap", func(t *testing.T) {
+		// given
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"},
+			Data: map[string]string{"mesh": `
+accessLogEncoding: TEXT
+enableTracing: true
+defaultConfig:
+  holdApplicationUntilProxyStarts: true
+  gatewayTopology:
+    numTrustedProxies: 2
+`},
+		}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(cm), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		differences, err := wrapper.GetMeshConfigDrift(context.Background(), "kubeconfig", istioManifestWithMeshConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []MeshConfigDifference{
+			{Path: "accessLogEncoding", Desired: "JSON", Actual: "TEXT"},
+			{Path: "defaultConfig.gatewayTopology.numTrustedProxies", Desired: float64(1), Actual: float64(2)},
+		}, differences)
+	})
+
+	t.Run("should report every desired value when the istio ConfigMap does not exist", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		differences, err := wrapper.GetMeshConfigDrift(context.Background(), "kubeconfig", istioManifestWithMeshConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, differences, 3)
+	})
+
+	t.Run("should return error when the chart does not contain IstioOperator", func(t *testing.T) {
+		// given
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetMeshConfigDrift(context.Background(), "kubeconfig", "", log)
+
+		// then
+		require.Error(t, err)
+	})
+}
@@ -35,6 +35,22 @@ func (_m *CommanderResolver) GetCommander(version istioctl.Version) (istioctl.Co
 	return r0, r1
 }
 
+// SupportedVersions provides a mock function with given fields:
+func (_m *CommanderResolver) SupportedVersions() []istioctl.Version {
+	ret := _m.Called()
+
+	var r0 []istioctl.Version
+	if rf, ok := ret.Get(0).(func() []istioctl.Version); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]istioctl.Version)
+		}
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewCommanderResolver interface {
 	mock.TestingT
 	Cleanup(func())
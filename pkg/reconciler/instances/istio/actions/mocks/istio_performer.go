@@ -12,6 +12,10 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	proxy "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
+
+	v1 "k8s.io/api/core/v1"
+
 	zap "go.uber.org/zap"
 )
 
@@ -20,13 +24,321 @@ type IstioPerformer struct {
 	mock.Mock
 }
 
-// Install provides a mock function with given fields: _a0, kubeConfig, istioChart, version, logger
-func (_m *IstioPerformer) Install(_a0 context.Context, kubeConfig string, istioChart string, version string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(_a0, kubeConfig, istioChart, version, logger)
+// CheckProxyResetCompletion provides a mock function with given fields: _a0, kubeConfig, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger
+func (_m *IstioPerformer) CheckProxyResetCompletion(_a0 context.Context, kubeConfig string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options actions.ProxyResetOptions, logger *zap.SugaredLogger) (v1.PodList, error) {
+	ret := _m.Called(_a0, kubeConfig, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+
+	var r0 v1.PodList
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, []string, actions.ProxyResetOptions, *zap.SugaredLogger) v1.PodList); ok {
+		r0 = rf(_a0, kubeConfig, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	} else {
+		r0 = ret.Get(0).(v1.PodList)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, []string, actions.ProxyResetOptions, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClientVersion provides a mock function with given fields: workspace, branchVersion, istioChart, logger
+func (_m *IstioPerformer) ClientVersion(workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) (actions.IstioStatus, error) {
+	ret := _m.Called(workspace, branchVersion, istioChart, logger)
+
+	var r0 actions.IstioStatus
+	if rf, ok := ret.Get(0).(func(chart.Factory, string, string, *zap.SugaredLogger) actions.IstioStatus); ok {
+		r0 = rf(workspace, branchVersion, istioChart, logger)
+	} else {
+		r0 = ret.Get(0).(actions.IstioStatus)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(chart.Factory, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(workspace, branchVersion, istioChart, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataPlaneVersionsDetailed provides a mock function with given fields: _a0, workspace, branchVersion, istioChart, kubeConfig, logger
+func (_m *IstioPerformer) GetDataPlaneVersionsDetailed(_a0 context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (map[string][]actions.PodReference, error) {
+	ret := _m.Called(_a0, workspace, branchVersion, istioChart, kubeConfig, logger)
+
+	var r0 map[string][]actions.PodReference
+	if rf, ok := ret.Get(0).(func(context.Context, chart.Factory, string, string, string, *zap.SugaredLogger) map[string][]actions.PodReference); ok {
+		r0 = rf(_a0, workspace, branchVersion, istioChart, kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]actions.PodReference)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, chart.Factory, string, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, workspace, branchVersion, istioChart, kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIstioCRDs provides a mock function with given fields: _a0, kubeConfig, logger
+func (_m *IstioPerformer) GetIstioCRDs(_a0 context.Context, kubeConfig string, logger *zap.SugaredLogger) ([]actions.IstioCRD, error) {
+	ret := _m.Called(_a0, kubeConfig, logger)
+
+	var r0 []actions.IstioCRD
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zap.SugaredLogger) []actions.IstioCRD); ok {
+		r0 = rf(_a0, kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.IstioCRD)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIstiodArgsDrift provides a mock function with given fields: _a0, kubeConfig, istioChart, logger
+func (_m *IstioPerformer) GetIstiodArgsDrift(_a0 context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) ([]actions.IstiodArgDifference, error) {
+	ret := _m.Called(_a0, kubeConfig, istioChart, logger)
+
+	var r0 []actions.IstiodArgDifference
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) []actions.IstiodArgDifference); ok {
+		r0 = rf(_a0, kubeConfig, istioChart, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.IstiodArgDifference)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, istioChart, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIstiodLeader provides a mock function with given fields: _a0, kubeConfig, logger
+func (_m *IstioPerformer) GetIstiodLeader(_a0 context.Context, kubeConfig string, logger *zap.SugaredLogger) (actions.IstiodLeaderDiagnostics, error) {
+	ret := _m.Called(_a0, kubeConfig, logger)
+
+	var r0 actions.IstiodLeaderDiagnostics
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zap.SugaredLogger) actions.IstiodLeaderDiagnostics); ok {
+		r0 = rf(_a0, kubeConfig, logger)
+	} else {
+		r0 = ret.Get(0).(actions.IstiodLeaderDiagnostics)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIstiodRevisions provides a mock function with given fields: _a0, kubeClient, namespace, logger
+func (_m *IstioPerformer) GetIstiodRevisions(_a0 context.Context, kubeClient kubernetes.Client, namespace string, logger *zap.SugaredLogger) ([]string, error) {
+	ret := _m.Called(_a0, kubeClient, namespace, logger)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, string, *zap.SugaredLogger) []string); ok {
+		r0 = rf(_a0, kubeClient, namespace, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, kubernetes.Client, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeClient, namespace, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIstioOperator provides a mock function with given fields: _a0, kubeConfig, logger
+func (_m *IstioPerformer) GetIstioOperator(_a0 context.Context, kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(_a0, kubeConfig, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zap.SugaredLogger) string); ok {
+		r0 = rf(_a0, kubeConfig, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIstioOperatorDiff provides a mock function with given fields: _a0, kubeConfig, istioChart, logger
+func (_m *IstioPerformer) GetIstioOperatorDiff(_a0 context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(_a0, kubeConfig, istioChart, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) string); ok {
+		r0 = rf(_a0, kubeConfig, istioChart, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, istioChart, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMeshConfigDrift provides a mock function with given fields: _a0, kubeConfig, istioChart, logger
+func (_m *IstioPerformer) GetMeshConfigDrift(_a0 context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) ([]actions.MeshConfigDifference, error) {
+	ret := _m.Called(_a0, kubeConfig, istioChart, logger)
+
+	var r0 []actions.MeshConfigDifference
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) []actions.MeshConfigDifference); ok {
+		r0 = rf(_a0, kubeConfig, istioChart, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.MeshConfigDifference)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, istioChart, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNamespacesWithPodsWithoutSidecar provides a mock function with given fields: _a0, kubeConfig, workspace, branchVersion, istioChart, logger
+func (_m *IstioPerformer) GetNamespacesWithPodsWithoutSidecar(_a0 context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) (map[string]int, error) {
+	ret := _m.Called(_a0, kubeConfig, workspace, branchVersion, istioChart, logger)
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func(context.Context, string, chart.Factory, string, string, *zap.SugaredLogger) map[string]int); ok {
+		r0 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, chart.Factory, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProxyImageVersion provides a mock function with given fields: _a0, kubeConfig, revision, logger
+func (_m *IstioPerformer) GetProxyImageVersion(_a0 context.Context, kubeConfig string, revision string, logger *zap.SugaredLogger) (string, error) {
+	ret := _m.Called(_a0, kubeConfig, revision, logger)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) string); ok {
+		r0 = rf(_a0, kubeConfig, revision, logger)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, revision, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProxyResetCandidates provides a mock function with given fields: _a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger
+func (_m *IstioPerformer) GetProxyResetCandidates(_a0 context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options actions.ProxyResetOptions, logger *zap.SugaredLogger) (v1.PodList, error) {
+	ret := _m.Called(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+
+	var r0 v1.PodList
+	if rf, ok := ret.Get(0).(func(context.Context, string, chart.Factory, string, string, string, string, string, []string, actions.ProxyResetOptions, *zap.SugaredLogger) v1.PodList); ok {
+		r0 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	} else {
+		r0 = ret.Get(0).(v1.PodList)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, chart.Factory, string, string, string, string, string, []string, actions.ProxyResetOptions, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhookConfigurations provides a mock function with given fields: _a0, kubeConfig, logger
+func (_m *IstioPerformer) GetWebhookConfigurations(_a0 context.Context, kubeConfig string, logger *zap.SugaredLogger) ([]actions.WebhookReport, error) {
+	ret := _m.Called(_a0, kubeConfig, logger)
+
+	var r0 []actions.WebhookReport
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zap.SugaredLogger) []actions.WebhookReport); ok {
+		r0 = rf(_a0, kubeConfig, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]actions.WebhookReport)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Install provides a mock function with given fields: _a0, kubeConfig, istioChart, version, revision, rollbackOnFailure, logger
+func (_m *IstioPerformer) Install(_a0 context.Context, kubeConfig string, istioChart string, version string, revision string, rollbackOnFailure bool, logger *zap.SugaredLogger) error {
+	ret := _m.Called(_a0, kubeConfig, istioChart, version, revision, rollbackOnFailure, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(_a0, kubeConfig, istioChart, version, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r0 = rf(_a0, kubeConfig, istioChart, version, revision, rollbackOnFailure, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -34,13 +346,13 @@ func (_m *IstioPerformer) Install(_a0 context.Context, kubeConfig string, istioC
 	return r0
 }
 
-// LabelNamespaces provides a mock function with given fields: _a0, kubeClient, workspace, branchVersion, istioChart, logger
-func (_m *IstioPerformer) LabelNamespaces(_a0 context.Context, kubeClient kubernetes.Client, workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(_a0, kubeClient, workspace, branchVersion, istioChart, logger)
+// LabelNamespaces provides a mock function with given fields: _a0, kubeClient, workspace, branchVersion, istioChart, revision, logger
+func (_m *IstioPerformer) LabelNamespaces(_a0 context.Context, kubeClient kubernetes.Client, workspace chart.Factory, branchVersion string, istioChart string, revision string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(_a0, kubeClient, workspace, branchVersion, istioChart, revision, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, chart.Factory, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(_a0, kubeClient, workspace, branchVersion, istioChart, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, chart.Factory, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(_a0, kubeClient, workspace, branchVersion, istioChart, revision, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -48,13 +360,36 @@ func (_m *IstioPerformer) LabelNamespaces(_a0 context.Context, kubeClient kubern
 	return r0
 }
 
-// ResetProxy provides a mock function with given fields: _a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, logger
-func (_m *IstioPerformer) ResetProxy(_a0 context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, logger)
+// ListLabeledNamespaces provides a mock function with given fields: _a0, kubeClient, logger
+func (_m *IstioPerformer) ListLabeledNamespaces(_a0 context.Context, kubeClient kubernetes.Client, logger *zap.SugaredLogger) ([]string, error) {
+	ret := _m.Called(_a0, kubeClient, logger)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, *zap.SugaredLogger) []string); ok {
+		r0 = rf(_a0, kubeClient, logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, kubernetes.Client, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeClient, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reinstall provides a mock function with given fields: _a0, kubeClient, istioChart, version, revision, namespace, logger
+func (_m *IstioPerformer) Reinstall(_a0 context.Context, kubeClient kubernetes.Client, istioChart string, version string, revision string, namespace string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(_a0, kubeClient, istioChart, version, revision, namespace, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, chart.Factory, string, string, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, string, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(_a0, kubeClient, istioChart, version, revision, namespace, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -62,13 +397,34 @@ func (_m *IstioPerformer) ResetProxy(_a0 context.Context, kubeConfig string, wor
 	return r0
 }
 
-// Uninstall provides a mock function with given fields: kubeClientSet, version, logger
-func (_m *IstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(kubeClientSet, version, logger)
+// ResetProxy provides a mock function with given fields: _a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger
+func (_m *IstioPerformer) ResetProxy(_a0 context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options actions.ProxyResetOptions, logger *zap.SugaredLogger) (proxy.ResetSummary, error) {
+	ret := _m.Called(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+
+	var r0 proxy.ResetSummary
+	if rf, ok := ret.Get(0).(func(context.Context, string, chart.Factory, string, string, string, string, string, []string, actions.ProxyResetOptions, *zap.SugaredLogger) proxy.ResetSummary); ok {
+		r0 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	} else {
+		r0 = ret.Get(0).(proxy.ResetSummary)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, chart.Factory, string, string, string, string, string, []string, actions.ProxyResetOptions, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Uninstall provides a mock function with given fields: _a0, kubeClientSet, version, revision, namespace, logger
+func (_m *IstioPerformer) Uninstall(_a0 context.Context, kubeClientSet kubernetes.Client, version string, revision string, namespace string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(_a0, kubeClientSet, version, revision, namespace, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(kubernetes.Client, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(kubeClientSet, version, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, kubernetes.Client, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(_a0, kubeClientSet, version, revision, namespace, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -76,13 +432,13 @@ func (_m *IstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version str
 	return r0
 }
 
-// Update provides a mock function with given fields: _a0, kubeConfig, istioChart, targetVersion, logger
-func (_m *IstioPerformer) Update(_a0 context.Context, kubeConfig string, istioChart string, targetVersion string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(_a0, kubeConfig, istioChart, targetVersion, logger)
+// Update provides a mock function with given fields: _a0, kubeConfig, istioChart, targetVersion, revision, skipIngressGatewayRestart, logger
+func (_m *IstioPerformer) Update(_a0 context.Context, kubeConfig string, istioChart string, targetVersion string, revision string, skipIngressGatewayRestart bool, logger *zap.SugaredLogger) error {
+	ret := _m.Called(_a0, kubeConfig, istioChart, targetVersion, revision, skipIngressGatewayRestart, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(_a0, kubeConfig, istioChart, targetVersion, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, bool, *zap.SugaredLogger) error); ok {
+		r0 = rf(_a0, kubeConfig, istioChart, targetVersion, revision, skipIngressGatewayRestart, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -91,19 +447,19 @@ func (_m *IstioPerformer) Update(_a0 context.Context, kubeConfig string, istioCh
 }
 
 // Version provides a mock function with given fields: workspace, branchVersion, istioChart, kubeConfig, logger
-func (_m *IstioPerformer) Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (actions.IstioStatus, error) {
-	ret := _m.Called(workspace, branchVersion, istioChart, kubeConfig, logger)
+func (_m *IstioPerformer) Version(_a0 context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (actions.IstioStatus, error) {
+	ret := _m.Called(_a0, workspace, branchVersion, istioChart, kubeConfig, logger)
 
 	var r0 actions.IstioStatus
-	if rf, ok := ret.Get(0).(func(chart.Factory, string, string, string, *zap.SugaredLogger) actions.IstioStatus); ok {
-		r0 = rf(workspace, branchVersion, istioChart, kubeConfig, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, chart.Factory, string, string, string, *zap.SugaredLogger) actions.IstioStatus); ok {
+		r0 = rf(_a0, workspace, branchVersion, istioChart, kubeConfig, logger)
 	} else {
 		r0 = ret.Get(0).(actions.IstioStatus)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(chart.Factory, string, string, string, *zap.SugaredLogger) error); ok {
-		r1 = rf(workspace, branchVersion, istioChart, kubeConfig, logger)
+	if rf, ok := ret.Get(1).(func(context.Context, chart.Factory, string, string, string, *zap.SugaredLogger) error); ok {
+		r1 = rf(_a0, workspace, branchVersion, istioChart, kubeConfig, logger)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -13,3 +13,7 @@ func (tcr TestCommanderResolver) GetCommander(_ istioctl.Version) (istioctl.Comm
 	}
 	return tcr.cmder, nil
 }
+
+func (tcr TestCommanderResolver) SupportedVersions() []istioctl.Version {
+	return nil
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "k8s.io/client-go/kubernetes"
+)
+
+// waitForNamespaceDeletion polls until the deleted namespace is gone. When it is still terminating after the timeout, the
+// resources which block the deletion, e.g. Istio CRs with finalizers, are logged as reported by the namespace conditions.
+func (c *DefaultIstioPerformer) waitForNamespaceDeletion(context context.Context, kubeClient k8sclient.Interface, namespace string, timeout, interval time.Duration, logger *zap.SugaredLogger) error {
+	var terminating *corev1.Namespace
+	err := wait.PollImmediate(interval, timeout, func() (bool, error) {
+		ns, err := kubeClient.CoreV1().Namespaces().Get(context, namespace, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			logger.Debugf("Waiting for namespace %s to be deleted: %v", namespace, err)
+			return false, nil
+		}
+		terminating = ns
+		logger.Infof("Waiting for namespace %s to be deleted, it is in phase %s", namespace, ns.Status.Phase)
+		return false, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		for _, message := range remainingNamespaceContent(terminating) {
+			logger.Warnf("Namespace %s is still terminating: %s", namespace, message)
+		}
+		return errors.Errorf("Namespace %s was not deleted within %s", namespace, timeout)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("Namespace %s is gone", namespace)
+	return nil
+}
+
+func remainingNamespaceContent(namespace *corev1.Namespace) []string {
+	if namespace == nil {
+		return nil
+	}
+	var messages []string
+	for _, condition := range namespace.Status.Conditions {
+		isRemaining := condition.Type == corev1.NamespaceContentRemaining || condition.Type == corev1.NamespaceFinalizersRemaining
+		if isRemaining && condition.Status == corev1.ConditionTrue {
+			messages = append(messages, condition.Message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,120 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTerminatingNamespaceClient returns a clientset in which the deletion of the namespace is only issued, and the namespace
+// disappears after it was polled the given number of times.
+func newTerminatingNamespaceClient(namespace *corev1.Namespace, pollsUntilGone int) (*fake.Clientset, *int) {
+	kubeClient := fake.NewSimpleClientset(namespace)
+	deleted := false
+	polls := 0
+	kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		deleted = true
+		return true, nil, nil
+	})
+	kubeClient.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if !deleted {
+			return false, nil, nil
+		}
+		polls++
+		if pollsUntilGone >= 0 && polls > pollsUntilGone {
+			return true, nil, kerrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, namespace.Name)
+		}
+		return false, nil, nil
+	})
+	return kubeClient, &polls
+}
+
+func Test_DefaultIstioPerformer_Uninstall_NamespaceDeletionWait(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should wait until the deleted namespace is gone", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		kubeClient, polls := newTerminatingNamespaceClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}}, 3)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil, nil).WithNamespaceDeletionWait(time.Second, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 4, *polls)
+	})
+
+	t.Run("should not wait for the deleted namespace by default", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		kubeClient, polls := newTerminatingNamespaceClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}}, -1)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil, nil)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		require.Zero(t, *polls)
+	})
+
+	t.Run("should fail and log the remaining content when the namespace is not gone in time", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		stuckNamespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+			Status: corev1.NamespaceStatus{
+				Phase: corev1.NamespaceTerminating,
+				Conditions: []corev1.NamespaceCondition{
+					{Type: corev1.NamespaceDeletionDiscoveryFailure, Status: corev1.ConditionFalse, Message: "All resources successfully discovered"},
+					{Type: corev1.NamespaceFinalizersRemaining, Status: corev1.ConditionTrue, Message: "Some content in the namespace has finalizers remaining: istio.io/finalizer in 1 resource instances"},
+				},
+			},
+		}
+		kubeClient, _ := newTerminatingNamespaceClient(stuckNamespace, -1)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		core, logs := observer.New(zap.WarnLevel)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, nil, nil, nil).WithNamespaceDeletionWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", zap.New(core).Sugar())
+
+		// then
+		require.EqualError(t, err, "Namespace istio-system was not deleted within 50ms")
+		warnings := logs.FilterMessageSnippet("istio.io/finalizer in 1 resource instances").All()
+		require.Len(t, warnings, 1)
+		require.NotEmpty(t, logs.FilterMessageSnippet("still terminating").All())
+	})
+}
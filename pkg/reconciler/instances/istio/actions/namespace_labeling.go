@@ -0,0 +1,103 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+
+	"github.com/panjf2000/ants/v2"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	k8sclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const namespaceLabelingWorkers = 10
+
+// namespaceInjectionOverride sets the istio-injection label value of the namespaces matching the pattern.
+type namespaceInjectionOverride struct {
+	pattern   string
+	injection string
+}
+
+// namespaceInjectionOverrides are ordered from the most to the least specific pattern.
+type namespaceInjectionOverrides []namespaceInjectionOverride
+
+// newNamespaceInjectionOverrides validates the injection label values per namespace pattern. Patterns use path.Match syntax,
+// e.g. "legacy-*", and a longer pattern takes precedence over a shorter one matching the same namespace.
+func newNamespaceInjectionOverrides(injectionPerPattern map[string]string) (namespaceInjectionOverrides, error) {
+	overrides := make(namespaceInjectionOverrides, 0, len(injectionPerPattern))
+	for pattern, injection := range injectionPerPattern {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "Invalid namespace pattern %s", pattern)
+		}
+		if injection != injectionEnabled && injection != injectionDisabled {
+			return nil, fmt.Errorf("Invalid injection label value %s for namespace pattern %s, expected %s or %s", injection, pattern, injectionEnabled, injectionDisabled)
+		}
+		overrides = append(overrides, namespaceInjectionOverride{pattern: pattern, injection: injection})
+	}
+	sort.Slice(overrides, func(i, j int) bool {
+		if len(overrides[i].pattern) != len(overrides[j].pattern) {
+			return len(overrides[i].pattern) > len(overrides[j].pattern)
+		}
+		return overrides[i].pattern < overrides[j].pattern
+	})
+	return overrides, nil
+}
+
+// injectionFor returns the injection label value of the most specific pattern matching the namespace.
+func (o namespaceInjectionOverrides) injectionFor(namespace string) (string, bool) {
+	for _, override := range o {
+		if matched, _ := path.Match(override.pattern, namespace); matched {
+			return override.injection, true
+		}
+	}
+	return "", false
+}
+
+// patchNamespaces applies the label patch to the namespaces using a bounded pool of workers. Every patch is retried on
+// conflict on its own and the errors of all failed patches are returned as an aggregate.
+func patchNamespaces(context context.Context, clientSet k8sclient.Interface, namespaces []string, labelPatch []byte, logger *zap.SugaredLogger) error {
+	pool, err := ants.NewPool(namespaceLabelingWorkers)
+	if err != nil {
+		return err
+	}
+	defer pool.Release()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	addError := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+
+	for _, namespace := range namespaces {
+		namespace := namespace
+		wg.Add(1)
+		err := pool.Submit(func() {
+			defer wg.Done()
+			logger.Debugf("Patching namespace %s with labels %s", namespace, labelPatch)
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				_, err := clientSet.CoreV1().Namespaces().Patch(context, namespace, types.MergePatchType, labelPatch, metav1.PatchOptions{})
+				return err
+			})
+			if err != nil {
+				addError(errors.Wrapf(err, "Could not label namespace %s", namespace))
+			}
+		})
+		if err != nil {
+			wg.Done()
+			addError(errors.Wrapf(err, "Could not schedule labeling of namespace %s", namespace))
+		}
+	}
+	wg.Wait()
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,173 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newNamespaces(count int) ([]runtime.Object, []string) {
+	objects := make([]runtime.Object, 0, count)
+	names := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("namespace-%d", i)
+		objects = append(objects, createNamespace(name))
+		names = append(names, name)
+	}
+	return objects, names
+}
+
+func Test_patchNamespaces(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	labelPatch, err := newNamespaceLabelPatch(map[string]string{"istio-injection": "enabled"})
+	require.NoError(t, err)
+
+	t.Run("should patch all target namespaces", func(t *testing.T) {
+		// given
+		objects, names := newNamespaces(50)
+		clientset := fake.NewSimpleClientset(append(objects, createNamespace("untouched"))...)
+
+		// when
+		err := patchNamespaces(context.TODO(), clientset, names, labelPatch, log)
+
+		// then
+		require.NoError(t, err)
+		for _, name := range names {
+			got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, "enabled", got.Labels["istio-injection"])
+		}
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "untouched", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, got.Labels, "istio-injection")
+	})
+
+	t.Run("should retry the patch of a namespace on conflict", func(t *testing.T) {
+		// given
+		objects, names := newNamespaces(5)
+		clientset := fake.NewSimpleClientset(objects...)
+		conflicts := 0
+		clientset.PrependReactor("patch", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			name := action.(k8stesting.PatchAction).GetName()
+			if name == "namespace-2" && conflicts < 2 {
+				conflicts++
+				return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "namespaces"}, name, errors.New("conflict"))
+			}
+			return false, nil, nil
+		})
+
+		// when
+		err := patchNamespaces(context.TODO(), clientset, names, labelPatch, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, conflicts)
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "namespace-2", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "enabled", got.Labels["istio-injection"])
+	})
+
+	t.Run("should patch the remaining namespaces and aggregate the errors of failed patches", func(t *testing.T) {
+		// given
+		objects, names := newNamespaces(10)
+		clientset := fake.NewSimpleClientset(objects...)
+		clientset.PrependReactor("patch", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			name := action.(k8stesting.PatchAction).GetName()
+			if name == "namespace-3" || name == "namespace-7" {
+				return true, nil, errors.New("patch error")
+			}
+			return false, nil, nil
+		})
+
+		// when
+		err := patchNamespaces(context.TODO(), clientset, names, labelPatch, log)
+
+		// then
+		var aggregate utilerrors.Aggregate
+		require.ErrorAs(t, err, &aggregate)
+		require.Len(t, aggregate.Errors(), 2)
+		require.Contains(t, err.Error(), "Could not label namespace namespace-3: patch error")
+		require.Contains(t, err.Error(), "Could not label namespace namespace-7: patch error")
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "namespace-9", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "enabled", got.Labels["istio-injection"])
+	})
+}
+
+func Benchmark_patchNamespaces(b *testing.B) {
+	log := logger.NewLogger(false)
+	labelPatch, err := newNamespaceLabelPatch(map[string]string{"istio-injection": "enabled"})
+	require.NoError(b, err)
+	objects, names := newNamespaces(500)
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		clientset := fake.NewSimpleClientset(objects...)
+		b.StartTimer()
+
+		err := patchNamespaces(context.TODO(), clientset, names, labelPatch, log)
+		require.NoError(b, err)
+	}
+}
+
+func Test_namespaceInjectionOverrides(t *testing.T) {
+
+	t.Run("should prefer the most specific pattern matching the namespace", func(t *testing.T) {
+		// given
+		overrides, err := newNamespaceInjectionOverrides(map[string]string{"legacy-*": "disabled", "legacy-app": "enabled", "*": "enabled"})
+		require.NoError(t, err)
+
+		// when
+		legacyApp, legacyAppMatched := overrides.injectionFor("legacy-app")
+		legacyShop, legacyShopMatched := overrides.injectionFor("legacy-shop")
+		other, otherMatched := overrides.injectionFor("other")
+
+		// then
+		require.True(t, legacyAppMatched)
+		require.Equal(t, "enabled", legacyApp)
+		require.True(t, legacyShopMatched)
+		require.Equal(t, "disabled", legacyShop)
+		require.True(t, otherMatched)
+		require.Equal(t, "enabled", other)
+	})
+
+	t.Run("should not match a namespace without a matching pattern", func(t *testing.T) {
+		// given
+		overrides, err := newNamespaceInjectionOverrides(map[string]string{"legacy-*": "disabled"})
+		require.NoError(t, err)
+
+		// when
+		_, matched := overrides.injectionFor("user-ns")
+
+		// then
+		require.False(t, matched)
+	})
+
+	t.Run("should fail for an invalid injection label value", func(t *testing.T) {
+		// when
+		_, err := newNamespaceInjectionOverrides(map[string]string{"batch": "off"})
+
+		// then
+		require.EqualError(t, err, "Invalid injection label value off for namespace pattern batch, expected enabled or disabled")
+	})
+
+	t.Run("should fail for an invalid namespace pattern", func(t *testing.T) {
+		// when
+		_, err := newNamespaceInjectionOverrides(map[string]string{"legacy-[": "disabled"})
+
+		// then
+		require.EqualError(t, err, "Invalid namespace pattern legacy-[: syntax error in pattern")
+	})
+}
@@ -0,0 +1,29 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const dataPlaneProxiesMetricName = "istio_data_plane_proxies"
+
+// DataPlaneVersionsOpenMetrics formats the data plane version distribution of the given status in the OpenMetrics text format.
+// Versions are sorted to keep the output deterministic.
+func DataPlaneVersionsOpenMetrics(istioStatus IstioStatus) string {
+	versions := make([]string, 0, len(istioStatus.DataPlaneVersionCounts))
+	for version := range istioStatus.DataPlaneVersionCounts {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# HELP %s Number of Istio proxies per version.\n", dataPlaneProxiesMetricName))
+	sb.WriteString(fmt.Sprintf("# TYPE %s gauge\n", dataPlaneProxiesMetricName))
+	for _, version := range versions {
+		sb.WriteString(fmt.Sprintf("%s{version=%q} %d\n", dataPlaneProxiesMetricName, version, istioStatus.DataPlaneVersionCounts[version]))
+	}
+	sb.WriteString("# EOF\n")
+
+	return sb.String()
+}
@@ -0,0 +1,43 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DataPlaneVersionsOpenMetrics(t *testing.T) {
+
+	t.Run("should emit one sorted line per data plane version", func(t *testing.T) {
+		// given
+		istioStatus := IstioStatus{
+			DataPlaneVersionCounts: map[string]int{"1.12.1": 3, "1.11.4": 10, "1.12.0": 1},
+		}
+
+		// when
+		metrics := DataPlaneVersionsOpenMetrics(istioStatus)
+
+		// then
+		require.Equal(t, `# HELP istio_data_plane_proxies Number of Istio proxies per version.
+# TYPE istio_data_plane_proxies gauge
+istio_data_plane_proxies{version="1.11.4"} 10
+istio_data_plane_proxies{version="1.12.0"} 1
+istio_data_plane_proxies{version="1.12.1"} 3
+# EOF
+`, metrics)
+	})
+
+	t.Run("should emit only metadata when there is no data plane", func(t *testing.T) {
+		// given
+		istioStatus := IstioStatus{}
+
+		// when
+		metrics := DataPlaneVersionsOpenMetrics(istioStatus)
+
+		// then
+		require.Equal(t, `# HELP istio_data_plane_proxies Number of Istio proxies per version.
+# TYPE istio_data_plane_proxies gauge
+# EOF
+`, metrics)
+	})
+}
@@ -5,7 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"strings"
 	"time"
 
 	avastretry "github.com/avast/retry-go"
@@ -23,10 +23,11 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 	helmChart "helm.sh/helm/v3/pkg/chart"
-	"helm.sh/helm/v3/pkg/chart/loader"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/util/retry"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -34,16 +35,42 @@ const (
 	delayBetweenRetries = 5 * time.Second
 	timeout             = 5 * time.Minute
 	interval            = 12 * time.Second
+
+	uninstallGracePeriod = 10 * time.Second
+
+	istioNamespace        = "istio-system"
+	istiodLeaderLeaseName = "istio-leader"
+	istiodLabelSelector   = "app=istiod"
 )
 
+// Defaults of the retries and waits of the DefaultIstioPerformer, for callers configuring only a part of them.
+const (
+	DefaultRetriesCount        = retriesCount
+	DefaultDelayBetweenRetries = delayBetweenRetries
+	DefaultTimeout             = timeout
+	DefaultInterval            = interval
+)
+
+var defaultExcludedNamespaces = []string{"kube-system"}
+
 type VersionType string
 
 type IstioStatus struct {
-	ClientVersion     string
-	TargetVersion     string
-	TargetPrefix      string
-	PilotVersion      string
-	DataPlaneVersions map[string]bool
+	ClientVersion          string
+	TargetVersion          string
+	TargetPrefix           string
+	PilotVersion           string
+	PilotVersions          map[string]bool
+	DataPlaneVersions      map[string]bool
+	DataPlaneVersionCounts map[string]int
+	ManagedByReconciler    bool
+}
+
+// IstiodLeaderDiagnostics describes the current holder of the istiod leader election lease.
+type IstiodLeaderDiagnostics struct {
+	LeaseName      string
+	HolderIdentity string
+	RenewTime      *time.Time
 }
 
 type IstioVersionOutput struct {
@@ -66,13 +93,16 @@ type MeshInfo struct {
 }
 
 type DataPlaneVersion struct {
+	ID           string `json:"ID,omitempty"`
 	IstioVersion string `json:"IstioVersion,omitempty"`
 }
 
 type chartValues struct {
 	Global struct {
-		SidecarMigration bool `json:"sidecarMigration"`
-		Images           struct {
+		SidecarMigration   bool              `json:"sidecarMigration"`
+		NamespaceLabels    map[string]string `json:"namespaceLabels"`
+		NamespaceInjection map[string]string `json:"namespaceInjection"`
+		Images             struct {
 			IstioPilot struct {
 				Version string `json:"version"`
 			} `json:"istio_pilot"`
@@ -84,7 +114,8 @@ type chartValues struct {
 	} `json:"global"`
 	HelmValues struct {
 		SidecarInjectorWebhook struct {
-			EnableNamespacesByDefault bool `json:"enableNamespacesByDefault"`
+			EnableNamespacesByDefault bool     `json:"enableNamespacesByDefault"`
+			ExcludedNamespaces        []string `json:"excludedNamespaces"`
 		} `json:"sidecarInjectorWebhook"`
 	} `json:"helmValues"`
 }
@@ -94,47 +125,350 @@ type chartValues struct {
 //go:generate mockery --name=IstioPerformer --outpkg=mock --case=underscore
 type IstioPerformer interface {
 
-	// Install Istio in given version on the cluster using istioChart.
-	Install(context context.Context, kubeConfig, istioChart, version string, logger *zap.SugaredLogger) error
+	// Install Istio in given version on the cluster using istioChart. A non-empty revision installs the control plane as that revision.
+	// With rollbackOnFailure the just attempted revision is uninstalled again when the installed version does not match the target version.
+	Install(context context.Context, kubeConfig, istioChart, version, revision string, rollbackOnFailure bool, logger *zap.SugaredLogger) error
+
+	// LabelNamespaces labels all namespaces with enabled istio sidecar migration, except for the namespaces excluded in istioChart.
+	// Namespaces are labeled with istio.io/rev: revision if a revision is given, with istio-injection: enabled otherwise.
+	// Namespaces already opted in for injection with istio-injection or istio.io/rev are left unmodified. Namespaces matching a pattern
+	// of the namespaceInjection chart values are labeled with istio-injection set to the configured value instead, e.g. disabled.
+	LabelNamespaces(context context.Context, kubeClient kubernetes.Client, workspace chart.Factory, branchVersion string, istioChart string, revision string, logger *zap.SugaredLogger) error
 
-	// LabelNamespaces labels all namespaces with enabled istio sidecar migration.
-	LabelNamespaces(context context.Context, kubeClient kubernetes.Client, workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) error
+	// ListLabeledNamespaces returns the sorted names of namespaces labeled for sidecar injection with istio-injection: enabled or istio.io/rev.
+	ListLabeledNamespaces(context context.Context, kubeClient kubernetes.Client, logger *zap.SugaredLogger) ([]string, error)
 
-	// Update Istio on the cluster to the targetVersion using istioChart.
-	Update(context context.Context, kubeConfig, istioChart, targetVersion string, logger *zap.SugaredLogger) error
+	// Update Istio on the cluster to the targetVersion using istioChart. A non-empty revision installs the control plane as that revision
+	// next to the existing one instead of upgrading it in place. With skipIngressGatewayRestart the restart of the ingress gateway is
+	// deferred, e.g. to a maintenance window, and IngressGatewayRestartPendingError is returned if a restart is needed.
+	Update(context context.Context, kubeConfig, istioChart, targetVersion, revision string, skipIngressGatewayRestart bool, logger *zap.SugaredLogger) error
 
 	// ResetProxy resets Istio proxy of all Istio sidecars on the cluster. The proxyImageVersion parameter controls the Istio proxy version.
-	ResetProxy(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, logger *zap.SugaredLogger) error
+	// If namespace is not empty, only the sidecars in this namespace are reset. If namespaces is not empty, only the sidecars in
+	// the listed namespaces are reset. The options tune how the pods to reset are selected. The returned summary reports the progress
+	// of the reset, also when it failed.
+	ResetProxy(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (proxy.ResetSummary, error)
+
+	// GetProxyImageVersion returns the version of the istio proxy image the running istiod of the revision injects into sidecars.
+	GetProxyImageVersion(context context.Context, kubeConfig string, revision string, logger *zap.SugaredLogger) (string, error)
+
+	// GetProxyResetCandidates returns the pods ResetProxy would restart with the same arguments because of a different istio proxy
+	// image, a CNI change or a missing sidecar, each pod listed once. No pod is restarted.
+	GetProxyResetCandidates(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (v1.PodList, error)
+
+	// CheckProxyResetCompletion returns the pods which still run an istio proxy image different from the expected one, so after
+	// ResetProxy with the same arguments it reports the proxies which did not converge to the target version. Pods out of the
+	// scope of the proxy reset, e.g. annotated with a reset warning, are not reported.
+	CheckProxyResetCompletion(context context.Context, kubeConfig string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (v1.PodList, error)
+
+	// Version reports status of Istio installation on the cluster. If the chart does not define a target version, the image tag of the installed istiod deployment is used.
+	Version(context context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (IstioStatus, error)
 
-	// Version reports status of Istio installation on the cluster.
-	Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (IstioStatus, error)
+	// GetDataPlaneVersionsDetailed reports for each istio version of the data plane the pods running a proxy in this version.
+	// Proxies reported by istioctl whose pod no longer exists on the cluster are left out.
+	GetDataPlaneVersionsDetailed(context context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (map[string][]PodReference, error)
+
+	// ClientVersion reports the version of the istioctl binary resolved for the target version of istioChart together with this target
+	// version, without contacting the cluster. The versions of the cluster are left empty in the returned status.
+	ClientVersion(workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) (IstioStatus, error)
+
+	// Reinstall uninstalls Istio from namespace and installs it again in given version, waiting in between until the old istiod pods are gone.
+	// With a revision only the control plane of this revision is reinstalled.
+	Reinstall(context context.Context, kubeClient kubernetes.Client, istioChart, version, revision, namespace string, logger *zap.SugaredLogger) error
 
 	// Uninstall Istio from the cluster and its corresponding resources, using given Istio version.
-	Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error
+	// With a revision only the control plane of this revision is removed.
+	// The namespace Istio is installed in is deleted only after istiod and the istio webhook configurations are gone, and when no other revision remains.
+	Uninstall(context context.Context, kubeClientSet kubernetes.Client, version, revision, namespace string, logger *zap.SugaredLogger) error
+
+	// GetIstiodRevisions returns the sorted revisions of the istiod deployments in namespace, an in-place installation is reported as "default".
+	GetIstiodRevisions(context context.Context, kubeClient kubernetes.Client, namespace string, logger *zap.SugaredLogger) ([]string, error)
+
+	// GetIstiodLeader reports the holder of the istiod leader election lease. It does not modify the cluster.
+	GetIstiodLeader(context context.Context, kubeConfig string, logger *zap.SugaredLogger) (IstiodLeaderDiagnostics, error)
+
+	// GetNamespacesWithPodsWithoutSidecar reports for each namespace labeled for sidecar injection the number of running pods without a sidecar.
+	GetNamespacesWithPodsWithoutSidecar(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) (map[string]int, error)
+
+	// GetMeshConfigDrift compares the MeshConfig of the IstioOperator in istioChart with the mesh config applied on the cluster.
+	GetMeshConfigDrift(context context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) ([]MeshConfigDifference, error)
+
+	// GetIstioCRDs lists the CRDs of the install.istio.io, networking.istio.io and security.istio.io groups with the number of
+	// their custom resources. It does not modify the cluster.
+	GetIstioCRDs(context context.Context, kubeConfig string, logger *zap.SugaredLogger) ([]IstioCRD, error)
+
+	// GetIstioOperator returns the YAML of the IstioOperator installed on the cluster or an empty string if there is none.
+	GetIstioOperator(context context.Context, kubeConfig string, logger *zap.SugaredLogger) (string, error)
+
+	// GetIstioOperatorDiff returns the unified diff between the IstioOperator on the cluster and the one which would be applied for istioChart.
+	GetIstioOperatorDiff(context context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) (string, error)
+
+	// GetIstiodArgsDrift compares the args of the live istiod container with the args intended by istioChart.
+	GetIstiodArgsDrift(context context.Context, kubeConfig string, istioChart string, logger *zap.SugaredLogger) ([]IstiodArgDifference, error)
+
+	// GetWebhookConfigurations reports the webhooks of istio related Mutating and Validating webhook configurations with their failure policies and CA bundle state.
+	GetWebhookConfigurations(context context.Context, kubeConfig string, logger *zap.SugaredLogger) ([]WebhookReport, error)
 }
 
 // CommanderResolver interface implementations must be able to provide istioctl.Commander instances for given istioctl.Version
 type CommanderResolver interface {
 	// GetCommander function returns istioctl.Commander instance for given istioctl version if supported, returns an error otherwise.
 	GetCommander(version istioctl.Version) (istioctl.Commander, error)
+
+	// SupportedVersions function returns the istioctl versions for which a commander can be provided, in ascending order.
+	// Other patch versions of the same minor versions are supported as well.
+	SupportedVersions() []istioctl.Version
 }
 
 // DefaultIstioPerformer provides a default implementation of IstioPerformer.
 // It uses istioctl binary to do its job. It delegates the job of finding proper istioctl binary for given operation to the configured CommandResolver.
 type DefaultIstioPerformer struct {
-	resolver        CommanderResolver
-	istioProxyReset proxy.IstioProxyReset
-	provider        clientset.Provider
-	gatherer        data.Gatherer
+	resolver                CommanderResolver
+	istioProxyReset         proxy.IstioProxyReset
+	provider                clientset.Provider
+	gatherer                data.Gatherer
+	namespace               string
+	maxConcurrentNamespaces int
+	uninstallRetriesCount   uint
+	uninstallRetryDelay     time.Duration
+	uninstallGracePeriod    time.Duration
+	istiodTerminationWait   time.Duration
+	istiodTerminationPoll   time.Duration
+	uninstallWaitTimeout    time.Duration
+	uninstallWaitInterval   time.Duration
+	namespaceDeleteTimeout  time.Duration
+	namespaceDeleteInterval time.Duration
+	validateOperatorSchema  bool
+	backupRetention         int
+	applyTimeout            time.Duration
+	phasedInstall           bool
+	phaseWaitTimeout        time.Duration
+	phaseWaitInterval       time.Duration
+	istioctlLogOutputLevel  string
+	dumpMergedConfig        bool
+	config                  PerformerConfig
+}
+
+// RetryDelayType selects how the delay between retries of gathering data from the cluster evolves.
+type RetryDelayType string
+
+const (
+	// RetryDelayFixed waits DelayBetweenRetries before every retry.
+	RetryDelayFixed RetryDelayType = "fixed"
+	// RetryDelayBackOff starts with DelayBetweenRetries and doubles the delay with every retry, up to MaxDelayBetweenRetries if set.
+	RetryDelayBackOff RetryDelayType = "backoff"
+)
+
+// PerformerConfig configures the retries and waits the DefaultIstioPerformer uses when gathering data from the cluster and
+// during the proxy reset. Zero values fall back to the defaults, retries use a fixed delay by default.
+type PerformerConfig struct {
+	RetriesCount           int
+	DelayBetweenRetries    time.Duration
+	DelayType              RetryDelayType
+	MaxDelayBetweenRetries time.Duration
+	Timeout                time.Duration
+	Interval               time.Duration
+}
+
+func (c PerformerConfig) withDefaults() PerformerConfig {
+	if c.RetriesCount == 0 {
+		c.RetriesCount = retriesCount
+	}
+	if c.DelayBetweenRetries == 0 {
+		c.DelayBetweenRetries = delayBetweenRetries
+	}
+	if c.DelayType == "" {
+		c.DelayType = RetryDelayFixed
+	}
+	if c.Timeout == 0 {
+		c.Timeout = timeout
+	}
+	if c.Interval == 0 {
+		c.Interval = interval
+	}
+	return c
+}
+
+func (c PerformerConfig) retryOptions() []avastretry.Option {
+	retryOpts := []avastretry.Option{
+		avastretry.Delay(c.DelayBetweenRetries),
+		avastretry.Attempts(uint(c.RetriesCount)),
+		avastretry.DelayType(c.delayType()),
+	}
+	if c.MaxDelayBetweenRetries > 0 {
+		retryOpts = append(retryOpts, avastretry.MaxDelay(c.MaxDelayBetweenRetries))
+	}
+	return retryOpts
+}
+
+// delayType returns the avast retry delay function of the configured RetryDelayType.
+func (c PerformerConfig) delayType() avastretry.DelayTypeFunc {
+	if c.DelayType == RetryDelayBackOff {
+		return avastretry.BackOffDelay
+	}
+	return avastretry.FixedDelay
+}
+
+// ApplyTimeoutError is returned when istioctl install or upgrade did not finish within the configured apply timeout.
+type ApplyTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *ApplyTimeoutError) Error() string {
+	return fmt.Sprintf("istioctl did not apply the Istio Operator within %s and was cancelled", e.Timeout)
+}
+
+// UnsupportedVersionError is returned when no istioctl commander can be provided for the target version.
+type UnsupportedVersionError struct {
+	Version           istioctl.Version
+	SupportedVersions []istioctl.Version
+	Err               error
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UnsupportedVersionError) Unwrap() error {
+	return e.Err
 }
 
 // NewDefaultIstioPerformer creates a new instance of the DefaultIstioPerformer.
 func NewDefaultIstioPerformer(resolver CommanderResolver, istioProxyReset proxy.IstioProxyReset, provider clientset.Provider, gatherer data.Gatherer) *DefaultIstioPerformer {
-	return &DefaultIstioPerformer{resolver, istioProxyReset, provider, gatherer}
+	return &DefaultIstioPerformer{
+		resolver:              resolver,
+		istioProxyReset:       istioProxyReset,
+		provider:              provider,
+		gatherer:              gatherer,
+		namespace:             istioNamespace,
+		uninstallRetriesCount: retriesCount,
+		uninstallRetryDelay:   delayBetweenRetries,
+		uninstallGracePeriod:  uninstallGracePeriod,
+		istiodTerminationWait: timeout,
+		istiodTerminationPoll: interval,
+		uninstallWaitTimeout:  timeout,
+		uninstallWaitInterval: interval,
+		phaseWaitTimeout:      timeout,
+		phaseWaitInterval:     interval,
+		config:                PerformerConfig{}.withDefaults(),
+	}
+}
+
+// WithPerformerConfig configures the retries and waits used when gathering data from the cluster and during the proxy reset.
+func (c *DefaultIstioPerformer) WithPerformerConfig(config PerformerConfig) *DefaultIstioPerformer {
+	c.config = config.withDefaults()
+	return c
+}
+
+// WithNamespace configures the namespace Istio is installed in. The istiod deployment, its ConfigMaps and leader lease, the
+// IstioOperator and its backups are looked up there. It is istio-system by default.
+func (c *DefaultIstioPerformer) WithNamespace(namespace string) *DefaultIstioPerformer {
+	c.namespace = namespace
+	return c
+}
+
+// WithMaxConcurrentNamespaces limits how many namespaces are processed in parallel during the proxy reset.
+func (c *DefaultIstioPerformer) WithMaxConcurrentNamespaces(maxConcurrentNamespaces int) *DefaultIstioPerformer {
+	c.maxConcurrentNamespaces = maxConcurrentNamespaces
+	return c
+}
+
+// WithUninstallRetry configures how often and with which delay istioctl uninstall and the istio-system namespace deletion are retried.
+func (c *DefaultIstioPerformer) WithUninstallRetry(retriesCount uint, delayBetweenRetries time.Duration) *DefaultIstioPerformer {
+	c.uninstallRetriesCount = retriesCount
+	c.uninstallRetryDelay = delayBetweenRetries
+	return c
+}
+
+// WithUninstallGracePeriod configures how long to wait after istioctl uninstall before the istio-system namespace is deleted,
+// giving istioctl time to remove the resources it manages. The grace period is 10 seconds by default, zero disables it.
+func (c *DefaultIstioPerformer) WithUninstallGracePeriod(uninstallGracePeriod time.Duration) *DefaultIstioPerformer {
+	c.uninstallGracePeriod = uninstallGracePeriod
+	return c
+}
+
+// WithIstiodTerminationWait configures how long Reinstall waits for the old istiod pods to terminate and how often it checks them.
+func (c *DefaultIstioPerformer) WithIstiodTerminationWait(timeout, interval time.Duration) *DefaultIstioPerformer {
+	c.istiodTerminationWait = timeout
+	c.istiodTerminationPoll = interval
+	return c
+}
+
+// WithUninstallWait configures how long Uninstall waits for istiod and the istio webhook configurations to be removed
+// before the istio-system namespace is deleted, and how often it checks them.
+func (c *DefaultIstioPerformer) WithUninstallWait(timeout, interval time.Duration) *DefaultIstioPerformer {
+	c.uninstallWaitTimeout = timeout
+	c.uninstallWaitInterval = interval
+	return c
+}
+
+// WithNamespaceDeletionWait configures how long Uninstall waits for the deleted istio-system namespace to be gone and how often
+// it checks it. Uninstall does not wait by default.
+func (c *DefaultIstioPerformer) WithNamespaceDeletionWait(timeout, interval time.Duration) *DefaultIstioPerformer {
+	c.namespaceDeleteTimeout = timeout
+	c.namespaceDeleteInterval = interval
+	return c
+}
+
+// WithIstioOperatorSchemaValidation enables validation of the merged IstioOperator before it is passed to istioctl.
+// It is disabled by default, as experimental fields unknown to the validation would be rejected.
+func (c *DefaultIstioPerformer) WithIstioOperatorSchemaValidation(validateOperatorSchema bool) *DefaultIstioPerformer {
+	c.validateOperatorSchema = validateOperatorSchema
+	return c
+}
+
+// WithIstioOperatorBackup enables backing up the installed IstioOperator in a ConfigMap before each update.
+// Only the retention most recent backups are kept, older ones are pruned. Backups are disabled if retention is not positive.
+func (c *DefaultIstioPerformer) WithIstioOperatorBackup(retention int) *DefaultIstioPerformer {
+	c.backupRetention = retention
+	return c
 }
 
-func (c *DefaultIstioPerformer) Uninstall(kubeClientSet kubernetes.Client, version string, logger *zap.SugaredLogger) error {
+// WithApplyTimeout configures the deadline for istioctl install and upgrade. When it is exceeded the istioctl process
+// is stopped and an ApplyTimeoutError is returned. There is no deadline by default.
+func (c *DefaultIstioPerformer) WithApplyTimeout(applyTimeout time.Duration) *DefaultIstioPerformer {
+	c.applyTimeout = applyTimeout
+	return c
+}
+
+// WithPhasedInstall makes Install apply the CRDs, the control plane and the gateways in separate phases instead of a single istioctl install.
+func (c *DefaultIstioPerformer) WithPhasedInstall(phasedInstall bool) *DefaultIstioPerformer {
+	c.phasedInstall = phasedInstall
+	return c
+}
+
+// WithPhaseWait configures how long the phased install waits for the CRDs and istiod between the phases and how often it checks them.
+func (c *DefaultIstioPerformer) WithPhaseWait(timeout, interval time.Duration) *DefaultIstioPerformer {
+	c.phaseWaitTimeout = timeout
+	c.phaseWaitInterval = interval
+	return c
+}
+
+// WithIstioctlLogOutputLevel configures the istioctl log output level of install, update and uninstall, e.g. "default:debug".
+// A level set on the context with istioctl.WithLogOutputLevel takes precedence. The istioctl default is used if empty.
+func (c *DefaultIstioPerformer) WithIstioctlLogOutputLevel(level string) *DefaultIstioPerformer {
+	c.istioctlLogOutputLevel = level
+	return c
+}
+
+// WithMergedConfigDump makes Install and Update log the merged IstioOperator passed to istioctl at debug level, to
+// diagnose why an installation differs from the IstioOperator of the chart.
+func (c *DefaultIstioPerformer) WithMergedConfigDump(dumpMergedConfig bool) *DefaultIstioPerformer {
+	c.dumpMergedConfig = dumpMergedConfig
+	return c
+}
+
+// withIstioctlLogOutputLevel returns a context carrying the configured istioctl log output level, unless ctx already has one.
+func (c *DefaultIstioPerformer) withIstioctlLogOutputLevel(ctx context.Context) context.Context {
+	if c.istioctlLogOutputLevel == "" || istioctl.LogOutputLevel(ctx) != "" {
+		return ctx
+	}
+	return istioctl.WithLogOutputLevel(ctx, c.istioctlLogOutputLevel)
+}
+
+func (c *DefaultIstioPerformer) Uninstall(context context.Context, kubeClientSet kubernetes.Client, version, revision, namespace string, logger *zap.SugaredLogger) error {
 	logger.Debug("Starting Istio uninstallation...")
+	context = c.withIstioctlLogOutputLevel(context)
 
 	execVersion, err := istioctl.VersionFromString(version)
 	if err != nil {
@@ -146,106 +480,340 @@ func (c *DefaultIstioPerformer) Uninstall(kubeClientSet kubernetes.Client, versi
 		return err
 	}
 
-	err = commander.Uninstall(kubeClientSet.Kubeconfig(), logger)
+	retryOpts := append(c.uninstallRetryOptions(logger), avastretry.Context(context))
+
+	err = avastretry.Do(func() error {
+		return commander.Uninstall(context, kubeClientSet.Kubeconfig(), revision, logger)
+	}, retryOpts...)
 	if err != nil {
 		return errors.Wrap(err, "Error occurred when calling istioctl")
 	}
 	logger.Debug("Istio uninstall triggered")
+
+	if c.uninstallGracePeriod > 0 {
+		logger.Debugf("Waiting %s for istioctl uninstall to propagate", c.uninstallGracePeriod)
+		select {
+		case <-context.Done():
+			return context.Err()
+		case <-time.After(c.uninstallGracePeriod):
+		}
+	}
+
 	kubeClient, err := kubeClientSet.Clientset()
 	if err != nil {
 		return err
 	}
 
+	err = c.waitForUninstallCompletion(context, kubeClient, namespace, revision, logger)
+	if err != nil {
+		return err
+	}
+
+	if revision != "" {
+		remainingRevisions, err := getInstalledIstiodDeployments(context, kubeClient, namespace)
+		if err != nil {
+			return err
+		}
+		if len(remainingRevisions) > 0 {
+			logger.Infof("Istio revision %s uninstalled, keeping namespace %s as istiod deployments remain: %s", revision, namespace, strings.Join(remainingRevisions, ", "))
+			return nil
+		}
+	}
+
 	policy := metav1.DeletePropagationForeground
-	err = kubeClient.CoreV1().Namespaces().Delete(context.TODO(), "istio-system", metav1.DeleteOptions{
-		PropagationPolicy: &policy,
-	})
+	err = avastretry.Do(func() error {
+		return kubeClient.CoreV1().Namespaces().Delete(context, namespace, metav1.DeleteOptions{
+			PropagationPolicy: &policy,
+		})
+	}, retryOpts...)
 	if err != nil {
 		return err
 	}
-	logger.Debug("Istio namespace deleted")
+	logger.Debugf("Istio namespace %s deleted", namespace)
+
+	err = deleteSidecarInjectorWebhookConfigurations(context, kubeClient, namespace, revision, logger)
+	if err != nil {
+		return err
+	}
+
+	if c.namespaceDeleteTimeout > 0 {
+		return c.waitForNamespaceDeletion(context, kubeClient, namespace, c.namespaceDeleteTimeout, c.namespaceDeleteInterval, logger)
+	}
 	return nil
 }
 
-func (c *DefaultIstioPerformer) Install(context context.Context, kubeConfig, istioChart, version string, logger *zap.SugaredLogger) error {
-	logger.Debug("Starting Istio installation...")
+func (c *DefaultIstioPerformer) uninstallRetryOptions(logger *zap.SugaredLogger) []avastretry.Option {
+	attempts := c.uninstallRetriesCount
+	if attempts == 0 {
+		// avast retry treats zero attempts as retrying until success
+		attempts = 1
+	}
 
-	execVersion, err := istioctl.VersionFromString(version)
+	return []avastretry.Option{
+		avastretry.Delay(c.uninstallRetryDelay),
+		avastretry.Attempts(attempts),
+		avastretry.DelayType(avastretry.FixedDelay),
+		avastretry.LastErrorOnly(true),
+		avastretry.OnRetry(func(n uint, err error) {
+			logger.Warnf("Uninstall attempt %d failed: %v", n+1, err)
+		}),
+	}
+}
+
+func (c *DefaultIstioPerformer) Reinstall(context context.Context, kubeClient kubernetes.Client, istioChart, version, revision, namespace string, logger *zap.SugaredLogger) error {
+	err := c.Uninstall(context, kubeClient, version, revision, namespace, logger)
 	if err != nil {
-		return errors.Wrap(err, "Error parsing version")
+		return err
 	}
 
-	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	clientSet, err := kubeClient.Clientset()
 	if err != nil {
 		return err
 	}
 
-	mergedIstioConfig, err := merge.IstioOperatorConfiguration(context, c.provider, istioOperatorManifest, kubeConfig, logger)
+	err = c.waitForIstiodTermination(context, clientSet, namespace, revision, logger)
 	if err != nil {
 		return err
 	}
 
-	mergedCNI, err := cni.ApplyCNIConfiguration(context, c.provider, mergedIstioConfig, kubeConfig, logger)
+	// Uninstall keeps the namespace while istiod deployments of other revisions remain in it.
+	var remainingRevisions []string
+	if revision != "" {
+		remainingRevisions, err = getInstalledIstiodDeployments(context, clientSet, namespace)
+		if err != nil {
+			return err
+		}
+	}
+	if len(remainingRevisions) == 0 {
+		// Istio is installed into the namespace Uninstall deleted, so it has to be gone even if Uninstall does not wait for it.
+		// Without a configured namespace deletion wait it is bounded like the wait for the istiod termination.
+		deletionTimeout, deletionInterval := c.namespaceDeleteTimeout, c.namespaceDeleteInterval
+		if deletionTimeout == 0 {
+			deletionTimeout, deletionInterval = c.istiodTerminationWait, c.istiodTerminationPoll
+		}
+		err = c.waitForNamespaceDeletion(context, clientSet, namespace, deletionTimeout, deletionInterval, logger)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.Install(context, kubeClient.Kubeconfig(), istioChart, version, revision, false, logger)
+}
+
+func (c *DefaultIstioPerformer) waitForIstiodTermination(context context.Context, kubeClient k8sclient.Interface, namespace, revision string, logger *zap.SugaredLogger) error {
+	labelSelector := istiodLabelSelector
+	if revision != "" {
+		labelSelector = fmt.Sprintf("%s,%s=%s", istiodLabelSelector, istioRevisionLabel, revision)
+	}
+	err := wait.PollImmediate(c.istiodTerminationPoll, c.istiodTerminationWait, func() (bool, error) {
+		pods, err := kubeClient.CoreV1().Pods(namespace).List(context, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return false, err
+		}
+		if len(pods.Items) > 0 {
+			logger.Debugf("Waiting for %d old istiod pods to terminate", len(pods.Items))
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "Old istiod pods did not terminate")
+	}
+
+	logger.Debug("Old istiod pods terminated")
+	return nil
+}
+
+func (c *DefaultIstioPerformer) Install(ctx context.Context, kubeConfig, istioChart, version, revision string, rollbackOnFailure bool, logger *zap.SugaredLogger) error {
+	logger.Debug("Starting Istio installation...")
+	ctx = c.withIstioctlLogOutputLevel(ctx)
+
+	execVersion, err := istioctl.VersionFromString(version)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing version")
+	}
+
+	mergedCNI, err := c.computeMergedConfig(ctx, kubeConfig, istioChart, logger)
 	if err != nil {
 		return err
 	}
 
+	if c.validateOperatorSchema {
+		err = manifest.ValidateIstioOperatorSchema(mergedCNI, true)
+		if err != nil {
+			return err
+		}
+	}
+
 	commander, err := c.resolver.GetCommander(execVersion)
 	if err != nil {
 		return err
 	}
 
-	err = commander.Install(mergedCNI, kubeConfig, logger)
+	if c.phasedInstall {
+		err = c.installInPhases(ctx, commander, mergedCNI, revision, kubeConfig, logger)
+	} else {
+		err = c.applyWithTimeout(ctx, func(applyContext context.Context) error {
+			return commander.Install(applyContext, mergedCNI, revision, kubeConfig, logger)
+		})
+	}
 	if err != nil {
-		return errors.Wrap(err, "Error occurred when calling istioctl")
+		return err
 	}
 
-	installedVersion, err := getInstalledIstioVersion(c.provider, kubeConfig, c.gatherer, logger)
+	installedVersion, err := getInstalledIstioVersion(c.provider, kubeConfig, c.gatherer, commander, c.config.retryOptions(), logger)
 	if err != nil {
 		return err
 	}
 
 	if execVersion.MajorMinorPatch() != installedVersion {
+		if rollbackOnFailure {
+			c.rollbackInstall(ctx, commander, kubeConfig, revision, logger)
+		}
 		return fmt.Errorf("Installed Istio version: %s do not match target version: %s", installedVersion, execVersion.MajorMinorPatch())
 	}
 
+	if err := c.waitForIstiodAvailable(ctx, kubeConfig, revision, logger); err != nil {
+		return err
+	}
+
+	c.markAsManaged(ctx, kubeConfig, logger)
+
 	logger.Infof("Istio in version %s successfully installed", version)
 
 	return nil
 }
 
-func (c *DefaultIstioPerformer) LabelNamespaces(context context.Context, kubeClient kubernetes.Client, workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) error {
-	logger.Debugf("Labeling namespaces with istio-injection: enabled")
+// rollbackInstall uninstalls the revision of a failed installation, so that the next reconciliation starts from a clean
+// cluster. A failed rollback is only warned about, as the failed installation is reported anyway.
+func (c *DefaultIstioPerformer) rollbackInstall(context context.Context, commander istioctl.Commander, kubeConfig, revision string, logger *zap.SugaredLogger) {
+	logger.Warnf("Rolling back the failed Istio installation of revision %q", revision)
+	if err := commander.Uninstall(context, kubeConfig, revision, logger); err != nil {
+		logger.Warnf("Could not roll back the failed Istio installation: %v", err)
+	}
+}
+
+// applyWithTimeout runs the istioctl apply within the configured apply timeout.
+func (c *DefaultIstioPerformer) applyWithTimeout(ctx context.Context, apply func(applyContext context.Context) error) error {
+	applyContext := ctx
+	if c.applyTimeout > 0 {
+		var cancel context.CancelFunc
+		applyContext, cancel = context.WithTimeout(ctx, c.applyTimeout)
+		defer cancel()
+	}
+
+	err := apply(applyContext)
+	if err != nil {
+		if ctx.Err() == nil && applyContext.Err() == context.DeadlineExceeded {
+			return &ApplyTimeoutError{Timeout: c.applyTimeout}
+		}
+		return errors.Wrap(err, "Error occurred when calling istioctl")
+	}
+
+	return nil
+}
+
+// computeMergedConfig returns the IstioOperator from istioChart merged with the Istio CRs and the CNI configuration found on the cluster.
+func (c *DefaultIstioPerformer) computeMergedConfig(context context.Context, kubeConfig, istioChart string, logger *zap.SugaredLogger) (string, error) {
+	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
+	if err != nil {
+		return "", err
+	}
+
+	mergedIstioConfig, err := merge.IstioOperatorConfiguration(context, c.provider, istioOperatorManifest, kubeConfig, logger)
+	if err != nil {
+		return "", err
+	}
+
+	mergedCNI, _, err := cni.ApplyCNIConfiguration(context, c.provider, mergedIstioConfig, kubeConfig, logger)
+	if err != nil {
+		return "", err
+	}
+
+	if c.dumpMergedConfig {
+		logger.Debugw("Merged Istio Operator", "istioOperator", mergedCNI)
+	}
+
+	return mergedCNI, nil
+}
+
+func (c *DefaultIstioPerformer) LabelNamespaces(context context.Context, kubeClient kubernetes.Client, workspace chart.Factory, branchVersion string, istioChart string, revision string, logger *zap.SugaredLogger) error {
+	labelKey, labelValue := injectionLabel(revision)
+	logger.Debugf("Labeling namespaces with %s: %s", labelKey, labelValue)
 	clientSet, err := kubeClient.Clientset()
 	if err != nil {
 		return err
 	}
 
-	labelPatch := `{"metadata": {"labels": {"istio-injection": "enabled"}}}`
-
-	sidecarMigrationEnabled, sidecarMigrationIsSet, err := isSidecarMigrationEnabled(workspace, branchVersion, istioChart)
+	chartLoader := newIstioChartLoader(workspace, branchVersion, istioChart)
+	sidecarMigrationEnabled, sidecarMigrationIsSet, err := isSidecarMigrationEnabled(chartLoader)
 	if err != nil {
 		return err
 	}
 	if sidecarMigrationEnabled && sidecarMigrationIsSet {
-		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
-			namespaces, err := clientSet.CoreV1().Namespaces().List(context, metav1.ListOptions{})
-			if err != nil {
-				return err
+		excludedNamespaces, err := getExcludedNamespaces(chartLoader)
+		if err != nil {
+			return err
+		}
+
+		injectionOverrides, err := getNamespaceInjectionOverrides(chartLoader)
+		if err != nil {
+			return err
+		}
+
+		namespaceLabels, err := getNamespaceLabels(chartLoader)
+		if err != nil {
+			return err
+		}
+		namespaceLabels[labelKey] = labelValue
+		labelPatch, err := newNamespaceLabelPatch(namespaceLabels)
+		if err != nil {
+			return err
+		}
+
+		namespaces, err := clientSet.CoreV1().Namespaces().List(context, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		var namespacesToLabel []string
+		namespacesToOverride := map[string][]string{}
+		for _, namespace := range namespaces.Items {
+			if excludedNamespaces[namespace.ObjectMeta.Name] {
+				continue
 			}
-			for _, namespace := range namespaces.Items {
-				_, isIstioInjectionSet := namespace.Labels["istio-injection"]
-				if !isIstioInjectionSet && namespace.ObjectMeta.Name != "kube-system" {
-					logger.Debugf("Patching namespace %s with label istio-injection: enabled", namespace.ObjectMeta.Name)
-					_, err = clientSet.CoreV1().Namespaces().Patch(context, namespace.ObjectMeta.Name, types.MergePatchType, []byte(labelPatch), metav1.PatchOptions{})
+			if injection, ok := injectionOverrides.injectionFor(namespace.ObjectMeta.Name); ok && injection != injectionEnabled {
+				if namespace.Labels[istioInjectionLabel] != injection {
+					namespacesToOverride[injection] = append(namespacesToOverride[injection], namespace.ObjectMeta.Name)
 				}
+				continue
 			}
-			return err
-		})
+			if !isLabeledForInjection(namespace.Labels, revision) {
+				namespacesToLabel = append(namespacesToLabel, namespace.ObjectMeta.Name)
+			}
+		}
+
+		err = patchNamespaces(context, clientSet, namespacesToLabel, labelPatch, logger)
 		if err != nil {
 			return err
 		}
 
+		for injection, overriddenNamespaces := range namespacesToOverride {
+			overrideLabels, err := getNamespaceLabels(chartLoader)
+			if err != nil {
+				return err
+			}
+			overrideLabels[istioInjectionLabel] = injection
+			overridePatch, err := newNamespaceLabelPatch(overrideLabels)
+			if err != nil {
+				return err
+			}
+			logger.Debugf("Labeling namespaces %s with %s: %s", strings.Join(overriddenNamespaces, ", "), istioInjectionLabel, injection)
+			err = patchNamespaces(context, clientSet, overriddenNamespaces, overridePatch, logger)
+			if err != nil {
+				return err
+			}
+		}
+
 		logger.Debugf("Namespaces have been labeled successfully")
 	} else {
 		logger.Debugf("Sidecar migration is disabled or it is not set, skipping labeling namespaces")
@@ -254,31 +822,43 @@ func (c *DefaultIstioPerformer) LabelNamespaces(context context.Context, kubeCli
 	return nil
 }
 
-func (c *DefaultIstioPerformer) Update(context context.Context, kubeConfig, istioChart, targetVersion string, logger *zap.SugaredLogger) error {
+func (c *DefaultIstioPerformer) Update(ctx context.Context, kubeConfig, istioChart, targetVersion, revision string, skipIngressGatewayRestart bool, logger *zap.SugaredLogger) error {
 	logger.Debug("Starting Istio update...")
+	ctx = c.withIstioctlLogOutputLevel(ctx)
 
 	version, err := istioctl.VersionFromString(targetVersion)
 	if err != nil {
 		return errors.Wrap(err, "Error parsing version")
 	}
 
-	istioOperatorManifest, err := manifest.ExtractIstioOperatorContextFrom(istioChart)
-	if err != nil {
+	if _, err := manifest.ExtractIstioOperatorContextFrom(istioChart); err != nil {
 		return err
 	}
 
-	ingressGatewayNeedsRestart, err := merge.NeedsIngressGatewayRestart(context, c.provider, kubeConfig, logger)
+	ingressGatewayNeedsRestart, err := merge.NeedsIngressGatewayRestart(ctx, c.provider, kubeConfig, logger)
 	if err != nil {
 		return err
 	}
 
-	mergedIstioConfig, err := merge.IstioOperatorConfiguration(context, c.provider, istioOperatorManifest, kubeConfig, logger)
+	mergedCNI, err := c.computeMergedConfig(ctx, kubeConfig, istioChart, logger)
 	if err != nil {
 		return err
 	}
-	mergedCNI, err := cni.ApplyCNIConfiguration(context, c.provider, mergedIstioConfig, kubeConfig, logger)
-	if err != nil {
-		return err
+
+	if c.validateOperatorSchema {
+		err = manifest.ValidateIstioOperatorSchema(mergedCNI, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.warnOnIngressGatewayServiceTypeChange(ctx, kubeConfig, mergedCNI, logger)
+
+	if c.backupRetention > 0 {
+		err = c.backupIstioOperator(ctx, kubeConfig, logger)
+		if err != nil {
+			return err
+		}
 	}
 
 	commander, err := c.resolver.GetCommander(version)
@@ -286,12 +866,14 @@ func (c *DefaultIstioPerformer) Update(context context.Context, kubeConfig, isti
 		return err
 	}
 
-	err = commander.Upgrade(mergedCNI, kubeConfig, logger)
+	err = c.applyWithTimeout(ctx, func(applyContext context.Context) error {
+		return commander.Upgrade(applyContext, mergedCNI, revision, kubeConfig, logger)
+	})
 	if err != nil {
-		return errors.Wrap(err, "Error occurred when calling istioctl")
+		return err
 	}
 
-	updatedVersion, err := getInstalledIstioVersion(c.provider, kubeConfig, c.gatherer, logger)
+	updatedVersion, err := getInstalledIstioVersion(c.provider, kubeConfig, c.gatherer, commander, c.config.retryOptions(), logger)
 	if err != nil {
 		return err
 	}
@@ -300,15 +882,35 @@ func (c *DefaultIstioPerformer) Update(context context.Context, kubeConfig, isti
 		return fmt.Errorf("Updated Istio version: %s do not match target version: %s", updatedVersion, version.MajorMinorPatch())
 	}
 
+	if err := c.waitForIstiodAvailable(ctx, kubeConfig, revision, logger); err != nil {
+		return err
+	}
+
 	logger.Infof("Istio has been updated successfully to version %s", targetVersion)
 
 	if ingressGatewayNeedsRestart {
-		logger.Infof("Restarting ingress-gateway")
 		istioClient, err := c.provider.GetIstioClient(kubeConfig)
 		if err != nil {
 			return err
 		}
-		err = ingressgateway.RestartDeployment(context, istioClient)
+		kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+		if err != nil {
+			return err
+		}
+		missingSecrets, err := ingressgateway.GetMissingCertificateSecrets(ctx, istioClient, kubeClient)
+		if err != nil {
+			return err
+		}
+		if len(missingSecrets) > 0 {
+			logger.Warnf("Skipping ingress-gateway restart, its certificate secrets are missing: %s", strings.Join(missingSecrets, ", "))
+			return nil
+		}
+		if skipIngressGatewayRestart {
+			logger.Warnf("Ingress-gateway needs a restart, skipping it as configured")
+			return &IngressGatewayRestartPendingError{}
+		}
+		logger.Infof("Restarting ingress-gateway")
+		err = ingressgateway.RestartDeployment(ctx, istioClient)
 		if err != nil {
 			return err
 		}
@@ -317,26 +919,61 @@ func (c *DefaultIstioPerformer) Update(context context.Context, kubeConfig, isti
 	return nil
 }
 
-func (c *DefaultIstioPerformer) ResetProxy(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, logger *zap.SugaredLogger) error {
+// warnOnIngressGatewayServiceTypeChange logs a warning if the update changes the type of the ingress gateway Service,
+// as e.g. switching from LoadBalancer may leave the load balancer of the cloud provider orphaned.
+func (c *DefaultIstioPerformer) warnOnIngressGatewayServiceTypeChange(context context.Context, kubeConfig, operatorManifest string, logger *zap.SugaredLogger) {
+	istioClient, err := c.provider.GetIstioClient(kubeConfig)
+	if err != nil {
+		logger.Warnf("Could not check ingress gateway Service type: %v", err)
+		return
+	}
+
+	change, err := ingressgateway.GetServiceTypeChange(context, istioClient, operatorManifest)
+	if err != nil {
+		logger.Warnf("Could not check ingress gateway Service type: %v", err)
+		return
+	}
+	if change.Changed() {
+		logger.Warnf("Ingress gateway Service type changes from %s to %s, resources of the previous type may need a manual cleanup", change.Live, change.Desired)
+	}
+}
+
+func (c *DefaultIstioPerformer) ResetProxy(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (proxy.ResetSummary, error) {
+	cfg, err := c.proxyResetConfig(context, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	if err != nil {
+		return proxy.ResetSummary{}, err
+	}
+
+	summary, err := c.istioProxyReset.Run(cfg)
+	if err != nil {
+		return summary, errors.Wrap(err, "Istio proxy reset error")
+	}
+
+	return summary, nil
+}
+
+// proxyResetConfig creates the configuration of the istio proxy reset, so ResetProxy and GetProxyResetCandidates select the
+// same pods.
+func (c *DefaultIstioPerformer) proxyResetConfig(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (istioConfig.IstioProxyConfig, error) {
 	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
 	if err != nil {
 		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
-		return err
+		return istioConfig.IstioProxyConfig{}, err
 	}
 	dynamicClient, err := c.provider.GetDynamicClient(kubeConfig)
 	if err != nil {
 		logger.Error("Could not retrieve Dynamic client from Kubeconfig!")
-		return err
+		return istioConfig.IstioProxyConfig{}, err
 	}
 	cniEnabled, err := cni.GetActualCNIState(dynamicClient)
 	if err != nil {
-		return err
+		return istioConfig.IstioProxyConfig{}, err
 	}
 
 	sidecarInjectionEnabledByDefault, err := IsSidecarInjectionNamespacesByDefaultEnabled(workspace, branchVersion, istioChart)
 	if err != nil {
 		logger.Error("Could not retrieve default istio sidecar injection!")
-		return err
+		return istioConfig.IstioProxyConfig{}, err
 	}
 
 	cfg := istioConfig.IstioProxyConfig{
@@ -344,32 +981,44 @@ func (c *DefaultIstioPerformer) ResetProxy(context context.Context, kubeConfig s
 		Context:                          context,
 		ImagePrefix:                      proxyImagePrefix,
 		ImageVersion:                     proxyImageVersion,
-		RetriesCount:                     retriesCount,
-		DelayBetweenRetries:              delayBetweenRetries,
-		Timeout:                          timeout,
-		Interval:                         interval,
+		RetriesCount:                     c.config.RetriesCount,
+		DelayBetweenRetries:              c.config.DelayBetweenRetries,
+		Timeout:                          c.config.Timeout,
+		Interval:                         c.config.Interval,
 		Kubeclient:                       kubeClient,
 		Debug:                            false,
 		Log:                              logger,
 		SidecarInjectionByDefaultEnabled: sidecarInjectionEnabledByDefault,
 		CNIEnabled:                       cniEnabled,
+		MaxConcurrentNamespaces:          c.maxConcurrentNamespaces,
+		Namespace:                        namespace,
+		Namespaces:                       namespaces,
+		ImageComparison:                  options.ImageComparison,
+		ImageDigest:                      options.ImageDigest,
+		JobPodsHandling:                  options.JobPodsHandling,
+		NamespacePriority:                options.NamespacePriority,
+		UnlistedNamespacesPriority:       options.UnlistedNamespacesPriority,
+		IncludeTerminatingPods:           options.IncludeTerminatingPods,
 	}
-
-	err = c.istioProxyReset.Run(cfg)
-	if err != nil {
-		return errors.Wrap(err, "Istio proxy reset error")
+	if cfg.ImageComparison == data.ImageComparisonDigest && cfg.ImageDigest == "" {
+		cfg.ImageDigest = c.resolveProxyImageDigest(kubeClient, data.ExpectedImage{Prefix: proxyImagePrefix, Version: proxyImageVersion}, logger)
 	}
 
-	return nil
+	return cfg, nil
 }
 
-func (c *DefaultIstioPerformer) Version(workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (IstioStatus, error) {
-	targetVersion, err := getTargetVersionFromIstioChart(workspace, branchVersion, istioChart, logger)
+func (c *DefaultIstioPerformer) Version(context context.Context, workspace chart.Factory, branchVersion string, istioChart string, kubeConfig string, logger *zap.SugaredLogger) (IstioStatus, error) {
+	chartLoader := newIstioChartLoader(workspace, branchVersion, istioChart)
+	targetVersion, err := getTargetVersionFromIstioChart(chartLoader, logger)
+	if errors.Is(err, errTargetVersionNotFound) {
+		logger.Debug("Target Istio version not defined in chart, falling back to the istiod deployment image tag")
+		targetVersion, err = c.getTargetVersionFromIstiodDeployment(context, kubeConfig, logger)
+	}
 	if err != nil {
 		return IstioStatus{}, errors.Wrap(err, "Target Version could not be found")
 	}
 
-	targetPrefix, err := getTargetProxyV2PrefixFromIstioChart(workspace, branchVersion, istioChart, logger)
+	targetPrefix, err := getTargetProxyV2PrefixFromIstioChart(chartLoader, logger)
 	if err != nil {
 		return IstioStatus{}, errors.Wrap(err, "Target Prefix could not be found")
 	}
@@ -381,7 +1030,7 @@ func (c *DefaultIstioPerformer) Version(workspace chart.Factory, branchVersion s
 
 	commander, err := c.resolver.GetCommander(version)
 	if err != nil {
-		return IstioStatus{}, err
+		return IstioStatus{}, &UnsupportedVersionError{Version: version, SupportedVersions: c.resolver.SupportedVersions(), Err: err}
 	}
 
 	versionOutput, err := commander.Version(kubeConfig, logger)
@@ -390,22 +1039,80 @@ func (c *DefaultIstioPerformer) Version(workspace chart.Factory, branchVersion s
 	}
 
 	mappedIstioVersion, err := mapVersionToStruct(versionOutput, targetVersion, targetPrefix)
+	if err != nil || mappedIstioVersion.PilotVersion == "" {
+		return mappedIstioVersion, err
+	}
+
+	mappedIstioVersion.ManagedByReconciler = c.isManagedByReconciler(kubeConfig, logger)
 
-	return mappedIstioVersion, err
+	return mappedIstioVersion, nil
 }
 
-func getTargetVersionFromIstioChart(workspace chart.Factory, branch string, istioChart string, logger *zap.SugaredLogger) (string, error) {
-	ws, err := workspace.Get(branch)
+func (c *DefaultIstioPerformer) ClientVersion(workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) (IstioStatus, error) {
+	targetVersion, err := getTargetVersionFromIstioChart(newIstioChartLoader(workspace, branchVersion, istioChart), logger)
 	if err != nil {
-		return "", err
+		return IstioStatus{}, errors.Wrap(err, "Target Version could not be found")
+	}
+
+	version, err := istioctl.VersionFromString(targetVersion)
+	if err != nil {
+		return IstioStatus{}, errors.Wrap(err, "Error parsing version")
+	}
+
+	commander, err := c.resolver.GetCommander(version)
+	if err != nil {
+		return IstioStatus{}, &UnsupportedVersionError{Version: version, SupportedVersions: c.resolver.SupportedVersions(), Err: err}
+	}
+
+	versionOutput, err := commander.ClientVersion(logger)
+	if err != nil {
+		return IstioStatus{}, err
+	}
+
+	clientVersion, err := mapClientVersion(versionOutput)
+	if err != nil {
+		return IstioStatus{}, err
+	}
+
+	return IstioStatus{ClientVersion: clientVersion, TargetVersion: targetVersion}, nil
+}
+
+func (c *DefaultIstioPerformer) GetIstiodLeader(context context.Context, kubeConfig string, logger *zap.SugaredLogger) (IstiodLeaderDiagnostics, error) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return IstiodLeaderDiagnostics{}, err
 	}
 
-	istioHelmChart, err := loader.Load(filepath.Join(ws.ResourceDir, istioChart))
+	lease, err := kubeClient.CoordinationV1().Leases(c.namespace).Get(context, istiodLeaderLeaseName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("Istiod leader election lease %s/%s not found", c.namespace, istiodLeaderLeaseName)
+			return IstiodLeaderDiagnostics{LeaseName: istiodLeaderLeaseName}, nil
+		}
+		return IstiodLeaderDiagnostics{}, errors.Wrap(err, "Could not get istiod leader election lease")
+	}
+
+	diagnostics := IstiodLeaderDiagnostics{LeaseName: lease.Name}
+	if lease.Spec.HolderIdentity != nil {
+		diagnostics.HolderIdentity = *lease.Spec.HolderIdentity
+	}
+	if lease.Spec.RenewTime != nil {
+		renewTime := lease.Spec.RenewTime.Time
+		diagnostics.RenewTime = &renewTime
+	}
+	logger.Debugf("Istiod leader is %s", diagnostics.HolderIdentity)
+
+	return diagnostics, nil
+}
+
+func getTargetVersionFromIstioChart(chartLoader *istioChartLoader, logger *zap.SugaredLogger) (string, error) {
+	istioHelmChart, err := chartLoader.load()
 	if err != nil {
 		return "", err
 	}
 
-	pilotVersion, err := getTargetVersionFromPilotInChartValues(istioHelmChart)
+	pilotVersion, err := getTargetVersionFromPilotInChartValues(chartLoader)
 	if err != nil {
 		return "", err
 	}
@@ -421,21 +1128,16 @@ func getTargetVersionFromIstioChart(workspace chart.Factory, branch string, isti
 		return chartVersion, nil
 	}
 
-	return "", errors.New("Target Istio version could not be found neither in Chart.yaml nor in helm values")
+	return "", errTargetVersionNotFound
 }
 
-func getTargetProxyV2PrefixFromIstioChart(workspace chart.Factory, branch string, istioChart string, logger *zap.SugaredLogger) (string, error) {
-	ws, err := workspace.Get(branch)
+func getTargetProxyV2PrefixFromIstioChart(chartLoader *istioChartLoader, logger *zap.SugaredLogger) (string, error) {
+	_, err := chartLoader.load()
 	if err != nil {
 		return "", err
 	}
 
-	istioHelmChart, err := loader.Load(filepath.Join(ws.ResourceDir, istioChart))
-	if err != nil {
-		return "", err
-	}
-
-	istioValuesRegistryPath, istioValuesDirectory, err := getTargetProxyV2PrefixFromIstioValues(istioHelmChart)
+	istioValuesRegistryPath, istioValuesDirectory, err := getTargetProxyV2PrefixFromIstioValues(chartLoader)
 	if err != nil {
 		return "", errors.New("Could not resolve target proxyV2 Istio prefix from values")
 	}
@@ -449,14 +1151,8 @@ func getTargetVersionFromVersionInChartDefinition(helmChart *helmChart.Chart) st
 	return helmChart.Metadata.Version
 }
 
-func getTargetVersionFromPilotInChartValues(helmChart *helmChart.Chart) (string, error) {
-	mapAsJSON, err := json.Marshal(helmChart.Values)
-	if err != nil {
-		return "", err
-	}
-
-	var chartValues chartValues
-	err = json.Unmarshal(mapAsJSON, &chartValues)
+func getTargetVersionFromPilotInChartValues(chartLoader *istioChartLoader) (string, error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
 		return "", err
 	}
@@ -464,14 +1160,8 @@ func getTargetVersionFromPilotInChartValues(helmChart *helmChart.Chart) (string,
 	return chartValues.Global.Images.IstioPilot.Version, nil
 }
 
-func getTargetProxyV2PrefixFromIstioValues(istioHelmChart *helmChart.Chart) (string, string, error) {
-	mapAsJSON, err := json.Marshal(istioHelmChart.Values)
-	if err != nil {
-		return "", "", err
-	}
-	var chartValues chartValues
-
-	err = json.Unmarshal(mapAsJSON, &chartValues)
+func getTargetProxyV2PrefixFromIstioValues(chartLoader *istioChartLoader) (string, string, error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
 		return "", "", err
 	}
@@ -497,6 +1187,14 @@ func getVersionFromJSON(versionType VersionType, json IstioVersionOutput) string
 
 func getUniqueVersionsFromJSON(versionType VersionType, json IstioVersionOutput) map[string]bool {
 	switch versionType {
+	case "pilot":
+		versions := map[string]bool{}
+		for _, component := range json.MeshVersion {
+			if component.Info != nil && component.Info.Version != "" {
+				versions[component.Info.Version] = true
+			}
+		}
+		return versions
 	case "dataPlane":
 		if len(json.DataPlaneVersion) > 0 {
 			versions := map[string]bool{}
@@ -513,6 +1211,33 @@ func getUniqueVersionsFromJSON(versionType VersionType, json IstioVersionOutput)
 	}
 }
 
+func getVersionCountsFromJSON(json IstioVersionOutput) map[string]int {
+	counts := map[string]int{}
+	for _, dpVersion := range json.DataPlaneVersion {
+		counts[dpVersion.IstioVersion]++
+	}
+	return counts
+}
+
+// mapClientVersion returns the client version reported by the istioctl version command.
+func mapClientVersion(versionOutput []byte) (string, error) {
+	index := bytes.IndexRune(versionOutput, '{')
+	if index < 0 {
+		return "", errors.New("the result of the version command does not contain a version")
+	}
+
+	var version IstioVersionOutput
+	err := json.Unmarshal(versionOutput[index:], &version)
+	if err != nil {
+		return "", err
+	}
+	if version.ClientVersion == nil || version.ClientVersion.Version == "" {
+		return "", errors.New("the result of the version command does not contain a client version")
+	}
+
+	return version.ClientVersion.Version, nil
+}
+
 func mapVersionToStruct(versionOutput []byte, targetVersion string, targetDirectory string) (IstioStatus, error) {
 	if len(versionOutput) == 0 {
 		return IstioStatus{}, errors.New("the result of the version command is empty")
@@ -530,36 +1255,27 @@ func mapVersionToStruct(versionOutput []byte, targetVersion string, targetDirect
 	}
 
 	return IstioStatus{
-		ClientVersion:     getVersionFromJSON("client", version),
-		TargetVersion:     targetVersion,
-		TargetPrefix:      targetDirectory,
-		PilotVersion:      getVersionFromJSON("pilot", version),
-		DataPlaneVersions: getUniqueVersionsFromJSON("dataPlane", version),
+		ClientVersion:          getVersionFromJSON("client", version),
+		TargetVersion:          targetVersion,
+		TargetPrefix:           targetDirectory,
+		PilotVersion:           getVersionFromJSON("pilot", version),
+		PilotVersions:          getUniqueVersionsFromJSON("pilot", version),
+		DataPlaneVersions:      getUniqueVersionsFromJSON("dataPlane", version),
+		DataPlaneVersionCounts: getVersionCountsFromJSON(version),
 	}, nil
 }
 
-func isSidecarMigrationEnabled(workspace chart.Factory, branch string, istioChart string) (option bool, isSet bool, err error) {
-	ws, err := workspace.Get(branch)
+func isSidecarMigrationEnabled(chartLoader *istioChartLoader) (option bool, isSet bool, err error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
 		return false, false, err
 	}
+	option = chartValues.Global.SidecarMigration
 
-	istioHelmChart, err := loader.Load(filepath.Join(ws.ResourceDir, istioChart))
-	if err != nil {
-		return false, false, err
-	}
-
-	mapAsJSON, err := json.Marshal(istioHelmChart.Values)
-	if err != nil {
-		return false, false, err
-	}
-	var chartValues chartValues
-
-	err = json.Unmarshal(mapAsJSON, &chartValues)
+	mapAsJSON, err := chartLoader.rawValues()
 	if err != nil {
 		return false, false, err
 	}
-	option = chartValues.Global.SidecarMigration
 
 	isSet = false
 	var rawValues map[string]map[string]interface{}
@@ -577,47 +1293,114 @@ func isSidecarMigrationEnabled(workspace chart.Factory, branch string, istioChar
 }
 
 func IsSidecarInjectionNamespacesByDefaultEnabled(workspace chart.Factory, branch string, istioChart string) (enableNamespacesByDefault bool, err error) {
-	ws, err := workspace.Get(branch)
+	return isSidecarInjectionNamespacesByDefaultEnabled(newIstioChartLoader(workspace, branch, istioChart))
+}
+
+func isSidecarInjectionNamespacesByDefaultEnabled(chartLoader *istioChartLoader) (bool, error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
 		return false, err
 	}
 
-	istioHelmChart, err := loader.Load(filepath.Join(ws.ResourceDir, istioChart))
+	return chartValues.HelmValues.SidecarInjectorWebhook.EnableNamespacesByDefault, nil
+}
+
+// getExcludedNamespaces returns the namespaces which must never be labeled for sidecar injection, configured in the chart
+// values under helmValues.sidecarInjectorWebhook.excludedNamespaces. Only kube-system is excluded if the value is not set.
+func getExcludedNamespaces(chartLoader *istioChartLoader) (map[string]bool, error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	namespaces := chartValues.HelmValues.SidecarInjectorWebhook.ExcludedNamespaces
+	if len(namespaces) == 0 {
+		namespaces = defaultExcludedNamespaces
+	}
+	excludedNamespaces := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		excludedNamespaces[namespace] = true
 	}
 
-	mapAsJSON, err := json.Marshal(istioHelmChart.Values)
+	return excludedNamespaces, nil
+}
+
+// getNamespaceInjectionOverrides returns the injection label values istioChart configures for namespace patterns.
+func getNamespaceInjectionOverrides(chartLoader *istioChartLoader) (namespaceInjectionOverrides, error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	var chartValues chartValues
 
-	err = json.Unmarshal(mapAsJSON, &chartValues)
+	return newNamespaceInjectionOverrides(chartValues.Global.NamespaceInjection)
+}
+
+// getNamespaceLabels returns the labels istioChart configures to be applied to labeled namespaces in addition to the injection label.
+func getNamespaceLabels(chartLoader *istioChartLoader) (map[string]string, error) {
+	chartValues, err := chartLoader.chartValues()
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	enableNamespacesByDefault = chartValues.HelmValues.SidecarInjectorWebhook.EnableNamespacesByDefault
 
-	return enableNamespacesByDefault, nil
+	namespaceLabels := make(map[string]string, len(chartValues.Global.NamespaceLabels)+1)
+	for key, value := range chartValues.Global.NamespaceLabels {
+		namespaceLabels[key] = value
+	}
+
+	return namespaceLabels, nil
 }
 
-func getInstalledIstioVersion(provider clientset.Provider, kubeConfig string, gatherer data.Gatherer, logger *zap.SugaredLogger) (string, error) {
+// newNamespaceLabelPatch returns a merge patch setting the given labels on a namespace.
+func newNamespaceLabelPatch(labels map[string]string) ([]byte, error) {
+	patch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	}
+	return json.Marshal(patch)
+}
+
+// getInstalledIstioVersion reads the installed Istio version from the istiod pods. If that fails, e.g. because the pods are
+// not labeled as expected, it falls back to the pilot version reported by istioctl.
+func getInstalledIstioVersion(provider clientset.Provider, kubeConfig string, gatherer data.Gatherer, commander istioctl.Commander, retryOpts []avastretry.Option, logger *zap.SugaredLogger) (string, error) {
 	kubeClient, err := provider.RetrieveFrom(kubeConfig, logger)
 	if err != nil {
 		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
 		return "", err
 	}
-	retryOpts := []avastretry.Option{
-		avastretry.Delay(delayBetweenRetries),
-		avastretry.Attempts(uint(retriesCount)),
-		avastretry.DelayType(avastretry.FixedDelay),
+
+	version, gathererErr := gatherer.GetInstalledIstioVersion(kubeClient, retryOpts, logger)
+	if gathererErr == nil {
+		return version, nil
 	}
 
-	version, err := gatherer.GetInstalledIstioVersion(kubeClient, retryOpts, logger)
+	logger.Warnf("Could not get installed Istio version from istiod pods, falling back to istioctl: %v", gathererErr)
+	version, err = getPilotVersionFromIstioctl(commander, kubeConfig, logger)
 	if err != nil {
-		return "", err
+		return "", errors.Errorf("Could not get installed Istio version from istiod pods: %v, nor from istioctl: %v", gathererErr, err)
 	}
 
 	return version, nil
 }
+
+func getPilotVersionFromIstioctl(commander istioctl.Commander, kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+	versionOutput, err := commander.Version(kubeConfig, logger)
+	if err != nil {
+		return "", err
+	}
+
+	istioStatus, err := mapVersionToStruct(versionOutput, "", "")
+	if err != nil {
+		return "", err
+	}
+	if istioStatus.PilotVersion == "" {
+		return "", errors.New("istioctl did not report a pilot version")
+	}
+
+	pilotVersion, err := istioctl.VersionFromString(istioStatus.PilotVersion)
+	if err != nil {
+		return "", err
+	}
+
+	return pilotVersion.MajorMinorPatch(), nil
+}
@@ -6,9 +6,11 @@ import (
 
 	"github.com/kyma-project/istio/operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	testutils "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions/test-utils"
@@ -60,19 +62,19 @@ func Test_DefaultIstioPerfomer_UpdateIGRestart(t *testing.T) {
 		require.NoError(t, err)
 
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
 		provider.On("GetIstioClient", mock.Anything).Return(ctrlClientSameConfig, nil)
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
 		gatherer := datamocks.Gatherer{}
 		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.NoError(t, err)
@@ -103,19 +105,19 @@ func Test_DefaultIstioPerfomer_UpdateIGRestart(t *testing.T) {
 		require.NoError(t, err)
 
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
 		provider.On("GetIstioClient", mock.Anything).Return(ctrlClientDiffConfig, nil)
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
 		gatherer := datamocks.Gatherer{}
 		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.NoError(t, err)
@@ -125,4 +127,150 @@ func Test_DefaultIstioPerfomer_UpdateIGRestart(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, dep.Spec.Template.Annotations["reconciler.kyma-project.io/lastRestartDate"])
 	})
+
+	t.Run("should not restart IG when its certificate secrets are missing", func(t *testing.T) {
+		// given
+		ctrlClient := testutils.GetIGClient(t, TestConfigMap)
+		mountCertificateSecret(t, ctrlClient, "istio-ingressgateway-certs")
+		numTrustedProxies := 2
+
+		istioCR := &v1alpha1.Istio{ObjectMeta: metav1.ObjectMeta{
+			Name:      "istio-test",
+			Namespace: "namespace",
+		},
+			Spec: v1alpha1.IstioSpec{
+				Config: v1alpha1.Config{
+					NumTrustedProxies: &numTrustedProxies,
+				},
+			},
+		}
+
+		err := ctrlClient.Create(context.TODO(), istioCR)
+		require.NoError(t, err)
+
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+
+		dep := appsv1.Deployment{}
+		err = ctrlClient.Get(context.TODO(), types.NamespacedName{Namespace: testutils.DepNamespace, Name: testutils.DepName}, &dep)
+		require.NoError(t, err)
+		require.Empty(t, dep.Spec.Template.Annotations["reconciler.kyma-project.io/lastRestartDate"])
+	})
+
+	t.Run("should restart IG when its certificate secrets are present", func(t *testing.T) {
+		// given
+		ctrlClient := testutils.GetIGClient(t, TestConfigMap)
+		mountCertificateSecret(t, ctrlClient, "istio-ingressgateway-certs")
+		numTrustedProxies := 2
+
+		istioCR := &v1alpha1.Istio{ObjectMeta: metav1.ObjectMeta{
+			Name:      "istio-test",
+			Namespace: "namespace",
+		},
+			Spec: v1alpha1.IstioSpec{
+				Config: v1alpha1.Config{
+					NumTrustedProxies: &numTrustedProxies,
+				},
+			},
+		}
+
+		err := ctrlClient.Create(context.TODO(), istioCR)
+		require.NoError(t, err)
+
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		certSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "istio-ingressgateway-certs", Namespace: "istio-system"}}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(certSecret, newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+
+		dep := appsv1.Deployment{}
+		err = ctrlClient.Get(context.TODO(), types.NamespacedName{Namespace: testutils.DepNamespace, Name: testutils.DepName}, &dep)
+		require.NoError(t, err)
+		require.NotEmpty(t, dep.Spec.Template.Annotations["reconciler.kyma-project.io/lastRestartDate"])
+	})
+
+	t.Run("should not restart IG and report pending restart when the restart is skipped", func(t *testing.T) {
+		// given
+		ctrlClientDiffConfig := testutils.GetIGClient(t, TestConfigMap)
+		numTrustedProxies := 2
+
+		istioCR := &v1alpha1.Istio{ObjectMeta: metav1.ObjectMeta{
+			Name:      "istio-test",
+			Namespace: "namespace",
+		},
+			Spec: v1alpha1.IstioSpec{
+				Config: v1alpha1.Config{
+					NumTrustedProxies: &numTrustedProxies,
+				},
+			},
+		}
+
+		err := ctrlClientDiffConfig.Create(context.TODO(), istioCR)
+		require.NoError(t, err)
+
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClientDiffConfig, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err = wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", true, log)
+
+		// then
+		var restartPendingErr *IngressGatewayRestartPendingError
+		require.ErrorAs(t, err, &restartPendingErr)
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+
+		dep := appsv1.Deployment{}
+		err = ctrlClientDiffConfig.Get(context.TODO(), types.NamespacedName{Namespace: testutils.DepNamespace, Name: testutils.DepName}, &dep)
+		require.NoError(t, err)
+		require.Empty(t, dep.Spec.Template.Annotations["reconciler.kyma-project.io/lastRestartDate"])
+	})
+}
+
+func mountCertificateSecret(t *testing.T, ctrlClient client.Client, secretName string) {
+	dep := appsv1.Deployment{}
+	err := ctrlClient.Get(context.TODO(), types.NamespacedName{Namespace: testutils.DepNamespace, Name: testutils.DepName}, &dep)
+	require.NoError(t, err)
+	dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name:         "ingressgateway-certs",
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: secretName}},
+	})
+	err = ctrlClient.Update(context.TODO(), &dep)
+	require.NoError(t, err)
 }
@@ -3,7 +3,10 @@ package actions
 import (
 	"context"
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/protobuf/types/known/wrapperspb"
 	operatorv1alpha1 "istio.io/api/operator/v1alpha1"
@@ -13,16 +16,24 @@ import (
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
 	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
+	avastretry "github.com/avast/retry-go"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
 	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	istioConfig "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
 	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	istioProxy "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
 	"github.com/kyma-project/istio/operator/api/v1alpha1"
@@ -114,6 +125,42 @@ metadata:
 		  }
 		]
 	  }`
+
+	istioctlMockMultiRevisionVersion = `{
+		"clientVersion": {
+		  "version": "1.12.0"
+		},
+		"meshVersion": [
+		  {
+			"Component": "pilot",
+			"Revision": "default",
+			"Info": {
+			  "version": "1.11.1"
+			}
+		  },
+		  {
+			"Component": "pilot",
+			"Revision": "canary",
+			"Info": {
+			  "version": "1.12.0"
+			}
+		  }
+		],
+		"dataPlaneVersion": [
+		  {
+			"ID": "first.default",
+			"IstioVersion": "1.11.1"
+		  },
+		  {
+			"ID": "second.default",
+			"IstioVersion": "1.11.1"
+		  },
+		  {
+			"ID": "third.canary",
+			"IstioVersion": "1.12.0"
+		  }
+		]
+	  }`
 )
 
 func Test_DefaultIstioPerformer_Install(t *testing.T) {
@@ -138,7 +185,7 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Install(context.TODO(), kubeConfig, "", "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, "", "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
@@ -148,7 +195,7 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 	t.Run("should not install when istio operator could not be found in manifest", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -157,18 +204,18 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Install(context.TODO(), kubeConfig, "", "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, "", "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
-		cmder.AssertNotCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should not install Istio when istioctl returned an error", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 		proxy := proxymocks.IstioProxyReset{}
@@ -179,40 +226,173 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "istioctl error")
-		cmder.AssertCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should install Istio when istioctl command was successful", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
 		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should pass revision to istioctl install", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), "canary", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("canary")), nil)
 		gatherer := datamocks.Gatherer{}
 		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "canary", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), "canary", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should return timeout error when istioctl install exceeds the apply timeout", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(errors.New("istioctl was stopped"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).
+			WithApplyTimeout(10 * time.Millisecond)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		var timeoutErr *ApplyTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+		gatherer.AssertNotCalled(t, "GetInstalledIstioVersion", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not return timeout error when the reconciliation context was cancelled", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(context.Canceled)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).
+			WithApplyTimeout(time.Minute)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		// when
+		err := wrapper.Install(ctx, kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		var timeoutErr *ApplyTimeoutError
+		require.False(t, errors.As(err, &timeoutErr))
+		require.Contains(t, err.Error(), "Error occurred when calling istioctl")
+	})
+
+	t.Run("should not install Istio when schema validation is enabled and Istio Operator has unknown fields", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithIstioOperatorSchemaValidation(true)
+		invalidIstioManifest := `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: namespace
+  name: name
+spec:
+  profil: default
+`
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, invalidIstioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown field spec.profil")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should install Istio when schema validation is enabled and Istio Operator is valid", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithIstioOperatorSchemaValidation(true)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should fail when installed Istio version do not match target version", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -224,190 +404,1265 @@ func Test_DefaultIstioPerformer_Install(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Installed Istio version: 1.2.2 do not match target version: 1.2.3")
-		cmder.AssertCalled(t, "Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
-}
 
-func Test_DefaultIstioPerformer_Uninstall(t *testing.T) {
-	kc := &mocks.Client{}
-	kc.On("Kubeconfig").Return("kubeconfig")
-	kc.On("Clientset").Return(fake.NewSimpleClientset(&corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
-	}), nil)
-	log := logger.NewLogger(false)
+	t.Run("should roll back the installation when installed Istio version do not match target version and rollback is enabled", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Uninstall", mock.Anything, kubeConfig, "1-2-3", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
-	t.Run("should not uninstall when istio version could not be resolved", func(t *testing.T) {
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.2", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "1-2-3", true, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Installed Istio version: 1.2.2 do not match target version: 1.2.3")
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, kubeConfig, "1-2-3", mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should return the version mismatch when the rollback of the installation fails", func(t *testing.T) {
 		// given
-		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Uninstall", mock.Anything, kubeConfig, "", mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("uninstall error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
 		gatherer := datamocks.Gatherer{}
-		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.2", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Uninstall(kc, "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", true, log)
 
 		// then
 		require.Error(t, err)
-		require.Equal(t, "istioctl not found", err.Error())
+		require.Contains(t, err.Error(), "Installed Istio version: 1.2.2 do not match target version: 1.2.3")
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, kubeConfig, "", mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
-	t.Run("should not uninstall Istio when istioctl returned an error", func(t *testing.T) {
+	t.Run("should not roll back the installation when installed Istio version do not match target version and rollback is disabled", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
 		gatherer := datamocks.Gatherer{}
-		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.2", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Uninstall(kc, "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "istioctl error")
-		cmder.AssertCalled(t, "Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertNotCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail when istiod did not become available after install", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newIstiodWithAvailableReplicas("", 1, 0)), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).
+			WithPerformerConfig(PerformerConfig{Timeout: 50 * time.Millisecond, Interval: 10 * time.Millisecond})
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		var notAvailableErr *IstiodNotAvailableError
+		require.ErrorAs(t, err, &notAvailableErr)
+		require.Contains(t, err.Error(), "did not become available within 50ms, 0 of 1 replicas available")
+	})
+
+	t.Run("should install the merged Istio Operator when the manifest contains a base and an overlay", func(t *testing.T) {
+		// given
+		baseAndOverlayManifest := `apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: namespace
+  name: base
+spec:
+  hub: base-hub
+  components:
+    pilot:
+      enabled: true
+---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: namespace
+  name: base
+spec:
+  hub: overlay-hub
+`
+		isMergedOperator := mock.MatchedBy(func(operator string) bool {
+			return strings.Contains(operator, `"hub":"overlay-hub"`) && strings.Contains(operator, `"pilot":{"enabled":true}`)
+		})
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, isMergedOperator, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, baseAndOverlayManifest, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Install", mock.Anything, isMergedOperator, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should install Istio when the installed version could only be read from istioctl", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("", errors.New("istiod pods not labeled"))
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.11.1", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_DefaultIstioPerformer_IstioctlLogOutputLevel(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	kc := &mocks.Client{}
+	kc.On("Kubeconfig").Return("kubeconfig")
+
+	withLevel := func(level string) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool {
+			return istioctl.LogOutputLevel(ctx) == level
+		})
+	}
+
+	t.Run("should pass the configured log output level to istioctl install", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		require.NoError(t, v1alpha1.AddToScheme(scheme.Scheme))
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(controllerfake.NewClientBuilder().WithScheme(scheme.Scheme).Build(), nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{}).
+			WithIstioctlLogOutputLevel("default:debug")
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		cmder.AssertCalled(t, "Install", withLevel("default:debug"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should pass the configured log output level to istioctl uninstall", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithUninstallRetry(1, 0).
+			WithIstioctlLogOutputLevel("debug")
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		cmder.AssertCalled(t, "Uninstall", withLevel("debug"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should prefer the log output level of the context over the configured one", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithUninstallRetry(1, 0).
+			WithIstioctlLogOutputLevel("debug")
+
+		// when
+		err := wrapper.Uninstall(istioctl.WithLogOutputLevel(context.TODO(), "warn"), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		cmder.AssertCalled(t, "Uninstall", withLevel("warn"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should keep the istioctl default log output level when none is configured", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{cmder: &cmder}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithUninstallRetry(1, 0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		cmder.AssertCalled(t, "Uninstall", withLevel(""), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
+func Test_DefaultIstioPerformer_Uninstall(t *testing.T) {
+	kc := &mocks.Client{}
+	kc.On("Kubeconfig").Return("kubeconfig")
+	kc.On("Clientset").Return(fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+	}), nil)
+	log := logger.NewLogger(false)
+
+	t.Run("should not uninstall when istio version could not be resolved", func(t *testing.T) {
+		// given
+		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, "istioctl not found", err.Error())
+	})
+
+	t.Run("should not uninstall Istio when istioctl returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		var wrapper IstioPerformer = NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).WithUninstallRetry(2, 0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		cmder.AssertNumberOfCalls(t, "Uninstall", 2)
+	})
+
+	t.Run("should uninstall Istio when istioctl succeeded after a transient failure", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("transient error")).Once()
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil).Once()
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		kubeClient := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+		})
+		transientKc := &mocks.Client{}
+		transientKc.On("Kubeconfig").Return("kubeconfig")
+		transientKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).WithUninstallRetry(3, 0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), transientKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertNumberOfCalls(t, "Uninstall", 2)
+	})
+
+	t.Run("should delete istio-system namespace after the configured grace period", func(t *testing.T) {
+		// given
+		var uninstalledAt, deletedAt time.Time
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil).
+			Run(func(args mock.Arguments) { uninstalledAt = time.Now() })
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		kubeClient := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+		})
+		kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			deletedAt = time.Now()
+			return false, nil, nil
+		})
+		graceKc := &mocks.Client{}
+		graceKc.On("Kubeconfig").Return("kubeconfig")
+		graceKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(100 * time.Millisecond)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), graceKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		require.False(t, uninstalledAt.IsZero())
+		require.GreaterOrEqual(t, deletedAt.Sub(uninstalledAt), 100*time.Millisecond)
+	})
+
+	t.Run("should stop waiting for the grace period when the context is cancelled", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		cancelKc := &mocks.Client{}
+		cancelKc.On("Kubeconfig").Return("kubeconfig")
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// when
+		err := wrapper.Uninstall(ctx, cancelKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, uninstallGracePeriod, wrapper.uninstallGracePeriod)
+		cancelKc.AssertNotCalled(t, "Clientset")
+	})
+
+	t.Run("should delete istio-system namespace when deletion succeeded after a transient failure", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		kubeClient := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+		})
+		deleteCalls := 0
+		kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			deleteCalls++
+			if deleteCalls == 1 {
+				return true, nil, errors.New("transient error")
+			}
+			return false, nil, nil
+		})
+		transientKc := &mocks.Client{}
+		transientKc.On("Kubeconfig").Return("kubeconfig")
+		transientKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).WithUninstallRetry(3, 0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), transientKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, deleteCalls)
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("should uninstall Istio when istioctl command was successful", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), kc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should delete sidecar injector webhook configurations left behind after the namespace was deleted", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		kubeClient := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+		})
+		kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			_ = kubeClient.Tracker().Add(&admissionv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"}})
+			_ = kubeClient.Tracker().Add(&admissionv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-validator-istio-system"}})
+			return false, nil, nil
+		})
+		staleKc := &mocks.Client{}
+		staleKc.On("Kubeconfig").Return("kubeconfig")
+		staleKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), staleKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), "istio-sidecar-injector", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+		_, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), "istio-validator-istio-system", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("should delete the sidecar injector webhook configuration of the uninstalled revision", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		kubeClient := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-system"},
+		})
+		kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			_ = kubeClient.Tracker().Add(&admissionv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector-canary"}})
+			_ = kubeClient.Tracker().Add(&admissionv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-validator-canary-istio-system"}})
+			return false, nil, nil
+		})
+		staleKc := &mocks.Client{}
+		staleKc.On("Kubeconfig").Return("kubeconfig")
+		staleKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), staleKc, "1.2.3", "canary", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		_, err = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.TODO(), "istio-sidecar-injector-canary", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+		_, err = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.TODO(), "istio-validator-canary-istio-system", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("should delete istio-system namespace only after istiod and istio webhooks are removed", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"}},
+			&admissionv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"}},
+			&admissionv1.ValidatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-validator-istio-system"}},
+		)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil).
+			Run(func(args mock.Arguments) {
+				go func() {
+					time.Sleep(50 * time.Millisecond)
+					_ = kubeClient.AppsV1().Deployments("istio-system").Delete(context.TODO(), "istiod", metav1.DeleteOptions{})
+					_ = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(context.TODO(), "istio-sidecar-injector", metav1.DeleteOptions{})
+					_ = kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), "istio-validator-istio-system", metav1.DeleteOptions{})
+				}()
+			})
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		var remainingOnDelete []string
+		kubeClient.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			remainingOnDelete, _ = getRemainingIstioResources(context.TODO(), kubeClient, "istio-system", "")
+			return false, nil, nil
+		})
+		waitKc := &mocks.Client{}
+		waitKc.On("Kubeconfig").Return("kubeconfig")
+		waitKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).WithUninstallWait(time.Second, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), waitKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, remainingOnDelete)
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("should not delete istio-system namespace when istio webhooks are not removed in time", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&admissionv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"}},
+		)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		timeoutKc := &mocks.Client{}
+		timeoutKc.On("Kubeconfig").Return("kubeconfig")
+		timeoutKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).WithUninstallWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), timeoutKc, "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio uninstall did not complete within 50ms")
+		require.Contains(t, err.Error(), "MutatingWebhookConfiguration istio-sidecar-injector")
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep istio-system namespace when other revisions remain after uninstalling a revision", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod-1-11-4", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+		)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), "1-10-2", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		revisionKc := &mocks.Client{}
+		revisionKc.On("Kubeconfig").Return("kubeconfig")
+		revisionKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), revisionKc, "1.2.3", "1-10-2", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), "1-10-2", mock.AnythingOfType("*zap.SugaredLogger"))
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("should delete istio-system namespace when the uninstalled revision was the last one", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod-1-10-2", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+		)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), "1-10-2", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil).
+			Run(func(args mock.Arguments) {
+				_ = kubeClient.AppsV1().Deployments("istio-system").Delete(context.TODO(), "istiod-1-10-2", metav1.DeleteOptions{})
+			})
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		revisionKc := &mocks.Client{}
+		revisionKc.On("Kubeconfig").Return("kubeconfig")
+		revisionKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), revisionKc, "1.2.3", "1-10-2", "istio-system", log)
+
+		// then
+		require.NoError(t, err)
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("should delete the given namespace instead of istio-system", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "custom-istio"}},
+		)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		namespaceKc := &mocks.Client{}
+		namespaceKc.On("Kubeconfig").Return("kubeconfig")
+		namespaceKc.On("Clientset").Return(kubeClient, nil)
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0)
+
+		// when
+		err := wrapper.Uninstall(context.TODO(), namespaceKc, "1.2.3", "", "custom-istio", log)
+
+		// then
+		require.NoError(t, err)
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "custom-istio", metav1.GetOptions{})
+		require.True(t, kerrors.IsNotFound(err))
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func Test_DefaultIstioPerformer_Reinstall(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should not install Istio when uninstall failed", func(t *testing.T) {
+		// given
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).WithUninstallRetry(1, 0)
+
+		// when
+		err := wrapper.Reinstall(context.TODO(), kc, "", "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "istioctl error")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.Anything)
+	})
+
+	t.Run("should not install Istio when old istiod pods did not terminate in time", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "istiod-old", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+		)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).
+			WithIstiodTerminationWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Reinstall(context.TODO(), kc, "", "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Old istiod pods did not terminate")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.Anything)
+	})
+
+	t.Run("should wait for the deleted namespace before installing Istio again", func(t *testing.T) {
+		// given
+		kubeClient, polls := newTerminatingNamespaceClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}}, 3)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).
+			WithIstiodTerminationWait(time.Second, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Reinstall(context.TODO(), kc, "", "1.2.3", "", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
+		require.Equal(t, 4, *polls)
+	})
+
+	t.Run("should not install Istio when the deleted namespace lingers", func(t *testing.T) {
+		// given
+		kubeClient, _ := newTerminatingNamespaceClient(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}}, -1)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).
+			WithIstiodTerminationWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Reinstall(context.TODO(), kc, "", "1.2.3", "", "istio-system", log)
+
+		// then
+		require.EqualError(t, err, "Namespace istio-system was not deleted within 50ms")
+		cmder.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.Anything)
+	})
+
+	t.Run("should reinstall only the given revision and keep the namespace of the other revisions", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "istio-system"}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "istiod-default", Namespace: "istio-system", Labels: map[string]string{"app": "istiod", "istio.io/rev": "default"}}},
+		)
+		kc := &mocks.Client{}
+		kc.On("Kubeconfig").Return("kubeconfig")
+		kc.On("Clientset").Return(kubeClient, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Uninstall", mock.Anything, mock.AnythingOfType("string"), "canary", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).WithUninstallGracePeriod(0).
+			WithIstiodTerminationWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Reinstall(context.TODO(), kc, "", "1.2.3", "canary", "istio-system", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Operator definition could not be found")
+		cmder.AssertCalled(t, "Uninstall", mock.Anything, mock.AnythingOfType("string"), "canary", mock.AnythingOfType("*zap.SugaredLogger"))
+		_, err = kubeClient.CoreV1().Namespaces().Get(context.TODO(), "istio-system", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+}
+
+func Test_DefaultIstioPerformer_waitForIstiodTermination(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should wait until lingering istiod pods are terminated", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		listCalls := 0
+		kubeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			listCalls++
+			if listCalls < 3 {
+				return true, &corev1.PodList{Items: []corev1.Pod{
+					{ObjectMeta: metav1.ObjectMeta{Name: "istiod-old", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+				}}, nil
+			}
+			return false, nil, nil
+		})
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithIstiodTerminationWait(time.Second, 10*time.Millisecond)
+
+		// when
+		err := wrapper.waitForIstiodTermination(context.TODO(), kubeClient, "istio-system", "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 3, listCalls)
+	})
+
+	t.Run("should return immediately when there are no istiod pods", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "istio-system", Labels: map[string]string{"app": "other"}}},
+		)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithIstiodTerminationWait(time.Second, 10*time.Millisecond)
+
+		// when
+		err := wrapper.waitForIstiodTermination(context.TODO(), kubeClient, "istio-system", "", log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should only wait for istiod pods in the given namespace", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "istiod-old", Namespace: "istio-system", Labels: map[string]string{"app": "istiod"}}},
+		)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithIstiodTerminationWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.waitForIstiodTermination(context.TODO(), kubeClient, "custom-istio", "", log)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should only wait for istiod pods of the given revision", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "istiod-default", Namespace: "istio-system", Labels: map[string]string{"app": "istiod", "istio.io/rev": "default"}}},
+		)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{}).
+			WithIstiodTerminationWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.waitForIstiodTermination(context.TODO(), kubeClient, "istio-system", "canary", log)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_DefaultIstioPerformer_LabelNamespaces(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should not label namespaces when sidecar migration value doesn't exist", func(t *testing.T) {
+		// given
+		namespace := "test"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-empty"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, got.Labels, "istio-injection")
+	})
+
+	t.Run("should not label namespaces when sidecar migration is disabled", func(t *testing.T) {
+		// given
+		namespace := "test"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-disabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, got.Labels, "istio-injection")
+	})
+
+	t.Run("should label namespaces when sidecar migration is enabled", func(t *testing.T) {
+		// given
+		namespace := "test"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, got.Labels, "istio-injection")
+		require.Equal(t, "enabled", got.Labels["istio-injection"])
+	})
+
+	t.Run("should label namespaces with injection label and additional labels of the chart", func(t *testing.T) {
+		// given
+		namespace := "test"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled-namespace-labels"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "enabled", got.Labels["istio-injection"])
+		require.Equal(t, "reconciler", got.Labels["kyma-project.io/managed-by"])
+		require.Equal(t, "mesh", got.Labels["team"])
+	})
+
+	t.Run("should label namespaces with revision label and additional labels of the chart", func(t *testing.T) {
+		// given
+		namespace := "test"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled-namespace-labels"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "canary", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "canary", got.Labels["istio.io/rev"])
+		require.NotContains(t, got.Labels, "istio-injection")
+		require.Equal(t, "reconciler", got.Labels["kyma-project.io/managed-by"])
+	})
+
+	t.Run("should not label kube-system namespace when sidecar migration is enabled", func(t *testing.T) {
+		// given
+		namespace := "kube-system"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotContains(t, got.Labels, "istio-injection")
+	})
+
+	t.Run("should not label namespace with user created label when sidecar migration is enabled", func(t *testing.T) {
+		// given
+		namespace := "user-ns"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespaceWithLabel(namespace, map[string]string{"istio-injection": "disabled"}))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Contains(t, got.Labels, "istio-injection")
+		require.Equal(t, "disabled", got.Labels["istio-injection"])
+	})
+
+	t.Run("should not label namespaces excluded in the chart values", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			createNamespace("kube-system"),
+			createNamespace("kube-public"),
+			createNamespace("kube-node-lease"),
+			createNamespace("monitoring"),
+			createNamespace("default"),
+			createNamespace("user-ns"),
+		)
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled-excluded-namespaces"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
+
+		// then
+		namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: "istio-injection=enabled"})
+		require.NoError(t, err)
+		var labeled []string
+		for _, namespace := range namespaces.Items {
+			labeled = append(labeled, namespace.Name)
+		}
+		require.ElementsMatch(t, []string{"default", "user-ns"}, labeled)
+	})
+
+	t.Run("should label namespaces with revision when revision is given", func(t *testing.T) {
+		// given
+		namespace := "test"
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "canary", log)
+		require.NoError(t, err)
+
+		// then
+		var patches []string
+		for _, action := range clientset.Actions() {
+			if patchAction, ok := action.(k8stesting.PatchAction); ok {
+				patches = append(patches, string(patchAction.GetPatch()))
+			}
+		}
+		require.Equal(t, []string{`{"metadata": {"labels": {"istio.io/rev": "canary"}}}`}, patches)
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "canary", got.Labels["istio.io/rev"])
+		require.NotContains(t, got.Labels, "istio-injection")
+	})
+
+	t.Run("should not label namespaces with revision when they are already labeled", func(t *testing.T) {
+		// given
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			createNamespaceWithLabel("same-revision", map[string]string{"istio.io/rev": "canary"}),
+			createNamespaceWithLabel("injection-disabled", map[string]string{"istio-injection": "disabled"}),
+		)
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "canary", log)
+		require.NoError(t, err)
+
+		// then
+		for _, action := range clientset.Actions() {
+			require.NotEqual(t, "patch", action.GetVerb())
+		}
 	})
 
-	t.Run("should uninstall Istio when istioctl command was successful", func(t *testing.T) {
+	t.Run("should not label namespaces with istio-injection when they are already on a revision", func(t *testing.T) {
 		// given
-		cmder := istioctlmocks.Commander{}
-		cmder.On("Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
-
-		proxy := proxymocks.IstioProxyReset{}
-		provider := clientsetmocks.Provider{}
-		gatherer := datamocks.Gatherer{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		kubeClient := mocks.Client{}
+		clientset := fake.NewSimpleClientset(
+			createNamespaceWithLabel("on-revision", map[string]string{"istio.io/rev": "1-10-2"}),
+			createNamespace("unlabeled"),
+		)
+		kubeClient.On("Clientset").Return(clientset, nil)
+		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
+		istioChart := "istio-sidecar-enabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.Uninstall(kc, "1.2.3", log)
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
+		require.NoError(t, err)
 
 		// then
+		onRevision, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "on-revision", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"istio.io/rev": "1-10-2"}, onRevision.Labels)
+		unlabeled, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "unlabeled", metav1.GetOptions{})
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Uninstall", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		require.Equal(t, "enabled", unlabeled.Labels["istio-injection"])
 	})
 
-}
-
-func Test_DefaultIstioPerformer_LabelNamespaces(t *testing.T) {
-
-	log := logger.NewLogger(false)
-
-	t.Run("should not label namespaces when sidecar migration value doesn't exist", func(t *testing.T) {
+	t.Run("should label namespaces on another revision with the given revision", func(t *testing.T) {
 		// given
-		namespace := "test"
 		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		clientset := fake.NewSimpleClientset(createNamespaceWithLabel("other-revision", map[string]string{"istio.io/rev": "1-10-2"}))
 		kubeClient.On("Clientset").Return(clientset, nil)
 		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
-		istioChart := "istio-sidecar-empty"
+		istioChart := "istio-sidecar-enabled"
 		factory := &workspacemocks.Factory{}
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, log)
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "canary", log)
 		require.NoError(t, err)
 
 		// then
-		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "other-revision", metav1.GetOptions{})
 		require.NoError(t, err)
+		require.Equal(t, "canary", got.Labels["istio.io/rev"])
 		require.NotContains(t, got.Labels, "istio-injection")
 	})
 
-	t.Run("should not label namespaces when sidecar migration is disabled", func(t *testing.T) {
+	t.Run("should enable and disable injection of namespaces as configured per namespace pattern", func(t *testing.T) {
 		// given
-		namespace := "test"
 		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		clientset := fake.NewSimpleClientset(
+			createNamespace("legacy-billing"),
+			createNamespaceWithLabel("legacy-shop", map[string]string{"istio-injection": "enabled"}),
+			createNamespace("legacy-app"),
+			createNamespace("batch"),
+			createNamespace("user-ns"),
+			createNamespace("kube-system"),
+		)
 		kubeClient.On("Clientset").Return(clientset, nil)
 		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
-		istioChart := "istio-sidecar-disabled"
+		istioChart := "istio-sidecar-enabled-namespace-injection"
 		factory := &workspacemocks.Factory{}
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, log)
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
 		require.NoError(t, err)
 
 		// then
-		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		injectionPerNamespace := map[string]string{}
+		namespaces, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
 		require.NoError(t, err)
-		require.NotContains(t, got.Labels, "istio-injection")
+		for _, namespace := range namespaces.Items {
+			injectionPerNamespace[namespace.Name] = namespace.Labels["istio-injection"]
+		}
+		require.Equal(t, map[string]string{
+			"legacy-billing": "disabled",
+			"legacy-shop":    "disabled",
+			"legacy-app":     "enabled",
+			"batch":          "disabled",
+			"user-ns":        "enabled",
+			"kube-system":    "",
+		}, injectionPerNamespace)
 	})
 
-	t.Run("should label namespaces when sidecar migration is enabled", func(t *testing.T) {
+	t.Run("should disable injection of namespaces matching a pattern when a revision is given", func(t *testing.T) {
 		// given
-		namespace := "test"
 		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		clientset := fake.NewSimpleClientset(createNamespace("batch"), createNamespace("user-ns"))
 		kubeClient.On("Clientset").Return(clientset, nil)
 		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
-		istioChart := "istio-sidecar-enabled"
+		istioChart := "istio-sidecar-enabled-namespace-injection"
 		factory := &workspacemocks.Factory{}
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, log)
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "canary", log)
 		require.NoError(t, err)
 
 		// then
-		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+		batch, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "batch", metav1.GetOptions{})
 		require.NoError(t, err)
-		require.Contains(t, got.Labels, "istio-injection")
-		require.Equal(t, "enabled", got.Labels["istio-injection"])
+		require.Equal(t, map[string]string{"istio-injection": "disabled"}, batch.Labels)
+		userNamespace, err := clientset.CoreV1().Namespaces().Get(context.TODO(), "user-ns", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"istio.io/rev": "canary"}, userNamespace.Labels)
 	})
 
-	t.Run("should not label kube-system namespace when sidecar migration is enabled", func(t *testing.T) {
+	t.Run("should not patch namespaces whose injection is already disabled", func(t *testing.T) {
 		// given
-		namespace := "kube-system"
 		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createNamespace(namespace))
+		clientset := fake.NewSimpleClientset(createNamespaceWithLabel("batch", map[string]string{"istio-injection": "disabled"}))
 		kubeClient.On("Clientset").Return(clientset, nil)
 		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
-		istioChart := "istio-sidecar-enabled"
+		istioChart := "istio-sidecar-enabled-namespace-injection"
 		factory := &workspacemocks.Factory{}
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, log)
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
 		require.NoError(t, err)
 
 		// then
-		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
-		require.NoError(t, err)
-		require.NotContains(t, got.Labels, "istio-injection")
+		for _, action := range clientset.Actions() {
+			require.NotEqual(t, "patch", action.GetVerb())
+		}
 	})
 
-	t.Run("should not label namespace with user created label when sidecar migration is enabled", func(t *testing.T) {
+	t.Run("should fail when the injection label value of a namespace pattern is invalid", func(t *testing.T) {
 		// given
-		namespace := "user-ns"
 		kubeClient := mocks.Client{}
-		clientset := fake.NewSimpleClientset(createNamespaceWithLabel(namespace, map[string]string{"istio-injection": "disabled"}))
+		clientset := fake.NewSimpleClientset(createNamespace("legacy-billing"))
 		kubeClient.On("Clientset").Return(clientset, nil)
 		wrapper := NewDefaultIstioPerformer(nil, nil, nil, nil)
-		istioChart := "istio-sidecar-enabled"
+		istioChart := "istio-sidecar-enabled-invalid-namespace-injection"
 		factory := &workspacemocks.Factory{}
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, log)
-		require.NoError(t, err)
+		err := wrapper.LabelNamespaces(context.TODO(), &kubeClient, factory, "", istioChart, "", log)
 
 		// then
-		got, err := clientset.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
-		require.NoError(t, err)
-		require.Contains(t, got.Labels, "istio-injection")
-		require.Equal(t, "disabled", got.Labels["istio-injection"])
+		require.EqualError(t, err, "Invalid injection label value skip for namespace pattern legacy-*, expected enabled or disabled")
+		for _, action := range clientset.Actions() {
+			require.NotEqual(t, "patch", action.GetVerb())
+		}
 	})
 }
 
@@ -445,7 +1700,7 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, "", "1.2.3", log)
+		err := wrapper.Update(context.TODO(), kubeConfig, "", "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
@@ -455,7 +1710,7 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 	t.Run("should not update when istio operator could not be found in manifest", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -464,7 +1719,7 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, "", "1.2.3", log)
+		err := wrapper.Update(context.TODO(), kubeConfig, "", "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
@@ -475,7 +1730,7 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 	t.Run("should not update Istio when istioctl returned an error", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(errors.New("istioctl error"))
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -486,40 +1741,67 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "istioctl error")
-		cmder.AssertCalled(t, "Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should update Istio when istioctl command was successful", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
 		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
 		gatherer := datamocks.Gatherer{}
 		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.NoError(t, err)
-		cmder.AssertCalled(t, "Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should return timeout error when istioctl upgrade exceeds the apply timeout", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(errors.New("istioctl was stopped"))
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).
+			WithApplyTimeout(10 * time.Millisecond)
+
+		// when
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		var timeoutErr *ApplyTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
 	})
 
 	t.Run("should fail when updated Istio version do not match target version", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
@@ -531,12 +1813,12 @@ func Test_DefaultIstioPerformer_Update(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", log)
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifest, "1.2.3", "", false, log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Updated Istio version: 1.2.2 do not match target version: 1.2.3")
-		cmder.AssertCalled(t, "Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 }
@@ -554,7 +1836,7 @@ func Test_DefaultIstioPerformer_CNI_Merge(t *testing.T) {
 	t.Run("should apply CNI config enabled true during Install when kyma-istio-cni ConfigMap is set to true and operator manifest is set to false", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Install", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		cm := &corev1.ConfigMap{
@@ -566,7 +1848,7 @@ func Test_DefaultIstioPerformer_CNI_Merge(t *testing.T) {
 				"cniEnabled": "true",
 			},
 		}
-		client := fake.NewSimpleClientset(cm)
+		client := fake.NewSimpleClientset(cm, newAvailableIstiod(""))
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
@@ -577,18 +1859,18 @@ func Test_DefaultIstioPerformer_CNI_Merge(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Install(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", log)
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", "", false, log)
 
 		// then
 		require.NoError(t, err)
 		expectedManifest := "{\"kind\":\"IstioOperator\",\"apiVersion\":\"install.istio.io/v1alpha1\",\"metadata\":{\"name\":\"name\",\"namespace\":\"namespace\",\"creationTimestamp\":null},\"spec\":{\"components\":{\"cni\":{\"enabled\":true}}}}"
-		cmder.AssertCalled(t, "Install", expectedManifest, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cmder.AssertCalled(t, "Install", mock.Anything, expectedManifest, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 	})
 
 	t.Run("should apply CNI config enabled true during Update when kyma-istio-cni ConfigMap is set to true and operator manifest is set to false", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		cm := &corev1.ConfigMap{
@@ -600,7 +1882,7 @@ func Test_DefaultIstioPerformer_CNI_Merge(t *testing.T) {
 				"cniEnabled": "true",
 			},
 		}
-		client := fake.NewSimpleClientset(cm)
+		client := fake.NewSimpleClientset(cm, newAvailableIstiod(""))
 
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
@@ -611,159 +1893,442 @@ func Test_DefaultIstioPerformer_CNI_Merge(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", log)
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		expectedManifest := "{\"kind\":\"IstioOperator\",\"apiVersion\":\"install.istio.io/v1alpha1\",\"metadata\":{\"name\":\"name\",\"namespace\":\"namespace\",\"creationTimestamp\":null},\"spec\":{\"components\":{\"cni\":{\"enabled\":true}}}}"
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, expectedManifest, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should keep false value from manifest when kyma-istio-cni ConfigMap does not exist", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Upgrade", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newAvailableIstiod("")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		err := wrapper.Update(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		expectedManifest := "{\"kind\":\"IstioOperator\",\"apiVersion\":\"install.istio.io/v1alpha1\",\"metadata\":{\"name\":\"name\",\"namespace\":\"namespace\",\"creationTimestamp\":null},\"spec\":{\"components\":{\"cni\":{\"enabled\":false}}}}"
+		cmder.AssertCalled(t, "Upgrade", mock.Anything, expectedManifest, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+
+	t.Run("should pass CNI state to run", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{}, errors.New("Proxy reset error"))
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		iop := istioOperator.IstioOperator{
+			Spec: &operatorv1alpha1.IstioOperatorSpec{
+				Components: &operatorv1alpha1.IstioComponentSetSpec{
+					Cni: &operatorv1alpha1.ComponentSpec{
+						Enabled: wrapperspb.Bool(true),
+					},
+				},
+			},
+		}
+		scheme := runtime.NewScheme()
+		err := istioOperator.SchemeBuilder.AddToScheme(scheme)
+		require.NoError(t, err)
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme, &iop)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		proxyImageVersion := "1.2.0"
+		proxyImagePrefix := "anything"
+		istioChart := "istio-sidecar-disabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		_, err = wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, proxyImagePrefix, "", nil, ProxyResetOptions{}, log)
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Proxy reset error")
+	})
+
+}
+
+func Test_DefaultIstioPerformer_ResetProxy(t *testing.T) {
+
+	kubeConfig := "kubeconfig"
+	log := logger.NewLogger(false)
+	ctx := context.Background()
+	defer ctx.Done()
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		proxyImageVersion := "1.2.0"
+		istioChart := "istio-sidecar-disabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, "", "", nil, ProxyResetOptions{}, log)
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
+
+	t.Run("should return error when istio proxy reset returned an error", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 2, PodsFailed: 1}, errors.New("Proxy reset error"))
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		proxyImageVersion := "1.2.0"
+		proxyImagePrefix := "anything"
+		istioChart := "istio-sidecar-disabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		summary, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, proxyImagePrefix, "", nil, ProxyResetOptions{}, log)
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Proxy reset error")
+		require.Equal(t, istioProxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 2, PodsFailed: 1}, summary)
+	})
+
+	t.Run("should return no error when istio proxy reset was successful", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 3}, nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		proxyImageVersion := "1.2.0"
+		proxyImagePrefix := "anything"
+		istioChart := "istio-sidecar-disabled"
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		summary, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, proxyImagePrefix, "", nil, ProxyResetOptions{}, log)
+		// then
+		require.NoError(t, err)
+		require.Equal(t, istioProxy.ResetSummary{PodsConsidered: 3, PodsRestarted: 3}, summary)
+	})
+
+	t.Run("should pass configured retries and waits to istio proxy reset", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{}, nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer).
+			WithPerformerConfig(PerformerConfig{RetriesCount: 20, DelayBetweenRetries: 30 * time.Second})
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", "istio-sidecar-disabled", "1.2.0", "anything", "", nil, ProxyResetOptions{}, log)
+
+		// then
+		require.NoError(t, err)
+		cfg := proxy.Calls[0].Arguments.Get(0).(istioConfig.IstioProxyConfig)
+		require.Equal(t, 20, cfg.RetriesCount)
+		require.Equal(t, 30*time.Second, cfg.DelayBetweenRetries)
+		require.Equal(t, timeout, cfg.Timeout)
+		require.Equal(t, interval, cfg.Interval)
+	})
+
+	t.Run("should pass the proxy reset options to istio proxy reset", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{}, nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		unlistedNamespacesPriority := 0
+		options := ProxyResetOptions{ImageComparison: data.ImageComparisonDigest, ImageDigest: "sha256:abc", JobPodsHandling: data.JobPodsHandlingRestart,
+			NamespacePriority: []string{"kyma-system"}, UnlistedNamespacesPriority: &unlistedNamespacesPriority,
+			IncludeTerminatingPods: true}
+
+		// when
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", "istio-sidecar-disabled", "1.2.0", "anything", "", nil, options, log)
 
 		// then
 		require.NoError(t, err)
-		expectedManifest := "{\"kind\":\"IstioOperator\",\"apiVersion\":\"install.istio.io/v1alpha1\",\"metadata\":{\"name\":\"name\",\"namespace\":\"namespace\",\"creationTimestamp\":null},\"spec\":{\"components\":{\"cni\":{\"enabled\":true}}}}"
-		cmder.AssertCalled(t, "Upgrade", expectedManifest, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cfg := proxy.Calls[0].Arguments.Get(0).(istioConfig.IstioProxyConfig)
+		require.Equal(t, data.ImageComparisonDigest, cfg.ImageComparison)
+		require.Equal(t, "sha256:abc", cfg.ImageDigest)
+		require.Equal(t, data.JobPodsHandlingRestart, cfg.JobPodsHandling)
+		require.Equal(t, []string{"kyma-system"}, cfg.NamespacePriority)
+		require.Equal(t, &unlistedNamespacesPriority, cfg.UnlistedNamespacesPriority)
+		require.True(t, cfg.IncludeTerminatingPods)
+		gatherer.AssertNotCalled(t, "GetIstioCPPods", mock.Anything, mock.Anything)
 	})
 
-	t.Run("should keep false value from manifest when kyma-istio-cni ConfigMap does not exist", func(t *testing.T) {
+	t.Run("should resolve the proxy image digest from the istio control plane pods", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
-		cmder.On("Upgrade", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{}, nil)
 		provider := clientsetmocks.Provider{}
-		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
 		gatherer := datamocks.Gatherer{}
-		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		gatherer.On("GetIstioCPPods", mock.Anything, mock.Anything).Return(&corev1.PodList{Items: []corev1.Pod{{
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:    "istio-proxy",
+				Image:   "anything/proxyv2:1.2.0",
+				ImageID: "docker-pullable://anything/proxyv2@sha256:abc",
+			}}},
+		}}}, nil)
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err := wrapper.Update(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", log)
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", "istio-sidecar-disabled", "1.2.0", "anything", "", nil, ProxyResetOptions{ImageComparison: data.ImageComparisonDigest}, log)
 
 		// then
 		require.NoError(t, err)
-		expectedManifest := "{\"kind\":\"IstioOperator\",\"apiVersion\":\"install.istio.io/v1alpha1\",\"metadata\":{\"name\":\"name\",\"namespace\":\"namespace\",\"creationTimestamp\":null},\"spec\":{\"components\":{\"cni\":{\"enabled\":false}}}}"
-		cmder.AssertCalled(t, "Upgrade", expectedManifest, mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		cfg := proxy.Calls[0].Arguments.Get(0).(istioConfig.IstioProxyConfig)
+		require.Equal(t, data.ImageComparisonDigest, cfg.ImageComparison)
+		require.Equal(t, "sha256:abc", cfg.ImageDigest)
 	})
 
-	t.Run("should pass CNI state to run", func(t *testing.T) {
+	t.Run("should compare tags when the proxy image digest can not be resolved", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
 		proxy := proxymocks.IstioProxyReset{}
-		proxy.On("Run", mock.Anything).Return(errors.New("Proxy reset error"))
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{}, nil)
 		provider := clientsetmocks.Provider{}
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
 
-		iop := istioOperator.IstioOperator{
-			Spec: &operatorv1alpha1.IstioOperatorSpec{
-				Components: &operatorv1alpha1.IstioComponentSetSpec{
-					Cni: &operatorv1alpha1.ComponentSpec{
-						Enabled: wrapperspb.Bool(true),
-					},
-				},
-			},
-		}
-		scheme := runtime.NewScheme()
-		err := istioOperator.SchemeBuilder.AddToScheme(scheme)
-		require.NoError(t, err)
-		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme, &iop)
+		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
 		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
 		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetIstioCPPods", mock.Anything, mock.Anything).Return(nil, errors.New("Gatherer error"))
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
-		proxyImageVersion := "1.2.0"
-		proxyImagePrefix := "anything"
-		istioChart := "istio-sidecar-disabled"
 		factory := &workspacemocks.Factory{}
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		err = wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, proxyImagePrefix, log)
+		_, err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", "istio-sidecar-disabled", "1.2.0", "anything", "", nil, ProxyResetOptions{ImageComparison: data.ImageComparisonDigest}, log)
+
+		// then
+		require.NoError(t, err)
+		cfg := proxy.Calls[0].Arguments.Get(0).(istioConfig.IstioProxyConfig)
+		require.Empty(t, cfg.ImageDigest)
+	})
+
+}
+
+func Test_PerformerConfig_withDefaults(t *testing.T) {
+
+	t.Run("should use defaults for values which are not set", func(t *testing.T) {
+		// when
+		config := PerformerConfig{Timeout: time.Minute}.withDefaults()
+
+		// then
+		require.Equal(t, PerformerConfig{
+			RetriesCount:        retriesCount,
+			DelayBetweenRetries: delayBetweenRetries,
+			DelayType:           RetryDelayFixed,
+			Timeout:             time.Minute,
+			Interval:            interval,
+		}, config)
+	})
+}
+
+func Test_PerformerConfig_retryOptions(t *testing.T) {
+
+	funcPointer := func(fn avastretry.DelayTypeFunc) uintptr {
+		return reflect.ValueOf(fn).Pointer()
+	}
+
+	t.Run("should use a fixed delay by default", func(t *testing.T) {
+		// when
+		config := PerformerConfig{}.withDefaults()
+
+		// then
+		require.Equal(t, funcPointer(avastretry.FixedDelay), funcPointer(config.delayType()))
+	})
+
+	t.Run("should use exponential backoff when configured", func(t *testing.T) {
+		// when
+		config := PerformerConfig{DelayType: RetryDelayBackOff}.withDefaults()
+
+		// then
+		require.Equal(t, funcPointer(avastretry.BackOffDelay), funcPointer(config.delayType()))
+	})
+
+	t.Run("should double the delay between retries with exponential backoff", func(t *testing.T) {
+		// given
+		config := PerformerConfig{RetriesCount: 4, DelayBetweenRetries: 10 * time.Millisecond, DelayType: RetryDelayBackOff}.withDefaults()
+		start := time.Now()
+
+		// when
+		err := avastretry.Do(func() error {
+			return errors.New("not ready")
+		}, config.retryOptions()...)
+
 		// then
 		require.Error(t, err)
-		require.Contains(t, err.Error(), "Proxy reset error")
+		require.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
 	})
 
+	t.Run("should cap the delay between retries with exponential backoff", func(t *testing.T) {
+		// given
+		config := PerformerConfig{RetriesCount: 5, DelayBetweenRetries: 10 * time.Millisecond, DelayType: RetryDelayBackOff, MaxDelayBetweenRetries: 10 * time.Millisecond}.withDefaults()
+		start := time.Now()
+
+		// when
+		err := avastretry.Do(func() error {
+			return errors.New("not ready")
+		}, config.retryOptions()...)
+
+		// then
+		require.Error(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+		require.Less(t, time.Since(start), 150*time.Millisecond)
+	})
 }
 
-func Test_DefaultIstioPerformer_ResetProxy(t *testing.T) {
+func Test_DefaultIstioPerformer_GetIstiodLeader(t *testing.T) {
 
 	kubeConfig := "kubeconfig"
 	log := logger.NewLogger(false)
 	ctx := context.Background()
 	defer ctx.Done()
-	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
-		// given
-		cmder := istioctlmocks.Commander{}
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
 
-		proxy := proxymocks.IstioProxyReset{}
+	t.Run("should report holder identity of the istiod leader election lease", func(t *testing.T) {
+		// given
+		holderIdentity := "istiod-5c9c8f7d4-abcde"
+		renewTime := metav1.NewMicroTime(time.Date(2022, 11, 1, 12, 0, 0, 0, time.UTC))
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-leader", Namespace: "istio-system"},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity: &holderIdentity,
+				RenewTime:      &renewTime,
+			},
+		}
 		provider := clientsetmocks.Provider{}
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
-		gatherer := datamocks.Gatherer{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
-		proxyImageVersion := "1.2.0"
-		istioChart := "istio-sidecar-disabled"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(lease), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
 
 		// when
-		err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, "", log)
+		diagnostics, err := wrapper.GetIstiodLeader(ctx, kubeConfig, log)
+
 		// then
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "Kubeclient error")
+		require.NoError(t, err)
+		require.Equal(t, "istio-leader", diagnostics.LeaseName)
+		require.Equal(t, holderIdentity, diagnostics.HolderIdentity)
+		require.NotNil(t, diagnostics.RenewTime)
+		require.True(t, renewTime.Time.Equal(*diagnostics.RenewTime))
 	})
 
-	t.Run("should return error when istio proxy reset returned an error", func(t *testing.T) {
+	t.Run("should read the lease from the configured namespace", func(t *testing.T) {
 		// given
-		cmder := istioctlmocks.Commander{}
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
-
-		proxy := proxymocks.IstioProxyReset{}
-		proxy.On("Run", mock.Anything).Return(errors.New("Proxy reset error"))
+		holderIdentity := "istiod-5c9c8f7d4-abcde"
+		lease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-leader", Namespace: "custom-istio"},
+			Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holderIdentity},
+		}
 		provider := clientsetmocks.Provider{}
-		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
-
-		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
-		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
-		gatherer := datamocks.Gatherer{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
-		proxyImageVersion := "1.2.0"
-		proxyImagePrefix := "anything"
-		istioChart := "istio-sidecar-disabled"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(lease), nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{}).
+			WithNamespace("custom-istio")
 
 		// when
-		err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, proxyImagePrefix, log)
+		diagnostics, err := wrapper.GetIstiodLeader(ctx, kubeConfig, log)
+
 		// then
-		require.Error(t, err)
-		require.Contains(t, err.Error(), "Proxy reset error")
+		require.NoError(t, err)
+		require.Equal(t, holderIdentity, diagnostics.HolderIdentity)
 	})
 
-	t.Run("should return no error when istio proxy reset was successful", func(t *testing.T) {
+	t.Run("should report no holder when the lease does not exist", func(t *testing.T) {
 		// given
-		cmder := istioctlmocks.Commander{}
-		cmdResolver := TestCommanderResolver{cmder: &cmder}
-
-		proxy := proxymocks.IstioProxyReset{}
-		proxy.On("Run", mock.Anything).Return(nil)
 		provider := clientsetmocks.Provider{}
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
-
-		dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
-		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicClient, nil)
-		gatherer := datamocks.Gatherer{}
-		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
-		proxyImageVersion := "1.2.0"
-		proxyImagePrefix := "anything"
-		istioChart := "istio-sidecar-disabled"
-		factory := &workspacemocks.Factory{}
-		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
 
 		// when
-		err := wrapper.ResetProxy(ctx, kubeConfig, factory, "", istioChart, proxyImageVersion, proxyImagePrefix, log)
+		diagnostics, err := wrapper.GetIstiodLeader(ctx, kubeConfig, log)
+
 		// then
 		require.NoError(t, err)
+		require.Empty(t, diagnostics.HolderIdentity)
+		require.Nil(t, diagnostics.RenewTime)
 	})
 
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetIstiodLeader(ctx, kubeConfig, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Kubeclient error")
+	})
 }
 
 func Test_DefaultIstioPerformer_Version(t *testing.T) {
@@ -783,7 +2348,7 @@ func Test_DefaultIstioPerformer_Version(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		ver, err := wrapper.Version(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
 
 		// then
 		require.Empty(t, ver)
@@ -791,6 +2356,29 @@ func Test_DefaultIstioPerformer_Version(t *testing.T) {
 		require.Equal(t, "istioctl not found", err.Error())
 	})
 
+	t.Run("should report the supported versions if the istio version is not supported", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		supportedVersion, err := istioctl.VersionFromString("1.1.0")
+		require.NoError(t, err)
+		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found"), supported: []istioctl.Version{supportedVersion}}
+
+		proxy := proxymocks.IstioProxyReset{}
+		provider := clientsetmocks.Provider{}
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
+
+		// when
+		_, err = wrapper.Version(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
+
+		// then
+		var unsupportedVersionErr *UnsupportedVersionError
+		require.ErrorAs(t, err, &unsupportedVersionErr)
+		require.Equal(t, []istioctl.Version{supportedVersion}, unsupportedVersionErr.SupportedVersions)
+		require.False(t, unsupportedVersionErr.Version.Empty())
+	})
+
 	t.Run("should not proceed if the version command output returns an empty string", func(t *testing.T) {
 		// given
 		cmder := istioctlmocks.Commander{}
@@ -805,7 +2393,7 @@ func Test_DefaultIstioPerformer_Version(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		ver, err := wrapper.Version(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
 
 		// then
 		require.Empty(t, ver)
@@ -827,7 +2415,7 @@ func Test_DefaultIstioPerformer_Version(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		ver, err := wrapper.Version(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
 
 		// then
 		require.Empty(t, ver)
@@ -849,10 +2437,10 @@ func Test_DefaultIstioPerformer_Version(t *testing.T) {
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		ver, err := wrapper.Version(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
 
 		// then
-		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.2.3-solo-fips-distroless", TargetPrefix: "anything/anything", DataPlaneVersions: map[string]bool{}}, ver)
+		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.2.3-solo-fips-distroless", TargetPrefix: "anything/anything", PilotVersions: map[string]bool{}, DataPlaneVersions: map[string]bool{}, DataPlaneVersionCounts: map[string]int{}}, ver)
 		require.NoError(t, err)
 		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 		cmder.AssertNumberOfCalls(t, "Version", 1)
@@ -867,20 +2455,154 @@ func Test_DefaultIstioPerformer_Version(t *testing.T) {
 		cmdResolver := TestCommanderResolver{cmder: &cmder}
 		proxy := proxymocks.IstioProxyReset{}
 		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
 		gatherer := datamocks.Gatherer{}
 		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxy, &provider, &gatherer)
 
 		// when
-		ver, err := wrapper.Version(factory, "version", "istio-test", kubeConfig, log)
+		ver, err := wrapper.Version(context.TODO(), factory, "version", "istio-test", kubeConfig, log)
 
 		// then
-		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.2.3-solo-fips-distroless", TargetPrefix: "anything/anything", PilotVersion: "1.11.1", DataPlaneVersions: map[string]bool{"1.11.1": true}}, ver)
+		require.EqualValues(t, IstioStatus{ClientVersion: "1.11.1", TargetVersion: "1.2.3-solo-fips-distroless", TargetPrefix: "anything/anything", PilotVersion: "1.11.1", PilotVersions: map[string]bool{"1.11.1": true}, DataPlaneVersions: map[string]bool{"1.11.1": true}, DataPlaneVersionCounts: map[string]int{"1.11.1": 1}}, ver)
 		require.NoError(t, err)
 		cmder.AssertCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
 		cmder.AssertNumberOfCalls(t, "Version", 1)
 	})
 }
 
+func Test_getInstalledIstioVersion(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	retryOpts := PerformerConfig{}.withDefaults().retryOptions()
+
+	t.Run("should get the installed version from the gatherer without calling istioctl", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+
+		// when
+		version, err := getInstalledIstioVersion(&provider, kubeConfig, &gatherer, &cmder, retryOpts, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.2.3", version)
+		cmder.AssertNotCalled(t, "Version", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fall back to the pilot version of istioctl when the gatherer failed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockCompleteVersion), nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("", errors.New("gatherer error"))
+
+		// when
+		version, err := getInstalledIstioVersion(&provider, kubeConfig, &gatherer, &cmder, retryOpts, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.1", version)
+	})
+
+	t.Run("should fail when istioctl does not report a pilot version either", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(istioctlMockSimpleVersion), nil)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("", errors.New("gatherer error"))
+
+		// when
+		_, err := getInstalledIstioVersion(&provider, kubeConfig, &gatherer, &cmder, retryOpts, log)
+
+		// then
+		require.EqualError(t, err, "Could not get installed Istio version from istiod pods: gatherer error, nor from istioctl: istioctl did not report a pilot version")
+	})
+
+	t.Run("should fail when both the gatherer and istioctl failed", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("istioctl error"))
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("", errors.New("gatherer error"))
+
+		// when
+		_, err := getInstalledIstioVersion(&provider, kubeConfig, &gatherer, &cmder, retryOpts, log)
+
+		// then
+		require.EqualError(t, err, "Could not get installed Istio version from istiod pods: gatherer error, nor from istioctl: istioctl error")
+	})
+}
+
+func Test_DefaultIstioPerformer_ClientVersion(t *testing.T) {
+
+	log := logger.NewLogger(false)
+
+	t.Run("should not proceed if the istioctl binary could not be resolved", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmdResolver := TestCommanderResolver{err: errors.New("istioctl not found")}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{})
+
+		// when
+		ver, err := wrapper.ClientVersion(factory, "version", "istio-test", log)
+
+		// then
+		require.Empty(t, ver)
+		require.EqualError(t, err, "istioctl not found")
+		var unsupportedVersionErr *UnsupportedVersionError
+		require.ErrorAs(t, err, &unsupportedVersionErr)
+	})
+
+	t.Run("should not proceed if the version command output does not contain a client version", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ClientVersion", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(""), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &clientsetmocks.Provider{}, &datamocks.Gatherer{})
+
+		// when
+		ver, err := wrapper.ClientVersion(factory, "version", "istio-test", log)
+
+		// then
+		require.Empty(t, ver)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not contain a version")
+	})
+
+	t.Run("should return the client version without contacting the cluster", func(t *testing.T) {
+		// given
+		factory := &workspacemocks.Factory{}
+		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+		cmder := istioctlmocks.Commander{}
+		cmder.On("ClientVersion", mock.AnythingOfType("*zap.SugaredLogger")).Return([]byte(`{"clientVersion":{"version":"1.11.2","revision":"revision","golang_version":"go1.16.7","status":"Clean","tag":"1.11.2"}}`), nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+		provider := clientsetmocks.Provider{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		ver, err := wrapper.ClientVersion(factory, "version", "istio-test", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, IstioStatus{ClientVersion: "1.11.2", TargetVersion: "1.2.3-solo-fips-distroless"}, ver)
+		cmder.AssertNotCalled(t, "Version", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+		provider.AssertNotCalled(t, "RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger"))
+	})
+}
+
 func Test_getTargetProxyV2PrefixFromIstioChart(t *testing.T) {
 	branch := "branch"
 	log := logger.NewLogger(false)
@@ -892,7 +2614,7 @@ func Test_getTargetProxyV2PrefixFromIstioChart(t *testing.T) {
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files/path-tests"}, nil)
 
 		// when
-		targetPrefix, err := getTargetProxyV2PrefixFromIstioChart(factory, branch, istioChart, log)
+		targetPrefix, err := getTargetProxyV2PrefixFromIstioChart(newIstioChartLoader(factory, branch, istioChart), log)
 
 		// then
 		expectedPrefix := "istio-proxy-path/istio-proxy-dir"
@@ -912,7 +2634,7 @@ func Test_getTargetVersionFromIstioChart(t *testing.T) {
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		targetVersion, err := getTargetVersionFromIstioChart(newIstioChartLoader(factory, branch, istioChart), log)
 
 		// then
 		require.Empty(t, targetVersion)
@@ -927,7 +2649,7 @@ func Test_getTargetVersionFromIstioChart(t *testing.T) {
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		targetVersion, err := getTargetVersionFromIstioChart(newIstioChartLoader(factory, branch, istioChart), log)
 
 		// then
 		require.NoError(t, err)
@@ -941,7 +2663,7 @@ func Test_getTargetVersionFromIstioChart(t *testing.T) {
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		targetVersion, err := getTargetVersionFromIstioChart(newIstioChartLoader(factory, branch, istioChart), log)
 
 		// then
 		require.NoError(t, err)
@@ -955,7 +2677,7 @@ func Test_getTargetVersionFromIstioChart(t *testing.T) {
 		factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
 
 		// when
-		targetVersion, err := getTargetVersionFromIstioChart(factory, branch, istioChart, log)
+		targetVersion, err := getTargetVersionFromIstioChart(newIstioChartLoader(factory, branch, istioChart), log)
 
 		// then
 		require.NoError(t, err)
@@ -985,11 +2707,13 @@ func TestMapVersionToStruct(t *testing.T) {
 		targetVersion := "targetVersion"
 		targetPrefix := "anything/anything"
 		expectedStruct := IstioStatus{
-			ClientVersion:     "1.11.1",
-			TargetVersion:     targetVersion,
-			TargetPrefix:      targetPrefix,
-			PilotVersion:      "1.11.1",
-			DataPlaneVersions: map[string]bool{"1.11.1": true},
+			ClientVersion:          "1.11.1",
+			TargetVersion:          targetVersion,
+			TargetPrefix:           targetPrefix,
+			PilotVersion:           "1.11.1",
+			PilotVersions:          map[string]bool{"1.11.1": true},
+			DataPlaneVersions:      map[string]bool{"1.11.1": true},
+			DataPlaneVersionCounts: map[string]int{"1.11.1": 1},
 		}
 
 		// when
@@ -1000,6 +2724,20 @@ func TestMapVersionToStruct(t *testing.T) {
 		require.EqualValues(t, expectedStruct, gotStruct)
 	})
 
+	t.Run("All pilot versions of a mesh with multiple revisions must be converted to struct", func(t *testing.T) {
+		// given
+		versionOutput := []byte(istioctlMockMultiRevisionVersion)
+
+		// when
+		gotStruct, err := mapVersionToStruct(versionOutput, "1.12.0", "anything/anything")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.11.1", gotStruct.PilotVersion)
+		require.Equal(t, map[string]bool{"1.11.1": true, "1.12.0": true}, gotStruct.PilotVersions)
+		require.Equal(t, map[string]int{"1.11.1": 2, "1.12.0": 1}, gotStruct.DataPlaneVersionCounts)
+	})
+
 }
 
 func TestGetVersionFromJSON(t *testing.T) {
@@ -1025,8 +2763,9 @@ func TestGetVersionFromJSON(t *testing.T) {
 }
 
 type TestCommanderResolver struct {
-	err   error
-	cmder istioctl.Commander
+	err       error
+	cmder     istioctl.Commander
+	supported []istioctl.Version
 }
 
 func (tcr TestCommanderResolver) GetCommander(_ istioctl.Version) (istioctl.Commander, error) {
@@ -1035,3 +2774,7 @@ func (tcr TestCommanderResolver) GetCommander(_ istioctl.Version) (istioctl.Comm
 	}
 	return tcr.cmder, nil
 }
+
+func (tcr TestCommanderResolver) SupportedVersions() []istioctl.Version {
+	return tcr.supported
+}
@@ -0,0 +1,196 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const istioCRDGroupSuffix = "istio.io"
+
+var defaultGatewayNames = map[string]string{
+	"ingressGateways": "istio-ingressgateway",
+	"egressGateways":  "istio-egressgateway",
+}
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+type installPhase struct {
+	name               string
+	disabledComponents []string
+	waitFor            func(context context.Context, kubeConfig string, logger *zap.SugaredLogger) error
+}
+
+// installInPhases installs the CRDs, the control plane and the gateways one after another, waiting for the CRDs to be
+// established and for istiod to be ready in between. Each phase applies the Istio Operator with the components of the
+// later phases disabled, the last phase applies the complete Istio Operator.
+func (c *DefaultIstioPerformer) installInPhases(ctx context.Context, commander istioctl.Commander, operatorManifest, revision, kubeConfig string, logger *zap.SugaredLogger) error {
+	phases := []installPhase{
+		{
+			name:               "CRDs",
+			disabledComponents: []string{"pilot", "cni", "istiodRemote", "ingressGateways", "egressGateways"},
+			waitFor:            c.waitForIstioCRDsEstablished,
+		},
+		{
+			name:               "control plane",
+			disabledComponents: []string{"ingressGateways", "egressGateways"},
+			waitFor: func(ctx context.Context, kubeConfig string, logger *zap.SugaredLogger) error {
+				return c.waitForIstiodReady(ctx, kubeConfig, revision, logger)
+			},
+		},
+		{
+			name: "gateways",
+		},
+	}
+
+	for _, phase := range phases {
+		phaseManifest, err := withDisabledComponents(operatorManifest, phase.disabledComponents...)
+		if err != nil {
+			return errors.Wrapf(err, "Could not prepare Istio Operator for install phase %s", phase.name)
+		}
+
+		logger.Infof("Installing Istio %s", phase.name)
+		err = c.applyWithTimeout(ctx, func(applyContext context.Context) error {
+			return commander.Install(applyContext, phaseManifest, revision, kubeConfig, logger)
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Istio install phase %s failed", phase.name)
+		}
+
+		if phase.waitFor != nil {
+			err = phase.waitFor(ctx, kubeConfig, logger)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// withDisabledComponents returns the Istio Operator in JSON format with the given components disabled.
+// Gateway components are lists, every gateway in them gets disabled.
+func withDisabledComponents(operatorManifest string, components ...string) (string, error) {
+	if len(components) == 0 {
+		return operatorManifest, nil
+	}
+
+	operator := map[string]interface{}{}
+	err := json.Unmarshal([]byte(operatorManifest), &operator)
+	if err != nil {
+		return "", err
+	}
+
+	for _, component := range components {
+		value, found, err := unstructured.NestedFieldNoCopy(operator, "spec", "components", component)
+		if err != nil {
+			return "", err
+		}
+
+		if gateways, ok := value.([]interface{}); ok {
+			for _, gateway := range gateways {
+				if gatewayMap, ok := gateway.(map[string]interface{}); ok {
+					gatewayMap["enabled"] = false
+				}
+			}
+			continue
+		}
+
+		if defaultGateway, isGateway := defaultGatewayNames[component]; isGateway {
+			// Not configured gateways fall back to the profile defaults, so the default gateway is disabled explicitly
+			err = unstructured.SetNestedSlice(operator, []interface{}{map[string]interface{}{"name": defaultGateway, "enabled": false}}, "spec", "components", component)
+		} else if found && value != nil {
+			err = unstructured.SetNestedField(operator, false, "spec", "components", component, "enabled")
+		} else {
+			err = unstructured.SetNestedMap(operator, map[string]interface{}{"enabled": false}, "spec", "components", component)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	result, err := json.Marshal(operator)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+func (c *DefaultIstioPerformer) waitForIstioCRDsEstablished(context context.Context, kubeConfig string, logger *zap.SugaredLogger) error {
+	dynamicClient, err := c.provider.GetDynamicClient(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollImmediate(c.phaseWaitInterval, c.phaseWaitTimeout, func() (bool, error) {
+		crds, err := dynamicClient.Resource(crdResource).List(context, metav1.ListOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		istioCRDs := 0
+		for _, crd := range crds.Items {
+			group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+			if !strings.HasSuffix(group, istioCRDGroupSuffix) {
+				continue
+			}
+			istioCRDs++
+			if !isCRDEstablished(crd) {
+				logger.Debugf("Waiting for CRD %s to be established", crd.GetName())
+				return false, nil
+			}
+		}
+		return istioCRDs > 0, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "Istio CRDs were not established")
+	}
+
+	logger.Debug("Istio CRDs established")
+	return nil
+}
+
+func isCRDEstablished(crd unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if ok && conditionMap["type"] == "Established" && conditionMap["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *DefaultIstioPerformer) waitForIstiodReady(context context.Context, kubeConfig, revision string, logger *zap.SugaredLogger) error {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollImmediate(c.phaseWaitInterval, c.phaseWaitTimeout, func() (bool, error) {
+		istiod, err := kubeClient.AppsV1().Deployments(c.namespace).Get(context, istiodDeploymentNameFor(revision), metav1.GetOptions{})
+		if err != nil {
+			logger.Debugf("Waiting for istiod deployment: %v", err)
+			return false, nil
+		}
+		ready := istiod.Status.ReadyReplicas > 0 && istiod.Status.ReadyReplicas == istiod.Status.Replicas
+		if !ready {
+			logger.Debugf("Waiting for istiod to be ready, %d of %d replicas ready", istiod.Status.ReadyReplicas, istiod.Status.Replicas)
+		}
+		return ready, nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "Istiod did not become ready")
+	}
+
+	logger.Debug("Istiod is ready")
+	return nil
+}
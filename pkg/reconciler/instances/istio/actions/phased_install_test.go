@@ -0,0 +1,170 @@
+package actions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/kyma-project/istio/operator/api/v1alpha1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	controllerfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_withDisabledComponents(t *testing.T) {
+
+	t.Run("should disable components and every configured gateway", func(t *testing.T) {
+		// given
+		operator := `{"spec":{"components":{"cni":{"enabled":true},"ingressGateways":[{"name":"istio-ingressgateway","enabled":true},{"name":"custom-gateway","enabled":true}]}}}`
+
+		// when
+		result, err := withDisabledComponents(operator, "pilot", "cni", "ingressGateways")
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, `{"spec":{"components":{"pilot":{"enabled":false},"cni":{"enabled":false},"ingressGateways":[{"name":"istio-ingressgateway","enabled":false},{"name":"custom-gateway","enabled":false}]}}}`, result)
+	})
+
+	t.Run("should explicitly disable default gateway when gateways are not configured", func(t *testing.T) {
+		// when
+		result, err := withDisabledComponents(`{"spec":{}}`, "ingressGateways")
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, `{"spec":{"components":{"ingressGateways":[{"name":"istio-ingressgateway","enabled":false}]}}}`, result)
+	})
+
+	t.Run("should return the manifest unchanged when no component is disabled", func(t *testing.T) {
+		// when
+		result, err := withDisabledComponents("manifest")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "manifest", result)
+	})
+}
+
+func Test_DefaultIstioPerformer_PhasedInstall(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	err := v1alpha1.AddToScheme(scheme.Scheme)
+	require.NoError(t, err)
+	ctrlClient := controllerfake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+	establishedCRD := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]interface{}{"name": "gateways.networking.istio.io"},
+		"spec":       map[string]interface{}{"group": "networking.istio.io"},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Established", "status": "True"},
+		}},
+	}}
+	newDynamicClient := func() *dynamicfake.FakeDynamicClient {
+		listKinds := map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"}
+		return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, establishedCRD)
+	}
+	newIstiod := func(readyReplicas int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "istiod", Namespace: "istio-system"},
+			Status:     appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: readyReplicas, AvailableReplicas: readyReplicas},
+		}
+	}
+
+	t.Run("should install CRDs, control plane and gateways in sequence", func(t *testing.T) {
+		// given
+		var appliedManifests []string
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Run(func(args mock.Arguments) {
+				appliedManifests = append(appliedManifests, args.String(1))
+			}).
+			Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newIstiod(1)), nil)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(newDynamicClient(), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return("1.2.3", nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &provider, &gatherer).
+			WithPhasedInstall(true).
+			WithPhaseWait(100*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", "", false, log)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, appliedManifests, 3)
+		require.Contains(t, appliedManifests[0], `"pilot":{"enabled":false}`)
+		require.Contains(t, appliedManifests[0], `"ingressGateways":[{"enabled":false,"name":"istio-ingressgateway"}]`)
+		require.NotContains(t, appliedManifests[1], `"pilot"`)
+		require.Contains(t, appliedManifests[1], `"ingressGateways":[{"enabled":false,"name":"istio-ingressgateway"}]`)
+		require.NotContains(t, appliedManifests[2], `"ingressGateways"`)
+	})
+
+	t.Run("should not install gateways when istiod did not become ready", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(newIstiod(0)), nil)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(newDynamicClient(), nil)
+		gatherer := datamocks.Gatherer{}
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &provider, &gatherer).
+			WithPhasedInstall(true).
+			WithPhaseWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istiod did not become ready")
+		cmder.AssertNumberOfCalls(t, "Install", 2)
+		gatherer.AssertNotCalled(t, "GetInstalledIstioVersion", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not install control plane when Istio CRDs were not established", func(t *testing.T) {
+		// given
+		cmder := istioctlmocks.Commander{}
+		cmder.On("Install", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		cmdResolver := TestCommanderResolver{cmder: &cmder}
+
+		listKinds := map[schema.GroupVersionResource]string{crdResource: "CustomResourceDefinitionList"}
+		provider := clientsetmocks.Provider{}
+		provider.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds), nil)
+		wrapper := NewDefaultIstioPerformer(cmdResolver, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{}).
+			WithPhasedInstall(true).
+			WithPhaseWait(50*time.Millisecond, 10*time.Millisecond)
+
+		// when
+		err := wrapper.Install(context.TODO(), kubeConfig, istioManifestCniDisabled, "1.2.3", "", false, log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio CRDs were not established")
+		cmder.AssertNumberOfCalls(t, "Install", 1)
+	})
+}
@@ -0,0 +1,55 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	istioConfig "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+)
+
+func (c *DefaultIstioPerformer) GetProxyResetCandidates(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (v1.PodList, error) {
+	cfg, err := c.proxyResetConfig(context, kubeConfig, workspace, branchVersion, istioChart, proxyImageVersion, proxyImagePrefix, namespace, namespaces, options, logger)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+
+	return c.istioProxyReset.Candidates(cfg)
+}
+
+func (c *DefaultIstioPerformer) CheckProxyResetCompletion(context context.Context, kubeConfig string, proxyImageVersion string, proxyImagePrefix string, namespace string, namespaces []string, options ProxyResetOptions, logger *zap.SugaredLogger) (v1.PodList, error) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return v1.PodList{}, err
+	}
+
+	cfg := istioConfig.IstioProxyConfig{
+		IsUpdate:               true,
+		Context:                context,
+		ImagePrefix:            proxyImagePrefix,
+		ImageVersion:           proxyImageVersion,
+		RetriesCount:           c.config.RetriesCount,
+		DelayBetweenRetries:    c.config.DelayBetweenRetries,
+		Kubeclient:             kubeClient,
+		Log:                    logger,
+		Namespace:              namespace,
+		Namespaces:             namespaces,
+		ImageComparison:        options.ImageComparison,
+		ImageDigest:            options.ImageDigest,
+		IncludeTerminatingPods: options.IncludeTerminatingPods,
+	}
+	if cfg.ImageComparison == data.ImageComparisonDigest && cfg.ImageDigest == "" {
+		cfg.ImageDigest = c.resolveProxyImageDigest(kubeClient, data.ExpectedImage{Prefix: proxyImagePrefix, Version: proxyImageVersion}, logger)
+	}
+
+	remaining, err := c.istioProxyReset.PodsWithDifferentImage(cfg)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	logger.Debugf("Found %d pods with an istio proxy image different from %s:%s", len(remaining.Items), proxyImagePrefix, proxyImageVersion)
+
+	return remaining, nil
+}
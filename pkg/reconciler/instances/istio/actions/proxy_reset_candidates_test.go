@@ -0,0 +1,162 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	istioConfig "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_GetProxyResetCandidates(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	ctx := context.Background()
+	factory := &workspacemocks.Factory{}
+	factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+	fixPod := func(name, namespace string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	newProvider := func() *clientsetmocks.Provider {
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		provider.On("GetDynamicClient", mock.AnythingOfType("string")).Return(dynamicfake.NewSimpleDynamicClient(scheme.Scheme), nil)
+		return &provider
+	}
+
+	t.Run("should return the candidates the istio proxy reset selects with the same config as ResetProxy", func(t *testing.T) {
+		// given
+		proxyReset := proxymocks.IstioProxyReset{}
+		proxyReset.On("Candidates", mock.AnythingOfType("config.IstioProxyConfig")).Return(corev1.PodList{Items: []corev1.Pod{
+			fixPod("pod1", "ns1"),
+			fixPod("pod2", "ns2"),
+		}}, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxyReset, newProvider(), &datamocks.Gatherer{})
+		options := ProxyResetOptions{JobPodsHandling: data.JobPodsHandlingSkip, IncludeTerminatingPods: true}
+
+		// when
+		candidates, err := wrapper.GetProxyResetCandidates(ctx, "kubeconfig", factory, "", "istio-sidecar-disabled", "1.2.0", "istio/proxyv2", "ns1", []string{"ns1", "ns2"}, options, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []corev1.Pod{fixPod("pod1", "ns1"), fixPod("pod2", "ns2")}, candidates.Items)
+		proxyReset.AssertNotCalled(t, "Run", mock.Anything)
+		cfg := proxyReset.Calls[0].Arguments.Get(0).(istioConfig.IstioProxyConfig)
+		require.Equal(t, ctx, cfg.Context)
+		require.True(t, cfg.IsUpdate)
+		require.Equal(t, "istio/proxyv2", cfg.ImagePrefix)
+		require.Equal(t, "1.2.0", cfg.ImageVersion)
+		require.Equal(t, "ns1", cfg.Namespace)
+		require.Equal(t, []string{"ns1", "ns2"}, cfg.Namespaces)
+		require.Equal(t, data.JobPodsHandlingSkip, cfg.JobPodsHandling)
+		require.True(t, cfg.IncludeTerminatingPods)
+	})
+
+	t.Run("should return error when the candidates could not be selected", func(t *testing.T) {
+		// given
+		proxyReset := proxymocks.IstioProxyReset{}
+		proxyReset.On("Candidates", mock.AnythingOfType("config.IstioProxyConfig")).Return(corev1.PodList{}, errors.New("gatherer error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxyReset, newProvider(), &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetProxyResetCandidates(ctx, "kubeconfig", factory, "", "istio-sidecar-disabled", "1.2.0", "istio/proxyv2", "", nil, ProxyResetOptions{}, log)
+
+		// then
+		require.EqualError(t, err, "gatherer error")
+	})
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		proxyReset := proxymocks.IstioProxyReset{}
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxyReset, &provider, &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetProxyResetCandidates(ctx, "kubeconfig", factory, "", "istio-sidecar-disabled", "1.2.0", "istio/proxyv2", "", nil, ProxyResetOptions{}, log)
+
+		// then
+		require.EqualError(t, err, "Kubeclient error")
+		proxyReset.AssertNotCalled(t, "Candidates", mock.Anything)
+	})
+}
+
+func Test_DefaultIstioPerformer_CheckProxyResetCompletion(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	ctx := context.Background()
+	fixPod := func(name, namespace string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	newProvider := func() *clientsetmocks.Provider {
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		return &provider
+	}
+
+	t.Run("should return no pods when all proxies converged", func(t *testing.T) {
+		// given
+		proxyReset := proxymocks.IstioProxyReset{}
+		proxyReset.On("PodsWithDifferentImage", mock.AnythingOfType("config.IstioProxyConfig")).Return(corev1.PodList{Items: []corev1.Pod{}}, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxyReset, newProvider(), &datamocks.Gatherer{})
+
+		// when
+		remaining, err := wrapper.CheckProxyResetCompletion(ctx, "kubeconfig", "1.2.0", "istio/proxyv2", "", nil, ProxyResetOptions{}, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, remaining.Items)
+	})
+
+	t.Run("should return the pods in the scope of the proxy reset which still run a different proxy image", func(t *testing.T) {
+		// given
+		proxyReset := proxymocks.IstioProxyReset{}
+		proxyReset.On("PodsWithDifferentImage", mock.AnythingOfType("config.IstioProxyConfig")).Return(corev1.PodList{Items: []corev1.Pod{fixPod("pod1", "ns1"), fixPod("pod2", "ns2")}}, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxyReset, newProvider(), &datamocks.Gatherer{})
+		options := ProxyResetOptions{ImageComparison: data.ImageComparisonDigest, ImageDigest: "sha256:abc", IncludeTerminatingPods: true}
+
+		// when
+		remaining, err := wrapper.CheckProxyResetCompletion(ctx, "kubeconfig", "1.2.0", "istio/proxyv2", "ns1", []string{"ns1", "ns2"}, options, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []corev1.Pod{fixPod("pod1", "ns1"), fixPod("pod2", "ns2")}, remaining.Items)
+		proxyReset.AssertNotCalled(t, "Run", mock.Anything)
+		cfg := proxyReset.Calls[0].Arguments.Get(0).(istioConfig.IstioProxyConfig)
+		require.Equal(t, ctx, cfg.Context)
+		require.Equal(t, "istio/proxyv2", cfg.ImagePrefix)
+		require.Equal(t, "1.2.0", cfg.ImageVersion)
+		require.Equal(t, "ns1", cfg.Namespace)
+		require.Equal(t, []string{"ns1", "ns2"}, cfg.Namespaces)
+		require.Equal(t, data.ImageComparisonDigest, cfg.ImageComparison)
+		require.Equal(t, "sha256:abc", cfg.ImageDigest)
+		require.True(t, cfg.IncludeTerminatingPods)
+	})
+
+	t.Run("should return error when pods could not be listed", func(t *testing.T) {
+		// given
+		proxyReset := proxymocks.IstioProxyReset{}
+		proxyReset.On("PodsWithDifferentImage", mock.AnythingOfType("config.IstioProxyConfig")).Return(corev1.PodList{}, errors.New("list error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxyReset, newProvider(), &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.CheckProxyResetCompletion(ctx, "kubeconfig", "1.2.0", "istio/proxyv2", "", nil, ProxyResetOptions{}, log)
+
+		// then
+		require.EqualError(t, err, "list error")
+	})
+}
@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ProxyResetOptions tune how ResetProxy selects the pods to reset. The zero value keeps the defaults of the proxy reset.
+type ProxyResetOptions struct {
+	// ImageComparison defines if proxy images are compared by tag or by digest, defaults to tag
+	ImageComparison data.ImageComparison
+
+	// ImageDigest of the target proxy image used with digest comparison. If it is empty, the digest is resolved from the
+	// Istio control plane pods running the target proxy image.
+	ImageDigest string
+
+	// JobPodsHandling defines how pods owned by Jobs are handled, defaults to annotating them with a warning
+	JobPodsHandling data.JobPodsHandling
+
+	// NamespacePriority orders the reset of namespaces by their position in the list, defaults to alphabetical order
+	NamespacePriority []string
+
+	// UnlistedNamespacesPriority is the position in NamespacePriority at which namespaces not listed there are reset, defaults to
+	// after all listed namespaces
+	UnlistedNamespacesPriority *int
+
+	// IncludeTerminatingPods resets also pods which are already being deleted, defaults to skipping them
+	IncludeTerminatingPods bool
+}
+
+// resolveProxyImageDigest returns the digest of the proxy image run by the Istio control plane pods, e.g. the ingress gateway.
+// An empty digest is returned if no pod reports it, in which case the proxy reset falls back to comparing tags.
+func (c *DefaultIstioPerformer) resolveProxyImageDigest(kubeClient kubernetes.Interface, image data.ExpectedImage, logger *zap.SugaredLogger) string {
+	pods, err := c.gatherer.GetIstioCPPods(kubeClient, c.config.retryOptions())
+	if err != nil {
+		logger.Warnf("Could not resolve the digest of istio proxy image %s:%s, comparing tags instead: %v", image.Prefix, image.Version, err)
+		return ""
+	}
+	digest := data.GetRunningImageDigest(*pods, image)
+	if digest == "" {
+		logger.Warnf("No pod runs istio proxy image %s:%s with a known digest, comparing tags instead", image.Prefix, image.Version)
+		return ""
+	}
+	logger.Debugf("Resolved digest %s of istio proxy image %s:%s", digest, image.Prefix, image.Version)
+	return digest
+}
@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	sidecarInjectorConfigMapName = "istio-sidecar-injector"
+	sidecarInjectorValuesKey     = "values"
+)
+
+// sidecarInjectorValues are the parts of the sidecar injector values which define the injected istio proxy image.
+type sidecarInjectorValues struct {
+	Global struct {
+		Tag   interface{} `json:"tag"`
+		Proxy struct {
+			Image string `json:"image"`
+		} `json:"proxy"`
+	} `json:"global"`
+}
+
+// GetProxyImageVersion returns the version of the istio proxy image the running istiod of the revision injects. It is read
+// from the sidecar injector configuration, a tag of the configured proxy image takes precedence over the global tag.
+func (c *DefaultIstioPerformer) GetProxyImageVersion(context context.Context, kubeConfig string, revision string, logger *zap.SugaredLogger) (string, error) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return "", err
+	}
+
+	name := sidecarInjectorConfigMapNameFor(revision)
+	configMap, err := kubeClient.CoreV1().ConfigMaps(c.namespace).Get(context, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not get sidecar injector configuration %s", name)
+	}
+
+	var values sidecarInjectorValues
+	err = json.Unmarshal([]byte(configMap.Data[sidecarInjectorValuesKey]), &values)
+	if err != nil {
+		return "", errors.Wrapf(err, "Could not parse values of sidecar injector configuration %s", name)
+	}
+
+	version := imageTag(values.Global.Proxy.Image)
+	if version == "" && values.Global.Tag != nil {
+		version = fmt.Sprint(values.Global.Tag)
+	}
+	if version == "" {
+		return "", errors.Errorf("Sidecar injector configuration %s defines no istio proxy image version", name)
+	}
+
+	_, err = istioctl.VersionFromString(version)
+	if err != nil {
+		return "", errors.Wrapf(err, "Invalid istio proxy image version in sidecar injector configuration %s", name)
+	}
+	logger.Debugf("Resolved istio proxy image version: %s from sidecar injector configuration %s", version, name)
+
+	return version, nil
+}
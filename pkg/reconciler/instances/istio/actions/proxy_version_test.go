@@ -0,0 +1,124 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_GetProxyImageVersion(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+
+	fixSidecarInjector := func(name string, values string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system"},
+			Data:       map[string]string{"values": values},
+		}
+	}
+
+	newPerformer := func(objects ...runtime.Object) *DefaultIstioPerformer {
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(objects...), nil)
+		return NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+	}
+
+	t.Run("should return the global tag of the sidecar injector configuration", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fixSidecarInjector("istio-sidecar-injector", `{"global":{"tag":"1.16.1-distroless","proxy":{"image":"proxyv2"}}}`))
+
+		// when
+		version, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.16.1-distroless", version)
+	})
+
+	t.Run("should prefer the tag of the configured proxy image", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fixSidecarInjector("istio-sidecar-injector", `{"global":{"tag":"1.16.1","proxy":{"image":"registry.local:5000/istio/proxyv2:1.15.4"}}}`))
+
+		// when
+		version, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.15.4", version)
+	})
+
+	t.Run("should read the sidecar injector configuration of the revision", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(
+			fixSidecarInjector("istio-sidecar-injector", `{"global":{"tag":"1.15.4"}}`),
+			fixSidecarInjector("istio-sidecar-injector-canary", `{"global":{"tag":"1.16.1"}}`),
+		)
+
+		// when
+		version, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "canary", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.16.1", version)
+	})
+
+	t.Run("should read the sidecar injector configuration from the configured namespace", func(t *testing.T) {
+		// given
+		sidecarInjector := fixSidecarInjector("istio-sidecar-injector", `{"global":{"tag":"1.16.1"}}`)
+		sidecarInjector.Namespace = "custom-istio"
+		wrapper := newPerformer(sidecarInjector).WithNamespace("custom-istio")
+
+		// when
+		version, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, "1.16.1", version)
+	})
+
+	t.Run("should return error when the configured version is not a semantic version", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fixSidecarInjector("istio-sidecar-injector", `{"global":{"tag":"latest"}}`))
+
+		// when
+		_, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid istio proxy image version in sidecar injector configuration istio-sidecar-injector")
+	})
+
+	t.Run("should return error when no version is configured", func(t *testing.T) {
+		// given
+		wrapper := newPerformer(fixSidecarInjector("istio-sidecar-injector", `{"global":{"proxy":{"image":"proxyv2"}}}`))
+
+		// when
+		_, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.EqualError(t, err, "Sidecar injector configuration istio-sidecar-injector defines no istio proxy image version")
+	})
+
+	t.Run("should return error when there is no sidecar injector configuration", func(t *testing.T) {
+		// given
+		wrapper := newPerformer()
+
+		// when
+		_, err := wrapper.GetProxyImageVersion(context.TODO(), kubeConfig, "", log)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not get sidecar injector configuration istio-sidecar-injector")
+	})
+}
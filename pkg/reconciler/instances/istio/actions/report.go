@@ -0,0 +1,65 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+)
+
+// ActionResult holds the outcome of a single action executed by RunAll.
+type ActionResult struct {
+	Action   string
+	Duration time.Duration
+	Err      error
+}
+
+// ReconcileReport aggregates the outcome of all actions executed by RunAll.
+type ReconcileReport struct {
+	Results  []ActionResult
+	Duration time.Duration
+}
+
+// Succeeded returns true if none of the actions failed.
+func (r ReconcileReport) Succeeded() bool {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns a single error combining the errors of all failed actions, or nil if all actions succeeded.
+func (r ReconcileReport) Err() error {
+	var failures []string
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", result.Action, result.Err))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d actions failed: %s", len(failures), len(r.Results), strings.Join(failures, "; "))
+}
+
+// RunAll runs the given actions in order and reports the outcome and duration of each of them.
+// Unlike ActionAggregate it does not stop on the first failing action.
+func RunAll(ctx *service.ActionContext, actions ...service.Action) ReconcileReport {
+	report := ReconcileReport{}
+	start := time.Now()
+	for _, action := range actions {
+		actionStart := time.Now()
+		err := action.Run(ctx)
+		report.Results = append(report.Results, ActionResult{
+			Action:   fmt.Sprintf("%T", action),
+			Duration: time.Since(actionStart),
+			Err:      err,
+		})
+	}
+	report.Duration = time.Since(start)
+
+	return report
+}
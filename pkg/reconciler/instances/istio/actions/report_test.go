@@ -0,0 +1,56 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAll(t *testing.T) {
+	t.Run("should report success when no action returns error", func(t *testing.T) {
+		// given
+		m1 := mockAction{}
+		m2 := mockAction{}
+
+		// when
+		report := actions.RunAll(&service.ActionContext{}, &m1, &m2)
+
+		// then
+		require.True(t, report.Succeeded())
+		require.NoError(t, report.Err())
+		require.Len(t, report.Results, 2)
+		require.Equal(t, "*actions_test.mockAction", report.Results[0].Action)
+		require.True(t, m1.runCalled)
+		require.True(t, m2.runCalled)
+	})
+
+	t.Run("should run all actions and aggregate errors of the failing ones", func(t *testing.T) {
+		// given
+		m1 := mockAction{returnErr: true}
+		m2 := mockAction{}
+		m3 := mockAction{returnErr: true}
+
+		// when
+		report := actions.RunAll(&service.ActionContext{}, &m1, &m2, &m3)
+
+		// then
+		require.False(t, report.Succeeded())
+		require.Len(t, report.Results, 3)
+		require.ErrorIs(t, report.Results[0].Err, errInner)
+		require.NoError(t, report.Results[1].Err)
+		require.ErrorIs(t, report.Results[2].Err, errInner)
+		require.EqualError(t, report.Err(), "2 of 3 actions failed: *actions_test.mockAction: inner err; *actions_test.mockAction: inner err")
+		require.True(t, m3.runCalled)
+	})
+
+	t.Run("should return an empty report when no actions are given", func(t *testing.T) {
+		// when
+		report := actions.RunAll(&service.ActionContext{})
+
+		// then
+		require.True(t, report.Succeeded())
+		require.Empty(t, report.Results)
+	})
+}
@@ -0,0 +1,46 @@
+package actions
+
+const (
+	istioInjectionLabel = "istio-injection"
+	injectionEnabled    = "enabled"
+	injectionDisabled   = "disabled"
+)
+
+// injectionLabel returns the namespace label which enables sidecar injection by the control plane of the revision.
+// Without a revision the default control plane is used.
+func injectionLabel(revision string) (string, string) {
+	if revision == "" {
+		return istioInjectionLabel, injectionEnabled
+	}
+	return istioRevisionLabel, revision
+}
+
+// isLabeledForInjection checks if the namespace labels already opt the namespace in for sidecar injection, so labeling it
+// for the revision would create a conflicting dual-injection state. Without a revision both istio-injection and istio.io/rev count,
+// with a revision istio-injection and istio.io/rev of the same revision count, so namespaces on other revisions are migrated.
+func isLabeledForInjection(labels map[string]string, revision string) bool {
+	if _, ok := labels[istioInjectionLabel]; ok {
+		return true
+	}
+	namespaceRevision, ok := labels[istioRevisionLabel]
+	if revision == "" {
+		return ok
+	}
+	return namespaceRevision == revision
+}
+
+// istiodDeploymentNameFor returns the name of the istiod deployment of the revision.
+func istiodDeploymentNameFor(revision string) string {
+	if revision == "" {
+		return istiodDeploymentName
+	}
+	return istiodDeploymentName + "-" + revision
+}
+
+// sidecarInjectorConfigMapNameFor returns the name of the sidecar injector configuration of the revision.
+func sidecarInjectorConfigMapNameFor(revision string) string {
+	if revision == "" {
+		return sidecarInjectorConfigMapName
+	}
+	return sidecarInjectorConfigMapName + "-" + revision
+}
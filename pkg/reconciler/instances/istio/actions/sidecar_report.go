@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"context"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const injectionEnabledLabelSelector = "istio-injection=enabled"
+
+func (c *DefaultIstioPerformer) GetNamespacesWithPodsWithoutSidecar(context context.Context, kubeConfig string, workspace chart.Factory, branchVersion string, istioChart string, logger *zap.SugaredLogger) (map[string]int, error) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	sidecarInjectionEnabledByDefault, err := IsSidecarInjectionNamespacesByDefaultEnabled(workspace, branchVersion, istioChart)
+	if err != nil {
+		logger.Error("Could not retrieve default istio sidecar injection!")
+		return nil, err
+	}
+
+	namespaces, err := kubeClient.CoreV1().Namespaces().List(context, metav1.ListOptions{LabelSelector: injectionEnabledLabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	report := make(map[string]int, len(namespaces.Items))
+	for _, namespace := range namespaces.Items {
+		report[namespace.Name] = 0
+	}
+
+	podsWithoutSidecar, err := c.gatherer.GetPodsWithoutSidecar(kubeClient, c.config.retryOptions(), sidecarInjectionEnabledByDefault, false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pod := range podsWithoutSidecar.Items {
+		if _, labeled := report[pod.Namespace]; labeled && pod.Status.Phase == v1.PodRunning {
+			report[pod.Namespace]++
+		}
+	}
+	logger.Debugf("Found pods without sidecar in %d namespaces labeled for injection", len(report))
+
+	return report, nil
+}
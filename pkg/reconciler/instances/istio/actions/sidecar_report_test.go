@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	workspacemocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_GetNamespacesWithPodsWithoutSidecar(t *testing.T) {
+
+	log := logger.NewLogger(false)
+	factory := &workspacemocks.Factory{}
+	factory.On("Get", mock.AnythingOfType("string")).Return(&chart.KymaWorkspace{ResourceDir: "../test_files"}, nil)
+	labeledNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"istio-injection": "enabled"}}}
+	}
+	podWithoutSidecar := func(name, namespace string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Status: corev1.PodStatus{Phase: phase}}
+	}
+
+	t.Run("should count running pods without sidecar per labeled namespace", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(
+			labeledNamespace("full-coverage"),
+			labeledNamespace("partial-coverage"),
+			labeledNamespace("no-coverage"),
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "not-labeled"}},
+		)
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(kubeClient, nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("bool"), mock.Anything).Return(corev1.PodList{Items: []corev1.Pod{
+			podWithoutSidecar("pod1", "partial-coverage", corev1.PodRunning),
+			podWithoutSidecar("pod2", "partial-coverage", corev1.PodPending),
+			podWithoutSidecar("pod3", "no-coverage", corev1.PodRunning),
+			podWithoutSidecar("pod4", "no-coverage", corev1.PodRunning),
+			podWithoutSidecar("pod5", "not-labeled", corev1.PodRunning),
+		}}, nil)
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &gatherer)
+
+		// when
+		report, err := wrapper.GetNamespacesWithPodsWithoutSidecar(context.TODO(), "kubeconfig", factory, "", "istio-sidecar-disabled", log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"full-coverage": 0, "partial-coverage": 1, "no-coverage": 2}, report)
+	})
+
+	t.Run("should return error when pods without sidecar could not be gathered", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(labeledNamespace("ns")), nil)
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("bool"), mock.Anything).Return(corev1.PodList{}, errors.New("gatherer error"))
+		wrapper := NewDefaultIstioPerformer(TestCommanderResolver{}, &proxymocks.IstioProxyReset{}, &provider, &gatherer)
+
+		// when
+		_, err := wrapper.GetNamespacesWithPodsWithoutSidecar(context.TODO(), "kubeconfig", factory, "", "istio-sidecar-disabled", log)
+
+		// then
+		require.EqualError(t, err, "gatherer error")
+	})
+}
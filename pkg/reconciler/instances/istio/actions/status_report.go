@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// IstioStatusReport is the JSON representation of IstioStatus with stable field names and deterministic ordering.
+type IstioStatusReport struct {
+	ClientVersion          string         `json:"clientVersion"`
+	TargetVersion          string         `json:"targetVersion"`
+	TargetPrefix           string         `json:"targetPrefix"`
+	PilotVersion           string         `json:"pilotVersion"`
+	DataPlaneVersions      []string       `json:"dataPlaneVersions"`
+	DataPlaneVersionCounts map[string]int `json:"dataPlaneVersionCounts,omitempty"`
+	ManagedByReconciler    bool           `json:"managedByReconciler"`
+}
+
+// NewIstioStatusReport creates the report for the status, the data plane versions are flattened to a sorted slice.
+func NewIstioStatusReport(status IstioStatus) IstioStatusReport {
+	dataPlaneVersions := make([]string, 0, len(status.DataPlaneVersions))
+	for version := range status.DataPlaneVersions {
+		dataPlaneVersions = append(dataPlaneVersions, version)
+	}
+	sort.Strings(dataPlaneVersions)
+
+	return IstioStatusReport{
+		ClientVersion:          status.ClientVersion,
+		TargetVersion:          status.TargetVersion,
+		TargetPrefix:           status.TargetPrefix,
+		PilotVersion:           status.PilotVersion,
+		DataPlaneVersions:      dataPlaneVersions,
+		DataPlaneVersionCounts: status.DataPlaneVersionCounts,
+		ManagedByReconciler:    status.ManagedByReconciler,
+	}
+}
+
+// MarshalIstioStatus returns the status as a JSON report, see IstioStatusReport.
+func MarshalIstioStatus(status IstioStatus) ([]byte, error) {
+	return json.Marshal(NewIstioStatusReport(status))
+}
@@ -0,0 +1,41 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MarshalIstioStatus(t *testing.T) {
+
+	t.Run("should marshal status with sorted data plane versions", func(t *testing.T) {
+		// given
+		status := IstioStatus{
+			ClientVersion:          "1.2.0",
+			TargetVersion:          "1.2.0",
+			TargetPrefix:           "eu.gcr.io/kyma-project/external/istio/proxyv2",
+			PilotVersion:           "1.1.0",
+			DataPlaneVersions:      map[string]bool{"1.1.0": true, "1.0.9": true, "1.1.10": true},
+			DataPlaneVersionCounts: map[string]int{"1.1.0": 3, "1.0.9": 1, "1.1.10": 2},
+			ManagedByReconciler:    true,
+		}
+
+		// when
+		result, err := MarshalIstioStatus(status)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, `{"clientVersion":"1.2.0","targetVersion":"1.2.0","targetPrefix":"eu.gcr.io/kyma-project/external/istio/proxyv2",`+
+			`"pilotVersion":"1.1.0","dataPlaneVersions":["1.0.9","1.1.0","1.1.10"],"dataPlaneVersionCounts":{"1.0.9":1,"1.1.0":3,"1.1.10":2},`+
+			`"managedByReconciler":true}`, string(result))
+	})
+
+	t.Run("should marshal empty data plane versions as empty list", func(t *testing.T) {
+		// when
+		result, err := MarshalIstioStatus(IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0"})
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, `{"clientVersion":"1.2.0","targetVersion":"1.2.0","targetPrefix":"","pilotVersion":"","dataPlaneVersions":[],"managedByReconciler":false}`, string(result))
+	})
+}
@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "k8s.io/client-go/kubernetes"
+)
+
+// waitForUninstallCompletion polls until the istiod deployment and the istio webhook configurations of the revision are gone,
+// so that the istio namespace is not deleted while istioctl is still tearing down webhooks.
+// Without a revision all istio webhook configurations have to be gone.
+func (c *DefaultIstioPerformer) waitForUninstallCompletion(context context.Context, kubeClient k8sclient.Interface, namespace, revision string, logger *zap.SugaredLogger) error {
+	var remaining []string
+	err := wait.PollImmediate(c.uninstallWaitInterval, c.uninstallWaitTimeout, func() (bool, error) {
+		var err error
+		remaining, err = getRemainingIstioResources(context, kubeClient, namespace, revision)
+		if err != nil {
+			return false, err
+		}
+		if len(remaining) > 0 {
+			logger.Debugf("Waiting for Istio resources to be removed: %s", strings.Join(remaining, ", "))
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if errors.Is(err, wait.ErrWaitTimeout) {
+			return errors.Errorf("Istio uninstall did not complete within %s, remaining resources: %s", c.uninstallWaitTimeout, strings.Join(remaining, ", "))
+		}
+		return errors.Wrap(err, "Could not verify that Istio uninstall completed")
+	}
+
+	logger.Debug("Istiod deployment and istio webhook configurations removed")
+	return nil
+}
+
+// getRemainingIstioResources returns the istiod deployment in namespace and istio webhook configurations of the revision which still exist on the cluster.
+func getRemainingIstioResources(context context.Context, kubeClient k8sclient.Interface, namespace, revision string) ([]string, error) {
+	var remaining []string
+
+	deploymentName := istiodDeploymentNameFor(revision)
+	_, err := kubeClient.AppsV1().Deployments(namespace).Get(context, deploymentName, metav1.GetOptions{})
+	if err == nil {
+		remaining = append(remaining, fmt.Sprintf("Deployment %s/%s", namespace, deploymentName))
+	} else if !kerrors.IsNotFound(err) {
+		return nil, errors.Wrap(err, "Could not get istiod deployment")
+	}
+
+	mutatingConfigurations, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list mutating webhook configurations")
+	}
+	for _, configuration := range mutatingConfigurations.Items {
+		if isIstioWebhookConfigurationOf(configuration.ObjectMeta, revision) {
+			remaining = append(remaining, fmt.Sprintf("%s %s", mutatingWebhookConfigurationKind, configuration.Name))
+		}
+	}
+
+	validatingConfigurations, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list validating webhook configurations")
+	}
+	for _, configuration := range validatingConfigurations.Items {
+		if isIstioWebhookConfigurationOf(configuration.ObjectMeta, revision) {
+			remaining = append(remaining, fmt.Sprintf("%s %s", validatingWebhookConfigurationKind, configuration.Name))
+		}
+	}
+
+	return remaining, nil
+}
+
+// isIstioWebhookConfigurationOf checks if the istio webhook configuration belongs to the revision. Without a revision all istio
+// webhook configurations match.
+func isIstioWebhookConfigurationOf(meta metav1.ObjectMeta, revision string) bool {
+	if revision == "" {
+		return isIstioWebhookConfiguration(meta)
+	}
+	return meta.Labels[istioRevisionLabel] == revision
+}
+
+// getInstalledIstiodDeployments returns the names of the istiod deployments of all revisions installed in namespace.
+func getInstalledIstiodDeployments(context context.Context, kubeClient k8sclient.Interface, namespace string) ([]string, error) {
+	deployments, err := kubeClient.AppsV1().Deployments(namespace).List(context, metav1.ListOptions{LabelSelector: istiodLabelSelector})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list istiod deployments")
+	}
+
+	names := make([]string, 0, len(deployments.Items))
+	for _, deployment := range deployments.Items {
+		names = append(names, deployment.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclient "k8s.io/client-go/kubernetes"
+)
+
+const (
+	mutatingWebhookConfigurationKind   = "MutatingWebhookConfiguration"
+	validatingWebhookConfigurationKind = "ValidatingWebhookConfiguration"
+	istioRevisionLabel                 = "istio.io/rev"
+	istioSidecarInjectorName           = "istio-sidecar-injector"
+	istioDefaultValidatorName          = "istiod-default-validator"
+)
+
+// WebhookReport describes a single webhook of an istio related Mutating or Validating webhook configuration.
+type WebhookReport struct {
+	Kind              string
+	Configuration     string
+	Webhook           string
+	FailurePolicy     string
+	CABundlePopulated bool
+}
+
+func (c *DefaultIstioPerformer) GetWebhookConfigurations(context context.Context, kubeConfig string, logger *zap.SugaredLogger) ([]WebhookReport, error) {
+	kubeClient, err := c.provider.RetrieveFrom(kubeConfig, logger)
+	if err != nil {
+		logger.Error("Could not retrieve KubeClient from Kubeconfig!")
+		return nil, err
+	}
+
+	var report []WebhookReport
+
+	mutatingConfigurations, err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().List(context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list mutating webhook configurations")
+	}
+	for _, configuration := range mutatingConfigurations.Items {
+		if !isIstioWebhookConfiguration(configuration.ObjectMeta) {
+			continue
+		}
+		for _, webhook := range configuration.Webhooks {
+			report = append(report, newWebhookReport(mutatingWebhookConfigurationKind, configuration.Name, webhook.Name, webhook.FailurePolicy, webhook.ClientConfig))
+		}
+	}
+
+	validatingConfigurations, err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Could not list validating webhook configurations")
+	}
+	for _, configuration := range validatingConfigurations.Items {
+		if !isIstioWebhookConfiguration(configuration.ObjectMeta) {
+			continue
+		}
+		for _, webhook := range configuration.Webhooks {
+			report = append(report, newWebhookReport(validatingWebhookConfigurationKind, configuration.Name, webhook.Name, webhook.FailurePolicy, webhook.ClientConfig))
+		}
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		if report[i].Configuration != report[j].Configuration {
+			return report[i].Configuration < report[j].Configuration
+		}
+		return report[i].Webhook < report[j].Webhook
+	})
+	logger.Debugf("Found %d istio webhooks", len(report))
+
+	return report, nil
+}
+
+func isIstioWebhookConfiguration(meta metav1.ObjectMeta) bool {
+	if _, ok := meta.Labels[istioRevisionLabel]; ok {
+		return true
+	}
+	return strings.Contains(meta.Name, "istio")
+}
+
+func newWebhookReport(kind, configuration, webhook string, failurePolicy *admissionv1.FailurePolicyType, clientConfig admissionv1.WebhookClientConfig) WebhookReport {
+	report := WebhookReport{
+		Kind:              kind,
+		Configuration:     configuration,
+		Webhook:           webhook,
+		CABundlePopulated: len(clientConfig.CABundle) > 0,
+	}
+	if failurePolicy != nil {
+		report.FailurePolicy = string(*failurePolicy)
+	}
+	return report
+}
+
+// deleteSidecarInjectorWebhookConfigurations deletes the sidecar injector and validator webhook configurations of the revision which
+// are left behind after the istio namespace was deleted. A lingering sidecar injector blocks the creation of pods mesh-wide.
+func deleteSidecarInjectorWebhookConfigurations(context context.Context, kubeClient k8sclient.Interface, namespace, revision string, logger *zap.SugaredLogger) error {
+	mutatingNames := []string{istioSidecarInjectorName}
+	validatingNames := []string{istioDefaultValidatorName, "istio-validator-" + namespace}
+	if revision != "" {
+		mutatingNames = append(mutatingNames, istioSidecarInjectorName+"-"+revision)
+		validatingNames = append(validatingNames, "istio-validator-"+revision+"-"+namespace)
+	}
+
+	for _, name := range mutatingNames {
+		err := kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Delete(context, name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Could not delete %s %s", mutatingWebhookConfigurationKind, name)
+		}
+		if err == nil {
+			logger.Infof("Deleted stale %s %s", mutatingWebhookConfigurationKind, name)
+		}
+	}
+
+	for _, name := range validatingNames {
+		err := kubeClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context, name, metav1.DeleteOptions{})
+		if err != nil && !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "Could not delete %s %s", validatingWebhookConfigurationKind, name)
+		}
+		if err == nil {
+			logger.Infof("Deleted stale %s %s", validatingWebhookConfigurationKind, name)
+		}
+	}
+
+	return nil
+}
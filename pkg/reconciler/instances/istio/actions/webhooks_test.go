@@ -0,0 +1,87 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/logger"
+	clientsetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset/mocks"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_DefaultIstioPerformer_GetWebhookConfigurations(t *testing.T) {
+
+	kubeConfig := "kubeConfig"
+	log := logger.NewLogger(false)
+	fail := admissionv1.Fail
+	ignore := admissionv1.Ignore
+
+	t.Run("should report istio webhooks with their failure policy and CA bundle state", func(t *testing.T) {
+		// given
+		injector := &admissionv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "istio-sidecar-injector"},
+			Webhooks: []admissionv1.MutatingWebhook{
+				{Name: "namespace.sidecar-injector.istio.io", FailurePolicy: &fail, ClientConfig: admissionv1.WebhookClientConfig{CABundle: []byte("ca")}},
+				{Name: "object.sidecar-injector.istio.io", FailurePolicy: &fail},
+			},
+		}
+		validator := &admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-validator", Labels: map[string]string{"istio.io/rev": "default"}},
+			Webhooks: []admissionv1.ValidatingWebhook{
+				{Name: "validation.istio.io", FailurePolicy: &ignore, ClientConfig: admissionv1.WebhookClientConfig{CABundle: []byte("ca")}},
+			},
+		}
+		unrelated := &admissionv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "cert-manager-webhook"},
+			Webhooks:   []admissionv1.ValidatingWebhook{{Name: "webhook.cert-manager.io", FailurePolicy: &fail}},
+		}
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(injector, validator, unrelated), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		report, err := wrapper.GetWebhookConfigurations(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []WebhookReport{
+			{Kind: "ValidatingWebhookConfiguration", Configuration: "default-validator", Webhook: "validation.istio.io", FailurePolicy: "Ignore", CABundlePopulated: true},
+			{Kind: "MutatingWebhookConfiguration", Configuration: "istio-sidecar-injector", Webhook: "namespace.sidecar-injector.istio.io", FailurePolicy: "Fail", CABundlePopulated: true},
+			{Kind: "MutatingWebhookConfiguration", Configuration: "istio-sidecar-injector", Webhook: "object.sidecar-injector.istio.io", FailurePolicy: "Fail", CABundlePopulated: false},
+		}, report)
+	})
+
+	t.Run("should return empty report when there are no istio webhooks", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(fake.NewSimpleClientset(), nil)
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		report, err := wrapper.GetWebhookConfigurations(context.TODO(), kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, report)
+	})
+
+	t.Run("should return error when kubeclient could not be retrieved", func(t *testing.T) {
+		// given
+		provider := clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Kubeclient error"))
+		wrapper := NewDefaultIstioPerformer(nil, &proxymocks.IstioProxyReset{}, &provider, &datamocks.Gatherer{})
+
+		// when
+		_, err := wrapper.GetWebhookConfigurations(context.TODO(), kubeConfig, log)
+
+		// then
+		require.EqualError(t, err, "Kubeclient error")
+	})
+}
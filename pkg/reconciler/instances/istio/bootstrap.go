@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/clientset"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
@@ -24,7 +25,7 @@ const (
 // Due to current Reconciler limitations - lack of well defined reconciler instances lifetime - we have to initialize it once per reconcile/delete action.
 func istioPerformerCreator(istioProxyReset proxy.IstioProxyReset, provider clientset.Provider, name string, gatherer data.Gatherer) bootstrapIstioPerformer {
 
-	res := func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+	res := func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
 		pathsConfig := os.Getenv(istioctlBinaryPathEnvKey)
 		if len(pathsConfig) > istioctlBinaryPathMaxLen {
 			return nil, fmt.Errorf("%s env variable exceeds the maximum istio path limit of %d characters", istioctlBinaryPathEnvKey, istioctlSingleBinaryPathMaxLen)
@@ -46,7 +47,9 @@ func istioPerformerCreator(istioProxyReset proxy.IstioProxyReset, provider clien
 			return nil, err
 		}
 
-		return actions.NewDefaultIstioPerformer(resolver, istioProxyReset, provider, gatherer), nil
+		performer := actions.NewDefaultIstioPerformer(resolver, istioProxyReset, provider, gatherer)
+		configureIstioPerformer(performer, task, logger)
+		return performer, nil
 	}
 	return res
 }
@@ -71,6 +74,10 @@ func (dcr *defaultCommanderResolver) GetCommander(version istioctl.Version) (ist
 	return &res, nil
 }
 
+func (dcr *defaultCommanderResolver) SupportedVersions() []istioctl.Version {
+	return dcr.istioBinaryResolver.AvailableVersions()
+}
+
 func newDefaultCommanderResolver(paths []string, log *zap.SugaredLogger) (actions.CommanderResolver, error) {
 
 	istioBinaryResolver, err := istioctl.NewDefaultIstioctlResolver(paths, istioctl.DefaultVersionChecker{})
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	istioctlmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
 	"github.com/stretchr/testify/require"
 )
 
@@ -181,3 +183,22 @@ func TestEnsureFileExecutable(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestDefaultCommanderResolverSupportedVersions(t *testing.T) {
+
+	t.Run("should return the versions of the available istioctl binaries", func(t *testing.T) {
+		//given
+		vc := istioctlmocks.VersionChecker{}
+		vc.On("GetIstioVersion", "/b").Return(istioctl.VersionFromString("1.2.4"))
+		vc.On("GetIstioVersion", "/a").Return(istioctl.VersionFromString("1.1.0"))
+		istioBinaryResolver, err := istioctl.NewDefaultIstioctlResolver([]string{"/b", "/a"}, &vc)
+		require.NoError(t, err)
+		resolver := defaultCommanderResolver{log: zap.NewNop().Sugar(), paths: []string{"/b", "/a"}, istioBinaryResolver: istioBinaryResolver}
+		//when
+		versions := resolver.SupportedVersions()
+		//then
+		require.Len(t, versions, 2)
+		require.Equal(t, "1.1.0", versions[0].String())
+		require.Equal(t, "1.2.4", versions[1].String())
+	})
+}
@@ -0,0 +1,22 @@
+package istio
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+)
+
+const (
+	cleanupNamespacesConfigKey = "istio.cleanup.namespaces"
+	kymaSystemNamespace        = "kyma-system"
+)
+
+// cleanupNamespaces returns the namespaces istio related resources are deleted from on uninstall, configured as a
+// comma-separated list. Without configuration kyma-system and the istio namespace are cleaned.
+func cleanupNamespaces(task *reconciler.Task) []string {
+	value, ok := task.Configuration[cleanupNamespacesConfigKey]
+	if ok && value != nil {
+		if namespaces := commaSeparatedList(value); len(namespaces) > 0 {
+			return namespaces
+		}
+	}
+	return []string{kymaSystemNamespace, istioSystemNamespace(task)}
+}
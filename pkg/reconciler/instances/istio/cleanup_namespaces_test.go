@@ -0,0 +1,43 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cleanupNamespaces(t *testing.T) {
+
+	t.Run("should clean kyma-system and the istio namespace by default", func(t *testing.T) {
+		// when
+		namespaces := cleanupNamespaces(&reconciler.Task{})
+
+		// then
+		require.Equal(t, []string{"kyma-system", "istio-system"}, namespaces)
+	})
+
+	t.Run("should clean kyma-system and the configured istio namespace by default", func(t *testing.T) {
+		// when
+		namespaces := cleanupNamespaces(&reconciler.Task{Configuration: map[string]interface{}{"istio.namespace": "custom-istio"}})
+
+		// then
+		require.Equal(t, []string{"kyma-system", "custom-istio"}, namespaces)
+	})
+
+	t.Run("should clean the configured namespaces", func(t *testing.T) {
+		// when
+		namespaces := cleanupNamespaces(&reconciler.Task{Configuration: map[string]interface{}{"istio.cleanup.namespaces": "team-a, team-b,istio-system"}})
+
+		// then
+		require.Equal(t, []string{"team-a", "team-b", "istio-system"}, namespaces)
+	})
+
+	t.Run("should clean the default namespaces when the configured list is empty", func(t *testing.T) {
+		// when
+		namespaces := cleanupNamespaces(&reconciler.Task{Configuration: map[string]interface{}{"istio.cleanup.namespaces": " , "}})
+
+		// then
+		require.Equal(t, []string{"kyma-system", "istio-system"}, namespaces)
+	})
+}
@@ -27,30 +27,37 @@ const (
 
 // ApplyCNIConfiguration applies CNI configuration from kyma-istio-cni ConfigMap to the Istio Operator.
 // If there is no such ConfigMap, it defaults to the operator file.
-func ApplyCNIConfiguration(ctx context.Context, provider clientset.Provider, operatorManifest string, kubeConfig string, logger *zap.SugaredLogger) (string, error) {
+// Besides the resulting manifest it returns whether CNI is enabled in it.
+func ApplyCNIConfiguration(ctx context.Context, provider clientset.Provider, operatorManifest string, kubeConfig string, logger *zap.SugaredLogger) (string, bool, error) {
 	kubeClient, err := provider.RetrieveFrom(kubeConfig, logger)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
 	cniEnabled, err := getCNIConfigMapValue(ctx, kubeClient)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
+	resultManifest := operatorManifest
 	if cniEnabled != "" {
-		combinedManifest, err := applyIstioCNI(cniEnabled, operatorManifest)
+		resultManifest, err = applyIstioCNI(cniEnabled, operatorManifest)
 		if err != nil {
 			logger.Error("could not apply Istio CNI ConfigMap into Istio Operator")
-			return "", err
+			return "", false, err
 		}
 		logger.Debugf("Istio CNI ConfigMap was applied to the Istio Operator configuration")
-		return combinedManifest, nil
+	} else {
+		logger.Debugf("no Istio CNI found on the cluster, applying default configuration")
 	}
 
-	logger.Debugf("no Istio CNI found on the cluster, applying default configuration")
+	resultCNIState, err := getCNIState(resultManifest)
+	if err != nil {
+		return "", false, err
+	}
+	logger.Infof("Istio CNI enabled in the configuration to be applied: %t", resultCNIState)
 
-	return operatorManifest, nil
+	return resultManifest, resultCNIState, nil
 }
 
 // GetActualCNIState checks if CNI is enabled on Istio Operator installed on the cluster and returns its value.
@@ -101,6 +108,19 @@ func applyIstioCNI(cmValue string, operatorManifest string) (string, error) {
 	return string(outputManifest), nil
 }
 
+func getCNIState(operatorManifest string) (bool, error) {
+	iop := istioOperator.IstioOperator{}
+	err := json.Unmarshal([]byte(operatorManifest), &iop)
+	if err != nil {
+		return false, err
+	}
+	if iop.Spec == nil || iop.Spec.Components == nil || iop.Spec.Components.Cni == nil {
+		return false, nil
+	}
+
+	return iop.Spec.Components.Cni.Enabled.GetValue(), nil
+}
+
 func getIstioOperator(dynamicClient dynamic.Interface) (*istioOperator.IstioOperator, error) {
 	res := schema.GroupVersionResource{Group: "install.istio.io", Version: "v1alpha1", Resource: "istiooperators"}
 	obj, err := dynamicClient.Resource(res).Namespace(istioNamespace).Get(context.Background(), istioOperatorName, metav1.GetOptions{})
@@ -46,12 +46,13 @@ func Test_ApplyCNIConfiguration(t *testing.T) {
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(nil, errors.New("Istio client error"))
 
 		// when
-		outputManifest, err := ApplyCNIConfiguration(ctx, &provider, istioManifest, kubeConfig, log)
+		outputManifest, cniEnabled, err := ApplyCNIConfiguration(ctx, &provider, istioManifest, kubeConfig, log)
 
 		// then
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Istio client error")
 		require.Equal(t, outputManifest, "")
+		require.False(t, cniEnabled)
 	})
 	t.Run("should return merged configuration, when there is a Istio CM with different configuration", func(t *testing.T) {
 		// given
@@ -70,13 +71,14 @@ func Test_ApplyCNIConfiguration(t *testing.T) {
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(client, nil)
 
 		// when
-		outputManifest, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
+		outputManifest, cniEnabled, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
 
 		// then
 		require.NoError(t, err)
 		err = json.Unmarshal([]byte(outputManifest), &iop)
 		require.NoError(t, err)
 		require.Equal(t, configMapValueBool, iop.Spec.Components.Cni.Enabled.GetValue())
+		require.False(t, cniEnabled)
 
 	})
 	t.Run("should not return merged configuration, when there is a Istio CM with invalid configuration", func(t *testing.T) {
@@ -93,11 +95,12 @@ func Test_ApplyCNIConfiguration(t *testing.T) {
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(client, nil)
 
 		// when
-		outputManifest, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
+		outputManifest, cniEnabled, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
 
 		// then
 		require.NoError(t, err)
 		require.Equal(t, outputManifest, istioManifest)
+		require.True(t, cniEnabled)
 	})
 	t.Run("should not return merged configuration, when there is a Istio CM with the same configuration", func(t *testing.T) {
 		// given
@@ -113,11 +116,12 @@ func Test_ApplyCNIConfiguration(t *testing.T) {
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(client, nil)
 
 		// when
-		outputManifest, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
+		outputManifest, cniEnabled, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
 
 		// then
 		require.NoError(t, err)
 		require.Equal(t, outputManifest, istioManifest)
+		require.True(t, cniEnabled)
 	})
 	t.Run("should default to the Istio Operator when ConfigMap is not on the cluster", func(t *testing.T) {
 		// given
@@ -126,11 +130,27 @@ func Test_ApplyCNIConfiguration(t *testing.T) {
 		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(client, nil)
 
 		// when
-		outputManifest, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
+		outputManifest, cniEnabled, err := ApplyCNIConfiguration(ctx, provider, istioManifest, kubeConfig, log)
 
 		// then
 		require.NoError(t, err)
 		require.Equal(t, outputManifest, istioManifest)
+		require.True(t, cniEnabled)
+	})
+	t.Run("should report CNI disabled when neither ConfigMap nor Istio Operator enable it", func(t *testing.T) {
+		// given
+		client := k8sClientFake.NewSimpleClientset()
+		provider := &clientsetmocks.Provider{}
+		provider.On("RetrieveFrom", mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(client, nil)
+		manifestWithoutCNI := `{"kind":"IstioOperator","apiVersion":"install.istio.io/v1alpha1","metadata":{"name":"default-operator","namespace":"istio-system"},"spec":{}}`
+
+		// when
+		outputManifest, cniEnabled, err := ApplyCNIConfiguration(ctx, provider, manifestWithoutCNI, kubeConfig, log)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, manifestWithoutCNI, outputManifest)
+		require.False(t, cniEnabled)
 	})
 }
 
@@ -0,0 +1,63 @@
+package istio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VersionViolation describes why two versions are not compatible.
+type VersionViolation string
+
+const (
+	// ViolationMinorVersionSkew means the versions differ by more than one minor version.
+	ViolationMinorVersionSkew VersionViolation = "minor-version-skew"
+
+	// ViolationClientVersionSkew means the istioctl binary differs from the target version by more than one minor version.
+	ViolationClientVersionSkew VersionViolation = "client-version-skew"
+
+	// ViolationVersionMismatch means the version does not match the target version.
+	ViolationVersionMismatch VersionViolation = "version-mismatch"
+)
+
+// IncompatibleVersionError is returned by the version compatibility checks when Component in FromVersion can not be
+// moved to ToVersion. Action is the operation which was checked, e.g. upgrade or downgrade.
+type IncompatibleVersionError struct {
+	Component   string
+	FromVersion string
+	ToVersion   string
+	Action      string
+	Violation   VersionViolation
+}
+
+func (e *IncompatibleVersionError) Error() string {
+	switch e.Violation {
+	case ViolationClientVersionSkew:
+		return fmt.Sprintf("Istio could not be updated since the binary version: %s is not compatible with the target version: %s - the difference between versions exceeds one minor version", e.FromVersion, e.ToVersion)
+	case ViolationVersionMismatch:
+		return fmt.Sprintf("Istio %s version %s do not match target version %s", e.Component, e.FromVersion, e.ToVersion)
+	default:
+		return fmt.Sprintf("Could not perform %s for %s from version: %s to version: %s - the difference between versions exceed one minor version",
+			e.Action, e.Component, e.FromVersion, e.ToVersion)
+	}
+}
+
+// IncompatibleVersionsError aggregates the incompatibilities of several data plane versions.
+// Unwrapping it returns the first incompatibility.
+type IncompatibleVersionsError struct {
+	Errors []*IncompatibleVersionError
+}
+
+func (e *IncompatibleVersionsError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("Found %d incompatible data plane versions: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *IncompatibleVersionsError) Unwrap() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e.Errors[0]
+}
@@ -0,0 +1,97 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IncompatibleVersionError(t *testing.T) {
+
+	t.Run("should return typed error from isComponentCompatible", func(t *testing.T) {
+		// when
+		_, err := isComponentCompatible("1.1.0", "1.3.0", "Pilot")
+
+		// then
+		var incompatibleErr *IncompatibleVersionError
+		require.True(t, errors.As(err, &incompatibleErr))
+		require.Equal(t, &IncompatibleVersionError{
+			Component:   "Pilot",
+			FromVersion: "1.1.0",
+			ToVersion:   "1.3.0",
+			Action:      "upgrade",
+			Violation:   ViolationMinorVersionSkew,
+		}, incompatibleErr)
+		require.EqualError(t, err, "Could not perform upgrade for Pilot from version: 1.1.0 to version: 1.3.0 - the difference between versions exceed one minor version")
+	})
+
+	t.Run("should return typed error from canUpdate", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.1.0",
+			TargetVersion:     "1.1.0",
+			PilotVersion:      "1.3.0",
+			DataPlaneVersions: map[string]bool{"1.3.0": true},
+		}
+
+		// when
+		_, err := canUpdate(version)
+
+		// then
+		var incompatibleErr *IncompatibleVersionError
+		require.True(t, errors.As(err, &incompatibleErr))
+		require.Equal(t, "downgrade", incompatibleErr.Action)
+		require.Equal(t, "Pilot", incompatibleErr.Component)
+	})
+
+	t.Run("should return typed error when pilot does not match the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion: "1.2.0",
+			TargetVersion: "1.2.0",
+			PilotVersion:  "1.1.0",
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		var incompatibleErr *IncompatibleVersionError
+		require.True(t, errors.As(err, &incompatibleErr))
+		require.Equal(t, ViolationVersionMismatch, incompatibleErr.Violation)
+		require.EqualError(t, err, "Istio pilot version 1.1.0 do not match target version 1.2.0")
+	})
+
+	t.Run("should return aggregated typed error for several incompatible data plane versions", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.3.0",
+			TargetVersion:     "1.3.0",
+			PilotVersion:      "1.3.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true, "1.1.0": true, "1.3.0": true},
+		}
+
+		// when
+		err := ensureCanResetProxies(version)
+
+		// then
+		var incompatibleErrs *IncompatibleVersionsError
+		require.True(t, errors.As(err, &incompatibleErrs))
+		require.Len(t, incompatibleErrs.Errors, 2)
+		require.Equal(t, "1.0.0", incompatibleErrs.Errors[0].FromVersion)
+		require.Equal(t, "1.1.0", incompatibleErrs.Errors[1].FromVersion)
+		var incompatibleErr *IncompatibleVersionError
+		require.True(t, errors.As(err, &incompatibleErr))
+		require.Equal(t, "1.0.0", incompatibleErr.FromVersion)
+	})
+
+	t.Run("should return typed error when istioctl is not compatible with the target version", func(t *testing.T) {
+		// when
+		err := (&IncompatibleVersionError{FromVersion: "1.0.0", ToVersion: "1.2.0", Violation: ViolationClientVersionSkew}).Error()
+
+		// then
+		require.Equal(t, "Istio could not be updated since the binary version: 1.0.0 is not compatible with the target version: 1.2.0 - the difference between versions exceeds one minor version", err)
+	})
+}
@@ -0,0 +1,111 @@
+package istio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"go.uber.org/zap"
+)
+
+const (
+	applyTimeoutConfigKey                    = "istio.applyTimeout"
+	continueOnCleanupErrorConfigKey          = "istio.uninstall.continueOnCleanupError"
+	dryRunConfigKey                          = "istio.dryRun"
+	dumpMergedConfigConfigKey                = "istio.dumpMergedConfig"
+	forceReinstallConfigKey                  = "istio.forceReinstall"
+	forceReinstallCleanConfigKey             = "istio.forceReinstall.clean"
+	installRollbackOnFailureConfigKey        = "istio.install.rollbackOnFailure"
+	ingressGatewaySkipRestartConfigKey       = "istio.ingressGateway.skipRestart"
+	istioOperatorBackupRetentionConfigKey    = "istio.istioOperatorBackup.retention"
+	istiodTerminationIntervalConfigKey       = "istio.forceReinstall.istiodTerminationInterval"
+	istiodTerminationTimeoutConfigKey        = "istio.forceReinstall.istiodTerminationTimeout"
+	labelNamespacesFailureAsWarningConfigKey = "istio.labelNamespaces.failureAsWarning"
+	labelNamespacesOnlyConfigKey             = "istio.labelNamespacesOnly"
+	maxConcurrentNamespacesConfigKey         = "istio.proxyReset.maxConcurrentNamespaces"
+	namespaceDeletionIntervalConfigKey       = "istio.uninstall.namespaceDeletionInterval"
+	namespaceDeletionTimeoutConfigKey        = "istio.uninstall.namespaceDeletionTimeout"
+	performerIntervalConfigKey               = "istio.performer.interval"
+	performerMaxRetryDelayConfigKey          = "istio.performer.maxRetryDelay"
+	performerRetriesConfigKey                = "istio.performer.retries"
+	performerRetryDelayConfigKey             = "istio.performer.retryDelay"
+	performerRetryDelayTypeConfigKey         = "istio.performer.retryDelayType"
+	performerTimeoutConfigKey                = "istio.performer.timeout"
+	phaseWaitIntervalConfigKey               = "istio.install.phaseWaitInterval"
+	phaseWaitTimeoutConfigKey                = "istio.install.phaseWaitTimeout"
+	phasedInstallConfigKey                   = "istio.install.phased"
+	proxyResetFailOnErrorConfigKey           = "istio.proxyReset.failOnError"
+	proxyVersionFromIstiodConfigKey          = "istio.proxyReset.versionFromIstiod"
+	uninstallBackupIstioOperatorConfigKey    = "istio.uninstall.backupIstioOperator"
+	uninstallGracePeriodConfigKey            = "istio.uninstall.gracePeriod"
+	uninstallRetriesConfigKey                = "istio.uninstall.retries"
+	uninstallRetryDelayConfigKey             = "istio.uninstall.retryDelay"
+	uninstallWaitIntervalConfigKey           = "istio.uninstall.waitInterval"
+	uninstallWaitTimeoutConfigKey            = "istio.uninstall.waitTimeout"
+	validateOperatorSchemaConfigKey          = "istio.validateOperatorSchema"
+)
+
+// boolConfig returns the boolean configured for the task under key. A missing or invalid value is false.
+func boolConfig(task *reconciler.Task, key string, logger *zap.SugaredLogger) bool {
+	value, ok := task.Configuration[key]
+	if !ok || value == nil {
+		return false
+	}
+	enabled, err := strconv.ParseBool(fmt.Sprint(value))
+	if err != nil {
+		logger.Warnf("Invalid %s value %v, using default false", key, value)
+		return false
+	}
+	return enabled
+}
+
+// intConfig returns the non-negative integer configured for the task under key. It reports false if the value is missing or invalid.
+func intConfig(task *reconciler.Task, key string, logger *zap.SugaredLogger) (int, bool) {
+	value, ok := task.Configuration[key]
+	if !ok || value == nil {
+		return 0, false
+	}
+	number, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || number < 0 {
+		logger.Warnf("Invalid %s value %v, using the default", key, value)
+		return 0, false
+	}
+	return number, true
+}
+
+// durationConfig returns the non-negative duration configured for the task under key, e.g. "30s". It reports false if the value
+// is missing or invalid.
+func durationConfig(task *reconciler.Task, key string, logger *zap.SugaredLogger) (time.Duration, bool) {
+	value, ok := task.Configuration[key]
+	if !ok || value == nil {
+		return 0, false
+	}
+	duration, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil || duration < 0 {
+		logger.Warnf("Invalid %s value %v, using the default", key, value)
+		return 0, false
+	}
+	return duration, true
+}
+
+// stringConfig returns the string configured for the task under key. A missing value is empty.
+func stringConfig(task *reconciler.Task, key string) string {
+	value, ok := task.Configuration[key]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// commaSeparatedList splits a comma-separated configuration value into its trimmed, non-empty elements.
+func commaSeparatedList(value interface{}) []string {
+	var elements []string
+	for _, element := range strings.Split(fmt.Sprint(value), ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
@@ -0,0 +1,123 @@
+package istio
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_boolConfig(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should be false when it is not configured", func(t *testing.T) {
+		// when
+		enabled := boolConfig(&reconciler.Task{}, continueOnCleanupErrorConfigKey, logger)
+
+		// then
+		require.False(t, enabled)
+	})
+
+	t.Run("should be true when it is enabled as boolean", func(t *testing.T) {
+		// when
+		enabled := boolConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.continueOnCleanupError": true}}, continueOnCleanupErrorConfigKey, logger)
+
+		// then
+		require.True(t, enabled)
+	})
+
+	t.Run("should be true when it is enabled as string", func(t *testing.T) {
+		// when
+		enabled := boolConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.continueOnCleanupError": "true"}}, continueOnCleanupErrorConfigKey, logger)
+
+		// then
+		require.True(t, enabled)
+	})
+
+	t.Run("should be false when it is disabled", func(t *testing.T) {
+		// when
+		enabled := boolConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.continueOnCleanupError": "false"}}, continueOnCleanupErrorConfigKey, logger)
+
+		// then
+		require.False(t, enabled)
+	})
+
+	t.Run("should be false when the configured value is invalid", func(t *testing.T) {
+		// when
+		enabled := boolConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.continueOnCleanupError": "always"}}, continueOnCleanupErrorConfigKey, logger)
+
+		// then
+		require.False(t, enabled)
+	})
+}
+
+func Test_intConfig(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should not be configured when it is missing", func(t *testing.T) {
+		// when
+		_, ok := intConfig(&reconciler.Task{}, maxConcurrentNamespacesConfigKey, logger)
+
+		// then
+		require.False(t, ok)
+	})
+
+	t.Run("should return the configured number", func(t *testing.T) {
+		// when
+		number, ok := intConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.maxConcurrentNamespaces": "4"}}, maxConcurrentNamespacesConfigKey, logger)
+
+		// then
+		require.True(t, ok)
+		require.Equal(t, 4, number)
+	})
+
+	t.Run("should not be configured when the number is negative", func(t *testing.T) {
+		// when
+		_, ok := intConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.maxConcurrentNamespaces": -1}}, maxConcurrentNamespacesConfigKey, logger)
+
+		// then
+		require.False(t, ok)
+	})
+}
+
+func Test_durationConfig(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should not be configured when it is missing", func(t *testing.T) {
+		// when
+		_, ok := durationConfig(&reconciler.Task{}, uninstallRetryDelayConfigKey, logger)
+
+		// then
+		require.False(t, ok)
+	})
+
+	t.Run("should return the configured duration", func(t *testing.T) {
+		// when
+		duration, ok := durationConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.retryDelay": "30s"}}, uninstallRetryDelayConfigKey, logger)
+
+		// then
+		require.True(t, ok)
+		require.Equal(t, 30*time.Second, duration)
+	})
+
+	t.Run("should not be configured when the duration is invalid", func(t *testing.T) {
+		// when
+		_, ok := durationConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.retryDelay": "soon"}}, uninstallRetryDelayConfigKey, logger)
+
+		// then
+		require.False(t, ok)
+	})
+
+	t.Run("should not be configured when the duration is negative", func(t *testing.T) {
+		// when
+		_, ok := durationConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.uninstall.retryDelay": "-1s"}}, uninstallRetryDelayConfigKey, logger)
+
+		// then
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,105 @@
+package istio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/pkg/errors"
+)
+
+// DryRunResult describes the decision MainReconcileAction made in dry-run mode and the versions it is based on.
+type DryRunResult struct {
+	Action            ActionKind
+	TargetVersion     string
+	PilotVersion      string
+	DataPlaneVersions string
+	Reason            string
+}
+
+// DryRunDiff reports what a reconciliation of the task would change without applying anything on the cluster.
+// The result contains the summary of planned actions followed by the unified diff between the IstioOperator
+// installed on the cluster and the rendered target IstioOperator.
+func DryRunDiff(context *service.ActionContext, getIstioPerformer bootstrapIstioPerformer) (string, error) {
+	context = withLogFields(context)
+	performer, err := getIstioPerformer(context.Task, context.Logger)
+	if err != nil {
+		return "", err
+	}
+
+	istioManifest, err := renderIstioManifest(context)
+	if err != nil {
+		return "", err
+	}
+
+	istioStatus, err := getInstalledVersion(context, performer)
+	if err != nil {
+		return "", err
+	}
+
+	diff, err := performer.GetIstioOperatorDiff(context.Context, context.KubeClient.Kubeconfig(), istioManifest.Manifest, context.Logger)
+	if err != nil {
+		return "", errors.Wrap(err, "Could not compute Istio Operator diff")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Planned actions:\n")
+	for _, plannedAction := range PlanActions(context.Task, istioStatus, context.Logger) {
+		sb.WriteString(fmt.Sprintf("- %s: %s", plannedAction.Action, plannedAction.Outcome))
+		if plannedAction.Description != "" {
+			sb.WriteString(fmt.Sprintf(" (%s)", plannedAction.Description))
+		}
+		if plannedAction.Reason != "" {
+			sb.WriteString(fmt.Sprintf(": %s", plannedAction.Reason))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	if diff == "" {
+		sb.WriteString("No changes to the Istio Operator\n")
+	} else {
+		sb.WriteString(diff)
+	}
+
+	result := sb.String()
+	context.Logger.Infof("Istio reconciliation dry-run result:\n%s", result)
+
+	return result, nil
+}
+
+// dryRunDeployIstio renders and validates the Istio chart and decides how Istio would be deployed, without
+// installing, updating or labeling anything on the cluster. A blocked deployment is reported with the error
+// the reconciliation would fail with.
+func dryRunDeployIstio(context *service.ActionContext, performer actions.IstioPerformer) (DryRunResult, error) {
+	plan, err := planDeployment(context, performer)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+
+	result := DryRunResult{
+		Action:            plan.actionKind,
+		TargetVersion:     plan.istioStatus.TargetVersion,
+		PilotVersion:      plan.istioStatus.PilotVersion,
+		DataPlaneVersions: dataPlaneVersionsString(plan.istioStatus, ","),
+	}
+	switch plan.actionKind {
+	case ActionKindSkip:
+		result.Reason = fmt.Sprintf("Istio is already at target version %s", plan.istioStatus.TargetVersion)
+	case ActionKindUpdate:
+		if plan.reinstall {
+			result.Reason = "Forced clean reinstall, Istio is uninstalled before it is installed again"
+		}
+	case ActionKindBlocked:
+		result.Reason = errorReason(plan.err)
+	}
+
+	context.Logger.Infow("Istio reconciliation dry-run, no changes applied to the cluster",
+		"action", result.Action,
+		"targetVersion", result.TargetVersion,
+		"pilotVersion", result.PilotVersion,
+		"dataPlaneVersions", result.DataPlaneVersions,
+		"reason", result.Reason)
+
+	return result, plan.err
+}
@@ -0,0 +1,309 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	chartmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	actionsmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DryRunDiff(t *testing.T) {
+
+	istioOnTheCluster := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.1.0",
+		DataPlaneVersions: map[string]bool{"1.1.0": true},
+	}
+
+	operatorDiff := `--- current
++++ target
+@@ -1,3 +1,3 @@
+ components:
+   cni:
+-    enabled: true
++    enabled: false
+`
+
+	t.Run("should return planned actions and Istio Operator diff without applying anything", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: "manifest"}, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("GetIstioOperatorDiff", actionContext.Context, "kubeconfig", "manifest", mock.AnythingOfType("*zap.SugaredLogger")).Return(operatorDiff, nil)
+
+		// when
+		result, err := DryRunDiff(actionContext, performerCreatorFn(&performer))
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, "- MainReconcileAction: run (Update Istio pilot from 1.1.0 and data plane from 1.1.0 to version 1.2.0)")
+		require.Contains(t, result, "-    enabled: true\n+    enabled: false")
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.Anything)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.Anything)
+	})
+
+	t.Run("should report no changes when the Istio Operator diff is empty", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: "manifest"}, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("GetIstioOperatorDiff", actionContext.Context, "kubeconfig", "manifest", mock.AnythingOfType("*zap.SugaredLogger")).Return("", nil)
+
+		// when
+		result, err := DryRunDiff(actionContext, performerCreatorFn(&performer))
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, "No changes to the Istio Operator")
+	})
+
+	t.Run("should return error when the Istio Operator diff could not be computed", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: "manifest"}, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(istioOnTheCluster, nil)
+		performer.On("GetIstioOperatorDiff", actionContext.Context, "kubeconfig", "manifest", mock.AnythingOfType("*zap.SugaredLogger")).Return("", errors.New("diff error"))
+
+		// when
+		_, err := DryRunDiff(actionContext, performerCreatorFn(&performer))
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "diff error")
+	})
+}
+
+func Test_MainReconcileAction_DryRun(t *testing.T) {
+
+	noIstioOnTheCluster := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		DataPlaneVersions: map[string]bool{},
+	}
+
+	t.Run("should not install, update or label namespaces in dry-run", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.dryRun": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(noIstioOnTheCluster, nil)
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		provider.AssertNumberOfCalls(t, "RenderManifest", 1)
+		performer.AssertNotCalled(t, "Install", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.Anything)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.Anything)
+		performer.AssertNotCalled(t, "LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", mock.Anything)
+	})
+
+	t.Run("should return error when the manifest could not be rendered in dry-run", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(nil, errors.New("template error"))
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.dryRun": "true"}
+		performer := actionsmocks.IstioPerformer{}
+		action := MainReconcileAction{getIstioPerformer: performerCreatorFn(&performer)}
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "template error")
+		performer.AssertNotCalled(t, "LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "", mock.Anything)
+	})
+}
+
+func Test_dryRunDeployIstio(t *testing.T) {
+
+	newActionContext := func() *service.ActionContext {
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		return newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+	}
+
+	t.Run("should decide to install when Istio is not on the cluster", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0"}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(newActionContext(), &performer)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindInstall, result.Action)
+	})
+
+	t.Run("should decide to update when Istio on the cluster can be updated", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(newActionContext(), &performer)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, DryRunResult{Action: ActionKindUpdate, TargetVersion: "1.2.0", PilotVersion: "1.1.0", DataPlaneVersions: "1.1.0"}, result)
+	})
+
+	t.Run("should report blocked with reason and error when Istio on the cluster can not be updated", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.3.0", TargetVersion: "1.3.0", PilotVersion: "1.1.0", DataPlaneVersions: map[string]bool{"1.1.0": true}}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(newActionContext(), &performer)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the difference between versions exceed one minor version")
+		require.Equal(t, ActionKindBlocked, result.Action)
+		require.Equal(t, err.Error(), result.Reason)
+	})
+
+	t.Run("should decide to skip when Istio on the cluster is already at target version", func(t *testing.T) {
+		// given
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(newActionContext(), &performer)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindSkip, result.Action)
+		require.Contains(t, result.Reason, "already at target version 1.2.0")
+	})
+
+	t.Run("should decide to update when forced and Istio on the cluster is already at target version", func(t *testing.T) {
+		// given
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(actionContext, &performer)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindUpdate, result.Action)
+	})
+
+	t.Run("should report a forced clean reinstall", func(t *testing.T) {
+		// given
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true, "istio.forceReinstall.clean": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.2.0": true}}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(actionContext, &performer)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ActionKindUpdate, result.Action)
+		require.Contains(t, result.Reason, "clean reinstall")
+		performer.AssertNotCalled(t, "Reinstall", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not force update in dry-run when the data plane is two minor versions behind the target version", func(t *testing.T) {
+		// given
+		actionContext := newActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.forceReinstall": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).
+			Return(actions.IstioStatus{ClientVersion: "1.2.0", TargetVersion: "1.2.0", PilotVersion: "1.2.0", DataPlaneVersions: map[string]bool{"1.0.0": true}}, nil)
+
+		// when
+		result, err := dryRunDeployIstio(actionContext, &performer)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, ActionKindBlocked, result.Action)
+	})
+
+	t.Run("should return error when the rendered Istio Operator is invalid", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: "apiVersion: install.istio.io/v1alpha1\nkind: IstioOperator\nmetadata:\n  name: name\n"}, nil)
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+		performer := actionsmocks.IstioPerformer{}
+
+		// when
+		_, err := dryRunDeployIstio(actionContext, &performer)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Rendered Istio manifest is invalid: Istio Operator is invalid: spec is missing")
+		performer.AssertNotCalled(t, "Version", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_DryRun_ProxyResetAndUninstall(t *testing.T) {
+
+	t.Run("should not reset proxies in dry-run", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.dryRun": true}
+		performer := actionsmocks.IstioPerformer{}
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, performer.Calls)
+	})
+
+	t.Run("should not uninstall istio in dry-run", func(t *testing.T) {
+		// given
+		kubeClient := newFakeKubeClient()
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &chartmocks.Provider{}, kubeClient)
+		actionContext.Task.Configuration = map[string]interface{}{"istio.dryRun": "true"}
+		performer := actionsmocks.IstioPerformer{}
+		action := NewUninstallAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, performer.Calls)
+		kubeClient.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,52 @@
+package istio
+
+import (
+	"sync"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+// helperVersions memoizes the parsed versions of the compatibility checks, which parse the same few versions of the
+// IstioStatus many times during a reconciliation.
+var helperVersions = newHelperVersionCache(semver.NewVersion)
+
+type parsedHelperVersion struct {
+	version helperVersion
+	err     error
+}
+
+// helperVersionCache parses every version string once and returns the stored result afterwards. The cache is not bounded
+// as the versions of an Istio installation are few and change rarely.
+type helperVersionCache struct {
+	mu       sync.RWMutex
+	versions map[string]parsedHelperVersion
+	parse    func(string) (*semver.Version, error)
+}
+
+func newHelperVersionCache(parse func(string) (*semver.Version, error)) *helperVersionCache {
+	return &helperVersionCache{
+		versions: map[string]parsedHelperVersion{},
+		parse:    parse,
+	}
+}
+
+func (c *helperVersionCache) get(versionInString string) (helperVersion, error) {
+	c.mu.RLock()
+	parsed, ok := c.versions[versionInString]
+	c.mu.RUnlock()
+	if ok {
+		return parsed.version, parsed.err
+	}
+
+	version, err := c.parse(versionInString)
+	if err == nil {
+		parsed = parsedHelperVersion{version: helperVersion{ver: *version}}
+	} else {
+		parsed = parsedHelperVersion{err: err}
+	}
+
+	c.mu.Lock()
+	c.versions[versionInString] = parsed
+	c.mu.Unlock()
+	return parsed.version, parsed.err
+}
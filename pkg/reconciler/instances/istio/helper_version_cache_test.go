@@ -0,0 +1,115 @@
+package istio
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/stretchr/testify/require"
+)
+
+func countingParse(parses *int64) func(string) (*semver.Version, error) {
+	return func(version string) (*semver.Version, error) {
+		atomic.AddInt64(parses, 1)
+		return semver.NewVersion(version)
+	}
+}
+
+func Test_helperVersionCache_get(t *testing.T) {
+
+	t.Run("should parse a version only once", func(t *testing.T) {
+		// given
+		var parses int64
+		cache := newHelperVersionCache(countingParse(&parses))
+
+		// when
+		first, err := cache.get("1.2.3")
+		require.NoError(t, err)
+		second, err := cache.get("1.2.3")
+		require.NoError(t, err)
+
+		// then
+		require.Equal(t, int64(1), parses)
+		require.Zero(t, first.compare(second))
+		require.Equal(t, "1.2.3", second.ver.String())
+	})
+
+	t.Run("should parse an invalid version only once", func(t *testing.T) {
+		// given
+		var parses int64
+		cache := newHelperVersionCache(countingParse(&parses))
+
+		// when
+		_, firstErr := cache.get("1.2.")
+		_, secondErr := cache.get("1.2.")
+
+		// then
+		require.Error(t, firstErr)
+		require.Equal(t, firstErr, secondErr)
+		require.Equal(t, int64(1), parses)
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		// given
+		var parses int64
+		cache := newHelperVersionCache(countingParse(&parses))
+		var wg sync.WaitGroup
+
+		// when
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				version, err := cache.get("1.2.3")
+				require.NoError(t, err)
+				require.Equal(t, "1.2.3", version.ver.String())
+			}()
+		}
+		wg.Wait()
+
+		// then
+		require.LessOrEqual(t, parses, int64(50))
+		require.Len(t, cache.versions, 1)
+	})
+}
+
+func Benchmark_helperVersionCache(b *testing.B) {
+	istioStatus := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.1.0",
+		DataPlaneVersions: map[string]bool{"1.1.0": true, "1.1.1": true},
+	}
+	checks := []func(){
+		func() { _ = isClientCompatibleWithTargetVersion(istioStatus) },
+		func() { _, _ = canUpdate(istioStatus) },
+		func() { _ = isAtTargetVersion(istioStatus) },
+		func() { _ = ensureCanResetProxies(istioStatus) },
+	}
+	original := helperVersions
+	defer func() { helperVersions = original }()
+
+	b.Run("shared cache", func(b *testing.B) {
+		var parses int64
+		helperVersions = newHelperVersionCache(countingParse(&parses))
+		for i := 0; i < b.N; i++ {
+			for _, check := range checks {
+				check()
+			}
+		}
+		b.ReportMetric(float64(parses)/float64(b.N), "parses/op")
+	})
+
+	b.Run("cache per check", func(b *testing.B) {
+		var parses int64
+		for i := 0; i < b.N; i++ {
+			for _, check := range checks {
+				helperVersions = newHelperVersionCache(countingParse(&parses))
+				check()
+			}
+		}
+		b.ReportMetric(float64(parses)/float64(b.N), "parses/op")
+	})
+}
@@ -0,0 +1,44 @@
+package ingressgateway
+
+import (
+	"context"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetMissingCertificateSecrets returns the sorted names of the secrets mounted by the ingress gateway Deployment which do not exist.
+// Restarting the ingress gateway while its certificate secrets are missing would make it serve without TLS certificates.
+func GetMissingCertificateSecrets(ctx context.Context, k8sClient client.Client, kubeClient kubernetes.Interface) ([]string, error) {
+	deployment := appsv1.Deployment{}
+	err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &deployment)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var missing []string
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.Secret == nil {
+			continue
+		}
+		_, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, volume.Secret.SecretName, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			missing = append(missing, volume.Secret.SecretName)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(missing)
+
+	return missing, nil
+}
@@ -0,0 +1,74 @@
+package ingressgateway_test
+
+import (
+	"context"
+	"testing"
+
+	ingressgateway "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/ingress-gateway"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetMissingCertificateSecrets(t *testing.T) {
+	deployment := appsv1.Deployment{
+		ObjectMeta: v1.ObjectMeta{Name: depName, Namespace: depNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{
+						{Name: "ingressgateway-certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "istio-ingressgateway-certs"}}},
+						{Name: "ingressgateway-ca-certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "istio-ingressgateway-ca-certs"}}},
+						{Name: "podinfo", VolumeSource: corev1.VolumeSource{DownwardAPI: &corev1.DownwardAPIVolumeSource{}}},
+					},
+				},
+			},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&deployment).Build()
+
+	t.Run("should return no secrets when all certificate secrets are present", func(t *testing.T) {
+		// given
+		kubeClient := k8sfake.NewSimpleClientset(
+			&corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "istio-ingressgateway-certs", Namespace: depNamespace}},
+			&corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "istio-ingressgateway-ca-certs", Namespace: depNamespace}},
+		)
+
+		// when
+		missing, err := ingressgateway.GetMissingCertificateSecrets(context.TODO(), client, kubeClient)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, missing)
+	})
+
+	t.Run("should return missing certificate secrets", func(t *testing.T) {
+		// given
+		kubeClient := k8sfake.NewSimpleClientset(
+			&corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "istio-ingressgateway-certs", Namespace: depNamespace}},
+		)
+
+		// when
+		missing, err := ingressgateway.GetMissingCertificateSecrets(context.TODO(), client, kubeClient)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"istio-ingressgateway-ca-certs"}, missing)
+	})
+
+	t.Run("should return no secrets when the ingress gateway Deployment does not exist", func(t *testing.T) {
+		// given
+		emptyClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+
+		// when
+		missing, err := ingressgateway.GetMissingCertificateSecrets(context.TODO(), emptyClient, k8sfake.NewSimpleClientset())
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, missing)
+	})
+}
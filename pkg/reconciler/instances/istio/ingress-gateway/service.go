@@ -0,0 +1,83 @@
+package ingressgateway
+
+import (
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultServiceType is the type Istio uses for the ingress gateway Service if none is configured.
+const defaultServiceType = corev1.ServiceTypeLoadBalancer
+
+// ServiceTypeChange describes the transition of the ingress gateway Service type caused by applying the Istio Operator.
+type ServiceTypeChange struct {
+	Live    corev1.ServiceType
+	Desired corev1.ServiceType
+}
+
+// Changed returns true if the ingress gateway Service exists and its type differs from the desired one.
+func (c ServiceTypeChange) Changed() bool {
+	return c.Live != "" && c.Live != c.Desired
+}
+
+// GetServiceTypeChange compares the type of the live ingress gateway Service with the type configured in the given Istio Operator in JSON format.
+// The live type is empty if the Service is not present on the cluster.
+func GetServiceTypeChange(ctx context.Context, client client.Client, operatorManifest string) (ServiceTypeChange, error) {
+	desired, err := getDesiredServiceType(operatorManifest)
+	if err != nil {
+		return ServiceTypeChange{}, err
+	}
+
+	service := corev1.Service{}
+	err = client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &service)
+	if k8serrors.IsNotFound(err) {
+		return ServiceTypeChange{Desired: desired}, nil
+	}
+	if err != nil {
+		return ServiceTypeChange{}, err
+	}
+
+	return ServiceTypeChange{Live: service.Spec.Type, Desired: desired}, nil
+}
+
+func getDesiredServiceType(operatorManifest string) (corev1.ServiceType, error) {
+	var istioOperator struct {
+		Spec struct {
+			Components struct {
+				IngressGateways []struct {
+					Name string `json:"name"`
+					K8s  struct {
+						Service struct {
+							Type corev1.ServiceType `json:"type"`
+						} `json:"service"`
+					} `json:"k8s"`
+				} `json:"ingressGateways"`
+			} `json:"components"`
+			Values struct {
+				Gateways map[string]struct {
+					Type corev1.ServiceType `json:"type"`
+				} `json:"gateways"`
+			} `json:"values"`
+		} `json:"spec"`
+	}
+	err := json.Unmarshal([]byte(operatorManifest), &istioOperator)
+	if err != nil {
+		return "", err
+	}
+
+	// Component settings take precedence over the helm values
+	for _, gateway := range istioOperator.Spec.Components.IngressGateways {
+		if gateway.Name == name && gateway.K8s.Service.Type != "" {
+			return gateway.K8s.Service.Type, nil
+		}
+	}
+	if gateway, ok := istioOperator.Spec.Values.Gateways[name]; ok && gateway.Type != "" {
+		return gateway.Type, nil
+	}
+
+	return defaultServiceType, nil
+}
@@ -0,0 +1,67 @@
+package ingressgateway_test
+
+import (
+	"context"
+	"testing"
+
+	ingressgateway "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/ingress-gateway"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGetServiceTypeChange(t *testing.T) {
+	err := corev1.AddToScheme(scheme.Scheme)
+	require.NoError(t, err)
+
+	withService := func(serviceType corev1.ServiceType) *fake.ClientBuilder {
+		service := corev1.Service{ObjectMeta: v1.ObjectMeta{Name: depName, Namespace: depNamespace}, Spec: corev1.ServiceSpec{Type: serviceType}}
+		return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&service)
+	}
+
+	t.Run("should detect change from LoadBalancer to NodePort configured in components", func(t *testing.T) {
+		client := withService(corev1.ServiceTypeLoadBalancer).Build()
+		operator := `{"spec":{"components":{"ingressGateways":[{"name":"istio-ingressgateway","k8s":{"service":{"type":"NodePort"}}}]}}}`
+
+		change, err := ingressgateway.GetServiceTypeChange(context.TODO(), client, operator)
+
+		require.NoError(t, err)
+		require.True(t, change.Changed())
+		require.Equal(t, corev1.ServiceTypeLoadBalancer, change.Live)
+		require.Equal(t, corev1.ServiceTypeNodePort, change.Desired)
+	})
+
+	t.Run("should detect change from NodePort to the default LoadBalancer", func(t *testing.T) {
+		client := withService(corev1.ServiceTypeNodePort).Build()
+		operator := `{"spec":{}}`
+
+		change, err := ingressgateway.GetServiceTypeChange(context.TODO(), client, operator)
+
+		require.NoError(t, err)
+		require.True(t, change.Changed())
+		require.Equal(t, corev1.ServiceTypeLoadBalancer, change.Desired)
+	})
+
+	t.Run("should not detect change when type configured in values matches", func(t *testing.T) {
+		client := withService(corev1.ServiceTypeNodePort).Build()
+		operator := `{"spec":{"values":{"gateways":{"istio-ingressgateway":{"type":"NodePort"}}}}}`
+
+		change, err := ingressgateway.GetServiceTypeChange(context.TODO(), client, operator)
+
+		require.NoError(t, err)
+		require.False(t, change.Changed())
+	})
+
+	t.Run("should not detect change when ingress gateway Service does not exist", func(t *testing.T) {
+		client := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		operator := `{"spec":{"components":{"ingressGateways":[{"name":"istio-ingressgateway","k8s":{"service":{"type":"NodePort"}}}]}}}`
+
+		change, err := ingressgateway.GetServiceTypeChange(context.TODO(), client, operator)
+
+		require.NoError(t, err)
+		require.False(t, change.Changed())
+		require.Empty(t, change.Live)
+	})
+}
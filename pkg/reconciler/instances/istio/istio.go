@@ -9,6 +9,7 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 const (
@@ -26,17 +27,28 @@ func init() {
 		log.Fatalf("Could not create '%s' component reconciler: %s", ReconcilerNameIstio, err)
 	}
 
+	err = loadHighestMinorPerMajor()
+	if err != nil {
+		log.Fatalf("Could not create '%s' component reconciler: %s", ReconcilerNameIstio, err)
+	}
+
 	gatherer := data.NewDefaultGatherer()
 	matcher := pod.NewParentKindMatcher()
 	provider := clientset.DefaultProvider{}
 	action := reset.NewDefaultPodsResetAction(matcher)
 	istioProxyReset := proxy.NewDefaultIstioProxyReset(gatherer, action)
 
+	actionMetrics := NewPrometheusActionMetrics()
+	err = registerActionMetrics(prometheus.DefaultRegisterer, actionMetrics, log)
+	if err != nil {
+		log.Warnf("Could not register metrics of '%s' component reconciler: %s", ReconcilerNameIstio, err)
+	}
+
 	istioPerformerCreatorFn := istioPerformerCreator(istioProxyReset, &provider, ReconcilerNameIstio, gatherer)
 	reconcilerIstio.
 		WithPreReconcileAction(NewStatusPreAction(istioPerformerCreatorFn)).
-		WithReconcileAction(NewIstioMainReconcileAction(istioPerformerCreatorFn)).
-		WithPostReconcileAction(actions.NewActionAggregate(NewProxyResetPostAction(istioPerformerCreatorFn))).
-		WithDeleteAction(NewUninstallAction(istioPerformerCreatorFn))
+		WithReconcileAction(NewIstioMainReconcileAction(istioPerformerCreatorFn).WithMetrics(actionMetrics)).
+		WithPostReconcileAction(actions.NewActionAggregate(NewProxyResetPostAction(istioPerformerCreatorFn).WithMetrics(actionMetrics))).
+		WithDeleteAction(NewUninstallAction(istioPerformerCreatorFn).WithMetrics(actionMetrics))
 
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
 	commandermocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/mocks"
 	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	istioProxy "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
 	proxymocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy/mocks"
 	k8smocks "github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes/mocks"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
@@ -143,15 +144,15 @@ const (
 	  }`
 )
 
+func performerCreatorFn(p *actions.DefaultIstioPerformer) func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+	return func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+		return p, nil
+	}
+}
+
 // TODO(piotrkpc): here we are testing particular action's behaviour not Istio reconciler. Consider moving those to action_test.go
 func Test_RunUpdateAction(t *testing.T) {
 
-	performerCreatorFn := func(p *actions.DefaultIstioPerformer) func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-		return func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-			return p, nil
-		}
-	}
-
 	wsf, _ := chart.NewFactory(nil, "./test_files", log.NewLogger(true))
 	model := reconciler.Task{
 		Component: "istio",
@@ -175,11 +176,11 @@ func Test_RunUpdateAction(t *testing.T) {
 		providerMock.On("GetIstioClient", mock.Anything).Return(ctrlClient, nil)
 		commanderMock := commandermocks.Commander{}
 		commanderMock.On("Version", mock.Anything, mock.Anything).Return([]byte(istioctlMockLatestVersion), nil)
-		commanderMock.On("Upgrade", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		commanderMock.On("Upgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &commanderMock}
 
 		proxy := proxymocks.IstioProxyReset{}
-		proxy.On("Run", mock.Anything).Return(nil)
+		proxy.On("Run", mock.Anything).Return(istioProxy.ResetSummary{}, nil)
 		gatherer := datamocks.Gatherer{}
 		gatherer.On("GetInstalledIstioVersion", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("*zap.SugaredLogger")).Return(model.Version, nil)
 		performer := actions.NewDefaultIstioPerformer(cmdResolver, &proxy, &providerMock, &gatherer)
@@ -192,14 +193,14 @@ func Test_RunUpdateAction(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		commanderMock.AssertCalled(t, "Version", mock.Anything, mock.Anything)
-		commanderMock.AssertCalled(t, "Upgrade", mock.Anything, mock.Anything, mock.Anything)
+		commanderMock.AssertCalled(t, "Upgrade", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("Istio update should NOT permit more than one minor downgrade", func(t *testing.T) {
 		// given
 		provider := clientset.DefaultProvider{}
 		commanderMock := commandermocks.Commander{}
-		commanderMock.On("Version", mock.Anything, mock.Anything).Return([]byte(istioctlMockTooNewVersion), nil)
+		commanderMock.On("ClientVersion", mock.Anything).Return([]byte(istioctlMockTooNewVersion), nil)
 		cmdResolver := TestCommanderResolver{cmder: &commanderMock}
 		gatherer := datamocks.Gatherer{}
 		performer := actions.NewDefaultIstioPerformer(cmdResolver, nil, &provider, &gatherer)
@@ -211,13 +212,15 @@ func Test_RunUpdateAction(t *testing.T) {
 
 		// then
 		require.EqualError(t, err, "Istio could not be updated since the binary version: 1.09.2 is not compatible with the target version: 1.11.2-solo-fips-distroless - the difference between versions exceeds one minor version")
-		commanderMock.AssertCalled(t, "Version", mock.Anything, mock.Anything)
+		commanderMock.AssertCalled(t, "ClientVersion", mock.Anything)
+		commanderMock.AssertNotCalled(t, "Version", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Istio update should be allowed when there is data plane and pilot version mismatch if the data plane is consistent", func(t *testing.T) {
 		// given
 		provider := clientset.DefaultProvider{}
 		commanderMock := commandermocks.Commander{}
+		commanderMock.On("ClientVersion", mock.Anything).Return([]byte(istioctlMockDataPlanePilotMismatchVersion), nil)
 		commanderMock.On("Version", mock.Anything, mock.Anything).Return([]byte(istioctlMockDataPlanePilotMismatchVersion), nil)
 		cmdResolver := TestCommanderResolver{cmder: &commanderMock}
 		gatherer := datamocks.Gatherer{}
@@ -236,12 +239,6 @@ func Test_RunUpdateAction(t *testing.T) {
 
 func Test_RunUninstallAction(t *testing.T) {
 
-	performerCreatorFn := func(p *actions.DefaultIstioPerformer) func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-		return func(logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
-			return p, nil
-		}
-	}
-
 	t.Run("Istio uninstall should also delete namespace", func(t *testing.T) {
 		// given
 		wsf, _ := chart.NewFactory(nil, "./test_files", log.NewLogger(true))
@@ -255,7 +252,7 @@ func Test_RunUninstallAction(t *testing.T) {
 		provider := clientset.DefaultProvider{}
 		commanderMock := commandermocks.Commander{}
 		commanderMock.On("Version", mock.Anything, mock.Anything).Return([]byte(istioctlMockCompleteVersion), nil)
-		commanderMock.On("Uninstall", mock.Anything, mock.Anything).Return(nil)
+		commanderMock.On("Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 		cmdResolver := TestCommanderResolver{cmder: &commanderMock}
 		gatherer := datamocks.Gatherer{}
 		performer := actions.NewDefaultIstioPerformer(cmdResolver, nil, &provider, &gatherer)
@@ -267,7 +264,7 @@ func Test_RunUninstallAction(t *testing.T) {
 		// then
 		require.NoError(t, err)
 		commanderMock.AssertCalled(t, "Version", mock.Anything, mock.Anything)
-		commanderMock.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything)
+		commanderMock.AssertCalled(t, "Uninstall", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.Anything)
 
 		// istio-system namespace should be deleted
 		fakeClient, _ := actionContext.KubeClient.Clientset()
@@ -326,6 +323,10 @@ func (tcr TestCommanderResolver) GetCommander(_ istioctl.Version) (istioctl.Comm
 	return tcr.cmder, nil
 }
 
+func (tcr TestCommanderResolver) SupportedVersions() []istioctl.Version {
+	return nil
+}
+
 func TestIstioReconciler(t *testing.T) {
 	istioReconciler, err := service.GetReconciler(istio.ReconcilerNameIstio)
 
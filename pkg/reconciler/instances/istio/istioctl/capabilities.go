@@ -0,0 +1,52 @@
+package istioctl
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Capability is an istioctl operation or flag which is not supported by every istioctl version.
+type Capability string
+
+const (
+	CapabilityApply     Capability = "apply"
+	CapabilityUninstall Capability = "x uninstall"
+	CapabilityPrecheck  Capability = "x precheck"
+	CapabilityRevision  Capability = "--revision"
+	CapabilityVklog     Capability = "--vklog"
+)
+
+// capabilityMatrix maps every Capability to the lowest istioctl version supporting it.
+var capabilityMatrix = map[Capability]string{
+	CapabilityApply:     "1.6.0",
+	CapabilityUninstall: "1.7.0",
+	CapabilityPrecheck:  "1.9.0",
+	CapabilityRevision:  "1.6.0",
+	CapabilityVklog:     "1.8.0",
+}
+
+// EnsureSupported returns an error if istioctl in the given version does not support all of the requested capabilities.
+func EnsureSupported(version Version, capabilities ...Capability) error {
+	var unsupported []string
+	for _, capability := range capabilities {
+		minVersionString, ok := capabilityMatrix[capability]
+		if !ok {
+			return errors.Errorf("Unknown istioctl capability '%s'", capability)
+		}
+		minVersion, err := VersionFromString(minVersionString)
+		if err != nil {
+			return err
+		}
+		if version.SmallerThan(minVersion) {
+			unsupported = append(unsupported, string(capability)+" (requires "+minVersion.String()+")")
+		}
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return errors.Errorf("istioctl version %s does not support: %s", version.String(), strings.Join(unsupported, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package istioctl_test
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnsureSupported(t *testing.T) {
+
+	t.Run("should not return error when all capabilities are supported", func(t *testing.T) {
+		// given
+		version, err := istioctl.VersionFromString("1.16.1")
+		require.NoError(t, err)
+
+		// when
+		err = istioctl.EnsureSupported(version, istioctl.CapabilityApply, istioctl.CapabilityUninstall, istioctl.CapabilityPrecheck, istioctl.CapabilityRevision)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should not return error when the version is exactly the minimal supported one", func(t *testing.T) {
+		// given
+		version, err := istioctl.VersionFromString("1.7.0")
+		require.NoError(t, err)
+
+		// when
+		err = istioctl.EnsureSupported(version, istioctl.CapabilityUninstall)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should return error listing all unsupported capabilities", func(t *testing.T) {
+		// given
+		version, err := istioctl.VersionFromString("1.6.5")
+		require.NoError(t, err)
+
+		// when
+		err = istioctl.EnsureSupported(version, istioctl.CapabilityApply, istioctl.CapabilityUninstall, istioctl.CapabilityPrecheck)
+
+		// then
+		require.EqualError(t, err, "istioctl version 1.6.5 does not support: x precheck (requires 1.9.0), x uninstall (requires 1.7.0)")
+	})
+
+	t.Run("should return error for unknown capability", func(t *testing.T) {
+		// given
+		version, err := istioctl.VersionFromString("1.16.1")
+		require.NoError(t, err)
+
+		// when
+		err = istioctl.EnsureSupported(version, istioctl.Capability("x unknown"))
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Unknown istioctl capability")
+	})
+}
@@ -1,6 +1,7 @@
 package istioctl
 
 import (
+	"context"
 	"os/exec"
 
 	"github.com/kyma-incubator/reconciler/pkg/features"
@@ -15,17 +16,25 @@ import (
 //go:generate mockery --name=Commander --output=mocks --case=underscore
 type Commander interface {
 
-	// Install wraps `istioctl installation` command.
-	Install(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error
+	// Install wraps `istioctl installation` command. The command is stopped when ctx is done.
+	// A non-empty revision installs the control plane as that revision next to the existing ones.
+	// The log output level set with WithLogOutputLevel on ctx is passed to istioctl.
+	Install(ctx context.Context, istioOperator, revision, kubeconfig string, logger *zap.SugaredLogger) error
 
-	// Upgrade wraps `istioctl upgrade` command.
-	Upgrade(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error
+	// Upgrade wraps `istioctl upgrade` command. The command is stopped when ctx is done.
+	// A non-empty revision installs the control plane as that revision next to the existing ones.
+	// The log output level set with WithLogOutputLevel on ctx is passed to istioctl.
+	Upgrade(ctx context.Context, istioOperator, revision, kubeconfig string, logger *zap.SugaredLogger) error
 
 	// Version wraps `istioctl version` command.
 	Version(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error)
 
-	// Uninstall wraps `istioctl x uninstall` command.
-	Uninstall(kubeconfig string, logger *zap.SugaredLogger) error
+	// ClientVersion wraps `istioctl version --remote=false` command, which reports only the version of the binary without contacting the cluster.
+	ClientVersion(logger *zap.SugaredLogger) ([]byte, error)
+
+	// Uninstall wraps `istioctl x uninstall` command. Without a revision all revisions are purged, otherwise only the given revision is removed.
+	// The istioctl process is stopped when ctx is cancelled, the log output level set with WithLogOutputLevel on ctx is passed to istioctl.
+	Uninstall(ctx context.Context, kubeconfig, revision string, logger *zap.SugaredLogger) error
 }
 
 var execCommand = exec.Command
@@ -42,7 +51,15 @@ func NewDefaultCommander(istioctl Executable) DefaultCommander {
 	return DefaultCommander{istioctl, &executor.DefaultCmdExecutor{}}
 }
 
-func (c *DefaultCommander) Uninstall(kubeconfig string, logger *zap.SugaredLogger) error {
+func (c *DefaultCommander) Uninstall(ctx context.Context, kubeconfig, revision string, logger *zap.SugaredLogger) error {
+	capabilities := []Capability{CapabilityUninstall}
+	if revision != "" {
+		capabilities = append(capabilities, CapabilityRevision)
+	}
+	err := c.ensureSupported(capabilities...)
+	if err != nil {
+		return err
+	}
 
 	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
 	if err != nil {
@@ -56,10 +73,27 @@ func (c *DefaultCommander) Uninstall(kubeconfig string, logger *zap.SugaredLogge
 		}
 	}()
 
-	return c.commandExecutor.RuntWithRetry(logger, c.istioctl.path, "x", "uninstall", "--purge", "--kubeconfig", kubeconfigPath, "--skip-confirmation")
+	args := []string{"x", "uninstall"}
+	if revision != "" {
+		args = append(args, "--revision", revision)
+	} else {
+		args = append(args, "--purge")
+	}
+	args = append(args, "--kubeconfig", kubeconfigPath, "--skip-confirmation")
+	args = appendLogOutputLevel(ctx, args)
+
+	return c.commandExecutor.RuntWithRetry(ctx, logger, c.istioctl.path, args...)
 }
 
-func (c *DefaultCommander) Install(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error {
+func (c *DefaultCommander) Install(ctx context.Context, istioOperator, revision, kubeconfig string, logger *zap.SugaredLogger) error {
+	capabilities := []Capability{CapabilityApply}
+	if features.Enabled(features.LogIstioOperator) {
+		capabilities = append(capabilities, CapabilityVklog)
+	}
+	err := c.ensureSupported(capabilities...)
+	if err != nil {
+		return err
+	}
 
 	kubeconfigPath, kubeconfigCf, err := file.CreateTempFileWith(kubeconfig)
 	logger.Debugf("Created kubeconfig temp file on %s ", kubeconfigPath)
@@ -89,12 +123,16 @@ func (c *DefaultCommander) Install(istioOperator, kubeconfig string, logger *zap
 
 	logger.Debugf("Creating executable istioctl apply command")
 
+	args := []string{"apply", "-f", istioOperatorPath, "--kubeconfig", kubeconfigPath, "--skip-confirmation"}
+	if revision != "" {
+		args = append(args, "--revision", revision)
+	}
 	if features.Enabled(features.LogIstioOperator) {
 		logger.Debugf("Rendered IstioOperator yaml was: %s ", istioOperator)
-		err = c.commandExecutor.RuntWithRetry(logger, c.istioctl.path, "apply", "-f", istioOperatorPath, "--kubeconfig", kubeconfigPath, "--skip-confirmation", "--vklog", logVerbosity)
-	} else {
-		err = c.commandExecutor.RuntWithRetry(logger, c.istioctl.path, "apply", "-f", istioOperatorPath, "--kubeconfig", kubeconfigPath, "--skip-confirmation")
+		args = append(args, "--vklog", logVerbosity)
 	}
+	args = appendLogOutputLevel(ctx, args)
+	err = c.commandExecutor.RuntWithRetry(ctx, logger, c.istioctl.path, args...)
 
 	if err != nil {
 		logger.Errorf("Got error from executing istioctl apply %v", err)
@@ -104,8 +142,8 @@ func (c *DefaultCommander) Install(istioOperator, kubeconfig string, logger *zap
 	return err
 }
 
-func (c *DefaultCommander) Upgrade(istioOperator, kubeconfig string, logger *zap.SugaredLogger) error {
-	return c.Install(istioOperator, kubeconfig, logger)
+func (c *DefaultCommander) Upgrade(ctx context.Context, istioOperator, revision, kubeconfig string, logger *zap.SugaredLogger) error {
+	return c.Install(ctx, istioOperator, revision, kubeconfig, logger)
 }
 
 func (c *DefaultCommander) Version(kubeconfig string, logger *zap.SugaredLogger) ([]byte, error) {
@@ -130,3 +168,31 @@ func (c *DefaultCommander) Version(kubeconfig string, logger *zap.SugaredLogger)
 
 	return out, nil
 }
+
+func (c *DefaultCommander) ClientVersion(logger *zap.SugaredLogger) ([]byte, error) {
+	logger.Debugf("Running istioctl version without contacting the cluster")
+	cmd := execCommand(c.istioctl.path, "version", "--remote=false", "--output", "json")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return out, nil
+}
+
+// appendLogOutputLevel adds the istioctl log output level of the context to the args, if there is one.
+func appendLogOutputLevel(ctx context.Context, args []string) []string {
+	if level := LogOutputLevel(ctx); level != "" {
+		args = append(args, "--log_output_level", level)
+	}
+	return args
+}
+
+// ensureSupported validates the capabilities against the version of the istioctl binary before it gets executed.
+// Binaries with unknown version are not validated.
+func (c *DefaultCommander) ensureSupported(capabilities ...Capability) error {
+	if c.istioctl.version.Empty() {
+		return nil
+	}
+	return EnsureSupported(c.istioctl.version, capabilities...)
+}
@@ -1,12 +1,16 @@
 package istioctl
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/executor"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/istioctl/executor/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -42,7 +46,7 @@ func fakeExecCommand(command string, args ...string) *exec.Cmd {
 
 func Test_DefaultCommander_Install(t *testing.T) {
 	mockCommandExecutor := mocks.CmdExecutor{}
-	mockCommandExecutor.On("RuntWithRetry", mock.Anything, mock.AnythingOfType("string"),
+	mockCommandExecutor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
@@ -54,19 +58,82 @@ func Test_DefaultCommander_Install(t *testing.T) {
 
 	t.Run("should run the apply command", func(t *testing.T) {
 		// when
-		errors := commander.Install("istioOperator", kubeconfig, log)
+		errors := commander.Install(context.TODO(), "istioOperator", "", kubeconfig, log)
 
 		// then
 		require.NoError(t, errors)
-		mockCommandExecutor.AssertCalled(t, "RuntWithRetry", log, "/bin/istio/istioctl", "apply", "-f",
+		mockCommandExecutor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "apply", "-f",
 			mock.AnythingOfType("string"), "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation")
 	})
+
+	t.Run("should run the apply command for the revision", func(t *testing.T) {
+		// given
+		revisionCommandExecutor := mocks.CmdExecutor{}
+		revisionCommandExecutor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+		revisionCommander := DefaultCommander{
+			istioctl:        Executable{path: "/bin/istio/istioctl"},
+			commandExecutor: &revisionCommandExecutor,
+		}
+
+		// when
+		err := revisionCommander.Install(context.TODO(), "istioOperator", "canary", kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		revisionCommandExecutor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "apply", "-f",
+			mock.AnythingOfType("string"), "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation", "--revision", "canary")
+	})
+
+	t.Run("should run the apply command with the log output level of the context", func(t *testing.T) {
+		// given
+		levelCommandExecutor := mocks.CmdExecutor{}
+		levelCommandExecutor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+		levelCommander := DefaultCommander{
+			istioctl:        Executable{path: "/bin/istio/istioctl"},
+			commandExecutor: &levelCommandExecutor,
+		}
+
+		// when
+		err := levelCommander.Install(WithLogOutputLevel(context.TODO(), "default:debug"), "istioOperator", "", kubeconfig, log)
+
+		// then
+		require.NoError(t, err)
+		levelCommandExecutor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "apply", "-f",
+			mock.AnythingOfType("string"), "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation", "--log_output_level", "default:debug")
+	})
+}
+
+func Test_DefaultCommander_Install_Cancel(t *testing.T) {
+	// given
+	istioctlPath := filepath.Join(t.TempDir(), "istioctl")
+	err := os.WriteFile(istioctlPath, []byte("#!/bin/sh\nsleep 30\n"), 0700)
+	require.NoError(t, err)
+	commander := DefaultCommander{
+		istioctl:        Executable{path: istioctlPath},
+		commandExecutor: &executor.DefaultCmdExecutor{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	start := time.Now()
+
+	// when
+	err = commander.Install(ctx, "istioOperator", "", kubeconfig, logger.NewLogger(false))
+
+	// then
+	require.ErrorIs(t, err, context.Canceled)
+	require.Less(t, time.Since(start), 5*time.Second)
 }
 
 func Test_DefaultCommander_Uninstall(t *testing.T) {
 
 	mockCommandExecutor := mocks.CmdExecutor{}
-	mockCommandExecutor.On("RuntWithRetry", mock.Anything, mock.AnythingOfType("string"),
+	mockCommandExecutor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
@@ -78,18 +145,74 @@ func Test_DefaultCommander_Uninstall(t *testing.T) {
 
 	t.Run("should run the uninstall command", func(t *testing.T) {
 		//when
-		err := commander.Uninstall(kubeconfig, log)
+		err := commander.Uninstall(context.TODO(), kubeconfig, "", log)
+
+		// then
+
+		require.NoError(t, err)
+		mockCommandExecutor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "x", "uninstall", "--purge", "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation")
+	})
+
+	t.Run("should run the uninstall command only for the revision", func(t *testing.T) {
+		// given
+		executor := mocks.CmdExecutor{}
+		executor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+		revisionCommander := DefaultCommander{
+			istioctl:        Executable{path: "/bin/istio/istioctl"},
+			commandExecutor: &executor,
+		}
+
+		// when
+		err := revisionCommander.Uninstall(context.TODO(), kubeconfig, "1-10-2", log)
 
 		// then
+		require.NoError(t, err)
+		executor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "x", "uninstall", "--revision", "1-10-2", "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation")
+	})
+
+	t.Run("should run the uninstall command with the log output level of the context", func(t *testing.T) {
+		// given
+		executor := mocks.CmdExecutor{}
+		executor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
+			mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+		levelCommander := DefaultCommander{
+			istioctl:        Executable{path: "/bin/istio/istioctl"},
+			commandExecutor: &executor,
+		}
 
+		// when
+		err := levelCommander.Uninstall(WithLogOutputLevel(context.TODO(), "debug"), kubeconfig, "", log)
+
+		// then
 		require.NoError(t, err)
-		mockCommandExecutor.AssertCalled(t, "RuntWithRetry", log, "/bin/istio/istioctl", "x", "uninstall", "--purge", "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation")
+		executor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "x", "uninstall", "--purge", "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation", "--log_output_level", "debug")
+	})
+
+	t.Run("should not run the uninstall command when istioctl does not support it", func(t *testing.T) {
+		// given
+		oldVersion, err := VersionFromString("1.6.0")
+		require.NoError(t, err)
+		executor := mocks.CmdExecutor{}
+		oldCommander := DefaultCommander{
+			istioctl:        Executable{version: oldVersion, path: "/bin/istio/istioctl"},
+			commandExecutor: &executor,
+		}
+
+		// when
+		err = oldCommander.Uninstall(context.TODO(), kubeconfig, "", log)
+
+		// then
+		require.EqualError(t, err, "istioctl version 1.6.0 does not support: x uninstall (requires 1.7.0)")
+		executor.AssertNotCalled(t, "RuntWithRetry", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
 func Test_DefaultCommander_Upgrade(t *testing.T) {
 	mockCommandExecutor := mocks.CmdExecutor{}
-	mockCommandExecutor.On("RuntWithRetry", mock.Anything, mock.AnythingOfType("string"),
+	mockCommandExecutor.On("RuntWithRetry", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"),
 		mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
 
@@ -101,11 +224,11 @@ func Test_DefaultCommander_Upgrade(t *testing.T) {
 
 	t.Run("should run the apply command", func(t *testing.T) {
 		// when
-		errors := commander.Upgrade("istioOperator", kubeconfig, log)
+		errors := commander.Upgrade(context.TODO(), "istioOperator", "", kubeconfig, log)
 
 		// then
 		require.NoError(t, errors)
-		mockCommandExecutor.AssertCalled(t, "RuntWithRetry", log, "/bin/istio/istioctl", "apply", "-f",
+		mockCommandExecutor.AssertCalled(t, "RuntWithRetry", mock.Anything, log, "/bin/istio/istioctl", "apply", "-f",
 			mock.AnythingOfType("string"), "--kubeconfig", mock.AnythingOfType("string"), "--skip-confirmation")
 	})
 }
@@ -127,3 +250,19 @@ func Test_DefaultCommander_Version(t *testing.T) {
 		require.EqualValues(t, testArgs[3], "--kubeconfig")
 	})
 }
+
+func Test_DefaultCommander_ClientVersion(t *testing.T) {
+	execCommand = fakeExecCommand
+	log := logger.NewLogger(false)
+	commander := DefaultCommander{}
+
+	t.Run("should run the version command without contacting the cluster", func(t *testing.T) {
+		// when
+		got, err := commander.ClientVersion(log)
+
+		// then
+		require.NoError(t, err)
+		require.EqualValues(t, versionOutput, string(got))
+		require.Equal(t, []string{"version", "--remote=false", "--output", "json"}, testArgs)
+	})
+}
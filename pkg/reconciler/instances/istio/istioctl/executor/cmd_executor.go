@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"github.com/avast/retry-go"
 	gocmd "github.com/go-cmd/cmd"
@@ -11,18 +12,30 @@ import (
 
 //go:generate mockery --name=CmdExecutor --output=mocks --case=underscore
 type CmdExecutor interface {
-	RuntWithRetry(logger *zap.SugaredLogger, command string, args ...string) error
+	RuntWithRetry(ctx context.Context, logger *zap.SugaredLogger, command string, args ...string) error
 }
 type DefaultCmdExecutor struct{}
 
-func (d *DefaultCmdExecutor) RuntWithRetry(logger *zap.SugaredLogger, cmdName string, arg ...string) error {
+// RuntWithRetry runs the command and retries it on failure. If ctx is cancelled the running command is stopped
+// and the context error is returned. The command runs in its own process group, which is terminated as a whole,
+// so no child processes of the command keep running.
+func (d *DefaultCmdExecutor) RuntWithRetry(ctx context.Context, logger *zap.SugaredLogger, cmdName string, arg ...string) error {
 	if len(cmdName) < 1 {
 		return errors.New("cmdName must be not empty")
 	}
 	retryable := func() error {
 		executableCmd := gocmd.NewCmd(cmdName, arg...)
-		// Run and wait for Cmd to return Status
-		status := <-executableCmd.Start()
+		// Run and wait for Cmd to return Status or for the context to be done
+		var status gocmd.Status
+		select {
+		case status = <-executableCmd.Start():
+		case <-ctx.Done():
+			stopErr := executableCmd.Stop()
+			if stopErr != nil {
+				logger.Warnf("could not stop command %s: %v", executableCmd.Name, stopErr)
+			}
+			return retry.Unrecoverable(ctx.Err())
+		}
 		stdout := strings.Join(status.Stdout, "\n")
 		logger.Debugf("executed command %s, got output: %s", executableCmd.Name, stdout)
 
@@ -43,6 +56,9 @@ func (d *DefaultCmdExecutor) RuntWithRetry(logger *zap.SugaredLogger, cmdName st
 		}
 		return nil
 	}
-	err := retry.Do(retryable, retry.Attempts(3))
+	err := retry.Do(retryable, retry.Attempts(3), retry.Context(ctx))
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	return err
 }
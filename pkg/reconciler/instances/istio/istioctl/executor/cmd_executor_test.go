@@ -1,9 +1,16 @@
 package executor
 
 import (
+	"context"
 	"github.com/kyma-incubator/reconciler/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 )
 
 var log = logger.NewLogger(false)
@@ -11,18 +18,53 @@ var log = logger.NewLogger(false)
 func Test_ErrorWhenNoCommandPassed(t *testing.T) {
 
 	cmdExecutor := DefaultCmdExecutor{}
-	err := cmdExecutor.RuntWithRetry(log, "")
+	err := cmdExecutor.RuntWithRetry(context.TODO(), log, "")
 	assert.Error(t, err, "cmdName must be not empty")
 }
 
 func Test_SuccessfulEchoCmd(t *testing.T) {
 	cmdExecutor := DefaultCmdExecutor{}
-	err := cmdExecutor.RuntWithRetry(log, "echo", "Hello", "Go")
+	err := cmdExecutor.RuntWithRetry(context.TODO(), log, "echo", "Hello", "Go")
 	assert.NoError(t, err)
 }
 
 func Test_UnSuccessfulDummyCmd(t *testing.T) {
 	cmdExecutor := DefaultCmdExecutor{}
-	err := cmdExecutor.RuntWithRetry(log, "may the fourth")
+	err := cmdExecutor.RuntWithRetry(context.TODO(), log, "may the fourth")
 	assert.Error(t, err)
 }
+
+func Test_CancelledContextStopsCmd(t *testing.T) {
+	cmdExecutor := DefaultCmdExecutor{}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := cmdExecutor.RuntWithRetry(ctx, log, "sleep", "10")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func Test_CancelledContextStopsChildProcesses(t *testing.T) {
+	cmdExecutor := DefaultCmdExecutor{}
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(pidFile)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		cancel()
+	}()
+
+	err := cmdExecutor.RuntWithRetry(ctx, log, "sh", "-c", "sleep 30 & echo $! > "+pidFile+"; wait")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	content, err := os.ReadFile(pidFile)
+	assert.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(pid, 0) != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	zap "go.uber.org/zap"
 )
@@ -12,20 +14,20 @@ type CmdExecutor struct {
 	mock.Mock
 }
 
-// RuntWithRetry provides a mock function with given fields: logger, command, args
-func (_m *CmdExecutor) RuntWithRetry(logger *zap.SugaredLogger, command string, args ...string) error {
+// RuntWithRetry provides a mock function with given fields: ctx, logger, command, args
+func (_m *CmdExecutor) RuntWithRetry(ctx context.Context, logger *zap.SugaredLogger, command string, args ...string) error {
 	_va := make([]interface{}, len(args))
 	for _i := range args {
 		_va[_i] = args[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, logger, command)
+	_ca = append(_ca, ctx, logger, command)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*zap.SugaredLogger, string, ...string) error); ok {
-		r0 = rf(logger, command, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, *zap.SugaredLogger, string, ...string) error); ok {
+		r0 = rf(ctx, logger, command, args...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,17 @@
+package istioctl
+
+import "context"
+
+type logOutputLevelKey struct{}
+
+// WithLogOutputLevel returns a copy of the context which makes the commander pass the level to the `--log_output_level`
+// flag of istioctl apply and uninstall, e.g. "debug" or "default:debug,installer:info". An empty level keeps the istioctl default.
+func WithLogOutputLevel(ctx context.Context, level string) context.Context {
+	return context.WithValue(ctx, logOutputLevelKey{}, level)
+}
+
+// LogOutputLevel returns the istioctl log output level of the context, or an empty string if none was set.
+func LogOutputLevel(ctx context.Context) string {
+	level, _ := ctx.Value(logOutputLevelKey{}).(string)
+	return level
+}
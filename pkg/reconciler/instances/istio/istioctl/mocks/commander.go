@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	zap "go.uber.org/zap"
 )
@@ -12,13 +14,36 @@ type Commander struct {
 	mock.Mock
 }
 
-// Install provides a mock function with given fields: istioOperator, kubeconfig, logger
-func (_m *Commander) Install(istioOperator string, kubeconfig string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(istioOperator, kubeconfig, logger)
+// ClientVersion provides a mock function with given fields: logger
+func (_m *Commander) ClientVersion(logger *zap.SugaredLogger) ([]byte, error) {
+	ret := _m.Called(logger)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(*zap.SugaredLogger) []byte); ok {
+		r0 = rf(logger)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*zap.SugaredLogger) error); ok {
+		r1 = rf(logger)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Install provides a mock function with given fields: ctx, istioOperator, revision, kubeconfig, logger
+func (_m *Commander) Install(ctx context.Context, istioOperator string, revision string, kubeconfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, istioOperator, revision, kubeconfig, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(istioOperator, kubeconfig, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, istioOperator, revision, kubeconfig, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -26,13 +51,13 @@ func (_m *Commander) Install(istioOperator string, kubeconfig string, logger *za
 	return r0
 }
 
-// Uninstall provides a mock function with given fields: kubeconfig, logger
-func (_m *Commander) Uninstall(kubeconfig string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(kubeconfig, logger)
+// Uninstall provides a mock function with given fields: ctx, kubeconfig, revision, logger
+func (_m *Commander) Uninstall(ctx context.Context, kubeconfig string, revision string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, kubeconfig, revision, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *zap.SugaredLogger) error); ok {
-		r0 = rf(kubeconfig, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, kubeconfig, revision, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -40,13 +65,13 @@ func (_m *Commander) Uninstall(kubeconfig string, logger *zap.SugaredLogger) err
 	return r0
 }
 
-// Upgrade provides a mock function with given fields: istioOperator, kubeconfig, logger
-func (_m *Commander) Upgrade(istioOperator string, kubeconfig string, logger *zap.SugaredLogger) error {
-	ret := _m.Called(istioOperator, kubeconfig, logger)
+// Upgrade provides a mock function with given fields: ctx, istioOperator, revision, kubeconfig, logger
+func (_m *Commander) Upgrade(ctx context.Context, istioOperator string, revision string, kubeconfig string, logger *zap.SugaredLogger) error {
+	ret := _m.Called(ctx, istioOperator, revision, kubeconfig, logger)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *zap.SugaredLogger) error); ok {
-		r0 = rf(istioOperator, kubeconfig, logger)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *zap.SugaredLogger) error); ok {
+		r0 = rf(ctx, istioOperator, revision, kubeconfig, logger)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -15,14 +15,15 @@ type Version struct {
 	value semver.Version
 }
 
-// VersionFromString returns a Version from passed semantic version in the format: "major.minor.patch", where all components must be positive integers
+// VersionFromString returns a Version from passed semantic version in the format: "major.minor.patch", where all components must be positive integers.
+// An optional leading "v" (e.g. "v1.17.2") is stripped before parsing.
 func VersionFromString(version string) (Version, error) {
 	trimmed := strings.TrimSpace(version)
 	if trimmed == "" {
 		return Version{}, errors.New("invalid istioctl version format: empty input")
 	}
 
-	val, err := semver.NewVersion(trimmed)
+	val, err := semver.NewVersion(strings.TrimPrefix(trimmed, "v"))
 
 	if err != nil {
 		return Version{}, errors.Errorf("Invalid istioctl version format for input '%s': %s", trimmed, err.Error())
@@ -64,6 +65,8 @@ func (v Version) MajorMinorPatch() string {
 //go:generate mockery --name=IstioctlResolver --outpkg=mock --case=underscore
 type ExecutableResolver interface {
 	FindIstioctl(version Version) (*Executable, error)
+	// AvailableVersions returns the versions of all known istioctl executables in ascending order.
+	AvailableVersions() []Version
 }
 
 type DefaultIstioctlResolver struct {
@@ -74,6 +77,14 @@ func (d *DefaultIstioctlResolver) FindIstioctl(version Version) (*Executable, er
 	return d.findMatchingBinary(version)
 }
 
+func (d *DefaultIstioctlResolver) AvailableVersions() []Version {
+	versions := make([]Version, 0, len(d.sortedBinaries))
+	for _, binary := range d.sortedBinaries {
+		versions = append(versions, binary.Version())
+	}
+	return versions
+}
+
 func NewDefaultIstioctlResolver(paths []string, vc VersionChecker) (*DefaultIstioctlResolver, error) {
 	binariesList := []Executable{}
 	for _, path := range paths {
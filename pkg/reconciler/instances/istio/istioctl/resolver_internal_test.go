@@ -111,6 +111,22 @@ func TestVersion(t *testing.T) {
 		_, err = VersionFromString("2.3.abc")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "Invalid istioctl version format for input '2.3.abc':")
+
+		_, err = VersionFromString("v")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid istioctl version format for input 'v':")
+	})
+
+	t.Run("should accept a leading v prefix", func(t *testing.T) {
+		plain, err := VersionFromString("1.17.2")
+		require.NoError(t, err)
+
+		prefixed, err := VersionFromString("v1.17.2")
+		require.NoError(t, err)
+
+		require.Equal(t, "1.17.2", prefixed.MajorMinorPatch())
+		require.Equal(t, plain.MajorMinorPatch(), prefixed.MajorMinorPatch())
+		require.Equal(t, 0, plain.Compare(prefixed))
 	})
 
 }
@@ -78,6 +78,25 @@ func Test_DefaultIstioctlResolver(t *testing.T) {
 		require.Error(t, err)
 		require.Equal(t, "No matching 'istioctl' binary found for version: 1.3.0. Available binaries: 1.2.1, 1.2.4, 1.2.7, 1.11.2", err.Error())
 	})
+
+	t.Run("should list the available versions in ascending order", func(t *testing.T) {
+		vc := mocks.VersionChecker{}
+
+		vc.On("GetIstioVersion", "/c").Return(istioctl.VersionFromString("1.2.4"))
+		vc.On("GetIstioVersion", "/b").Return(istioctl.VersionFromString("1.2.1"))
+		vc.On("GetIstioVersion", "/a").Return(istioctl.VersionFromString("1.11.2"))
+
+		paths := []string{"/a", "/b", "/c"}
+		resolver, err := istioctl.NewDefaultIstioctlResolver(paths, &vc)
+		require.NoError(t, err)
+
+		versions := resolver.AvailableVersions()
+
+		require.Len(t, versions, 3)
+		require.Equal(t, "1.2.1", versions[0].String())
+		require.Equal(t, "1.2.4", versions[1].String())
+		require.Equal(t, "1.11.2", versions[2].String())
+	})
 }
 
 func Test_DefaultVersionChecker(t *testing.T) {
@@ -0,0 +1,34 @@
+package istio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"go.uber.org/zap"
+)
+
+const istioctlLogOutputLevelConfigKey = "istio.istioctl.logOutputLevel"
+
+var istioctlLogLevels = map[string]bool{"none": true, "fatal": true, "error": true, "warn": true, "info": true, "debug": true}
+
+// istioctlLogOutputLevel returns the istioctl log output level configured for the task, either a single level like "debug"
+// or comma separated scoped levels like "default:debug,installer:info". An empty string keeps the istioctl default.
+func istioctlLogOutputLevel(task *reconciler.Task, logger *zap.SugaredLogger) string {
+	value, ok := task.Configuration[istioctlLogOutputLevelConfigKey]
+	if !ok {
+		return ""
+	}
+	level := strings.TrimSpace(fmt.Sprint(value))
+	for _, scopedLevel := range strings.Split(level, ",") {
+		_, scopeLevel, found := strings.Cut(scopedLevel, ":")
+		if !found {
+			scopeLevel = scopedLevel
+		}
+		if !istioctlLogLevels[strings.TrimSpace(scopeLevel)] {
+			logger.Warnf("Invalid %s value %v, the istioctl default log output level is used", istioctlLogOutputLevelConfigKey, value)
+			return ""
+		}
+	}
+	return level
+}
@@ -0,0 +1,46 @@
+package istio
+
+import (
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_istioctlLogOutputLevel(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should keep the istioctl default when it is not configured", func(t *testing.T) {
+		// when
+		level := istioctlLogOutputLevel(&reconciler.Task{}, logger)
+
+		// then
+		require.Empty(t, level)
+	})
+
+	t.Run("should return the configured level", func(t *testing.T) {
+		// when
+		level := istioctlLogOutputLevel(&reconciler.Task{Configuration: map[string]interface{}{"istio.istioctl.logOutputLevel": "debug"}}, logger)
+
+		// then
+		require.Equal(t, "debug", level)
+	})
+
+	t.Run("should return the configured scoped levels", func(t *testing.T) {
+		// when
+		level := istioctlLogOutputLevel(&reconciler.Task{Configuration: map[string]interface{}{"istio.istioctl.logOutputLevel": "default:debug,installer:info"}}, logger)
+
+		// then
+		require.Equal(t, "default:debug,installer:info", level)
+	})
+
+	t.Run("should keep the istioctl default when the configured level is invalid", func(t *testing.T) {
+		// when
+		level := istioctlLogOutputLevel(&reconciler.Task{Configuration: map[string]interface{}{"istio.istioctl.logOutputLevel": "default:verbose"}}, logger)
+
+		// then
+		require.Empty(t, level)
+	})
+}
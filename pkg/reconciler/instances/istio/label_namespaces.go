@@ -0,0 +1,42 @@
+package istio
+
+import (
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/pkg/errors"
+)
+
+// LabelNamespacesAction only (re-)applies the sidecar injection labels to the namespaces, the Istio control plane is not
+// touched. It allows scheduling the labeling of namespaces independently of the reconciliation of Istio. The reconcile action
+// runs it instead of reconciling Istio if the task enables istio.labelNamespacesOnly.
+type LabelNamespacesAction struct {
+	lastErrorRecorder
+	getIstioPerformer bootstrapIstioPerformer
+}
+
+// NewLabelNamespacesAction returns an instance of LabelNamespacesAction
+func NewLabelNamespacesAction(getIstioPerformer bootstrapIstioPerformer) *LabelNamespacesAction {
+	return &LabelNamespacesAction{getIstioPerformer: getIstioPerformer}
+}
+
+func (a *LabelNamespacesAction) Run(context *service.ActionContext) error {
+	err := a.run(withLogFields(context))
+	a.recordError(err)
+	return err
+}
+
+func (a *LabelNamespacesAction) run(context *service.ActionContext) error {
+	context.Logger.Debug("Label namespaces action of istio triggered")
+
+	performer, err := a.getIstioPerformer(context.Task, context.Logger)
+	if err != nil {
+		return err
+	}
+
+	err = performer.LabelNamespaces(context.Context, context.KubeClient, context.WorkspaceFactory, context.Task.Version,
+		context.Task.Component, istioRevision(context.Task), context.Logger)
+	if err != nil {
+		return errors.Wrap(err, "Could not label namespaces")
+	}
+
+	return nil
+}
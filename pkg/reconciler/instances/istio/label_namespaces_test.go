@@ -0,0 +1,127 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	actionsmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func Test_LabelNamespacesAction_Run(t *testing.T) {
+	t.Run("should only label namespaces", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		action := NewLabelNamespacesAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Nil(t, action.LastError())
+		performer.AssertExpectations(t)
+		require.Len(t, performer.Calls, 1)
+	})
+
+	t.Run("should label namespaces for the configured revision", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.revision": "canary"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, "canary", mock.Anything).Return(nil)
+		action := NewLabelNamespacesAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertExpectations(t)
+	})
+
+	t.Run("should return an error when labeling namespaces failed", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("label error"))
+		action := NewLabelNamespacesAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "Could not label namespaces: label error")
+		require.EqualError(t, action.LastError().Err, "Could not label namespaces: label error")
+	})
+
+	t.Run("should return an error when the performer could not be created", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		action := NewLabelNamespacesAction(func(task *reconciler.Task, logger *zap.SugaredLogger) (actions.IstioPerformer, error) {
+			return nil, errors.New("performer error")
+		})
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "performer error")
+	})
+}
+
+func Test_LabelNamespacesOnly(t *testing.T) {
+	t.Run("should only label namespaces in the reconcile action", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.labelNamespacesOnly": true}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, actionContext.KubeClient, actionContext.WorkspaceFactory, "version", "component", "", mock.AnythingOfType("*zap.SugaredLogger")).Return(nil)
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		performer.AssertExpectations(t)
+		require.Len(t, performer.Calls, 1)
+	})
+
+	t.Run("should return the label namespaces error of the reconcile action", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.labelNamespacesOnly": "true"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("LabelNamespaces", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("label error"))
+		action := NewIstioMainReconcileAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.EqualError(t, err, "Could not label namespaces: label error")
+		require.Len(t, performer.Calls, 1)
+	})
+
+	t.Run("should skip the proxy reset", func(t *testing.T) {
+		// given
+		actionContext := newFakeActionContext()
+		actionContext.Task.Configuration = map[string]interface{}{"istio.labelNamespacesOnly": true}
+		performer := actionsmocks.IstioPerformer{}
+		action := NewProxyResetPostAction(performerCreatorFn(&performer))
+
+		// when
+		err := action.Run(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, performer.Calls)
+	})
+}
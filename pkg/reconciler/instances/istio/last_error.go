@@ -0,0 +1,35 @@
+package istio
+
+import (
+	"sync"
+	"time"
+)
+
+// LastError is the most recent error returned by an action together with the time it occurred.
+type LastError struct {
+	Err       error
+	Timestamp time.Time
+}
+
+// lastErrorRecorder retains the error of the latest action run. A successful run clears it.
+type lastErrorRecorder struct {
+	mu        sync.Mutex
+	lastError *LastError
+}
+
+// LastError returns the error of the latest run, or nil if the latest run was successful.
+func (r *lastErrorRecorder) LastError() *LastError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastError
+}
+
+func (r *lastErrorRecorder) recordError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		r.lastError = nil
+		return
+	}
+	r.lastError = &LastError{Err: err, Timestamp: time.Now()}
+}
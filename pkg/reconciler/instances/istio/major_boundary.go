@@ -0,0 +1,50 @@
+package istio
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const highestMinorPerMajorEnvKey = "ISTIO_HIGHEST_MINOR_PER_MAJOR"
+
+// highestMinorPerMajor holds the last minor version released for a major version of Istio. A major version listed here is
+// considered adjacent to the .0 minor version of the next major version. It is loaded from the ISTIO_HIGHEST_MINOR_PER_MAJOR
+// env variable when the reconciler is initialized.
+var highestMinorPerMajor = map[int64]int64{}
+
+// loadHighestMinorPerMajor configures the highest minor version per major version from the ISTIO_HIGHEST_MINOR_PER_MAJOR env variable.
+func loadHighestMinorPerMajor() error {
+	highestMinors, err := parseHighestMinorPerMajor(os.Getenv(highestMinorPerMajorEnvKey))
+	if err != nil {
+		return errors.Wrapf(err, "Error parsing env variable '%s'", highestMinorPerMajorEnvKey)
+	}
+	highestMinorPerMajor = highestMinors
+	return nil
+}
+
+// parseHighestMinorPerMajor parses a comma-separated list of major:minor pairs, e.g. "1:30". An empty input yields an empty map.
+func parseHighestMinorPerMajor(input string) (map[int64]int64, error) {
+	highestMinors := map[int64]int64{}
+	for _, pair := range commaSeparatedList(input) {
+		majorString, minorString, found := strings.Cut(pair, ":")
+		if !found {
+			return nil, errors.Errorf("Invalid major:minor pair '%s'", pair)
+		}
+		major, err := strconv.ParseInt(strings.TrimSpace(majorString), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid major version in '%s'", pair)
+		}
+		minor, err := strconv.ParseInt(strings.TrimSpace(minorString), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid minor version in '%s'", pair)
+		}
+		if major < 0 || minor < 0 {
+			return nil, errors.Errorf("Negative version in '%s'", pair)
+		}
+		highestMinors[major] = minor
+	}
+	return highestMinors, nil
+}
@@ -0,0 +1,93 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseHighestMinorPerMajor(t *testing.T) {
+
+	t.Run("should return empty map when nothing is configured", func(t *testing.T) {
+		// when
+		highestMinors, err := parseHighestMinorPerMajor("")
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, highestMinors)
+	})
+
+	t.Run("should parse all major:minor pairs", func(t *testing.T) {
+		// when
+		highestMinors, err := parseHighestMinorPerMajor("1:30, 2:12")
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, map[int64]int64{1: 30, 2: 12}, highestMinors)
+	})
+
+	t.Run("should return error when a pair has no minor version", func(t *testing.T) {
+		// when
+		_, err := parseHighestMinorPerMajor("1")
+
+		// then
+		require.EqualError(t, err, "Invalid major:minor pair '1'")
+	})
+
+	t.Run("should return error when a version is not a number", func(t *testing.T) {
+		// when
+		_, err := parseHighestMinorPerMajor("1:x")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid minor version in '1:x'")
+	})
+}
+
+func Test_loadHighestMinorPerMajor(t *testing.T) {
+	highestMinors := highestMinorPerMajor
+	defer func() { highestMinorPerMajor = highestMinors }()
+
+	majorStep := actions.IstioStatus{ClientVersion: "2.0.0", TargetVersion: "2.0.0", PilotVersion: "1.30.2", DataPlaneVersions: map[string]bool{"1.30.2": true}}
+
+	t.Run("should plan update across the major version boundary when the highest minor version is configured", func(t *testing.T) {
+		// given
+		t.Setenv(highestMinorPerMajorEnvKey, "1:30")
+
+		// when
+		err := loadHighestMinorPerMajor()
+		require.NoError(t, err)
+		actionKind, planErr := PlanAction(majorStep)
+
+		// then
+		require.NoError(t, planErr)
+		require.Equal(t, ActionKindUpdate, actionKind)
+	})
+
+	t.Run("should plan blocked across the major version boundary when the highest minor version is not configured", func(t *testing.T) {
+		// given
+		t.Setenv(highestMinorPerMajorEnvKey, "")
+
+		// when
+		err := loadHighestMinorPerMajor()
+		require.NoError(t, err)
+		actionKind, planErr := PlanAction(majorStep)
+
+		// then
+		require.Error(t, planErr)
+		require.Equal(t, ActionKindBlocked, actionKind)
+	})
+
+	t.Run("should return error when the env variable is invalid", func(t *testing.T) {
+		// given
+		t.Setenv(highestMinorPerMajorEnvKey, "1-30")
+
+		// when
+		err := loadHighestMinorPerMajor()
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), highestMinorPerMajorEnvKey)
+	})
+}
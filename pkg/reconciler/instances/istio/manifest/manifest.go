@@ -2,16 +2,18 @@ package manifest
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/kubernetes"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
 	istioOperatorKind = "IstioOperator"
 )
 
-// Returns a manifest with IstioOperator CR excluded. The given manifest must be in YAML format.
+// Returns a manifest with all IstioOperator CRs excluded. The given manifest must be in YAML format.
 func GenerateNewManifestWithoutIstioOperatorFrom(manifest string) (string, error) {
 	unstructs, err := kubernetes.ToUnstructured([]byte(manifest), true)
 	if err != nil {
@@ -36,25 +38,182 @@ func GenerateNewManifestWithoutIstioOperatorFrom(manifest string) (string, error
 	return builder.String(), nil
 }
 
-// Returns IstioOperator CR, if present in the given manifest. Returns an error otherwise. The given manifest must be in YAML format.
+// FilterManifestByKind returns a manifest with only the resources of the given kinds, in the order of the given manifest.
+// The given manifest must be in YAML format.
+func FilterManifestByKind(manifestYAML string, kinds ...string) (string, error) {
+	unstructs, err := kubernetes.ToUnstructured([]byte(manifestYAML), true)
+	if err != nil {
+		return "", err
+	}
+
+	wantedKinds := make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		wantedKinds[kind] = true
+	}
+
+	builder := strings.Builder{}
+	for _, unstruct := range unstructs {
+		if !wantedKinds[unstruct.GetKind()] {
+			continue
+		}
+
+		unstructBytes, err := unstruct.MarshalJSON()
+		if err != nil {
+			return "", err
+		}
+
+		builder.WriteString("---\n")
+		builder.WriteString(string(unstructBytes))
+	}
+
+	return builder.String(), nil
+}
+
+// Returns IstioOperator CR, if present in the given manifest. Returns an error otherwise. If the manifest contains multiple
+// IstioOperator CRs, the first one is returned. Later documents with the same name and namespace are overlays of it, their
+// spec is merged in the order of the manifest, so later overlays override the fields of earlier ones. The given manifest
+// must be in YAML format.
 func ExtractIstioOperatorContextFrom(manifest string) (string, error) {
 	unstructs, err := kubernetes.ToUnstructured([]byte(manifest), true)
 	if err != nil {
 		return "", err
 	}
 
+	for _, unstruct := range unstructs {
+		if unstruct.GetKind() == istioOperatorKind {
+			return mergeIstioOperatorDocuments(unstructs, unstruct.GetName(), unstruct.GetNamespace())
+		}
+	}
+
+	return "", errors.New("Istio Operator definition could not be found in manifest")
+}
+
+// Returns IstioOperator CR with the given name and, if not empty, the given namespace. Returns an error if there is no such CR in the manifest.
+// Overlays of the CR are merged the same way as by ExtractIstioOperatorContextFrom. The given manifest must be in YAML format.
+func ExtractIstioOperatorContextByNameFrom(manifest, name, namespace string) (string, error) {
+	unstructs, err := kubernetes.ToUnstructured([]byte(manifest), true)
+	if err != nil {
+		return "", err
+	}
+
+	for _, unstruct := range unstructs {
+		if isIstioOperator(unstruct, name, namespace) {
+			return mergeIstioOperatorDocuments(unstructs, name, unstruct.GetNamespace())
+		}
+	}
+
+	if namespace != "" {
+		return "", fmt.Errorf("Istio Operator definition %s/%s could not be found in manifest", namespace, name)
+	}
+	return "", fmt.Errorf("Istio Operator definition %s could not be found in manifest", name)
+}
+
+// ValidateSingleIstioOperator checks that the given manifest contains exactly one IstioOperator CR, possibly split into a
+// base and overlay documents of the same name and namespace. Returns an error otherwise. The given manifest must be in YAML format.
+func ValidateSingleIstioOperator(manifest string) error {
+	unstructs, err := kubernetes.ToUnstructured([]byte(manifest), true)
+	if err != nil {
+		return err
+	}
+
+	var operators []string
 	for _, unstruct := range unstructs {
 		if unstruct.GetKind() != istioOperatorKind {
 			continue
 		}
+		operator := fmt.Sprintf("%s/%s", unstruct.GetNamespace(), unstruct.GetName())
+		if !containsString(operators, operator) {
+			operators = append(operators, operator)
+		}
+	}
 
-		unstructBytes, err := unstruct.MarshalJSON()
+	switch len(operators) {
+	case 0:
+		return errors.New("Istio Operator definition could not be found in manifest, check the configuration of the Istio chart")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("Manifest contains %d Istio Operator definitions (%s), expected exactly one", len(operators), strings.Join(operators, ", "))
+	}
+}
+
+// mergeIstioOperatorDocuments returns the IstioOperator CR with the given name and namespace in JSON format, with the spec
+// of all its documents merged in the order of the manifest.
+func mergeIstioOperatorDocuments(unstructs []*unstructured.Unstructured, name, namespace string) (string, error) {
+	merged, err := mergeIstioOperatorObjects(unstructs, name, namespace)
+	if err != nil {
+		return "", err
+	}
+
+	unstructBytes, err := (&unstructured.Unstructured{Object: merged}).MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+
+	return string(unstructBytes), nil
+}
+
+// mergeIstioOperatorObjects returns the first IstioOperator CR with the given name and namespace, with the spec of the
+// later documents of the same CR merged into its spec. Everything except the spec, e.g. the metadata, is taken from the
+// first document.
+func mergeIstioOperatorObjects(unstructs []*unstructured.Unstructured, name, namespace string) (map[string]interface{}, error) {
+	var merged map[string]interface{}
+	for _, unstruct := range unstructs {
+		if unstruct.GetKind() != istioOperatorKind || unstruct.GetName() != name || unstruct.GetNamespace() != namespace {
+			continue
+		}
+		if merged == nil {
+			merged = unstruct.DeepCopy().Object
+			continue
+		}
+
+		overlaySpec, found, err := unstructured.NestedMap(unstruct.Object, "spec")
 		if err != nil {
-			return "", nil
+			return nil, errors.New("Istio Operator overlay spec must be of type object")
 		}
+		if !found {
+			continue
+		}
+		baseSpec, _, err := unstructured.NestedMap(merged, "spec")
+		if err != nil {
+			return nil, errors.New("Istio Operator spec must be of type object")
+		}
+		merged["spec"] = mergeObjects(baseSpec, overlaySpec)
+	}
+	return merged, nil
+}
 
-		return string(unstructBytes), nil
+func isIstioOperator(unstruct *unstructured.Unstructured, name, namespace string) bool {
+	if unstruct.GetKind() != istioOperatorKind || unstruct.GetName() != name {
+		return false
 	}
+	return namespace == "" || unstruct.GetNamespace() == namespace
+}
 
-	return "", errors.New("Istio Operator definition could not be found in manifest")
+// mergeObjects deep merges the overlay into the base. Nested objects are merged, all other values of the overlay, including
+// lists, replace the values of the base.
+func mergeObjects(base, overlay map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseObject, baseIsObject := merged[key].(map[string]interface{})
+		overlayObject, overlayIsObject := value.(map[string]interface{})
+		if baseIsObject && overlayIsObject {
+			merged[key] = mergeObjects(baseObject, overlayObject)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,6 +27,41 @@ metadata:
   namespace: namespace
   name: name
 `
+
+	baseAndOverlayOperatorsManifest = `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: base
+spec:
+  profile: default
+  hub: base-hub
+  components:
+    pilot:
+      enabled: true
+---
+apiVersion: version/v1
+kind: Kind1
+metadata:
+  namespace: namespace
+  name: name
+---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: base
+spec:
+  hub: overlay-hub
+  components:
+    cni:
+      enabled: true
+  values:
+    global:
+      proxy:
+        holdApplicationUntilProxyStarts: true
+`
 )
 
 func Test_extractIstioOperatorContextFrom(t *testing.T) {
@@ -49,4 +85,250 @@ func Test_extractIstioOperatorContextFrom(t *testing.T) {
 		require.Contains(t, result, "IstioOperator")
 	})
 
+	t.Run("should merge multiple istio operators in the order of the manifest", func(t *testing.T) {
+		// when
+		result, err := ExtractIstioOperatorContextFrom(baseAndOverlayOperatorsManifest)
+
+		// then
+		require.NoError(t, err)
+		require.JSONEq(t, `{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","metadata":{"namespace":"istio-system","name":"base"},"spec":{"profile":"default","hub":"overlay-hub","components":{"cni":{"enabled":true},"pilot":{"enabled":true}},"values":{"global":{"proxy":{"holdApplicationUntilProxyStarts":true}}}}}`, result)
+	})
+
+	t.Run("should not merge istio operators with a different name", func(t *testing.T) {
+		// given
+		otherOperatorManifest := baseAndOverlayOperatorsManifest + `---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: other
+  labels:
+    other: "true"
+spec:
+  hub: other-hub
+`
+
+		// when
+		result, err := ExtractIstioOperatorContextFrom(otherOperatorManifest)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, `"hub":"overlay-hub"`)
+		require.NotContains(t, result, "other")
+	})
+
+	t.Run("should take everything except the spec from the first istio operator document", func(t *testing.T) {
+		// given
+		overlayWithMetadataManifest := baseAndOverlayOperatorsManifest + `---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: base
+  labels:
+    overlay: "true"
+spec:
+  profile: minimal
+`
+
+		// when
+		result, err := ExtractIstioOperatorContextFrom(overlayWithMetadataManifest)
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, `"profile":"minimal"`)
+		require.NotContains(t, result, "labels")
+	})
+
+}
+
+func Test_GenerateNewManifestWithoutIstioOperatorFrom(t *testing.T) {
+
+	t.Run("should strip all istio operators from manifest", func(t *testing.T) {
+		// when
+		result, err := GenerateNewManifestWithoutIstioOperatorFrom(baseAndOverlayOperatorsManifest)
+
+		// then
+		require.NoError(t, err)
+		require.NotContains(t, result, "IstioOperator")
+		require.Contains(t, result, "Kind1")
+	})
+}
+
+func Test_extractIstioOperatorContextByNameFrom(t *testing.T) {
+
+	multipleOperatorsManifest := `
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: default-operator
+spec:
+  profile: default
+---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: gateways-operator
+spec:
+  profile: empty
+---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: other-namespace
+  name: gateways-operator
+spec:
+  profile: minimal
+`
+
+	t.Run("should extract istio operator with the given name", func(t *testing.T) {
+		// when
+		result, err := ExtractIstioOperatorContextByNameFrom(multipleOperatorsManifest, "default-operator", "")
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, `"name":"default-operator"`)
+		require.Contains(t, result, `"profile":"default"`)
+	})
+
+	t.Run("should extract istio operator with the given name and namespace", func(t *testing.T) {
+		// when
+		result, err := ExtractIstioOperatorContextByNameFrom(multipleOperatorsManifest, "gateways-operator", "other-namespace")
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, `"namespace":"other-namespace"`)
+		require.Contains(t, result, `"profile":"minimal"`)
+	})
+
+	t.Run("should merge the overlays of the istio operator with the given name", func(t *testing.T) {
+		// given
+		overlayManifest := multipleOperatorsManifest + `---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: istio-system
+  name: gateways-operator
+spec:
+  hub: overlay-hub
+`
+
+		// when
+		result, err := ExtractIstioOperatorContextByNameFrom(overlayManifest, "gateways-operator", "")
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, `"namespace":"istio-system"`)
+		require.Contains(t, result, `"profile":"empty"`)
+		require.Contains(t, result, `"hub":"overlay-hub"`)
+	})
+
+	t.Run("should return error when there is no istio operator with the given name", func(t *testing.T) {
+		// when
+		result, err := ExtractIstioOperatorContextByNameFrom(multipleOperatorsManifest, "unknown-operator", "")
+
+		// then
+		require.Empty(t, result)
+		require.EqualError(t, err, "Istio Operator definition unknown-operator could not be found in manifest")
+	})
+
+	t.Run("should return error when there is no istio operator with the given name in the given namespace", func(t *testing.T) {
+		// when
+		result, err := ExtractIstioOperatorContextByNameFrom(multipleOperatorsManifest, "default-operator", "other-namespace")
+
+		// then
+		require.Empty(t, result)
+		require.EqualError(t, err, "Istio Operator definition other-namespace/default-operator could not be found in manifest")
+	})
+}
+
+func Test_ValidateSingleIstioOperator(t *testing.T) {
+
+	t.Run("should accept manifest with exactly one istio operator", func(t *testing.T) {
+		// when
+		err := ValidateSingleIstioOperator(istioManifest)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept manifest with a base and an overlay of one istio operator", func(t *testing.T) {
+		// when
+		err := ValidateSingleIstioOperator(baseAndOverlayOperatorsManifest)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should reject manifest without istio operator", func(t *testing.T) {
+		// when
+		err := ValidateSingleIstioOperator("")
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Istio Operator definition could not be found in manifest")
+	})
+
+	t.Run("should reject manifest with multiple istio operators", func(t *testing.T) {
+		// given
+		multipleOperatorsManifest := istioManifest + `---
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  namespace: namespace
+  name: second
+`
+
+		// when
+		err := ValidateSingleIstioOperator(multipleOperatorsManifest)
+
+		// then
+		require.EqualError(t, err, "Manifest contains 2 Istio Operator definitions (namespace/name, namespace/second), expected exactly one")
+	})
+}
+
+func Test_FilterManifestByKind(t *testing.T) {
+
+	t.Run("should return empty manifest from empty input manifest", func(t *testing.T) {
+		// when
+		result, err := FilterManifestByKind("", "Kind1")
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("should return only documents of the given kind", func(t *testing.T) {
+		// when
+		result, err := FilterManifestByKind(istioManifest, "Kind2")
+
+		// then
+		require.NoError(t, err)
+		require.Contains(t, result, "Kind2")
+		require.NotContains(t, result, "Kind1")
+		require.NotContains(t, result, "IstioOperator")
+		require.Equal(t, 1, strings.Count(result, "---\n"))
+	})
+
+	t.Run("should return documents of all given kinds in manifest order", func(t *testing.T) {
+		// when
+		result, err := FilterManifestByKind(istioManifest, "Kind2", "Kind1")
+
+		// then
+		require.NoError(t, err)
+		require.NotContains(t, result, "IstioOperator")
+		require.Equal(t, 2, strings.Count(result, "---\n"))
+		require.Less(t, strings.Index(result, "Kind1"), strings.Index(result, "Kind2"))
+	})
+
+	t.Run("should return empty manifest when no document matches", func(t *testing.T) {
+		// when
+		result, err := FilterManifestByKind(istioManifest, "Dashboard")
+
+		// then
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
 }
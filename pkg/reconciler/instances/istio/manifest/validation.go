@@ -0,0 +1,137 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+type fieldKind string
+
+const (
+	fieldKindString fieldKind = "string"
+	fieldKindObject fieldKind = "object"
+	fieldKindAny    fieldKind = "any"
+)
+
+const istioOperatorGroup = "install.istio.io"
+
+// istioOperatorFields lists the known top-level fields of the IstioOperator resource. The types of apiVersion, kind and spec
+// are checked with the structure of the IstioOperator.
+var istioOperatorFields = map[string]fieldKind{
+	"apiVersion": fieldKindAny,
+	"kind":       fieldKindAny,
+	"metadata":   fieldKindObject,
+	"spec":       fieldKindAny,
+	"status":     fieldKindObject,
+}
+
+// istioOperatorSpecFields lists the known fields of the IstioOperator spec.
+var istioOperatorSpecFields = map[string]fieldKind{
+	"profile":            fieldKindString,
+	"installPackagePath": fieldKindString,
+	"hub":                fieldKindString,
+	"tag":                fieldKindAny,
+	"resourceSuffix":     fieldKindString,
+	"namespace":          fieldKindString,
+	"revision":           fieldKindString,
+	"meshConfig":         fieldKindObject,
+	"components":         fieldKindObject,
+	"addonComponents":    fieldKindObject,
+	"values":             fieldKindObject,
+	"unvalidatedValues":  fieldKindObject,
+}
+
+// ValidateIstioOperatorSchema checks that the given IstioOperator in YAML or JSON format is structurally sound, i.e. it has
+// the apiVersion of the IstioOperator API group, the IstioOperator kind and a spec object. With knownFieldsOnly it also
+// rejects unknown or mistyped top-level and spec fields. It returns an error listing all violations.
+//
+// The structural checks always apply, as istioctl rejects such an IstioOperator anyway, only later in the middle of an
+// installation. The field checks are opt-in, as istioctl accepts experimental fields which are unknown to them.
+func ValidateIstioOperatorSchema(operatorManifest string, knownFieldsOnly bool) error {
+	var istioOperator map[string]interface{}
+	err := yaml.Unmarshal([]byte(operatorManifest), &istioOperator)
+	if err != nil {
+		return errors.Wrap(err, "Could not parse Istio Operator")
+	}
+	if istioOperator == nil {
+		return errors.New("Istio Operator is empty")
+	}
+
+	violations := validateStructure(istioOperator)
+	if knownFieldsOnly {
+		var fieldViolations []string
+		fieldViolations = append(fieldViolations, validateFields(istioOperator, istioOperatorFields, "")...)
+		if spec, ok := istioOperator["spec"].(map[string]interface{}); ok {
+			fieldViolations = append(fieldViolations, validateFields(spec, istioOperatorSpecFields, "spec.")...)
+		}
+		sort.Strings(fieldViolations)
+		violations = append(violations, fieldViolations...)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("Istio Operator is invalid: %s", strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+func validateStructure(istioOperator map[string]interface{}) []string {
+	var violations []string
+	switch apiVersion, ok := istioOperator["apiVersion"].(string); {
+	case !ok || apiVersion == "":
+		violations = append(violations, "apiVersion is missing")
+	default:
+		groupVersion, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil || groupVersion.Group != istioOperatorGroup || groupVersion.Version == "" {
+			violations = append(violations, fmt.Sprintf("apiVersion %s is not a version of %s", apiVersion, istioOperatorGroup))
+		}
+	}
+	switch kind, ok := istioOperator["kind"].(string); {
+	case !ok || kind == "":
+		violations = append(violations, "kind is missing")
+	case kind != istioOperatorKind:
+		violations = append(violations, fmt.Sprintf("kind %s is not %s", kind, istioOperatorKind))
+	}
+	switch spec, ok := istioOperator["spec"]; {
+	case !ok || spec == nil:
+		violations = append(violations, "spec is missing")
+	case !hasKind(spec, fieldKindObject):
+		violations = append(violations, "spec must be of type object")
+	}
+	return violations
+}
+
+func validateFields(object map[string]interface{}, knownFields map[string]fieldKind, prefix string) []string {
+	var violations []string
+	for name, value := range object {
+		kind, ok := knownFields[name]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("unknown field %s%s", prefix, name))
+			continue
+		}
+		if !hasKind(value, kind) {
+			violations = append(violations, fmt.Sprintf("field %s%s must be of type %s", prefix, name, kind))
+		}
+	}
+	return violations
+}
+
+func hasKind(value interface{}, kind fieldKind) bool {
+	if value == nil {
+		return true
+	}
+	switch kind {
+	case fieldKindString:
+		_, ok := value.(string)
+		return ok
+	case fieldKindObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	default:
+		return true
+	}
+}
@@ -0,0 +1,129 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateIstioOperatorSchema(t *testing.T) {
+
+	t.Run("should accept a valid Istio Operator", func(t *testing.T) {
+		// given
+		operator := `{"kind":"IstioOperator","apiVersion":"install.istio.io/v1alpha1","metadata":{"name":"default-operator","namespace":"istio-system"},"spec":{"profile":"default","tag":"1.2.3","meshConfig":{"accessLogEncoding":"JSON"},"components":{"cni":{"enabled":true}},"values":{}}}`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, true)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should report unknown and mistyped fields", func(t *testing.T) {
+		// given
+		operator := `{"kind":"IstioOperator","apiVersion":"install.istio.io/v1alpha1","spec":{"meshconfig":{},"components":true,"profile":{"name":"default"}},"extra":1}`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, true)
+
+		// then
+		require.Error(t, err)
+		require.Equal(t, "Istio Operator is invalid: field spec.components must be of type object, field spec.profile must be of type string, unknown field extra, unknown field spec.meshconfig", err.Error())
+	})
+
+	t.Run("should accept a valid Istio Operator in YAML format without checking its fields", func(t *testing.T) {
+		// given
+		operator := `apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: default-operator
+  namespace: istio-system
+spec:
+  profile: default
+`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, false)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept an Istio Operator in JSON format with an empty spec", func(t *testing.T) {
+		// when
+		err := ValidateIstioOperatorSchema(`{"apiVersion":"install.istio.io/v1alpha1","kind":"IstioOperator","spec":{}}`, false)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should report missing apiVersion, kind and spec", func(t *testing.T) {
+		// given
+		operator := `metadata:
+  name: default-operator
+`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, false)
+
+		// then
+		require.EqualError(t, err, "Istio Operator is invalid: apiVersion is missing, kind is missing, spec is missing")
+	})
+
+	t.Run("should report the structural violations before the unknown fields", func(t *testing.T) {
+		// given
+		operator := `{"kind":"IstioOperator","spec":{"profil":"default"}}`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, true)
+
+		// then
+		require.EqualError(t, err, "Istio Operator is invalid: apiVersion is missing, unknown field spec.profil")
+	})
+
+	t.Run("should report malformed apiVersion, kind and spec", func(t *testing.T) {
+		// given
+		operator := `apiVersion: networking.istio.io/v1beta1
+kind: Gateway
+spec: default
+`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, false)
+
+		// then
+		require.EqualError(t, err, "Istio Operator is invalid: apiVersion networking.istio.io/v1beta1 is not a version of install.istio.io, kind Gateway is not IstioOperator, spec must be of type object")
+	})
+
+	t.Run("should report an apiVersion without the Istio Operator group", func(t *testing.T) {
+		// given
+		operator := `apiVersion: v1alpha1
+kind: IstioOperator
+spec:
+  profile: default
+`
+
+		// when
+		err := ValidateIstioOperatorSchema(operator, false)
+
+		// then
+		require.EqualError(t, err, "Istio Operator is invalid: apiVersion v1alpha1 is not a version of install.istio.io")
+	})
+
+	t.Run("should return error when Istio Operator is empty", func(t *testing.T) {
+		// when
+		err := ValidateIstioOperatorSchema("", false)
+
+		// then
+		require.EqualError(t, err, "Istio Operator is empty")
+	})
+
+	t.Run("should return error when Istio Operator is not valid YAML", func(t *testing.T) {
+		// when
+		err := ValidateIstioOperatorSchema("kind: [IstioOperator", false)
+
+		// then
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Could not parse Istio Operator")
+	})
+}
@@ -0,0 +1,169 @@
+package istio
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	metricsSubsystem   = "istio"
+	targetVersionLabel = "target_version"
+	actionLabel        = "action"
+
+	reconcileActionName  = "reconcile"
+	uninstallActionName  = "uninstall"
+	proxyResetActionName = "proxy_reset"
+)
+
+// ActionMetrics records how often the istio actions install, update, skip or are blocked from updating Istio and how long
+// they take.
+// The target version is empty if the action failed before it was resolved.
+type ActionMetrics interface {
+	// IncInstall counts an installation of Istio in the target version.
+	IncInstall(targetVersion string)
+
+	// IncUpdate counts an update of Istio to the target version.
+	IncUpdate(targetVersion string)
+
+	// IncSkip counts a reconciliation which neither installed nor updated Istio, as it is already at the target version.
+	IncSkip(targetVersion string)
+
+	// IncBlocked counts a reconciliation which could not update Istio, as the installed versions are not compatible.
+	IncBlocked(targetVersion string)
+
+	// ObserveDuration records the duration of a single run of the action.
+	ObserveDuration(action, targetVersion string, duration time.Duration)
+}
+
+// noopActionMetrics is the ActionMetrics used when no metrics were configured.
+type noopActionMetrics struct{}
+
+func (noopActionMetrics) IncInstall(string) {}
+
+func (noopActionMetrics) IncUpdate(string) {}
+
+func (noopActionMetrics) IncSkip(string) {}
+
+func (noopActionMetrics) IncBlocked(string) {}
+
+func (noopActionMetrics) ObserveDuration(string, string, time.Duration) {}
+
+// PrometheusActionMetrics provides ActionMetrics as Prometheus metrics:
+// - istio_install_total - number of Istio installations
+// - istio_update_total - number of Istio updates
+// - istio_skip_total - number of reconciliations which skipped installing or updating Istio
+// - istio_blocked_total - number of reconciliations which could not update Istio from incompatible versions
+// - istio_action_duration_seconds - duration of the istio actions
+// It implements the prometheus.Collector interface, so it has to be registered to be exposed.
+type PrometheusActionMetrics struct {
+	installTotal   *prometheus.CounterVec
+	updateTotal    *prometheus.CounterVec
+	skipTotal      *prometheus.CounterVec
+	blockedTotal   *prometheus.CounterVec
+	actionDuration *prometheus.HistogramVec
+}
+
+// NewPrometheusActionMetrics creates a new instance of PrometheusActionMetrics.
+func NewPrometheusActionMetrics() *PrometheusActionMetrics {
+	return &PrometheusActionMetrics{
+		installTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "install_total",
+			Help:      "Number of Istio installations",
+		}, []string{targetVersionLabel}),
+		updateTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "update_total",
+			Help:      "Number of Istio updates",
+		}, []string{targetVersionLabel}),
+		skipTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "skip_total",
+			Help:      "Number of reconciliations which neither installed nor updated Istio",
+		}, []string{targetVersionLabel}),
+		blockedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "blocked_total",
+			Help:      "Number of reconciliations which could not update Istio from incompatible versions",
+		}, []string{targetVersionLabel}),
+		actionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: metricsSubsystem,
+			Name:      "action_duration_seconds",
+			Help:      "Duration of the istio actions",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 11),
+		}, []string{actionLabel, targetVersionLabel}),
+	}
+}
+
+func (m *PrometheusActionMetrics) IncInstall(targetVersion string) {
+	m.installTotal.WithLabelValues(targetVersion).Inc()
+}
+
+func (m *PrometheusActionMetrics) IncUpdate(targetVersion string) {
+	m.updateTotal.WithLabelValues(targetVersion).Inc()
+}
+
+func (m *PrometheusActionMetrics) IncSkip(targetVersion string) {
+	m.skipTotal.WithLabelValues(targetVersion).Inc()
+}
+
+func (m *PrometheusActionMetrics) IncBlocked(targetVersion string) {
+	m.blockedTotal.WithLabelValues(targetVersion).Inc()
+}
+
+func (m *PrometheusActionMetrics) ObserveDuration(action, targetVersion string, duration time.Duration) {
+	m.actionDuration.WithLabelValues(action, targetVersion).Observe(duration.Seconds())
+}
+
+// Describe implements the prometheus.Collector interface.
+func (m *PrometheusActionMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.installTotal.Describe(ch)
+	m.updateTotal.Describe(ch)
+	m.skipTotal.Describe(ch)
+	m.blockedTotal.Describe(ch)
+	m.actionDuration.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (m *PrometheusActionMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.installTotal.Collect(ch)
+	m.updateTotal.Collect(ch)
+	m.skipTotal.Collect(ch)
+	m.blockedTotal.Collect(ch)
+	m.actionDuration.Collect(ch)
+}
+
+// registerActionMetrics registers the metrics with the registerer. Metrics which were already registered are kept.
+func registerActionMetrics(registerer prometheus.Registerer, metrics *PrometheusActionMetrics, logger *zap.SugaredLogger) error {
+	err := registerer.Register(metrics)
+	switch err := err.(type) {
+	case prometheus.AlreadyRegisteredError:
+		logger.Warnf("skipping registration of istio action metrics as they were already registered, existing: %v",
+			err.ExistingCollector)
+		return nil
+	}
+	return err
+}
+
+// actionObservation measures a single run of an action and keeps the target version resolved during the run.
+type actionObservation struct {
+	metrics       ActionMetrics
+	action        string
+	targetVersion string
+	start         time.Time
+	status        *ReconcileStatus
+}
+
+func newActionObservation(metrics ActionMetrics, action string) *actionObservation {
+	if metrics == nil {
+		metrics = noopActionMetrics{}
+	}
+	return &actionObservation{metrics: metrics, action: action, start: time.Now()}
+}
+
+// observeDuration records the duration since the start of the run.
+func (o *actionObservation) observeDuration() {
+	o.metrics.ObserveDuration(o.action, o.targetVersion, time.Since(o.start))
+}
@@ -0,0 +1,67 @@
+package istio
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PrometheusActionMetrics(t *testing.T) {
+
+	t.Run("should count decisions per target version", func(t *testing.T) {
+		// given
+		metrics := NewPrometheusActionMetrics()
+
+		// when
+		metrics.IncInstall("1.2.0")
+		metrics.IncUpdate("1.2.0")
+		metrics.IncUpdate("1.2.0")
+		metrics.IncSkip("1.3.0")
+		metrics.IncBlocked("1.4.0")
+		metrics.ObserveDuration(reconcileActionName, "1.2.0", time.Second)
+
+		// then
+		require.Equal(t, float64(1), testutil.ToFloat64(metrics.installTotal.WithLabelValues("1.2.0")))
+		require.Equal(t, float64(2), testutil.ToFloat64(metrics.updateTotal.WithLabelValues("1.2.0")))
+		require.Equal(t, float64(1), testutil.ToFloat64(metrics.skipTotal.WithLabelValues("1.3.0")))
+		require.Equal(t, float64(1), testutil.ToFloat64(metrics.blockedTotal.WithLabelValues("1.4.0")))
+		require.Equal(t, 5, testutil.CollectAndCount(metrics))
+	})
+}
+
+func Test_registerActionMetrics(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should register the metrics", func(t *testing.T) {
+		// given
+		registry := prometheus.NewRegistry()
+		metrics := NewPrometheusActionMetrics()
+
+		// when
+		err := registerActionMetrics(registry, metrics, logger)
+		metrics.IncInstall("1.2.0")
+
+		// then
+		require.NoError(t, err)
+		count, err := testutil.GatherAndCount(registry, "istio_install_total")
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("should keep the metrics which were already registered", func(t *testing.T) {
+		// given
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registerActionMetrics(registry, NewPrometheusActionMetrics(), logger))
+
+		// when
+		err := registerActionMetrics(registry, NewPrometheusActionMetrics(), logger)
+
+		// then
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,18 @@
+package istio
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+)
+
+const namespaceConfigKey = "istio.namespace"
+
+// istioSystemNamespace returns the namespace Istio is installed in, istio-system if the task does not configure one.
+func istioSystemNamespace(task *reconciler.Task) string {
+	value, ok := task.Configuration[namespaceConfigKey]
+	if !ok || value == nil || fmt.Sprint(value) == "" {
+		return istioNamespace
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,35 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_istioSystemNamespace(t *testing.T) {
+
+	t.Run("should return istio-system when namespace is not configured", func(t *testing.T) {
+		// when
+		namespace := istioSystemNamespace(&reconciler.Task{})
+
+		// then
+		require.Equal(t, "istio-system", namespace)
+	})
+
+	t.Run("should return istio-system when configured namespace is empty", func(t *testing.T) {
+		// when
+		namespace := istioSystemNamespace(&reconciler.Task{Configuration: map[string]interface{}{"istio.namespace": ""}})
+
+		// then
+		require.Equal(t, "istio-system", namespace)
+	})
+
+	t.Run("should return configured namespace", func(t *testing.T) {
+		// when
+		namespace := istioSystemNamespace(&reconciler.Task{Configuration: map[string]interface{}{"istio.namespace": "custom-istio"}})
+
+		// then
+		require.Equal(t, "custom-istio", namespace)
+	})
+}
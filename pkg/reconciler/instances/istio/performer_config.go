@@ -0,0 +1,130 @@
+package istio
+
+import (
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"go.uber.org/zap"
+)
+
+// configurableIstioPerformer is the part of the DefaultIstioPerformer which is configured for the task.
+type configurableIstioPerformer interface {
+	WithNamespace(namespace string) *actions.DefaultIstioPerformer
+	WithMaxConcurrentNamespaces(maxConcurrentNamespaces int) *actions.DefaultIstioPerformer
+	WithUninstallRetry(retriesCount uint, delayBetweenRetries time.Duration) *actions.DefaultIstioPerformer
+	WithIstiodTerminationWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer
+	WithIstioOperatorSchemaValidation(validateOperatorSchema bool) *actions.DefaultIstioPerformer
+	WithUninstallGracePeriod(uninstallGracePeriod time.Duration) *actions.DefaultIstioPerformer
+	WithIstioOperatorBackup(retention int) *actions.DefaultIstioPerformer
+	WithApplyTimeout(applyTimeout time.Duration) *actions.DefaultIstioPerformer
+	WithPhasedInstall(phasedInstall bool) *actions.DefaultIstioPerformer
+	WithPhaseWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer
+	WithPerformerConfig(config actions.PerformerConfig) *actions.DefaultIstioPerformer
+	WithUninstallWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer
+	WithNamespaceDeletionWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer
+	WithIstioctlLogOutputLevel(level string) *actions.DefaultIstioPerformer
+	WithMergedConfigDump(dumpMergedConfig bool) *actions.DefaultIstioPerformer
+}
+
+// configureIstioPerformer applies the settings of the performer configured for the task. Settings the task does not configure
+// keep the defaults of the performer.
+func configureIstioPerformer(performer configurableIstioPerformer, task *reconciler.Task, logger *zap.SugaredLogger) {
+	performer.WithNamespace(istioSystemNamespace(task))
+	if maxConcurrentNamespaces, ok := intConfig(task, maxConcurrentNamespacesConfigKey, logger); ok {
+		performer.WithMaxConcurrentNamespaces(maxConcurrentNamespaces)
+	}
+	configureUninstallRetry(performer, task, logger)
+	configureWait(task, istiodTerminationTimeoutConfigKey, istiodTerminationIntervalConfigKey, logger, performer.WithIstiodTerminationWait)
+	performer.WithIstioOperatorSchemaValidation(boolConfig(task, validateOperatorSchemaConfigKey, logger))
+	if uninstallGracePeriod, ok := durationConfig(task, uninstallGracePeriodConfigKey, logger); ok {
+		performer.WithUninstallGracePeriod(uninstallGracePeriod)
+	}
+	if retention, ok := intConfig(task, istioOperatorBackupRetentionConfigKey, logger); ok {
+		performer.WithIstioOperatorBackup(retention)
+	}
+	if applyTimeout, ok := durationConfig(task, applyTimeoutConfigKey, logger); ok {
+		performer.WithApplyTimeout(applyTimeout)
+	}
+	performer.WithPhasedInstall(boolConfig(task, phasedInstallConfigKey, logger))
+	configureWait(task, phaseWaitTimeoutConfigKey, phaseWaitIntervalConfigKey, logger, performer.WithPhaseWait)
+	performer.WithPerformerConfig(performerConfig(task, logger))
+	configureWait(task, uninstallWaitTimeoutConfigKey, uninstallWaitIntervalConfigKey, logger, performer.WithUninstallWait)
+	configureNamespaceDeletionWait(performer, task, logger)
+	performer.WithIstioctlLogOutputLevel(istioctlLogOutputLevel(task, logger))
+	performer.WithMergedConfigDump(boolConfig(task, dumpMergedConfigConfigKey, logger))
+}
+
+// configureUninstallRetry configures the retries of istioctl uninstall and the istio-system namespace deletion. A zero
+// retries count is ignored, as it would retry until the uninstall succeeds.
+func configureUninstallRetry(performer configurableIstioPerformer, task *reconciler.Task, logger *zap.SugaredLogger) {
+	retries, retriesConfigured := intConfig(task, uninstallRetriesConfigKey, logger)
+	delay, delayConfigured := durationConfig(task, uninstallRetryDelayConfigKey, logger)
+	if retriesConfigured && retries == 0 {
+		logger.Warnf("Invalid %s value 0, using the default", uninstallRetriesConfigKey)
+		retriesConfigured = false
+	}
+	if !retriesConfigured && !delayConfigured {
+		return
+	}
+	if !retriesConfigured {
+		retries = actions.DefaultRetriesCount
+	}
+	if !delayConfigured {
+		delay = actions.DefaultDelayBetweenRetries
+	}
+	performer.WithUninstallRetry(uint(retries), delay)
+}
+
+// configureWait passes the wait timeout and check interval configured for the task to configure, if at least one of them is
+// configured. The other one keeps the default of the performer. A zero interval is ignored, as it would check without pause.
+func configureWait(task *reconciler.Task, timeoutKey, intervalKey string, logger *zap.SugaredLogger, configure func(timeout, interval time.Duration) *actions.DefaultIstioPerformer) {
+	timeout, timeoutConfigured := durationConfig(task, timeoutKey, logger)
+	interval, intervalConfigured := durationConfig(task, intervalKey, logger)
+	if intervalConfigured && interval == 0 {
+		logger.Warnf("Invalid %s value 0, using the default", intervalKey)
+		intervalConfigured = false
+	}
+	if !timeoutConfigured && !intervalConfigured {
+		return
+	}
+	if !timeoutConfigured {
+		timeout = actions.DefaultTimeout
+	}
+	if !intervalConfigured {
+		interval = actions.DefaultInterval
+	}
+	configure(timeout, interval)
+}
+
+// performerConfig returns the retries and waits of the performer configured for the task. Settings the task does not configure
+// are left zero, so the performer uses its defaults.
+func performerConfig(task *reconciler.Task, logger *zap.SugaredLogger) actions.PerformerConfig {
+	var config actions.PerformerConfig
+	config.RetriesCount, _ = intConfig(task, performerRetriesConfigKey, logger)
+	config.DelayBetweenRetries, _ = durationConfig(task, performerRetryDelayConfigKey, logger)
+	config.MaxDelayBetweenRetries, _ = durationConfig(task, performerMaxRetryDelayConfigKey, logger)
+	config.Timeout, _ = durationConfig(task, performerTimeoutConfigKey, logger)
+	config.Interval, _ = durationConfig(task, performerIntervalConfigKey, logger)
+	switch delayType := actions.RetryDelayType(stringConfig(task, performerRetryDelayTypeConfigKey)); delayType {
+	case "", actions.RetryDelayFixed, actions.RetryDelayBackOff:
+		config.DelayType = delayType
+	default:
+		logger.Warnf("Invalid %s value %s, using default %s", performerRetryDelayTypeConfigKey, delayType, actions.RetryDelayFixed)
+	}
+	return config
+}
+
+// configureNamespaceDeletionWait configures the wait for the deleted istio-system namespace. Uninstall waits only if the task
+// configures its timeout, the interval keeps the default of the performer if it is not configured.
+func configureNamespaceDeletionWait(performer configurableIstioPerformer, task *reconciler.Task, logger *zap.SugaredLogger) {
+	timeout, ok := durationConfig(task, namespaceDeletionTimeoutConfigKey, logger)
+	if !ok {
+		return
+	}
+	interval, ok := durationConfig(task, namespaceDeletionIntervalConfigKey, logger)
+	if !ok || interval == 0 {
+		interval = actions.DefaultInterval
+	}
+	performer.WithNamespaceDeletionWait(timeout, interval)
+}
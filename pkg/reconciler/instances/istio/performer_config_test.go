@@ -0,0 +1,350 @@
+package istio
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/stretchr/testify/require"
+)
+
+// performerSettings records the settings which configureIstioPerformer applies, by the name of the setting.
+type performerSettings map[string][]interface{}
+
+func (s performerSettings) WithNamespace(namespace string) *actions.DefaultIstioPerformer {
+	s["Namespace"] = []interface{}{namespace}
+	return nil
+}
+
+func (s performerSettings) WithMaxConcurrentNamespaces(maxConcurrentNamespaces int) *actions.DefaultIstioPerformer {
+	s["MaxConcurrentNamespaces"] = []interface{}{maxConcurrentNamespaces}
+	return nil
+}
+
+func (s performerSettings) WithUninstallRetry(retriesCount uint, delayBetweenRetries time.Duration) *actions.DefaultIstioPerformer {
+	s["UninstallRetry"] = []interface{}{retriesCount, delayBetweenRetries}
+	return nil
+}
+
+func (s performerSettings) WithIstiodTerminationWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer {
+	s["IstiodTerminationWait"] = []interface{}{timeout, interval}
+	return nil
+}
+
+func (s performerSettings) WithIstioOperatorSchemaValidation(validateOperatorSchema bool) *actions.DefaultIstioPerformer {
+	s["IstioOperatorSchemaValidation"] = []interface{}{validateOperatorSchema}
+	return nil
+}
+
+func (s performerSettings) WithUninstallGracePeriod(uninstallGracePeriod time.Duration) *actions.DefaultIstioPerformer {
+	s["UninstallGracePeriod"] = []interface{}{uninstallGracePeriod}
+	return nil
+}
+
+func (s performerSettings) WithIstioOperatorBackup(retention int) *actions.DefaultIstioPerformer {
+	s["IstioOperatorBackup"] = []interface{}{retention}
+	return nil
+}
+
+func (s performerSettings) WithApplyTimeout(applyTimeout time.Duration) *actions.DefaultIstioPerformer {
+	s["ApplyTimeout"] = []interface{}{applyTimeout}
+	return nil
+}
+
+func (s performerSettings) WithPhasedInstall(phasedInstall bool) *actions.DefaultIstioPerformer {
+	s["PhasedInstall"] = []interface{}{phasedInstall}
+	return nil
+}
+
+func (s performerSettings) WithPhaseWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer {
+	s["PhaseWait"] = []interface{}{timeout, interval}
+	return nil
+}
+
+func (s performerSettings) WithPerformerConfig(config actions.PerformerConfig) *actions.DefaultIstioPerformer {
+	s["PerformerConfig"] = []interface{}{config}
+	return nil
+}
+
+func (s performerSettings) WithUninstallWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer {
+	s["UninstallWait"] = []interface{}{timeout, interval}
+	return nil
+}
+
+func (s performerSettings) WithNamespaceDeletionWait(timeout, interval time.Duration) *actions.DefaultIstioPerformer {
+	s["NamespaceDeletionWait"] = []interface{}{timeout, interval}
+	return nil
+}
+
+func (s performerSettings) WithIstioctlLogOutputLevel(level string) *actions.DefaultIstioPerformer {
+	s["IstioctlLogOutputLevel"] = []interface{}{level}
+	return nil
+}
+
+func (s performerSettings) WithMergedConfigDump(dumpMergedConfig bool) *actions.DefaultIstioPerformer {
+	s["MergedConfigDump"] = []interface{}{dumpMergedConfig}
+	return nil
+}
+
+func Test_configureIstioPerformer(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	configure := func(configuration map[string]interface{}) performerSettings {
+		settings := performerSettings{}
+		configureIstioPerformer(settings, &reconciler.Task{Configuration: configuration}, logger)
+		return settings
+	}
+
+	t.Run("should keep the defaults of the performer when the task configures nothing", func(t *testing.T) {
+		// when
+		settings := configure(nil)
+
+		// then
+		require.Equal(t, []interface{}{"istio-system"}, settings["Namespace"])
+		require.NotContains(t, settings, "MaxConcurrentNamespaces")
+		require.NotContains(t, settings, "UninstallRetry")
+		require.NotContains(t, settings, "IstiodTerminationWait")
+		require.Equal(t, []interface{}{false}, settings["IstioOperatorSchemaValidation"])
+		require.NotContains(t, settings, "UninstallGracePeriod")
+		require.NotContains(t, settings, "IstioOperatorBackup")
+		require.NotContains(t, settings, "ApplyTimeout")
+		require.Equal(t, []interface{}{false}, settings["PhasedInstall"])
+		require.NotContains(t, settings, "PhaseWait")
+		require.Equal(t, []interface{}{actions.PerformerConfig{}}, settings["PerformerConfig"])
+		require.NotContains(t, settings, "UninstallWait")
+		require.NotContains(t, settings, "NamespaceDeletionWait")
+		require.Equal(t, []interface{}{""}, settings["IstioctlLogOutputLevel"])
+		require.Equal(t, []interface{}{false}, settings["MergedConfigDump"])
+	})
+
+	t.Run("should configure the namespace istio is installed in", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.namespace": "custom-istio"})
+
+		// then
+		require.Equal(t, []interface{}{"custom-istio"}, settings["Namespace"])
+	})
+
+	t.Run("should configure the maximum of concurrently reset namespaces", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.proxyReset.maxConcurrentNamespaces": "4"})
+
+		// then
+		require.Equal(t, []interface{}{4}, settings["MaxConcurrentNamespaces"])
+	})
+
+	t.Run("should configure the uninstall retries", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.uninstall.retries": "3", "istio.uninstall.retryDelay": "30s"})
+
+		// then
+		require.Equal(t, []interface{}{uint(3), 30 * time.Second}, settings["UninstallRetry"])
+	})
+
+	t.Run("should keep the default delay between the uninstall retries when only the retries are configured", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.uninstall.retries": "3"})
+
+		// then
+		require.Equal(t, []interface{}{uint(3), actions.DefaultDelayBetweenRetries}, settings["UninstallRetry"])
+	})
+
+	t.Run("should ignore zero uninstall retries", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.uninstall.retries": "0"})
+
+		// then
+		require.NotContains(t, settings, "UninstallRetry")
+	})
+
+	t.Run("should configure the wait for the istiod termination on reinstall", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.forceReinstall.istiodTerminationTimeout": "2m", "istio.forceReinstall.istiodTerminationInterval": "3s"})
+
+		// then
+		require.Equal(t, []interface{}{2 * time.Minute, 3 * time.Second}, settings["IstiodTerminationWait"])
+	})
+
+	t.Run("should enable the IstioOperator schema validation", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.validateOperatorSchema": true})
+
+		// then
+		require.Equal(t, []interface{}{true}, settings["IstioOperatorSchemaValidation"])
+	})
+
+	t.Run("should configure the uninstall grace period", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.uninstall.gracePeriod": "30s"})
+
+		// then
+		require.Equal(t, []interface{}{30 * time.Second}, settings["UninstallGracePeriod"])
+	})
+
+	t.Run("should configure the retention of the IstioOperator backups", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.istioOperatorBackup.retention": "2"})
+
+		// then
+		require.Equal(t, []interface{}{2}, settings["IstioOperatorBackup"])
+	})
+
+	t.Run("should configure the istioctl apply timeout", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.applyTimeout": "30s"})
+
+		// then
+		require.Equal(t, []interface{}{30 * time.Second}, settings["ApplyTimeout"])
+	})
+
+	t.Run("should enable the phased install and configure its waits", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.install.phased": true, "istio.install.phaseWaitTimeout": "2m", "istio.install.phaseWaitInterval": "3s"})
+
+		// then
+		require.Equal(t, []interface{}{true}, settings["PhasedInstall"])
+		require.Equal(t, []interface{}{2 * time.Minute, 3 * time.Second}, settings["PhaseWait"])
+	})
+
+	t.Run("should configure the retries and waits of the performer", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.performer.retries": "3"})
+
+		// then
+		require.Equal(t, []interface{}{actions.PerformerConfig{RetriesCount: 3}}, settings["PerformerConfig"])
+	})
+
+	t.Run("should configure the wait for the uninstall completion", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.uninstall.waitTimeout": "2m", "istio.uninstall.waitInterval": "3s"})
+
+		// then
+		require.Equal(t, []interface{}{2 * time.Minute, 3 * time.Second}, settings["UninstallWait"])
+	})
+
+	t.Run("should configure the wait for the istio-system namespace deletion", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.uninstall.namespaceDeletionTimeout": "2m"})
+
+		// then
+		require.Equal(t, []interface{}{2 * time.Minute, actions.DefaultInterval}, settings["NamespaceDeletionWait"])
+	})
+
+	t.Run("should configure the istioctl log output level", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.istioctl.logOutputLevel": "debug"})
+
+		// then
+		require.Equal(t, []interface{}{"debug"}, settings["IstioctlLogOutputLevel"])
+	})
+
+	t.Run("should enable the merged IstioOperator dump", func(t *testing.T) {
+		// when
+		settings := configure(map[string]interface{}{"istio.dumpMergedConfig": true})
+
+		// then
+		require.Equal(t, []interface{}{true}, settings["MergedConfigDump"])
+	})
+}
+
+func Test_configureWait(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	configureWaitOf := func(configuration map[string]interface{}) (bool, time.Duration, time.Duration) {
+		configured := false
+		var configuredTimeout, configuredInterval time.Duration
+		configureWait(&reconciler.Task{Configuration: configuration}, istiodTerminationTimeoutConfigKey, istiodTerminationIntervalConfigKey, logger,
+			func(timeout, interval time.Duration) *actions.DefaultIstioPerformer {
+				configured = true
+				configuredTimeout, configuredInterval = timeout, interval
+				return nil
+			})
+		return configured, configuredTimeout, configuredInterval
+	}
+
+	t.Run("should not configure the wait when neither timeout nor interval is configured", func(t *testing.T) {
+		// when
+		configured, _, _ := configureWaitOf(nil)
+
+		// then
+		require.False(t, configured)
+	})
+
+	t.Run("should configure the timeout and keep the default interval", func(t *testing.T) {
+		// when
+		configured, timeout, interval := configureWaitOf(map[string]interface{}{"istio.forceReinstall.istiodTerminationTimeout": "1m"})
+
+		// then
+		require.True(t, configured)
+		require.Equal(t, time.Minute, timeout)
+		require.Equal(t, actions.DefaultInterval, interval)
+	})
+
+	t.Run("should configure the interval and keep the default timeout", func(t *testing.T) {
+		// when
+		configured, timeout, interval := configureWaitOf(map[string]interface{}{"istio.forceReinstall.istiodTerminationInterval": "2s"})
+
+		// then
+		require.True(t, configured)
+		require.Equal(t, actions.DefaultTimeout, timeout)
+		require.Equal(t, 2*time.Second, interval)
+	})
+
+	t.Run("should ignore a zero interval", func(t *testing.T) {
+		// when
+		configured, _, _ := configureWaitOf(map[string]interface{}{"istio.forceReinstall.istiodTerminationInterval": "0s"})
+
+		// then
+		require.False(t, configured)
+	})
+}
+
+func Test_performerConfig(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should leave everything to the defaults when nothing is configured", func(t *testing.T) {
+		// when
+		config := performerConfig(&reconciler.Task{}, logger)
+
+		// then
+		require.Equal(t, actions.PerformerConfig{}, config)
+	})
+
+	t.Run("should return the configured retries and waits", func(t *testing.T) {
+		// given
+		task := &reconciler.Task{Configuration: map[string]interface{}{
+			"istio.performer.retries":        "3",
+			"istio.performer.retryDelay":     "1s",
+			"istio.performer.retryDelayType": "backoff",
+			"istio.performer.maxRetryDelay":  "10s",
+			"istio.performer.timeout":        "2m",
+			"istio.performer.interval":       "5s",
+		}}
+
+		// when
+		config := performerConfig(task, logger)
+
+		// then
+		require.Equal(t, actions.PerformerConfig{
+			RetriesCount:           3,
+			DelayBetweenRetries:    time.Second,
+			DelayType:              actions.RetryDelayBackOff,
+			MaxDelayBetweenRetries: 10 * time.Second,
+			Timeout:                2 * time.Minute,
+			Interval:               5 * time.Second,
+		}, config)
+	})
+
+	t.Run("should leave an invalid retry delay type to the default", func(t *testing.T) {
+		// when
+		config := performerConfig(&reconciler.Task{Configuration: map[string]interface{}{"istio.performer.retryDelayType": "random"}}, logger)
+
+		// then
+		require.Empty(t, config.DelayType)
+	})
+}
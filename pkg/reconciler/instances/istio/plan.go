@@ -0,0 +1,182 @@
+package istio
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"go.uber.org/zap"
+)
+
+type PlannedOutcome string
+
+const (
+	PlannedOutcomeRun  PlannedOutcome = "run"
+	PlannedOutcomeSkip PlannedOutcome = "skip"
+	PlannedOutcomeFail PlannedOutcome = "fail"
+)
+
+// PlannedAction describes a single step of the istio reconciliation together with its predicted outcome.
+type PlannedAction struct {
+	Action      string
+	Description string
+	Outcome     PlannedOutcome
+	Reason      string
+}
+
+// PlanActions returns the ordered list of actions which would be executed for the given task and the current istio status.
+// Nothing is executed on the cluster, the prediction relies on the same decisions as the actions themselves.
+func PlanActions(task *reconciler.Task, istioStatus actions.IstioStatus, logger *zap.SugaredLogger) []PlannedAction {
+	if task.Type == model.OperationTypeDelete {
+		return planDeleteActions(istioStatus)
+	}
+	return planReconcileActions(task, istioStatus, logger)
+}
+
+func planReconcileActions(task *reconciler.Task, istioStatus actions.IstioStatus, logger *zap.SugaredLogger) []PlannedAction {
+	var plan []PlannedAction
+
+	if !isClientCompatibleWithTargetVersion(istioStatus) {
+		plan = append(plan, PlannedAction{
+			Action:      "StatusPreAction",
+			Description: "Check istioctl compatibility with the target version",
+			Outcome:     PlannedOutcomeFail,
+			Reason:      fmt.Sprintf("binary version: %s is not compatible with the target version: %s", istioStatus.ClientVersion, istioStatus.TargetVersion),
+		})
+		return append(plan, notReached("MainReconcileAction", "ProxyResetPostAction")...)
+	}
+	plan = append(plan, PlannedAction{
+		Action:      "StatusPreAction",
+		Description: "Check istioctl compatibility with the target version",
+		Outcome:     PlannedOutcomeRun,
+	})
+
+	if boolConfig(task, labelNamespacesOnlyConfigKey, logger) {
+		return append(plan, PlannedAction{
+			Action:      "MainReconcileAction",
+			Description: "Label namespaces with istio-injection: enabled if sidecar migration is enabled, without deploying Istio",
+			Outcome:     PlannedOutcomeRun,
+		}, PlannedAction{
+			Action:      "ProxyResetPostAction",
+			Description: fmt.Sprintf("Reset Istio proxies to version %s", istioStatus.TargetVersion),
+			Outcome:     PlannedOutcomeSkip,
+			Reason:      "only the namespaces are labeled",
+		})
+	}
+
+	deployed := true
+	deployment := decideDeployment(task, istioStatus, logger)
+	switch deployment.actionKind {
+	case ActionKindInstall:
+		plan = append(plan, PlannedAction{
+			Action:      "MainReconcileAction",
+			Description: fmt.Sprintf("Install Istio in version %s", istioStatus.TargetVersion),
+			Outcome:     PlannedOutcomeRun,
+		})
+	case ActionKindSkip:
+		plan = append(plan, PlannedAction{
+			Action:      "MainReconcileAction",
+			Description: fmt.Sprintf("Update Istio to version %s", istioStatus.TargetVersion),
+			Outcome:     PlannedOutcomeSkip,
+			Reason:      fmt.Sprintf("Istio is already at target version %s", istioStatus.TargetVersion),
+		})
+	case ActionKindUpdate:
+		plannedUpdate := PlannedAction{
+			Action:      "MainReconcileAction",
+			Description: fmt.Sprintf("Update Istio pilot from %s and data plane from %s to version %s", istioStatus.PilotVersion, dataPlaneVersionsString(istioStatus, ","), istioStatus.TargetVersion),
+			Outcome:     PlannedOutcomeRun,
+		}
+		if deployment.reinstall {
+			plannedUpdate.Description = fmt.Sprintf("Uninstall Istio and install it again in version %s", istioStatus.TargetVersion)
+			plannedUpdate.Reason = "forced clean reinstall was requested"
+		} else if deployment.forced {
+			plannedUpdate.Reason = "forced reconcile was requested"
+		}
+		plan = append(plan, plannedUpdate)
+	default:
+		deployed = false
+		plan = append(plan, PlannedAction{
+			Action:      "MainReconcileAction",
+			Description: fmt.Sprintf("Update Istio to version %s", istioStatus.TargetVersion),
+			Outcome:     PlannedOutcomeFail,
+			Reason:      errorReason(deployment.err),
+		})
+	}
+
+	// LabelNamespaces is executed regardless of the deployment result
+	plan = append(plan, PlannedAction{
+		Action:      "LabelNamespaces",
+		Description: "Label namespaces with istio-injection: enabled if sidecar migration is enabled",
+		Outcome:     PlannedOutcomeRun,
+	})
+
+	if !deployed {
+		return append(plan, notReached("ProxyResetPostAction")...)
+	}
+
+	// After a successful deployment the pilot is expected to run in the target version
+	expectedStatus := istioStatus
+	expectedStatus.PilotVersion = istioStatus.TargetVersion
+	expectedStatus.PilotVersions = map[string]bool{istioStatus.TargetVersion: true}
+	return append(plan, planProxyReset(task, expectedStatus, logger))
+}
+
+// planProxyReset predicts the proxy reset for the status expected after the deployment. Like the ProxyResetPostAction a
+// reset which can not be performed only fails if failOnError is configured.
+func planProxyReset(task *reconciler.Task, istioStatus actions.IstioStatus, logger *zap.SugaredLogger) PlannedAction {
+	planned := PlannedAction{
+		Action:      "ProxyResetPostAction",
+		Description: fmt.Sprintf("Reset Istio proxies to version %s", istioStatus.TargetVersion),
+		Outcome:     PlannedOutcomeRun,
+	}
+
+	err := ensureProxyTargetCompatibleWithPilot(istioStatus, istioStatus.TargetVersion)
+	if err == nil {
+		err = ensureCanResetProxies(istioStatus)
+	}
+	if err != nil {
+		planned.Outcome = PlannedOutcomeSkip
+		if boolConfig(task, proxyResetFailOnErrorConfigKey, logger) {
+			planned.Outcome = PlannedOutcomeFail
+		}
+		planned.Reason = err.Error()
+	}
+	return planned
+}
+
+func planDeleteActions(istioStatus actions.IstioStatus) []PlannedAction {
+	if !canUninstall(istioStatus) {
+		return []PlannedAction{{
+			Action:      "UninstallAction",
+			Description: "Uninstall Istio and its related resources",
+			Outcome:     PlannedOutcomeSkip,
+			Reason:      "Istio is not installed",
+		}}
+	}
+
+	return []PlannedAction{{
+		Action:      "UninstallAction",
+		Description: fmt.Sprintf("Uninstall Istio in version %s and its related resources", istioStatus.PilotVersion),
+		Outcome:     PlannedOutcomeRun,
+	}}
+}
+
+func notReached(actionNames ...string) []PlannedAction {
+	var plan []PlannedAction
+	for _, actionName := range actionNames {
+		plan = append(plan, PlannedAction{
+			Action:  actionName,
+			Outcome: PlannedOutcomeSkip,
+			Reason:  "previous action fails",
+		})
+	}
+	return plan
+}
+
+func errorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,208 @@
+package istio
+
+import (
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/model"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PlanActions(t *testing.T) {
+
+	logger := log.NewLogger(true)
+	reconcileTask := &reconciler.Task{Type: model.OperationTypeReconcile}
+	deleteTask := &reconciler.Task{Type: model.OperationTypeDelete}
+
+	t.Run("should plan installation when istio is not on the cluster", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "",
+			DataPlaneVersions: map[string]bool{},
+		}
+
+		// when
+		plan := PlanActions(reconcileTask, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 4)
+		require.Equal(t, "StatusPreAction", plan[0].Action)
+		require.Equal(t, PlannedOutcomeRun, plan[0].Outcome)
+		require.Equal(t, "MainReconcileAction", plan[1].Action)
+		require.Equal(t, PlannedOutcomeRun, plan[1].Outcome)
+		require.Contains(t, plan[1].Description, "Install Istio in version 1.2.0")
+		require.Equal(t, "LabelNamespaces", plan[2].Action)
+		require.Equal(t, PlannedOutcomeRun, plan[2].Outcome)
+		require.Equal(t, "ProxyResetPostAction", plan[3].Action)
+		require.Equal(t, PlannedOutcomeRun, plan[3].Outcome)
+	})
+
+	t.Run("should plan update when istio is on the cluster in a compatible version", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		plan := PlanActions(reconcileTask, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 4)
+		require.Equal(t, PlannedOutcomeRun, plan[1].Outcome)
+		require.Contains(t, plan[1].Description, "Update Istio pilot from 1.1.0 and data plane from 1.1.0 to version 1.2.0")
+		require.Equal(t, PlannedOutcomeRun, plan[3].Outcome)
+	})
+
+	t.Run("should predict failing update and skip proxy reset when the versions differ by more than one minor", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.3.0",
+			TargetVersion:     "1.3.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		plan := PlanActions(reconcileTask, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 4)
+		require.Equal(t, PlannedOutcomeFail, plan[1].Outcome)
+		require.Contains(t, plan[1].Reason, "the difference between versions exceed one minor version")
+		require.Equal(t, PlannedOutcomeRun, plan[2].Outcome)
+		require.Equal(t, PlannedOutcomeSkip, plan[3].Outcome)
+	})
+
+	t.Run("should predict failing pre action when istioctl is not compatible with the target version", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.0.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		plan := PlanActions(reconcileTask, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 3)
+		require.Equal(t, PlannedOutcomeFail, plan[0].Outcome)
+		require.Equal(t, PlannedOutcomeSkip, plan[1].Outcome)
+		require.Equal(t, PlannedOutcomeSkip, plan[2].Outcome)
+	})
+
+	t.Run("should plan a clean reinstall when it is forced for istio at the target version", func(t *testing.T) {
+		// given
+		task := &reconciler.Task{
+			Type: model.OperationTypeReconcile,
+			Configuration: map[string]interface{}{
+				"istio.forceReinstall":       "true",
+				"istio.forceReinstall.clean": "true",
+			},
+		}
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		plan := PlanActions(task, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 4)
+		require.Equal(t, PlannedOutcomeRun, plan[1].Outcome)
+		require.Contains(t, plan[1].Description, "Uninstall Istio and install it again in version 1.2.0")
+		require.Equal(t, "forced clean reinstall was requested", plan[1].Reason)
+		require.Equal(t, PlannedOutcomeRun, plan[3].Outcome)
+	})
+
+	t.Run("should plan only the namespace labeling when it is configured", func(t *testing.T) {
+		// given
+		task := &reconciler.Task{
+			Type:          model.OperationTypeReconcile,
+			Configuration: map[string]interface{}{"istio.labelNamespacesOnly": "true"},
+		}
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		plan := PlanActions(task, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 3)
+		require.Equal(t, "MainReconcileAction", plan[1].Action)
+		require.Equal(t, PlannedOutcomeRun, plan[1].Outcome)
+		require.Contains(t, plan[1].Description, "without deploying Istio")
+		require.Equal(t, "ProxyResetPostAction", plan[2].Action)
+		require.Equal(t, PlannedOutcomeSkip, plan[2].Outcome)
+	})
+
+	t.Run("should plan uninstall for a delete task when istio is installed", func(t *testing.T) {
+		// given
+		istioStatus := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.2.0": true},
+		}
+
+		// when
+		plan := PlanActions(deleteTask, istioStatus, logger)
+
+		// then
+		require.Len(t, plan, 1)
+		require.Equal(t, "UninstallAction", plan[0].Action)
+		require.Equal(t, PlannedOutcomeRun, plan[0].Outcome)
+	})
+}
+
+func Test_planProxyReset(t *testing.T) {
+
+	logger := log.NewLogger(true)
+	istioStatus := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.1.0",
+		DataPlaneVersions: map[string]bool{"1.1.0": true},
+	}
+
+	t.Run("should predict skipped proxy reset when it can not be performed", func(t *testing.T) {
+		// given
+		task := &reconciler.Task{Type: model.OperationTypeReconcile}
+
+		// when
+		planned := planProxyReset(task, istioStatus, logger)
+
+		// then
+		require.Equal(t, PlannedOutcomeSkip, planned.Outcome)
+		require.NotEmpty(t, planned.Reason)
+	})
+
+	t.Run("should predict failing proxy reset when it can not be performed and failOnError is configured", func(t *testing.T) {
+		// given
+		task := &reconciler.Task{
+			Type:          model.OperationTypeReconcile,
+			Configuration: map[string]interface{}{"istio.proxyReset.failOnError": "true"},
+		}
+
+		// when
+		planned := planProxyReset(task, istioStatus, logger)
+
+		// then
+		require.Equal(t, PlannedOutcomeFail, planned.Outcome)
+		require.NotEmpty(t, planned.Reason)
+	})
+}
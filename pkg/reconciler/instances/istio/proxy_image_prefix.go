@@ -0,0 +1,29 @@
+package istio
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"go.uber.org/zap"
+)
+
+const proxyImagePrefixConfigKey = "istio.proxy.imagePrefix"
+
+// imagePrefixPattern matches a registry host with an optional port followed by optional repository path components, e.g. registry.local:5000/istio.
+var imagePrefixPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+
+// proxyImagePrefix returns the image prefix of the proxies to reset. An image prefix configured for the task takes precedence over the
+// target prefix of the Istio chart, so that images mirrored to a private registry can be used.
+func proxyImagePrefix(task *reconciler.Task, targetPrefix string, logger *zap.SugaredLogger) string {
+	value, ok := task.Configuration[proxyImagePrefixConfigKey]
+	if !ok || value == nil {
+		return targetPrefix
+	}
+	prefix := fmt.Sprint(value)
+	if !imagePrefixPattern.MatchString(prefix) {
+		logger.Warnf("Invalid %s value %s, using image prefix %s of the Istio chart", proxyImagePrefixConfigKey, prefix, targetPrefix)
+		return targetPrefix
+	}
+	return prefix
+}
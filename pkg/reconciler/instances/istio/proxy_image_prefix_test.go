@@ -0,0 +1,37 @@
+package istio
+
+import (
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_proxyImagePrefix(t *testing.T) {
+	logger := log.NewLogger(true)
+
+	t.Run("should return the target prefix when no override is configured", func(t *testing.T) {
+		// when
+		prefix := proxyImagePrefix(&reconciler.Task{}, "eu.gcr.io/kyma-project/external/istio", logger)
+
+		// then
+		require.Equal(t, "eu.gcr.io/kyma-project/external/istio", prefix)
+	})
+
+	t.Run("should return the configured override", func(t *testing.T) {
+		// when
+		prefix := proxyImagePrefix(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxy.imagePrefix": "registry.local:5000/mirror/istio"}}, "eu.gcr.io/kyma-project/external/istio", logger)
+
+		// then
+		require.Equal(t, "registry.local:5000/mirror/istio", prefix)
+	})
+
+	t.Run("should return the target prefix when the configured override is not a registry path", func(t *testing.T) {
+		// when
+		prefix := proxyImagePrefix(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxy.imagePrefix": "registry.local/istio:1.2.3"}}, "eu.gcr.io/kyma-project/external/istio", logger)
+
+		// then
+		require.Equal(t, "eu.gcr.io/kyma-project/external/istio", prefix)
+	})
+}
@@ -0,0 +1,31 @@
+package istio
+
+import (
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+)
+
+const (
+	proxyResetNamespaceConfigKey  = "istio.proxyReset.namespace"
+	proxyResetNamespacesConfigKey = "istio.proxyReset.namespaces"
+)
+
+// proxyResetNamespace returns the namespace the proxy reset is limited to. An empty namespace means all namespaces.
+func proxyResetNamespace(task *reconciler.Task) string {
+	value, ok := task.Configuration[proxyResetNamespaceConfigKey]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// proxyResetNamespaces returns the allowlist of namespaces the proxy reset is limited to, configured as a comma-separated list.
+// An empty allowlist means all namespaces.
+func proxyResetNamespaces(task *reconciler.Task) []string {
+	value, ok := task.Configuration[proxyResetNamespacesConfigKey]
+	if !ok || value == nil {
+		return nil
+	}
+	return commaSeparatedList(value)
+}
@@ -0,0 +1,46 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_proxyResetNamespace(t *testing.T) {
+
+	t.Run("should return empty namespace when it is not configured", func(t *testing.T) {
+		// when
+		namespace := proxyResetNamespace(&reconciler.Task{})
+
+		// then
+		require.Empty(t, namespace)
+	})
+
+	t.Run("should return configured namespace", func(t *testing.T) {
+		// when
+		namespace := proxyResetNamespace(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.namespace": "default"}})
+
+		// then
+		require.Equal(t, "default", namespace)
+	})
+}
+
+func Test_proxyResetNamespaces(t *testing.T) {
+
+	t.Run("should return no namespaces when they are not configured", func(t *testing.T) {
+		// when
+		namespaces := proxyResetNamespaces(&reconciler.Task{})
+
+		// then
+		require.Empty(t, namespaces)
+	})
+
+	t.Run("should return configured namespaces", func(t *testing.T) {
+		// when
+		namespaces := proxyResetNamespaces(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.namespaces": "default, team-a,,team-b "}})
+
+		// then
+		require.Equal(t, []string{"default", "team-a", "team-b"}, namespaces)
+	})
+}
@@ -0,0 +1,82 @@
+package istio
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"go.uber.org/zap"
+)
+
+const (
+	proxyResetImageComparisonConfigKey            = "istio.proxyReset.imageComparison"
+	proxyResetImageDigestConfigKey                = "istio.proxyReset.imageDigest"
+	proxyResetJobPodsHandlingConfigKey            = "istio.proxyReset.jobPodsHandling"
+	proxyResetNamespacePriorityConfigKey          = "istio.proxyReset.namespacePriority"
+	proxyResetUnlistedNamespacesPriorityConfigKey = "istio.proxyReset.unlistedNamespacesPriority"
+	proxyResetIncludeTerminatingPodsConfigKey     = "istio.proxyReset.includeTerminatingPods"
+)
+
+// proxyResetOptions returns the options of the proxy reset configured for the task.
+func proxyResetOptions(task *reconciler.Task, logger *zap.SugaredLogger) actions.ProxyResetOptions {
+	return actions.ProxyResetOptions{
+		ImageComparison:            proxyResetImageComparison(task, logger),
+		ImageDigest:                stringConfig(task, proxyResetImageDigestConfigKey),
+		JobPodsHandling:            proxyResetJobPodsHandling(task, logger),
+		NamespacePriority:          proxyResetNamespacePriority(task),
+		UnlistedNamespacesPriority: proxyResetUnlistedNamespacesPriority(task, logger),
+		IncludeTerminatingPods:     boolConfig(task, proxyResetIncludeTerminatingPodsConfigKey, logger),
+	}
+}
+
+// proxyResetImageComparison returns how proxy images are compared, by tag or by digest. A missing or invalid value compares tags.
+func proxyResetImageComparison(task *reconciler.Task, logger *zap.SugaredLogger) data.ImageComparison {
+	switch comparison := data.ImageComparison(stringConfig(task, proxyResetImageComparisonConfigKey)); comparison {
+	case "":
+		return data.ImageComparisonTag
+	case data.ImageComparisonTag, data.ImageComparisonDigest:
+		return comparison
+	default:
+		logger.Warnf("Invalid %s value %s, using default %s", proxyResetImageComparisonConfigKey, comparison, data.ImageComparisonTag)
+		return data.ImageComparisonTag
+	}
+}
+
+// proxyResetJobPodsHandling returns how pods owned by Jobs are handled. A missing or invalid value annotates them with a warning.
+func proxyResetJobPodsHandling(task *reconciler.Task, logger *zap.SugaredLogger) data.JobPodsHandling {
+	switch handling := data.JobPodsHandling(stringConfig(task, proxyResetJobPodsHandlingConfigKey)); handling {
+	case "":
+		return data.JobPodsHandlingAnnotate
+	case data.JobPodsHandlingAnnotate, data.JobPodsHandlingSkip, data.JobPodsHandlingRestart, data.JobPodsHandlingFail:
+		return handling
+	default:
+		logger.Warnf("Invalid %s value %s, using default %s", proxyResetJobPodsHandlingConfigKey, handling, data.JobPodsHandlingAnnotate)
+		return data.JobPodsHandlingAnnotate
+	}
+}
+
+// proxyResetNamespacePriority returns the namespaces to reset first, in this order, configured as a comma-separated list.
+func proxyResetNamespacePriority(task *reconciler.Task) []string {
+	value, ok := task.Configuration[proxyResetNamespacePriorityConfigKey]
+	if !ok || value == nil {
+		return nil
+	}
+	return commaSeparatedList(value)
+}
+
+// proxyResetUnlistedNamespacesPriority returns the position in the namespace priority at which the unlisted namespaces are reset.
+// A missing or invalid value resets them after all listed namespaces.
+func proxyResetUnlistedNamespacesPriority(task *reconciler.Task, logger *zap.SugaredLogger) *int {
+	value, ok := task.Configuration[proxyResetUnlistedNamespacesPriorityConfigKey]
+	if !ok || value == nil {
+		return nil
+	}
+	priority, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil || priority < 0 {
+		logger.Warnf("Invalid %s value %v, resetting unlisted namespaces after all listed ones", proxyResetUnlistedNamespacesPriorityConfigKey, value)
+		return nil
+	}
+	return &priority
+}
@@ -0,0 +1,89 @@
+package istio
+
+import (
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_proxyResetOptions(t *testing.T) {
+
+	logger := log.NewLogger(true)
+
+	t.Run("should return the defaults when nothing is configured", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{}, logger)
+
+		// then
+		require.Equal(t, actions.ProxyResetOptions{ImageComparison: data.ImageComparisonTag, JobPodsHandling: data.JobPodsHandlingAnnotate}, options)
+	})
+
+	t.Run("should return the configured image comparison and digest", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{
+			"istio.proxyReset.imageComparison": "digest",
+			"istio.proxyReset.imageDigest":     "sha256:abc",
+		}}, logger)
+
+		// then
+		require.Equal(t, data.ImageComparisonDigest, options.ImageComparison)
+		require.Equal(t, "sha256:abc", options.ImageDigest)
+	})
+
+	t.Run("should compare tags when the configured image comparison is invalid", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.imageComparison": "checksum"}}, logger)
+
+		// then
+		require.Equal(t, data.ImageComparisonTag, options.ImageComparison)
+	})
+
+	t.Run("should return the configured job pods handling", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.jobPodsHandling": "restart"}}, logger)
+
+		// then
+		require.Equal(t, data.JobPodsHandlingRestart, options.JobPodsHandling)
+	})
+
+	t.Run("should annotate job pods when the configured job pods handling is invalid", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.jobPodsHandling": "ignore"}}, logger)
+
+		// then
+		require.Equal(t, data.JobPodsHandlingAnnotate, options.JobPodsHandling)
+	})
+
+	t.Run("should return the configured namespace priority", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{
+			"istio.proxyReset.namespacePriority":          "kyma-system, team-a",
+			"istio.proxyReset.unlistedNamespacesPriority": 1,
+		}}, logger)
+
+		// then
+		require.Equal(t, []string{"kyma-system", "team-a"}, options.NamespacePriority)
+		require.NotNil(t, options.UnlistedNamespacesPriority)
+		require.Equal(t, 1, *options.UnlistedNamespacesPriority)
+	})
+
+	t.Run("should reset unlisted namespaces last when the configured priority is invalid", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.unlistedNamespacesPriority": "first"}}, logger)
+
+		// then
+		require.Nil(t, options.UnlistedNamespacesPriority)
+	})
+
+	t.Run("should include terminating pods when it is enabled", func(t *testing.T) {
+		// when
+		options := proxyResetOptions(&reconciler.Task{Configuration: map[string]interface{}{"istio.proxyReset.includeTerminatingPods": "true"}}, logger)
+
+		// then
+		require.True(t, options.IncludeTerminatingPods)
+	})
+}
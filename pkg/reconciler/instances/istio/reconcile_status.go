@@ -0,0 +1,73 @@
+package istio
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+)
+
+// ReconcileOutcome is the action MainReconcileAction took on the cluster.
+type ReconcileOutcome string
+
+const (
+	ReconcileOutcomeInstall ReconcileOutcome = "install"
+	ReconcileOutcomeUpdate  ReconcileOutcome = "update"
+	ReconcileOutcomeSkipped ReconcileOutcome = "skipped"
+	ReconcileOutcomeBlocked ReconcileOutcome = "blocked"
+)
+
+// ReconcileStatus describes the outcome of a MainReconcileAction run together with the versions it is based on.
+type ReconcileStatus struct {
+	Outcome                 ReconcileOutcome
+	SourcePilotVersions     []string
+	SourceDataPlaneVersions []string
+	TargetVersion           string
+	Timestamp               time.Time
+	// IngressGatewayRestartPending is set when the update skipped the required ingress gateway restart.
+	IngressGatewayRestartPending bool
+}
+
+func newReconcileStatus(outcome ReconcileOutcome, istioStatus actions.IstioStatus) *ReconcileStatus {
+	status := &ReconcileStatus{
+		Outcome:                 outcome,
+		SourcePilotVersions:     []string{},
+		SourceDataPlaneVersions: []string{},
+		TargetVersion:           istioStatus.TargetVersion,
+		Timestamp:               time.Now(),
+	}
+	for _, pilotVersion := range pilotVersions(istioStatus) {
+		if pilotVersion != "" {
+			status.SourcePilotVersions = append(status.SourcePilotVersions, pilotVersion)
+		}
+	}
+	for dpVersion := range istioStatus.DataPlaneVersions {
+		status.SourceDataPlaneVersions = append(status.SourceDataPlaneVersions, dpVersion)
+	}
+	sort.Strings(status.SourceDataPlaneVersions)
+	return status
+}
+
+// reconcileStatusRecorder retains the status of the latest action run.
+type reconcileStatusRecorder struct {
+	mu         sync.Mutex
+	lastStatus *ReconcileStatus
+}
+
+// LastStatus returns the status of the latest run, or nil before the first run.
+func (r *reconcileStatusRecorder) LastStatus() *ReconcileStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastStatus
+}
+
+// recordStatus retains the given status. Without a status, the run took no action and is recorded as skipped.
+func (r *reconcileStatusRecorder) recordStatus(status *ReconcileStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if status == nil {
+		status = &ReconcileStatus{Outcome: ReconcileOutcomeSkipped, SourcePilotVersions: []string{}, SourceDataPlaneVersions: []string{}, Timestamp: time.Now()}
+	}
+	r.lastStatus = status
+}
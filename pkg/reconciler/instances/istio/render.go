@@ -0,0 +1,60 @@
+package istio
+
+import (
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"go.uber.org/zap"
+)
+
+const (
+	renderManifestAttempts = 3
+)
+
+// renderManifestRetryDelay is the initial delay between render attempts, it is doubled after each failed attempt.
+var renderManifestRetryDelay = 2 * time.Second
+
+// renderIstioManifest renders the Istio chart of the task. Rendering is retried with an exponential backoff, as it can fail
+// transiently while the workspace is cloned concurrently. Errors of the chart templates are permanent and returned immediately.
+func renderIstioManifest(context *service.ActionContext) (*chart.Manifest, error) {
+	component := chart.NewComponentBuilder(context.Task.Version, context.Task.Component).
+		WithNamespace(istioSystemNamespace(context.Task)).
+		WithProfile(context.Task.Profile).
+		WithConfiguration(context.Task.Configuration).Build()
+
+	var istioManifest *chart.Manifest
+	err := retry.Do(func() error {
+		var err error
+		istioManifest, err = context.ChartProvider.RenderManifest(component)
+		return err
+	}, renderManifestRetryOptions(context.Logger)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return istioManifest, nil
+}
+
+func renderManifestRetryOptions(logger *zap.SugaredLogger) []retry.Option {
+	return []retry.Option{
+		retry.Delay(renderManifestRetryDelay),
+		retry.Attempts(renderManifestAttempts),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(func(err error) bool {
+			return !isPermanentRenderError(err)
+		}),
+		retry.OnRetry(func(n uint, err error) {
+			logger.Warnf("Rendering of the Istio manifest failed in attempt %d: %v", n+1, err)
+		}),
+	}
+}
+
+// isPermanentRenderError checks if the error was raised by the chart templates, which a retry does not resolve.
+func isPermanentRenderError(err error) bool {
+	message := err.Error()
+	return strings.Contains(message, "Failed to render HELM template") || strings.Contains(message, "parse error")
+}
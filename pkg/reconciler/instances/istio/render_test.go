@@ -0,0 +1,65 @@
+package istio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	chartmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renderIstioManifest(t *testing.T) {
+	retryDelay := renderManifestRetryDelay
+	renderManifestRetryDelay = time.Millisecond
+	defer func() { renderManifestRetryDelay = retryDelay }()
+
+	t.Run("should retry rendering when the provider fails transiently", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(nil, errors.New("loader failed to load helm chart")).Once()
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil).Once()
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+
+		// when
+		result, err := renderIstioManifest(actionContext)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, istioManifest, result.Manifest)
+		provider.AssertNumberOfCalls(t, "RenderManifest", 2)
+	})
+
+	t.Run("should return the last error when all render attempts fail", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(nil, errors.New("loader failed to load helm chart"))
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+
+		// when
+		result, err := renderIstioManifest(actionContext)
+
+		// then
+		require.Nil(t, result)
+		require.EqualError(t, err, "loader failed to load helm chart")
+		provider.AssertNumberOfCalls(t, "RenderManifest", renderManifestAttempts)
+	})
+
+	t.Run("should not retry rendering when the chart templates are invalid", func(t *testing.T) {
+		// given
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(nil, errors.New("Failed to render HELM template for component 'istio': parse error"))
+		actionContext := newFakeServiceContext(&chartmocks.Factory{}, &provider, newFakeKubeClient())
+
+		// when
+		result, err := renderIstioManifest(actionContext)
+
+		// then
+		require.Nil(t, result)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "parse error")
+		provider.AssertNumberOfCalls(t, "RenderManifest", 1)
+	})
+}
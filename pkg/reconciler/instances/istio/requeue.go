@@ -0,0 +1,68 @@
+package istio
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"go.uber.org/zap"
+)
+
+const (
+	requeueAfterRemediationConfigKey = "istio.requeueAfterRemediation"
+	defaultRequeueAfterRemediation   = 5 * time.Minute
+)
+
+// RetryAfterRemediationError is returned when the update is blocked by a condition which can be remediated by resetting
+// the Istio proxies to the pilot version. Instead of treating it as a dead end, the proxies should be reset and the
+// reconciliation retried after RequeueAfter.
+type RetryAfterRemediationError struct {
+	Cause        error
+	Remediation  string
+	RequeueAfter time.Duration
+}
+
+func (e *RetryAfterRemediationError) Error() string {
+	return fmt.Sprintf("%s - %s, retry after %s", e.Cause.Error(), e.Remediation, e.RequeueAfter)
+}
+
+func (e *RetryAfterRemediationError) Unwrap() error {
+	return e.Cause
+}
+
+func newProxyResetRemediationError(cause error, pilotVersion string) *RetryAfterRemediationError {
+	return &RetryAfterRemediationError{
+		Cause:        cause,
+		Remediation:  fmt.Sprintf("reset Istio proxies to pilot version %s", pilotVersion),
+		RequeueAfter: defaultRequeueAfterRemediation,
+	}
+}
+
+// canBeRemediatedByProxyReset checks whether resetting the proxies to the pilot version makes every data plane version
+// compatible with the target version. The pilot version itself is expected to be compatible with the target version.
+func canBeRemediatedByProxyReset(istioStatus actions.IstioStatus) bool {
+	if istioStatus.PilotVersion == "" {
+		return false
+	}
+	for dpVersion := range istioStatus.DataPlaneVersions {
+		if isCompatible, _ := isComponentCompatible(dpVersion, istioStatus.PilotVersion, "Data plane"); !isCompatible {
+			return false
+		}
+	}
+	return true
+}
+
+// requeueAfterRemediation returns the requeue interval configured for the task, falling back to the default one.
+func requeueAfterRemediation(task *reconciler.Task, logger *zap.SugaredLogger) time.Duration {
+	value, ok := task.Configuration[requeueAfterRemediationConfigKey]
+	if !ok {
+		return defaultRequeueAfterRemediation
+	}
+	requeueAfter, err := time.ParseDuration(fmt.Sprint(value))
+	if err != nil || requeueAfter <= 0 {
+		logger.Warnf("Invalid %s value %v, using default %s", requeueAfterRemediationConfigKey, value, defaultRequeueAfterRemediation)
+		return defaultRequeueAfterRemediation
+	}
+	return requeueAfter
+}
@@ -0,0 +1,123 @@
+package istio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/chart"
+	chartmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/chart/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	actionsmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_canUpdate_RequeueHint(t *testing.T) {
+
+	t.Run("should return requeue hint when data plane skew can be remediated by a proxy reset", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.2.0",
+			TargetVersion:     "1.2.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true, "1.1.0": true},
+		}
+
+		// when
+		result, err := canUpdate(version)
+
+		// then
+		require.False(t, result)
+		var remediationErr *RetryAfterRemediationError
+		require.True(t, errors.As(err, &remediationErr))
+		require.Equal(t, defaultRequeueAfterRemediation, remediationErr.RequeueAfter)
+		require.Equal(t, "reset Istio proxies to pilot version 1.1.0", remediationErr.Remediation)
+		require.Contains(t, err.Error(), "Could not perform upgrade for Data plane from version: 1.0.0 to version: 1.2.0")
+	})
+
+	t.Run("should not return requeue hint when data plane skew can not be remediated by a proxy reset", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.3.0",
+			TargetVersion:     "1.3.0",
+			PilotVersion:      "1.2.0",
+			DataPlaneVersions: map[string]bool{"1.0.0": true},
+		}
+
+		// when
+		result, err := canUpdate(version)
+
+		// then
+		require.False(t, result)
+		var remediationErr *RetryAfterRemediationError
+		require.False(t, errors.As(err, &remediationErr))
+	})
+
+	t.Run("should not return requeue hint when pilot is not compatible with the target version", func(t *testing.T) {
+		// given
+		version := actions.IstioStatus{
+			ClientVersion:     "1.3.0",
+			TargetVersion:     "1.3.0",
+			PilotVersion:      "1.1.0",
+			DataPlaneVersions: map[string]bool{"1.1.0": true},
+		}
+
+		// when
+		result, err := canUpdate(version)
+
+		// then
+		require.False(t, result)
+		var remediationErr *RetryAfterRemediationError
+		require.False(t, errors.As(err, &remediationErr))
+	})
+}
+
+func Test_MainReconcileAction_RequeueHint(t *testing.T) {
+
+	blockedByDataPlane := actions.IstioStatus{
+		ClientVersion:     "1.2.0",
+		TargetVersion:     "1.2.0",
+		PilotVersion:      "1.1.0",
+		DataPlaneVersions: map[string]bool{"1.0.0": true},
+	}
+
+	t.Run("should use requeue interval configured for the task", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.requeueAfterRemediation": "30s"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(blockedByDataPlane, nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		var remediationErr *RetryAfterRemediationError
+		require.True(t, errors.As(err, &remediationErr))
+		require.Equal(t, 30*time.Second, remediationErr.RequeueAfter)
+		performer.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("bool"), mock.Anything)
+	})
+
+	t.Run("should use default requeue interval when the configured one is invalid", func(t *testing.T) {
+		// given
+		factory := chartmocks.Factory{}
+		provider := chartmocks.Provider{}
+		provider.On("RenderManifest", mock.AnythingOfType("*chart.Component")).Return(&chart.Manifest{Manifest: istioManifest}, nil)
+		actionContext := newFakeServiceContext(&factory, &provider, newFakeKubeClient())
+		actionContext.Task.Configuration = map[string]interface{}{"istio.requeueAfterRemediation": "soon"}
+		performer := actionsmocks.IstioPerformer{}
+		performer.On("Version", mock.Anything, mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("*zap.SugaredLogger")).Return(blockedByDataPlane, nil)
+
+		// when
+		err := deployIstio(actionContext, &performer, newActionObservation(noopActionMetrics{}, reconcileActionName))
+
+		// then
+		var remediationErr *RetryAfterRemediationError
+		require.True(t, errors.As(err, &remediationErr))
+		require.Equal(t, defaultRequeueAfterRemediation, remediationErr.RequeueAfter)
+	})
+}
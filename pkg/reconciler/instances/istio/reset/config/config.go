@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
 )
@@ -22,6 +23,12 @@ type IstioProxyConfig struct {
 	// ImageVersion of Istio
 	ImageVersion string
 
+	// ImageDigest of the Istio proxy image, used when ImageComparison is set to digest
+	ImageDigest string
+
+	// ImageComparison defines if proxy images are compared by tag or by digest, defaults to tag
+	ImageComparison data.ImageComparison
+
 	// RetriesCount after an unsuccessful attempt
 	RetriesCount int
 
@@ -48,4 +55,26 @@ type IstioProxyConfig struct {
 
 	// Is CNI enabled on the cluster
 	CNIEnabled bool
+
+	// JobPodsHandling defines how pods owned by Jobs are handled, defaults to annotating them with a warning
+	JobPodsHandling data.JobPodsHandling
+
+	// MaxConcurrentNamespaces limits how many namespaces are reset in parallel, defaults to sequential processing
+	MaxConcurrentNamespaces int
+
+	// NamespacePriority orders the reset of namespaces by their position in the list, defaults to alphabetical order
+	NamespacePriority []string
+
+	// UnlistedNamespacesPriority is the position in NamespacePriority at which namespaces not listed there are reset, defaults to
+	// after all listed namespaces
+	UnlistedNamespacesPriority *int
+
+	// IncludeTerminatingPods resets also pods which are already being deleted, defaults to skipping them
+	IncludeTerminatingPods bool
+
+	// Namespace limits the reset to pods in this namespace, defaults to all namespaces
+	Namespace string
+
+	// Namespaces limits the reset to pods in the listed namespaces, defaults to all namespaces
+	Namespaces []string
 }
@@ -27,14 +27,22 @@ type Gatherer interface {
 	// GetIstioCPPods from the cluster and return them as a v1.PodList.
 	GetIstioCPPods(kubeClient kubernetes.Interface, retryOpts []retry.Option) (podsList *v1.PodList, err error)
 
-	// GetPodsWithDifferentImage than the passed expected image to filter them out from the pods list.
-	GetPodsWithDifferentImage(inputPodsList v1.PodList, image ExpectedImage) (outputPodsList v1.PodList)
+	// GetAllPodsWithDifferentImage from the cluster than the passed expected image. Pods are listed in pages and filtered page by page,
+	// so the complete list of pods is never held in memory. Terminating pods are only returned with includeTerminatingPods.
+	GetAllPodsWithDifferentImage(kubeClient kubernetes.Interface, retryOpts []retry.Option, image ExpectedImage, includeTerminatingPods bool) (podsList v1.PodList, err error)
 
-	// GetPodsWithoutSidecar return a list of pods which should have a sidecar injected but do not have it.
-	GetPodsWithoutSidecar(kubeClient kubernetes.Interface, retryOpts []retry.Option, sidecarInjectionEnabledbyDefault bool) (podsList v1.PodList, err error)
+	// GetPodsWithDifferentImage than the passed expected image to filter them out from the pods list. Pods which opted out of
+	// sidecar injection with the sidecar.istio.io/inject label or annotation set to "false" are left out. Terminating pods are only
+	// returned with includeTerminatingPods.
+	GetPodsWithDifferentImage(inputPodsList v1.PodList, image ExpectedImage, includeTerminatingPods bool) (outputPodsList v1.PodList)
 
-	// GetPodsForCNIChange return a list of pods which have a istio-init container.
-	GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOpts []retry.Option, cniEnabled bool) (podsList v1.PodList, err error)
+	// GetPodsWithoutSidecar return a list of pods which should have a sidecar injected but do not have it. Pods which opted out of
+	// sidecar injection with the sidecar.istio.io/inject label or annotation set to "false" are left out. Terminating pods are only
+	// returned with includeTerminatingPods.
+	GetPodsWithoutSidecar(kubeClient kubernetes.Interface, retryOpts []retry.Option, sidecarInjectionEnabledbyDefault bool, includeTerminatingPods bool) (podsList v1.PodList, err error)
+
+	// GetPodsForCNIChange return a list of pods which have a istio-init container. Terminating pods are only returned with includeTerminatingPods.
+	GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOpts []retry.Option, cniEnabled bool, includeTerminatingPods bool) (podsList v1.PodList, err error)
 
 	// GetInstalledIstioVersion verifies and returns installed Istio.
 	GetInstalledIstioVersion(kubeClient kubernetes.Interface, retryOpts []retry.Option, logger *zap.SugaredLogger) (string, error)
@@ -43,16 +51,53 @@ type Gatherer interface {
 // DefaultGatherer that gets pods from the Kubernetes cluster
 type DefaultGatherer struct{}
 
+// ImageComparison defines how the istio proxy image of a pod is compared with the ExpectedImage.
+type ImageComparison string
+
+const (
+	// ImageComparisonTag compares the prefix and the tag of the container image.
+	ImageComparisonTag ImageComparison = "tag"
+
+	// ImageComparisonDigest compares the digest of the image the container runs with the expected digest.
+	ImageComparisonDigest ImageComparison = "digest"
+)
+
 // ExpectedImage to be verified by the proxy.
 type ExpectedImage struct {
 	Prefix  string
 	Version string
+
+	// Digest of the expected image, only used with ImageComparisonDigest.
+	Digest string
+
+	// Comparison mode of the image, defaults to ImageComparisonTag.
+	Comparison ImageComparison
 }
 
+// JobPodsHandling defines how pods owned by Jobs are handled during the proxy reset.
+type JobPodsHandling string
+
+const (
+	// JobPodsHandlingAnnotate annotates pods owned by Jobs with a warning instead of resetting them.
+	JobPodsHandlingAnnotate JobPodsHandling = "annotate"
+
+	// JobPodsHandlingSkip leaves pods owned by Jobs untouched.
+	JobPodsHandlingSkip JobPodsHandling = "skip"
+
+	// JobPodsHandlingRestart deletes pods owned by Jobs, so they are recreated by the Job controller.
+	JobPodsHandlingRestart JobPodsHandling = "restart"
+
+	// JobPodsHandlingFail fails the proxy reset if any pod owned by a Job requires a reset.
+	JobPodsHandlingFail JobPodsHandling = "fail"
+)
+
 const (
 	istioValidationContainerName = "istio-validation"
 	istioInitContainerName       = "istio-init"
 	istioSidecarName             = "istio-proxy"
+
+	// podsListPageSize is the maximum number of pods requested from the API server at once.
+	podsListPageSize int64 = 500
 )
 
 // NewDefaultGatherer creates a new instance of DefaultGatherer.
@@ -61,17 +106,24 @@ func NewDefaultGatherer() *DefaultGatherer {
 }
 
 func (i *DefaultGatherer) GetAllPods(kubeClient kubernetes.Interface, retryOpts []retry.Option) (podsList *v1.PodList, err error) {
-	err = retry.Do(func() error {
-		podsList, err = kubeClient.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
-		if err != nil {
-			return err
-		}
+	podsList = &v1.PodList{}
+	err = listPodsInPages(kubeClient, "", retryOpts, func(page v1.PodList) {
+		podsList.Items = append(podsList.Items, page.Items...)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
-	}, retryOpts...)
+	return
+}
 
+func (i *DefaultGatherer) GetAllPodsWithDifferentImage(kubeClient kubernetes.Interface, retryOpts []retry.Option, image ExpectedImage, includeTerminatingPods bool) (podsList v1.PodList, err error) {
+	podsList.Items = []v1.Pod{}
+	err = listPodsInPages(kubeClient, "", retryOpts, func(page v1.PodList) {
+		podsList.Items = append(podsList.Items, i.GetPodsWithDifferentImage(page, image, includeTerminatingPods).Items...)
+	})
 	if err != nil {
-		return nil, err
+		return v1.PodList{}, err
 	}
 
 	return
@@ -94,12 +146,15 @@ func (i *DefaultGatherer) GetIstioCPPods(kubeClient kubernetes.Interface, retryO
 	return
 }
 
-func (i *DefaultGatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, image ExpectedImage) (outputPodsList v1.PodList) {
+func (i *DefaultGatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, image ExpectedImage, includeTerminatingPods bool) (outputPodsList v1.PodList) {
 	inputPodsList.DeepCopyInto(&outputPodsList)
 	outputPodsList.Items = []v1.Pod{}
 
 	for _, pod := range inputPodsList.Items {
-		if _, containsIstioSidecarAnnotation := pod.Annotations["sidecar.istio.io/status"]; !containsIstioSidecarAnnotation || !isPodReady(pod) {
+		if _, containsIstioSidecarAnnotation := pod.Annotations["sidecar.istio.io/status"]; !containsIstioSidecarAnnotation || !isPodReady(pod, includeTerminatingPods) {
+			continue
+		}
+		if isSidecarInjectionOptedOut(pod) {
 			continue
 		}
 
@@ -109,9 +164,7 @@ func (i *DefaultGatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, im
 			if !isIstioSidecar(istioSidecarNames, container.Name) {
 				continue
 			}
-			containsPrefix := strings.Contains(container.Image, image.Prefix)
-			hasSuffix := strings.HasSuffix(container.Image, image.Version)
-			if !hasSuffix || !containsPrefix {
+			if hasDifferentImage(container, pod.Status.ContainerStatuses, image) {
 				outputPodsList.Items = append(outputPodsList.Items, *pod.DeepCopy())
 			}
 		}
@@ -120,24 +173,21 @@ func (i *DefaultGatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, im
 	return
 }
 
-func (i *DefaultGatherer) GetPodsWithoutSidecar(kubeClient kubernetes.Interface, retryOpts []retry.Option, sidecarInjectionEnabledbyDefault bool) (podsList v1.PodList, err error) {
-	allPodsWithNamespaceAnnotations, err := getAllPodsWithNamespaceAnnotations(kubeClient, retryOpts)
+func (i *DefaultGatherer) GetPodsWithoutSidecar(kubeClient kubernetes.Interface, retryOpts []retry.Option, sidecarInjectionEnabledbyDefault bool, includeTerminatingPods bool) (podsList v1.PodList, err error) {
+	podsList.Items = []v1.Pod{}
+	err = listPodsWithNamespaceAnnotationsInPages(kubeClient, retryOpts, func(page v1.PodList) {
+		// filter pods
+		podsWithSidecarRequired, _ := getPodsWithAnnotation(page, sidecarInjectionEnabledbyDefault)
+		podsList.Items = append(podsList.Items, getPodsWithoutSidecar(podsWithSidecarRequired, includeTerminatingPods).Items...)
+	})
 	if err != nil {
-		return
+		return v1.PodList{}, err
 	}
 
-	// filter pods
-	podsList, _ = getPodsWithAnnotation(allPodsWithNamespaceAnnotations, sidecarInjectionEnabledbyDefault)
-	podsList = getPodsWithoutSidecar(podsList)
 	return
 }
 
-func (i *DefaultGatherer) GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOpts []retry.Option, cniEnabled bool) (podsList v1.PodList, err error) {
-	allPodsWithNamespaceAnnotations, err := getAllPodsWithNamespaceAnnotations(kubeClient, retryOpts)
-	if err != nil {
-		return
-	}
-
+func (i *DefaultGatherer) GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOpts []retry.Option, cniEnabled bool, includeTerminatingPods bool) (podsList v1.PodList, err error) {
 	// We depend on the cni state and init container name, because of the limitations of the applied state between main action and post action.
 	var containerName string
 	switch cniEnabled {
@@ -147,8 +197,14 @@ func (i *DefaultGatherer) GetPodsForCNIChange(kubeClient kubernetes.Interface, r
 		containerName = istioValidationContainerName
 	}
 
-	// filter pods
-	podsList = getPodsForCNIChange(allPodsWithNamespaceAnnotations, containerName)
+	podsList.Items = []v1.Pod{}
+	err = listPodsWithNamespaceAnnotationsInPages(kubeClient, retryOpts, func(page v1.PodList) {
+		// filter pods
+		podsList.Items = append(podsList.Items, getPodsForCNIChange(page, containerName, includeTerminatingPods).Items...)
+	})
+	if err != nil {
+		return v1.PodList{}, err
+	}
 
 	return
 }
@@ -204,12 +260,12 @@ func getPodsWithAnnotation(inputPodsList v1.PodList, sidecarInjectionEnabledbyDe
 	return
 }
 
-func getPodsWithoutSidecar(inputPodsList v1.PodList) (outputPodsList v1.PodList) {
+func getPodsWithoutSidecar(inputPodsList v1.PodList, includeTerminatingPods bool) (outputPodsList v1.PodList) {
 	inputPodsList.DeepCopyInto(&outputPodsList)
 	outputPodsList.Items = []v1.Pod{}
 
 	for _, pod := range inputPodsList.Items {
-		if !isPodReady(pod) {
+		if !isPodReady(pod, includeTerminatingPods) {
 			continue
 		}
 
@@ -221,12 +277,12 @@ func getPodsWithoutSidecar(inputPodsList v1.PodList) (outputPodsList v1.PodList)
 	return
 }
 
-func getPodsForCNIChange(inputPodsList v1.PodList, containerName string) (outputPodsList v1.PodList) {
+func getPodsForCNIChange(inputPodsList v1.PodList, containerName string, includeTerminatingPods bool) (outputPodsList v1.PodList) {
 	inputPodsList.DeepCopyInto(&outputPodsList)
 	outputPodsList.Items = []v1.Pod{}
 
 	for _, pod := range inputPodsList.Items {
-		if !isPodReady(pod) {
+		if !isPodReady(pod, includeTerminatingPods) {
 			continue
 		}
 
@@ -258,10 +314,19 @@ func hasIstioInitContainer(containers []v1.Container, initContainerName string)
 	return proxyImage != ""
 }
 
+// isSidecarInjectionOptedOut checks if the pod opted out of sidecar injection. The sidecar.istio.io/inject label takes
+// precedence over the annotation of the same name, as it does for the istio sidecar injector.
+func isSidecarInjectionOptedOut(pod v1.Pod) bool {
+	if podLabelValue, podLabeled := pod.Labels["sidecar.istio.io/inject"]; podLabeled {
+		return podLabelValue == "false"
+	}
+	return pod.Annotations["sidecar.istio.io/inject"] == "false"
+}
+
 func checkPodSidecarInjectionLogic(pod v1.Pod, sidecarInjectionEnabledbyDefault bool) (requireSidecar bool) {
 	namespaceLabelValue, namespaceLabeled := pod.Annotations["reconciler/namespace-istio-injection"]
 	podAnnotationValue, podAnnotated := pod.Annotations["sidecar.istio.io/inject"]
-	podLabelValue, podLabeled := pod.Labels["sidecar.istio.io/inject"]
+	_, podLabeled := pod.Labels["sidecar.istio.io/inject"]
 
 	//Automatic sidecar injection is ignored for pods on the host network
 	if pod.Spec.HostNetwork {
@@ -272,11 +337,7 @@ func checkPodSidecarInjectionLogic(pod v1.Pod, sidecarInjectionEnabledbyDefault
 		return false
 	}
 
-	if podLabeled && podLabelValue == "false" {
-		return false
-	}
-
-	if !podLabeled && podAnnotated && podAnnotationValue == "false" {
+	if isSidecarInjectionOptedOut(pod) {
 		return false
 	}
 
@@ -291,9 +352,12 @@ func checkPodSidecarInjectionLogic(pod v1.Pod, sidecarInjectionEnabledbyDefault
 	return true
 }
 
-func getAllPodsWithNamespaceAnnotations(kubeClient kubernetes.Interface, retryOpts []retry.Option) (podsList v1.PodList, err error) {
+// listPodsWithNamespaceAnnotationsInPages lists the pods of all namespaces except system namespaces in pages and passes each page to handlePage.
+// The istio-injection label of the namespace is added to each pod as annotation.
+func listPodsWithNamespaceAnnotationsInPages(kubeClient kubernetes.Interface, retryOpts []retry.Option, handlePage func(page v1.PodList)) error {
 	var namespaces *v1.NamespaceList
-	err = retry.Do(func() error {
+	err := retry.Do(func() error {
+		var err error
 		namespaces, err = kubeClient.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
 		if err != nil {
 			return err
@@ -301,44 +365,65 @@ func getAllPodsWithNamespaceAnnotations(kubeClient kubernetes.Interface, retryOp
 		return nil
 	}, retryOpts...)
 	if err != nil {
-		return podsList, err
+		return err
 	}
 
-	err = retry.Do(func() error {
-		for _, namespace := range namespaces.Items {
-			if namespace.ObjectMeta.Name == "kube-system" {
-				continue
-			}
-			if namespace.ObjectMeta.Name == "kube-public" {
-				continue
-			}
-			if namespace.ObjectMeta.Name == "istio-system" {
-				continue
+	for _, namespace := range namespaces.Items {
+		if namespace.ObjectMeta.Name == "kube-system" {
+			continue
+		}
+		if namespace.ObjectMeta.Name == "kube-public" {
+			continue
+		}
+		if namespace.ObjectMeta.Name == "istio-system" {
+			continue
+		}
+
+		istioInjection, isNamespaceLabeled := namespace.Labels["istio-injection"]
+		err = listPodsInPages(kubeClient, namespace.Name, retryOpts, func(page v1.PodList) {
+			if isNamespaceLabeled {
+				for i := range page.Items {
+					if page.Items[i].Annotations == nil {
+						page.Items[i].Annotations = make(map[string]string)
+					}
+					page.Items[i].Annotations["reconciler/namespace-istio-injection"] = istioInjection
+				}
 			}
+			handlePage(page)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-			pods, err := kubeClient.CoreV1().Pods(namespace.Name).List(context.Background(), metav1.ListOptions{})
+// listPodsInPages lists the pods of the namespace using Limit and Continue, and passes each page to handlePage,
+// so the complete list of pods is never held in memory. Each page request is retried with retryOpts.
+func listPodsInPages(kubeClient kubernetes.Interface, namespace string, retryOpts []retry.Option, handlePage func(page v1.PodList)) error {
+	continueToken := ""
+	for {
+		var page *v1.PodList
+		err := retry.Do(func() error {
+			var err error
+			page, err = kubeClient.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{Limit: podsListPageSize, Continue: continueToken})
 			if err != nil {
 				return err
 			}
-
-			for _, pod := range pods.Items {
-				if _, isNamespaceLabeled := namespace.Labels["istio-injection"]; isNamespaceLabeled {
-					if pod.Annotations == nil {
-						pod.Annotations = make(map[string]string)
-					}
-					pod.Annotations["reconciler/namespace-istio-injection"] = namespace.Labels["istio-injection"]
-				}
-				podsList.Items = append(podsList.Items, pod)
-			}
+			return nil
+		}, retryOpts...)
+		if err != nil {
+			return err
 		}
 
-		return nil
-	}, retryOpts...)
-	if err != nil {
-		return podsList, err
-	}
+		handlePage(*page)
 
-	return
+		continueToken = page.Continue
+		if continueToken == "" {
+			return nil
+		}
+	}
 }
 
 // getIstioSidecarNamesFromAnnotations gets all container names in pod annoted with podAnnotations that are Istio sidecars
@@ -364,8 +449,39 @@ func isIstioSidecar(istioSidecarNames []string, containerName string) bool {
 	return false
 }
 
-// isPodReady checks if the pod is Ready, returns true if the Pod is in the Running state and not Pending or Terminating.
-func isPodReady(pod v1.Pod) bool {
+// hasDifferentImage checks whether the container runs a different image than the expected one.
+// With ImageComparisonDigest the digest reported in the container status is used, if it is not known the tag is compared instead.
+func hasDifferentImage(container v1.Container, containerStatuses []v1.ContainerStatus, image ExpectedImage) bool {
+	if image.Comparison == ImageComparisonDigest && image.Digest != "" {
+		for _, status := range containerStatuses {
+			if status.Name != container.Name {
+				continue
+			}
+			if digest := getImageDigest(status.ImageID); digest != "" {
+				return digest != image.Digest
+			}
+		}
+	}
+
+	containsPrefix := strings.Contains(container.Image, image.Prefix)
+	hasSuffix := strings.HasSuffix(container.Image, image.Version)
+	return !hasSuffix || !containsPrefix
+}
+
+// getImageDigest returns the digest part of the image ID reported by the container runtime, e.g. docker-pullable://istio/proxyv2@sha256:abc
+func getImageDigest(imageID string) string {
+	if index := strings.LastIndex(imageID, "@"); index != -1 {
+		return imageID[index+1:]
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return imageID
+	}
+	return ""
+}
+
+// isPodReady checks if the pod is Ready, returns true if the Pod is in the Running state and not Pending. A Terminating pod is
+// only ready with includeTerminatingPods.
+func isPodReady(pod v1.Pod, includeTerminatingPods bool) bool {
 
 	if pod.Status.Phase != v1.PodRunning {
 		return false
@@ -376,7 +492,7 @@ func isPodReady(pod v1.Pod) bool {
 		}
 	}
 
-	return pod.ObjectMeta.DeletionTimestamp == nil
+	return includeTerminatingPods || pod.ObjectMeta.DeletionTimestamp == nil
 }
 
 // RemoveAnnotatedPods removes pods with annotation annotationKey from in podList
@@ -391,6 +507,84 @@ func RemoveAnnotatedPods(in v1.PodList, annotationKey string) (out v1.PodList) {
 	return
 }
 
+// KeepAnnotatedPods keeps only pods with annotation annotationKey from in podList
+func KeepAnnotatedPods(in v1.PodList, annotationKey string) (out v1.PodList) {
+	in.DeepCopyInto(&out)
+	out.Items = []v1.Pod{}
+	for i := 0; i < len(in.Items); i++ {
+		if _, ok := in.Items[i].Annotations[annotationKey]; ok {
+			out.Items = append(out.Items, in.Items[i])
+		}
+	}
+	return
+}
+
+// KeepPodsInNamespace returns only the pods of the given namespace. An empty namespace keeps all pods.
+func KeepPodsInNamespace(in v1.PodList, namespace string) (out v1.PodList) {
+	if namespace == "" {
+		return in
+	}
+	in.DeepCopyInto(&out)
+	out.Items = []v1.Pod{}
+	for i := 0; i < len(in.Items); i++ {
+		if in.Items[i].Namespace == namespace {
+			out.Items = append(out.Items, in.Items[i])
+		}
+	}
+	return
+}
+
+// KeepPodsInNamespaces returns only the pods of the given namespaces. An empty list of namespaces keeps all pods.
+func KeepPodsInNamespaces(in v1.PodList, namespaces []string) (out v1.PodList) {
+	if len(namespaces) == 0 {
+		return in
+	}
+	allowed := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		allowed[namespace] = true
+	}
+	in.DeepCopyInto(&out)
+	out.Items = []v1.Pod{}
+	for i := 0; i < len(in.Items); i++ {
+		if allowed[in.Items[i].Namespace] {
+			out.Items = append(out.Items, in.Items[i])
+		}
+	}
+	return
+}
+
+// SplitJobOwnedPods splits in into pods owned by a Job and all other pods, based on the first owner reference.
+func SplitJobOwnedPods(in v1.PodList) (jobOwned v1.PodList, others v1.PodList) {
+	in.DeepCopyInto(&jobOwned)
+	jobOwned.Items = []v1.Pod{}
+	in.DeepCopyInto(&others)
+	others.Items = []v1.Pod{}
+	for i := 0; i < len(in.Items); i++ {
+		if len(in.Items[i].OwnerReferences) > 0 && in.Items[i].OwnerReferences[0].Kind == "Job" {
+			jobOwned.Items = append(jobOwned.Items, in.Items[i])
+		} else {
+			others.Items = append(others.Items, in.Items[i])
+		}
+	}
+	return
+}
+
+// GetRunningImageDigest returns the digest of the image with prefix and version of image which a container in pods runs,
+// based on the image ID reported in the container statuses. An empty string is returned if no container reports it.
+func GetRunningImageDigest(pods v1.PodList, image ExpectedImage) string {
+	for _, pod := range pods.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+			if !strings.Contains(status.Image, image.Prefix) || !strings.HasSuffix(status.Image, image.Version) {
+				continue
+			}
+			if digest := getImageDigest(status.ImageID); digest != "" {
+				return digest
+			}
+		}
+	}
+	return ""
+}
+
 func getImageVersion(image string) (istioctl.Version, error) {
 	matches := reference.ReferenceRegexp.FindStringSubmatch(image)
 	if matches == nil || len(matches) < 3 {
@@ -9,7 +9,9 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func Test_Gatherer_GetAllPods(t *testing.T) {
@@ -186,6 +188,150 @@ func Test_GetInstalledIstioVersion(t *testing.T) {
 	})
 }
 
+func Test_Gatherer_GetAllPodsWithDifferentImage(t *testing.T) {
+	image := ExpectedImage{
+		Prefix:  "istio/proxyv2",
+		Version: "1.10.1",
+	}
+	podWithExpectedImage := fixPodWith("application", "kyma", "istio/proxyv2:1.10.1", "Running")
+	podWithDifferentImage := fixPodWith("istio", "custom", "istio/proxyv2:1.10.2", "Running")
+	secondPodWithDifferentImage := fixPodWith("other", "custom", "istio/proxyv2:1.10.0", "Running")
+	retryOpts := getTestingRetryOptions()
+
+	t.Run("should get pods with different image from the cluster", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(podWithExpectedImage, podWithDifferentImage)
+		gatherer := DefaultGatherer{}
+
+		// when
+		pods, err := gatherer.GetAllPodsWithDifferentImage(kubeClient, retryOpts, image, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 1)
+		require.Equal(t, "istio", pods.Items[0].Name)
+	})
+
+	t.Run("should get pods with different image from all pages", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		pageRequests := fixPaginatedPodsReactor(kubeClient, []v1.Pod{*podWithDifferentImage, *podWithExpectedImage}, []v1.Pod{*secondPodWithDifferentImage})
+		gatherer := DefaultGatherer{}
+
+		// when
+		pods, err := gatherer.GetAllPodsWithDifferentImage(kubeClient, retryOpts, image, false)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, *pageRequests)
+		require.Len(t, pods.Items, 2)
+		require.Equal(t, "istio", pods.Items[0].Name)
+		require.Equal(t, "other", pods.Items[1].Name)
+	})
+
+	t.Run("should return an error when a page could not be listed", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset()
+		kubeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, fmt.Errorf("list error")
+		})
+		gatherer := DefaultGatherer{}
+
+		// when
+		pods, err := gatherer.GetAllPodsWithDifferentImage(kubeClient, retryOpts, image, false)
+
+		// then
+		require.Error(t, err)
+		require.Empty(t, pods.Items)
+	})
+}
+
+func Test_Gatherer_GetAllPodsWithDifferentImage_TerminatingPods(t *testing.T) {
+	image := ExpectedImage{
+		Prefix:  "istio/proxyv2",
+		Version: "1.10.1",
+	}
+	runningPod := fixPodWith("running", "custom", "istio/proxyv2:1.10.2", "Running")
+	terminatingPod := fixPodWith("terminating", "custom", "istio/proxyv2:1.10.2", "Running")
+	deletionTimestamp := metav1.Now()
+	terminatingPod.DeletionTimestamp = &deletionTimestamp
+	terminatingPod.Finalizers = []string{"test"}
+	retryOpts := getTestingRetryOptions()
+
+	t.Run("should not get terminating pods by default", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(runningPod, terminatingPod)
+		gatherer := DefaultGatherer{}
+
+		// when
+		pods, err := gatherer.GetAllPodsWithDifferentImage(kubeClient, retryOpts, image, false)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 1)
+		require.Equal(t, "running", pods.Items[0].Name)
+	})
+
+	t.Run("should get terminating pods when they are included", func(t *testing.T) {
+		// given
+		kubeClient := fake.NewSimpleClientset(runningPod, terminatingPod)
+		gatherer := DefaultGatherer{}
+
+		// when
+		pods, err := gatherer.GetAllPodsWithDifferentImage(kubeClient, retryOpts, image, true)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, pods.Items, 2)
+	})
+}
+
+func Test_Gatherer_GetAllPods_Pagination(t *testing.T) {
+	// given
+	firstPod := fixPodWith("application", "kyma", "istio/proxyv2:1.10.1", "Running")
+	secondPod := fixPodWith("istio", "custom", "istio/proxyv2:1.10.2", "Running")
+	kubeClient := fake.NewSimpleClientset()
+	pageRequests := fixPaginatedPodsReactor(kubeClient, []v1.Pod{*firstPod}, []v1.Pod{*secondPod})
+	gatherer := DefaultGatherer{}
+
+	// when
+	pods, err := gatherer.GetAllPods(kubeClient, getTestingRetryOptions())
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, 2, *pageRequests)
+	require.Len(t, pods.Items, 2)
+}
+
+func Benchmark_Gatherer_GetAllPods_GetPodsWithDifferentImage(b *testing.B) {
+	kubeClient := fake.NewSimpleClientset()
+	fixPaginatedPodsReactor(kubeClient, fixBenchmarkPodPages(20, int(podsListPageSize))...)
+	gatherer := DefaultGatherer{}
+	image := ExpectedImage{Prefix: "istio/proxyv2", Version: "1.10.1"}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pods, err := gatherer.GetAllPods(kubeClient, getTestingRetryOptions())
+		require.NoError(b, err)
+		gatherer.GetPodsWithDifferentImage(*pods, image, false)
+	}
+}
+
+func Benchmark_Gatherer_GetAllPodsWithDifferentImage(b *testing.B) {
+	kubeClient := fake.NewSimpleClientset()
+	fixPaginatedPodsReactor(kubeClient, fixBenchmarkPodPages(20, int(podsListPageSize))...)
+	gatherer := DefaultGatherer{}
+	image := ExpectedImage{Prefix: "istio/proxyv2", Version: "1.10.1"}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := gatherer.GetAllPodsWithDifferentImage(kubeClient, getTestingRetryOptions(), image, false)
+		require.NoError(b, err)
+	}
+}
+
 func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 	retryOpts := getTestingRetryOptions()
 	enabledNS := fixNamespaceWith("enabled", map[string]string{"istio-injection": "enabled"})
@@ -200,7 +346,7 @@ func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled)
+		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled, false)
 
 		// then
 		require.NoError(t, err)
@@ -215,7 +361,7 @@ func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled)
+		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled, false)
 
 		// then
 		require.NoError(t, err)
@@ -230,7 +376,7 @@ func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled)
+		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled, false)
 
 		// then
 		require.NoError(t, err)
@@ -245,7 +391,7 @@ func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled)
+		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled, false)
 
 		// then
 		require.NoError(t, err)
@@ -260,7 +406,7 @@ func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled)
+		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled, false)
 
 		// then
 		require.NoError(t, err)
@@ -274,7 +420,7 @@ func Test_Gatherer_GetPodsForCNIChange(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled)
+		pods, err := gatherer.GetPodsForCNIChange(kubeClient, retryOpts, cniEnabled, false)
 
 		// then
 		require.NoError(t, err)
@@ -303,7 +449,7 @@ func Test_Gatherer_GetPodsWithDifferentImage(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image)
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image, false)
 
 		// then
 		require.Empty(t, podsWithDifferentImage.Items)
@@ -331,12 +477,115 @@ func Test_Gatherer_GetPodsWithDifferentImage(t *testing.T) {
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image)
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image, false)
 
 		// then
 		require.Equal(t, podsWithDifferentImage.Items, expected.Items)
 		require.NotEmpty(t, podsWithDifferentImage.Items)
 	})
+
+	t.Run("should not get pods which opted out of sidecar injection", func(t *testing.T) {
+		// given
+		annotatedFalse := fixPodWith("annotated", "kyma", "istio/proxyv2:1.10.2", "Running")
+		annotatedFalse.Annotations["sidecar.istio.io/inject"] = "false"
+		labeledFalse := fixPodWith("labeled", "kyma", "istio/proxyv2:1.10.2", "Running")
+		labeledFalse.Labels = map[string]string{"sidecar.istio.io/inject": "false"}
+		annotatedTrue := fixPodWith("injected", "kyma", "istio/proxyv2:1.10.2", "Running")
+		annotatedTrue.Annotations["sidecar.istio.io/inject"] = "true"
+		pods := v1.PodList{Items: []v1.Pod{*annotatedFalse, *labeledFalse, *annotatedTrue}}
+		gatherer := DefaultGatherer{}
+
+		// when
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image, false)
+
+		// then
+		require.Equal(t, []v1.Pod{*annotatedTrue}, podsWithDifferentImage.Items)
+	})
+
+	t.Run("should get pods annotated with sidecar.istio.io/inject=false when the label enables the injection", func(t *testing.T) {
+		// given
+		pod := fixPodWith("application", "kyma", "istio/proxyv2:1.10.2", "Running")
+		pod.Annotations["sidecar.istio.io/inject"] = "false"
+		pod.Labels = map[string]string{"sidecar.istio.io/inject": "true"}
+		gatherer := DefaultGatherer{}
+
+		// when
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(v1.PodList{Items: []v1.Pod{*pod}}, image, false)
+
+		// then
+		require.Equal(t, []v1.Pod{*pod}, podsWithDifferentImage.Items)
+	})
+}
+
+func Test_Gatherer_GetPodsWithDifferentImage_ImageComparison(t *testing.T) {
+	expectedDigest := "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	otherDigest := "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+
+	fixPodWithImageDigest := func(name, image, digest string) *v1.Pod {
+		pod := fixPodWith(name, "kyma", image, "Running")
+		pod.Status.ContainerStatuses = []v1.ContainerStatus{
+			{Name: name + "-containertwo", Image: image, ImageID: "docker-pullable://istio/proxyv2@" + digest},
+		}
+		return pod
+	}
+	tagEqualDigestDifferent := fixPodWithImageDigest("tag-equal", "istio/proxyv2:1.10.1", otherDigest)
+	tagDifferentDigestEqual := fixPodWithImageDigest("tag-different", "istio/proxyv2:1.10.1-distroless", expectedDigest)
+
+	var pods v1.PodList
+	pods.Items = []v1.Pod{*tagEqualDigestDifferent, *tagDifferentDigestEqual}
+
+	t.Run("should compare by tag by default", func(t *testing.T) {
+		// given
+		image := ExpectedImage{
+			Prefix:  "istio/proxyv2",
+			Version: "1.10.1",
+			Digest:  expectedDigest,
+		}
+		gatherer := DefaultGatherer{}
+
+		// when
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image, false)
+
+		// then
+		require.Len(t, podsWithDifferentImage.Items, 1)
+		require.Equal(t, "tag-different", podsWithDifferentImage.Items[0].Name)
+	})
+
+	t.Run("should compare by digest when digest comparison is configured", func(t *testing.T) {
+		// given
+		image := ExpectedImage{
+			Prefix:     "istio/proxyv2",
+			Version:    "1.10.1",
+			Digest:     expectedDigest,
+			Comparison: ImageComparisonDigest,
+		}
+		gatherer := DefaultGatherer{}
+
+		// when
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(pods, image, false)
+
+		// then
+		require.Len(t, podsWithDifferentImage.Items, 1)
+		require.Equal(t, "tag-equal", podsWithDifferentImage.Items[0].Name)
+	})
+
+	t.Run("should fall back to tag comparison when the container digest is not known", func(t *testing.T) {
+		// given
+		image := ExpectedImage{
+			Prefix:     "istio/proxyv2",
+			Version:    "1.10.1",
+			Digest:     expectedDigest,
+			Comparison: ImageComparisonDigest,
+		}
+		podWithoutStatus := fixPodWith("no-status", "kyma", "istio/proxyv2:1.10.2", "Running")
+		gatherer := DefaultGatherer{}
+
+		// when
+		podsWithDifferentImage := gatherer.GetPodsWithDifferentImage(v1.PodList{Items: []v1.Pod{*podWithoutStatus}}, image, false)
+
+		// then
+		require.Len(t, podsWithDifferentImage.Items, 1)
+	})
 }
 
 func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *testing.T) {
@@ -373,7 +622,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -386,7 +635,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -398,7 +647,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -410,7 +659,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -422,7 +671,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -434,7 +683,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -446,7 +695,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -458,7 +707,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -470,7 +719,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -482,7 +731,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -494,7 +743,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -506,7 +755,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -518,7 +767,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -530,7 +779,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionEnabledByDefault(t *tes
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -573,7 +822,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -586,7 +835,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -598,7 +847,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -610,7 +859,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -622,7 +871,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -634,7 +883,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -646,7 +895,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -658,7 +907,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -670,7 +919,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -682,7 +931,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -694,7 +943,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -706,7 +955,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -718,7 +967,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -730,7 +979,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -742,7 +991,7 @@ func Test_Gatherer_GetPodsWithoutSidecar_sidecarInjectionDisabledByDefault(t *te
 		gatherer := DefaultGatherer{}
 
 		// when
-		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault)
+		podsWithoutSidecar, err := gatherer.GetPodsWithoutSidecar(kubeClient, retryOpts, sidecarInjectionEnabledByDefault, false)
 
 		// then
 		require.NoError(t, err)
@@ -970,6 +1219,131 @@ func TestRemoveAnnotatedPods(t *testing.T) {
 
 }
 
+func TestKeepAnnotatedPods(t *testing.T) {
+
+	t.Run("should keep only annotated pods", func(t *testing.T) {
+		in := v1.PodList{Items: []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}},
+		}, {}}}
+		got := KeepAnnotatedPods(in, "foo")
+		require.Equal(t, in.Items[:1], got.Items)
+	})
+
+	t.Run("should keep no pods if none is annotated", func(t *testing.T) {
+		in := v1.PodList{Items: []v1.Pod{{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}},
+		}, {}}}
+		got := KeepAnnotatedPods(in, "baz")
+		require.Equal(t, 0, len(got.Items))
+	})
+}
+
+func TestKeepPodsInNamespace(t *testing.T) {
+	pods := v1.PodList{Items: []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "target"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "other"}},
+	}}
+
+	t.Run("should keep only pods in the namespace", func(t *testing.T) {
+		// when
+		out := KeepPodsInNamespace(pods, "target")
+
+		// then
+		require.Len(t, out.Items, 1)
+		require.Equal(t, "pod1", out.Items[0].Name)
+	})
+
+	t.Run("should keep all pods when namespace is empty", func(t *testing.T) {
+		// when
+		out := KeepPodsInNamespace(pods, "")
+
+		// then
+		require.Equal(t, pods, out)
+	})
+}
+
+func TestKeepPodsInNamespaces(t *testing.T) {
+	pods := v1.PodList{Items: []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "target"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "other"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Namespace: "another"}},
+	}}
+
+	t.Run("should keep only pods in the allowlisted namespaces", func(t *testing.T) {
+		// when
+		out := KeepPodsInNamespaces(pods, []string{"target", "another"})
+
+		// then
+		require.Len(t, out.Items, 2)
+		require.Equal(t, "pod1", out.Items[0].Name)
+		require.Equal(t, "pod3", out.Items[1].Name)
+	})
+
+	t.Run("should keep all pods when the allowlist is empty", func(t *testing.T) {
+		// when
+		out := KeepPodsInNamespaces(pods, nil)
+
+		// then
+		require.Equal(t, pods, out)
+	})
+}
+
+func TestGetRunningImageDigest(t *testing.T) {
+
+	image := ExpectedImage{Prefix: "istio/proxyv2", Version: "1.10.1"}
+	fixPod := func(image, imageID string) v1.Pod {
+		return v1.Pod{Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{{Name: "istio-proxy", Image: image, ImageID: imageID}}}}
+	}
+
+	t.Run("should return the digest of the container running the image", func(t *testing.T) {
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPod("istio/proxyv2:1.10.0", "docker-pullable://istio/proxyv2@sha256:old"),
+			fixPod("istio/proxyv2:1.10.1", "docker-pullable://istio/proxyv2@sha256:new"),
+		}}
+		require.Equal(t, "sha256:new", GetRunningImageDigest(pods, image))
+	})
+
+	t.Run("should return an empty digest when no container reports it", func(t *testing.T) {
+		pods := v1.PodList{Items: []v1.Pod{
+			fixPod("istio/proxyv2:1.10.0", "docker-pullable://istio/proxyv2@sha256:old"),
+			fixPod("istio/proxyv2:1.10.1", ""),
+		}}
+		require.Empty(t, GetRunningImageDigest(pods, image))
+	})
+
+}
+
+// fixPaginatedPodsReactor serves the pages one by one for consecutive pod list requests, setting Continue on all but the last page.
+// It returns the number of page requests served so far.
+func fixPaginatedPodsReactor(kubeClient *fake.Clientset, pages ...[]v1.Pod) *int {
+	pageRequests := 0
+	kubeClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		page := &v1.PodList{Items: pages[pageRequests%len(pages)]}
+		pageRequests++
+		if pageRequests%len(pages) != 0 {
+			page.Continue = fmt.Sprintf("page-%d", pageRequests)
+		}
+		return true, page, nil
+	})
+	return &pageRequests
+}
+
+// fixBenchmarkPodPages returns count pages of pageSize pods, every tenth of them running a different istio proxy image.
+func fixBenchmarkPodPages(count, pageSize int) [][]v1.Pod {
+	pages := make([][]v1.Pod, count)
+	for p := range pages {
+		pages[p] = make([]v1.Pod, pageSize)
+		for i := range pages[p] {
+			image := "istio/proxyv2:1.10.1"
+			if i%10 == 0 {
+				image = "istio/proxyv2:1.10.0"
+			}
+			pages[p][i] = *fixPodWith(fmt.Sprintf("pod-%d-%d", p, i), "kyma", image, "Running")
+		}
+	}
+	return pages
+}
+
 func getTestingRetryOptions() []retry.Option {
 	return []retry.Option{
 		retry.Delay(0),
@@ -46,6 +46,30 @@ func (_m *Gatherer) GetAllPods(kubeClient kubernetes.Interface, retryOpts []retr
 	return r0, r1
 }
 
+// GetAllPodsWithDifferentImage provides a mock function with given fields: kubeClient, retryOpts, image, includeTerminatingPods
+func (_m *Gatherer) GetAllPodsWithDifferentImage(kubeClient kubernetes.Interface, retryOpts []retry.Option, image data.ExpectedImage, includeTerminatingPods bool) (v1.PodList, error) {
+	ret := _m.Called(kubeClient, retryOpts, image, includeTerminatingPods)
+
+	var r0 v1.PodList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, data.ExpectedImage, bool) (v1.PodList, error)); ok {
+		return rf(kubeClient, retryOpts, image, includeTerminatingPods)
+	}
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, data.ExpectedImage, bool) v1.PodList); ok {
+		r0 = rf(kubeClient, retryOpts, image, includeTerminatingPods)
+	} else {
+		r0 = ret.Get(0).(v1.PodList)
+	}
+
+	if rf, ok := ret.Get(1).(func(kubernetes.Interface, []retry.Option, data.ExpectedImage, bool) error); ok {
+		r1 = rf(kubeClient, retryOpts, image, includeTerminatingPods)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInstalledIstioVersion provides a mock function with given fields: kubeClient, retryOpts, logger
 func (_m *Gatherer) GetInstalledIstioVersion(kubeClient kubernetes.Interface, retryOpts []retry.Option, logger *zap.SugaredLogger) (string, error) {
 	ret := _m.Called(kubeClient, retryOpts, logger)
@@ -96,23 +120,23 @@ func (_m *Gatherer) GetIstioCPPods(kubeClient kubernetes.Interface, retryOpts []
 	return r0, r1
 }
 
-// GetPodsForCNIChange provides a mock function with given fields: kubeClient, retryOpts, cniEnabled
-func (_m *Gatherer) GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOpts []retry.Option, cniEnabled bool) (v1.PodList, error) {
-	ret := _m.Called(kubeClient, retryOpts, cniEnabled)
+// GetPodsForCNIChange provides a mock function with given fields: kubeClient, retryOpts, cniEnabled, includeTerminatingPods
+func (_m *Gatherer) GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOpts []retry.Option, cniEnabled bool, includeTerminatingPods bool) (v1.PodList, error) {
+	ret := _m.Called(kubeClient, retryOpts, cniEnabled, includeTerminatingPods)
 
 	var r0 v1.PodList
 	var r1 error
-	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool) (v1.PodList, error)); ok {
-		return rf(kubeClient, retryOpts, cniEnabled)
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool, bool) (v1.PodList, error)); ok {
+		return rf(kubeClient, retryOpts, cniEnabled, includeTerminatingPods)
 	}
-	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool) v1.PodList); ok {
-		r0 = rf(kubeClient, retryOpts, cniEnabled)
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool, bool) v1.PodList); ok {
+		r0 = rf(kubeClient, retryOpts, cniEnabled, includeTerminatingPods)
 	} else {
 		r0 = ret.Get(0).(v1.PodList)
 	}
 
-	if rf, ok := ret.Get(1).(func(kubernetes.Interface, []retry.Option, bool) error); ok {
-		r1 = rf(kubeClient, retryOpts, cniEnabled)
+	if rf, ok := ret.Get(1).(func(kubernetes.Interface, []retry.Option, bool, bool) error); ok {
+		r1 = rf(kubeClient, retryOpts, cniEnabled, includeTerminatingPods)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,13 +144,13 @@ func (_m *Gatherer) GetPodsForCNIChange(kubeClient kubernetes.Interface, retryOp
 	return r0, r1
 }
 
-// GetPodsWithDifferentImage provides a mock function with given fields: inputPodsList, image
-func (_m *Gatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, image data.ExpectedImage) v1.PodList {
-	ret := _m.Called(inputPodsList, image)
+// GetPodsWithDifferentImage provides a mock function with given fields: inputPodsList, image, includeTerminatingPods
+func (_m *Gatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, image data.ExpectedImage, includeTerminatingPods bool) v1.PodList {
+	ret := _m.Called(inputPodsList, image, includeTerminatingPods)
 
 	var r0 v1.PodList
-	if rf, ok := ret.Get(0).(func(v1.PodList, data.ExpectedImage) v1.PodList); ok {
-		r0 = rf(inputPodsList, image)
+	if rf, ok := ret.Get(0).(func(v1.PodList, data.ExpectedImage, bool) v1.PodList); ok {
+		r0 = rf(inputPodsList, image, includeTerminatingPods)
 	} else {
 		r0 = ret.Get(0).(v1.PodList)
 	}
@@ -134,23 +158,23 @@ func (_m *Gatherer) GetPodsWithDifferentImage(inputPodsList v1.PodList, image da
 	return r0
 }
 
-// GetPodsWithoutSidecar provides a mock function with given fields: kubeClient, retryOpts, sidecarInjectionEnabledbyDefault
-func (_m *Gatherer) GetPodsWithoutSidecar(kubeClient kubernetes.Interface, retryOpts []retry.Option, sidecarInjectionEnabledbyDefault bool) (v1.PodList, error) {
-	ret := _m.Called(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault)
+// GetPodsWithoutSidecar provides a mock function with given fields: kubeClient, retryOpts, sidecarInjectionEnabledbyDefault, includeTerminatingPods
+func (_m *Gatherer) GetPodsWithoutSidecar(kubeClient kubernetes.Interface, retryOpts []retry.Option, sidecarInjectionEnabledbyDefault bool, includeTerminatingPods bool) (v1.PodList, error) {
+	ret := _m.Called(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault, includeTerminatingPods)
 
 	var r0 v1.PodList
 	var r1 error
-	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool) (v1.PodList, error)); ok {
-		return rf(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault)
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool, bool) (v1.PodList, error)); ok {
+		return rf(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault, includeTerminatingPods)
 	}
-	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool) v1.PodList); ok {
-		r0 = rf(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault)
+	if rf, ok := ret.Get(0).(func(kubernetes.Interface, []retry.Option, bool, bool) v1.PodList); ok {
+		r0 = rf(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault, includeTerminatingPods)
 	} else {
 		r0 = ret.Get(0).(v1.PodList)
 	}
 
-	if rf, ok := ret.Get(1).(func(kubernetes.Interface, []retry.Option, bool) error); ok {
-		r1 = rf(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault)
+	if rf, ok := ret.Get(1).(func(kubernetes.Interface, []retry.Option, bool, bool) error); ok {
+		r1 = rf(kubeClient, retryOpts, sidecarInjectionEnabledbyDefault, includeTerminatingPods)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -5,6 +5,10 @@ package mocks
 import (
 	config "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
 	mock "github.com/stretchr/testify/mock"
+
+	proxy "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/proxy"
+
+	v1 "k8s.io/api/core/v1"
 )
 
 // IstioProxyReset is an autogenerated mock type for the IstioProxyReset type
@@ -12,18 +16,67 @@ type IstioProxyReset struct {
 	mock.Mock
 }
 
+// Candidates provides a mock function with given fields: cfg
+func (_m *IstioProxyReset) Candidates(cfg config.IstioProxyConfig) (v1.PodList, error) {
+	ret := _m.Called(cfg)
+
+	var r0 v1.PodList
+	if rf, ok := ret.Get(0).(func(config.IstioProxyConfig) v1.PodList); ok {
+		r0 = rf(cfg)
+	} else {
+		r0 = ret.Get(0).(v1.PodList)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(config.IstioProxyConfig) error); ok {
+		r1 = rf(cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PodsWithDifferentImage provides a mock function with given fields: cfg
+func (_m *IstioProxyReset) PodsWithDifferentImage(cfg config.IstioProxyConfig) (v1.PodList, error) {
+	ret := _m.Called(cfg)
+
+	var r0 v1.PodList
+	if rf, ok := ret.Get(0).(func(config.IstioProxyConfig) v1.PodList); ok {
+		r0 = rf(cfg)
+	} else {
+		r0 = ret.Get(0).(v1.PodList)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(config.IstioProxyConfig) error); ok {
+		r1 = rf(cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Run provides a mock function with given fields: cfg
-func (_m *IstioProxyReset) Run(cfg config.IstioProxyConfig) error {
+func (_m *IstioProxyReset) Run(cfg config.IstioProxyConfig) (proxy.ResetSummary, error) {
 	ret := _m.Called(cfg)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(config.IstioProxyConfig) error); ok {
+	var r0 proxy.ResetSummary
+	if rf, ok := ret.Get(0).(func(config.IstioProxyConfig) proxy.ResetSummary); ok {
 		r0 = rf(cfg)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(proxy.ResetSummary)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(config.IstioProxyConfig) error); ok {
+		r1 = rf(cfg)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
 type mockConstructorTestingTNewIstioProxyReset interface {
@@ -0,0 +1,166 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ResetReasonDifferentImage marks pods running an istio proxy image different from the target image.
+	ResetReasonDifferentImage = "different-image"
+
+	// ResetReasonCNIChange marks pods which need to be restarted to roll out the CNI plugin change.
+	ResetReasonCNIChange = "cni-change"
+
+	// ResetReasonMissingSidecar marks pods which require a sidecar but do not have one.
+	ResetReasonMissingSidecar = "missing-sidecar"
+)
+
+// ResetPlan describes the proxy reset Run would perform with the same config, without executing it.
+type ResetPlan struct {
+	TargetImage             TargetImage     `json:"targetImage"`
+	MaxConcurrentNamespaces int             `json:"maxConcurrentNamespaces"`
+	JobPodsHandling         string          `json:"jobPodsHandling,omitempty"`
+	Steps                   []ResetPlanStep `json:"steps"`
+}
+
+// TargetImage is the istio proxy image pods are compared with.
+type TargetImage struct {
+	Prefix     string `json:"prefix"`
+	Version    string `json:"version"`
+	Digest     string `json:"digest,omitempty"`
+	Comparison string `json:"comparison,omitempty"`
+}
+
+// ResetPlanStep describes the pods reset for one reason, in the order Run processes them.
+type ResetPlanStep struct {
+	Reason string `json:"reason"`
+	// NamespaceOrder lists the namespaces in the order they are reset.
+	NamespaceOrder []string `json:"namespaceOrder"`
+	// CandidatesPerNamespace is the number of pods reset in each namespace.
+	CandidatesPerNamespace map[string]int `json:"candidatesPerNamespace"`
+	// Waves groups NamespaceOrder into batches of at most MaxConcurrentNamespaces namespaces reset in parallel.
+	Waves      [][]string     `json:"waves"`
+	Exclusions []PodExclusion `json:"exclusions,omitempty"`
+}
+
+// PodExclusion describes a candidate pod which is not reset and why.
+type PodExclusion struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// JSON returns the plan in JSON format.
+func (p ResetPlan) JSON() (string, error) {
+	result, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// Plan gathers the pods Run would reset with the config and returns the resulting plan. No pod is reset, deleted or annotated.
+func (i *DefaultIstioProxyReset) Plan(cfg config.IstioProxyConfig) (ResetPlan, error) {
+	image := expectedImage(cfg)
+	retryOpts := retryOptions(cfg)
+
+	maxConcurrentNamespaces := cfg.MaxConcurrentNamespaces
+	if maxConcurrentNamespaces < 1 {
+		maxConcurrentNamespaces = 1
+	}
+
+	plan := ResetPlan{
+		TargetImage: TargetImage{
+			Prefix:     image.Prefix,
+			Version:    image.Version,
+			Digest:     image.Digest,
+			Comparison: string(image.Comparison),
+		},
+		MaxConcurrentNamespaces: maxConcurrentNamespaces,
+		JobPodsHandling:         string(cfg.JobPodsHandling),
+		Steps:                   []ResetPlanStep{},
+	}
+
+	if cfg.IsUpdate {
+		podsWithDifferentImage, podsWithoutAnnotation, err := i.podsWithDifferentImage(cfg, retryOpts)
+		if err != nil {
+			return ResetPlan{}, err
+		}
+
+		step := planStep(cfg, maxConcurrentNamespaces, ResetReasonDifferentImage, podsWithoutAnnotation)
+		step.Exclusions = append(excludedPods(podsWithDifferentImage, podsWithoutAnnotation, "annotated with "+pod.AnnotationResetWarningKey), step.Exclusions...)
+		plan.Steps = append(plan.Steps, step)
+	}
+
+	podsWithCNIChange, err := i.podsWithCNIChange(cfg, retryOpts)
+	if err != nil {
+		return ResetPlan{}, err
+	}
+	plan.Steps = append(plan.Steps, planStep(cfg, maxConcurrentNamespaces, ResetReasonCNIChange, podsWithCNIChange))
+
+	podsWithoutSidecar, err := i.podsWithoutSidecar(cfg, retryOpts)
+	if err != nil {
+		return ResetPlan{}, err
+	}
+	plan.Steps = append(plan.Steps, planStep(cfg, maxConcurrentNamespaces, ResetReasonMissingSidecar, podsWithoutSidecar))
+
+	return plan, nil
+}
+
+// planStep applies the same filtering and ordering to the pods as Run does.
+func planStep(cfg config.IstioProxyConfig, maxConcurrentNamespaces int, reason string, pods v1.PodList) ResetPlanStep {
+	step := ResetPlanStep{
+		Reason:                 reason,
+		NamespaceOrder:         []string{},
+		CandidatesPerNamespace: map[string]int{},
+		Waves:                  [][]string{},
+	}
+
+	jobOwnedPods, otherPods := data.SplitJobOwnedPods(pods)
+	switch cfg.JobPodsHandling {
+	case data.JobPodsHandlingSkip, data.JobPodsHandlingRestart, data.JobPodsHandlingFail:
+		step.Exclusions = append(step.Exclusions, excludedPods(jobOwnedPods, v1.PodList{}, fmt.Sprintf("owned by Job (%s)", cfg.JobPodsHandling))...)
+		pods = otherPods
+	}
+
+	namespaces, podsByNamespace := groupPodsByNamespace(pods)
+	if len(cfg.NamespacePriority) > 0 {
+		sortNamespacesByPriority(namespaces, cfg.NamespacePriority, cfg.UnlistedNamespacesPriority)
+	}
+
+	step.NamespaceOrder = append(step.NamespaceOrder, namespaces...)
+	for _, namespace := range namespaces {
+		step.CandidatesPerNamespace[namespace] = len(podsByNamespace[namespace].Items)
+	}
+	for start := 0; start < len(namespaces); start += maxConcurrentNamespaces {
+		end := start + maxConcurrentNamespaces
+		if end > len(namespaces) {
+			end = len(namespaces)
+		}
+		step.Waves = append(step.Waves, namespaces[start:end])
+	}
+
+	return step
+}
+
+// excludedPods returns the pods of all which are not in kept as exclusions with the given reason.
+func excludedPods(all, kept v1.PodList, reason string) []PodExclusion {
+	keptPods := make(map[string]bool, len(kept.Items))
+	for _, p := range kept.Items {
+		keptPods[p.Namespace+"/"+p.Name] = true
+	}
+
+	var exclusions []PodExclusion
+	for _, p := range all.Items {
+		if !keptPods[p.Namespace+"/"+p.Name] {
+			exclusions = append(exclusions, PodExclusion{Namespace: p.Namespace, Name: p.Name, Reason: reason})
+		}
+	}
+	return exclusions
+}
@@ -0,0 +1,153 @@
+package proxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	log "github.com/kyma-incubator/reconciler/pkg/logger"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
+	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	podresetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_IstioProxyReset_Plan(t *testing.T) {
+	newPod := func(namespace, name string) v1.Pod {
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	annotatedPod := newPod("ns-a", "annotated-pod")
+	annotatedPod.Annotations = map[string]string{pod.AnnotationResetWarningKey: "warning"}
+	jobPod := newPod("ns-b", "job-pod")
+	jobPod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "job"}}
+	candidates := v1.PodList{Items: []v1.Pod{
+		newPod("ns-a", "pod-1"),
+		newPod("ns-a", "pod-2"),
+		annotatedPod,
+		newPod("ns-b", "pod-3"),
+		jobPod,
+		newPod("ns-c", "pod-4"),
+		newPod("kyma-system", "pod-5"),
+	}}
+
+	newGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(candidates, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{newPod("ns-c", "no-sidecar")}}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		return &gatherer
+	}
+
+	t.Run("should reflect configured options and candidates without resetting pods", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			ImagePrefix:             "istio/proxyv2",
+			ImageVersion:            "1.10.2",
+			ImageComparison:         data.ImageComparisonTag,
+			Kubeclient:              fake.NewSimpleClientset(),
+			Log:                     log.NewLogger(true),
+			IsUpdate:                true,
+			MaxConcurrentNamespaces: 2,
+			NamespacePriority:       []string{"kyma-system"},
+			JobPodsHandling:         data.JobPodsHandlingSkip,
+		}
+		action := podresetmocks.Action{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), &action)
+
+		// when
+		plan, err := istioProxyReset.Plan(cfg)
+
+		// then
+		require.NoError(t, err)
+		action.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		require.Equal(t, TargetImage{Prefix: "istio/proxyv2", Version: "1.10.2", Comparison: "tag"}, plan.TargetImage)
+		require.Equal(t, 2, plan.MaxConcurrentNamespaces)
+		require.Len(t, plan.Steps, 3)
+
+		imageStep := plan.Steps[0]
+		require.Equal(t, ResetReasonDifferentImage, imageStep.Reason)
+		require.Equal(t, []string{"kyma-system", "ns-a", "ns-b", "ns-c"}, imageStep.NamespaceOrder)
+		require.Equal(t, map[string]int{"kyma-system": 1, "ns-a": 2, "ns-b": 1, "ns-c": 1}, imageStep.CandidatesPerNamespace)
+		require.Equal(t, [][]string{{"kyma-system", "ns-a"}, {"ns-b", "ns-c"}}, imageStep.Waves)
+		require.Equal(t, []PodExclusion{
+			{Namespace: "ns-a", Name: "annotated-pod", Reason: "annotated with " + pod.AnnotationResetWarningKey},
+			{Namespace: "ns-b", Name: "job-pod", Reason: "owned by Job (skip)"},
+		}, imageStep.Exclusions)
+
+		require.Equal(t, ResetReasonCNIChange, plan.Steps[1].Reason)
+		require.Empty(t, plan.Steps[1].Waves)
+
+		require.Equal(t, ResetReasonMissingSidecar, plan.Steps[2].Reason)
+		require.Equal(t, map[string]int{"ns-c": 1}, plan.Steps[2].CandidatesPerNamespace)
+	})
+
+	t.Run("should not plan the image step when it is not an update", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+		}
+		gatherer := newGatherer()
+		istioProxyReset := NewDefaultIstioProxyReset(gatherer, &podresetmocks.Action{})
+
+		// when
+		plan, err := istioProxyReset.Plan(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, plan.Steps, 2)
+		require.Equal(t, 1, plan.MaxConcurrentNamespaces)
+		require.Equal(t, [][]string{{"ns-c"}}, plan.Steps[1].Waves)
+		gatherer.AssertNotCalled(t, "GetAllPodsWithDifferentImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should pass the terminating pods option to the gatherer", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient:             fake.NewSimpleClientset(),
+			Log:                    log.NewLogger(true),
+			IsUpdate:               true,
+			IncludeTerminatingPods: true,
+		}
+		gatherer := newGatherer()
+		istioProxyReset := NewDefaultIstioProxyReset(gatherer, &podresetmocks.Action{})
+
+		// when
+		_, err := istioProxyReset.Plan(cfg)
+
+		// then
+		require.NoError(t, err)
+		gatherer.AssertCalled(t, "GetAllPodsWithDifferentImage", mock.Anything, mock.Anything, mock.Anything, true)
+		gatherer.AssertCalled(t, "GetPodsForCNIChange", mock.Anything, mock.Anything, mock.Anything, true)
+		gatherer.AssertCalled(t, "GetPodsWithoutSidecar", mock.Anything, mock.Anything, mock.Anything, true)
+	})
+
+	t.Run("should be serializable to JSON", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			ImagePrefix:  "istio/proxyv2",
+			ImageVersion: "1.10.2",
+			Kubeclient:   fake.NewSimpleClientset(),
+			Log:          log.NewLogger(true),
+		}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), &podresetmocks.Action{})
+		plan, err := istioProxyReset.Plan(cfg)
+		require.NoError(t, err)
+
+		// when
+		result, err := plan.JSON()
+
+		// then
+		require.NoError(t, err)
+		var decoded ResetPlan
+		require.NoError(t, json.Unmarshal([]byte(result), &decoded))
+		require.Equal(t, plan, decoded)
+		require.Contains(t, result, `"prefix": "istio/proxyv2"`)
+	})
+}
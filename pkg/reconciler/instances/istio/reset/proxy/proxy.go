@@ -1,19 +1,92 @@
 package proxy
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/avast/retry-go"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset"
+	"golang.org/x/sync/errgroup"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // IstioProxyReset performs istio proxy containers reset on objects in the k8s cluster.
 //
 //go:generate mockery --name=IstioProxyReset --outpkg=mocks --case=underscore
 type IstioProxyReset interface {
-	// Run istio proxy containers reset using the config.
-	Run(cfg config.IstioProxyConfig) error
+	// Run istio proxy containers reset using the config. The summary reports the progress made also when the reset failed.
+	Run(cfg config.IstioProxyConfig) (ResetSummary, error)
+
+	// Candidates returns the pods Run would reset with the config, each pod listed once. No pod is reset.
+	Candidates(cfg config.IstioProxyConfig) (v1.PodList, error)
+
+	// PodsWithDifferentImage returns the pods in scope of the config which run an istio proxy image different from the expected
+	// one and are not annotated with a reset warning, so after Run the pods whose proxy did not converge. No pod is reset.
+	PodsWithDifferentImage(cfg config.IstioProxyConfig) (v1.PodList, error)
+}
+
+// ResetSummary reports how many pods a proxy reset found in scope, restarted, failed to restart and skipped. Each pod is
+// counted once, also if it is found for several reasons.
+type ResetSummary struct {
+	PodsConsidered int
+	PodsRestarted  int
+	PodsFailed     int
+	// PodsSkipped are the pods left as they are, e.g. because of a reset warning annotation or as they are owned by a Job.
+	PodsSkipped int
+}
+
+// resetProgress collects the ResetSummary of pods reset concurrently.
+type resetProgress struct {
+	mu      sync.Mutex
+	summary ResetSummary
+	counted map[string]map[string]bool
+}
+
+func (p *resetProgress) considered(pods v1.PodList) {
+	p.count(pods, "considered", &p.summary.PodsConsidered)
+}
+
+func (p *resetProgress) restarted(pods v1.PodList) {
+	p.count(pods, "restarted", &p.summary.PodsRestarted)
+}
+
+func (p *resetProgress) failed(pods v1.PodList) {
+	p.count(pods, "failed", &p.summary.PodsFailed)
+}
+
+func (p *resetProgress) skipped(pods v1.PodList) {
+	p.count(pods, "skipped", &p.summary.PodsSkipped)
+}
+
+// count adds the pods not counted in the given state yet to counter, pods are identified by their namespace and name.
+func (p *resetProgress) count(pods v1.PodList, state string, counter *int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.counted == nil {
+		p.counted = make(map[string]map[string]bool)
+	}
+	if p.counted[state] == nil {
+		p.counted[state] = make(map[string]bool)
+	}
+	for _, countedPod := range pods.Items {
+		key := countedPod.Namespace + "/" + countedPod.Name
+		if !p.counted[state][key] {
+			p.counted[state][key] = true
+			*counter++
+		}
+	}
+}
+
+func (p *resetProgress) get() ResetSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.summary
 }
 
 // DefaultIstioProxyReset provides a default implementation of the IstioProxyReset.
@@ -30,33 +103,24 @@ func NewDefaultIstioProxyReset(gatherer data.Gatherer, action reset.Action) *Def
 	}
 }
 
-func (i *DefaultIstioProxyReset) Run(cfg config.IstioProxyConfig) error {
-	image := data.ExpectedImage{
-		Prefix:  cfg.ImagePrefix,
-		Version: cfg.ImageVersion,
-	}
+func (i *DefaultIstioProxyReset) Run(cfg config.IstioProxyConfig) (ResetSummary, error) {
+	progress := &resetProgress{}
 
 	waitOpts := pod.WaitOptions{
 		Interval: cfg.Interval,
 		Timeout:  cfg.Timeout,
 	}
-
-	retryOpts := []retry.Option{
-		retry.Delay(cfg.DelayBetweenRetries),
-		retry.Attempts(uint(cfg.RetriesCount)),
-		retry.DelayType(retry.FixedDelay),
-	}
+	retryOpts := retryOptions(cfg)
 
 	if cfg.IsUpdate {
-		pods, err := i.gatherer.GetAllPods(cfg.Kubeclient, retryOpts)
+		podsWithDifferentImage, podsWithoutAnnotation, err := i.podsWithDifferentImage(cfg, retryOpts)
 		if err != nil {
-			return err
+			return progress.get(), err
 		}
-		cfg.Log.Debugf("Found %d pods in total", len(pods.Items))
-		podsWithDifferentImage := i.gatherer.GetPodsWithDifferentImage(*pods, image)
+		progress.considered(podsWithDifferentImage)
+		progress.skipped(data.KeepAnnotatedPods(podsWithDifferentImage, pod.AnnotationResetWarningKey))
 
-		cfg.Log.Debugf("Found %d pods with different istio proxy image (%s)", len(podsWithDifferentImage.Items), image)
-		podsWithoutAnnotation := data.RemoveAnnotatedPods(podsWithDifferentImage, pod.AnnotationResetWarningKey)
+		cfg.Log.Debugf("Found %d pods with different istio proxy image (%s)", len(podsWithDifferentImage.Items), expectedImage(cfg))
 		if len(podsWithDifferentImage.Items) >= 1 && len(podsWithoutAnnotation.Items) == 0 {
 			cfg.Log.Warnf(
 				"Found %d pods with different istio proxy image, but we cannot update sidecar proxy image for them. Look for pods with annotation %s,"+
@@ -66,40 +130,269 @@ func (i *DefaultIstioProxyReset) Run(cfg config.IstioProxyConfig) error {
 			)
 		}
 		if len(podsWithoutAnnotation.Items) >= 1 {
-			err = i.action.Reset(cfg.Context, cfg.Kubeclient, retryOpts, podsWithoutAnnotation, cfg.Log, cfg.Debug, waitOpts)
+			err = i.resetPods(cfg, retryOpts, waitOpts, podsWithoutAnnotation, progress)
 			if err != nil {
-				return err
+				return progress.get(), err
 			}
 			cfg.Log.Infof("Proxy reset for %d pods successfully done", len(podsWithoutAnnotation.Items))
 		}
 	}
 
-	podsWithCNIChange, err := i.gatherer.GetPodsForCNIChange(cfg.Kubeclient, retryOpts, cfg.CNIEnabled)
+	podsWithCNIChange, err := i.podsWithCNIChange(cfg, retryOpts)
 	if err != nil {
-		return err
+		return progress.get(), err
 	}
+	progress.considered(podsWithCNIChange)
 	if len(podsWithCNIChange.Items) >= 1 {
 		cfg.Log.Debugf("Found %d pods that need CNI plugin rollout", len(podsWithCNIChange.Items))
-		err = i.action.Reset(cfg.Context, cfg.Kubeclient, retryOpts, podsWithCNIChange, cfg.Log, cfg.Debug, waitOpts)
+		err = i.resetPods(cfg, retryOpts, waitOpts, podsWithCNIChange, progress)
 		if err != nil {
-			return err
+			return progress.get(), err
 		}
 		cfg.Log.Infof("CNI plugin rollout for %d pods successfully done", len(podsWithCNIChange.Items))
 	}
 
-	podsWithoutSidecar, err := i.gatherer.GetPodsWithoutSidecar(cfg.Kubeclient, retryOpts, cfg.SidecarInjectionByDefaultEnabled)
+	podsWithoutSidecar, err := i.podsWithoutSidecar(cfg, retryOpts)
 	if err != nil {
-		return err
+		return progress.get(), err
 	}
+	progress.considered(podsWithoutSidecar)
 	cfg.Log.Debugf("Found %d pods without sidecar", len(podsWithoutSidecar.Items))
 
 	if len(podsWithoutSidecar.Items) >= 1 {
-		err = i.action.Reset(cfg.Context, cfg.Kubeclient, retryOpts, podsWithoutSidecar, cfg.Log, cfg.Debug, waitOpts)
+		err = i.resetPods(cfg, retryOpts, waitOpts, podsWithoutSidecar, progress)
 		if err != nil {
-			return err
+			return progress.get(), err
 		}
 		cfg.Log.Infof("Proxy reset for %d pods without sidecar successfully done", len(podsWithoutSidecar.Items))
 	}
 
-	return nil
+	return progress.get(), nil
+}
+
+func (i *DefaultIstioProxyReset) Candidates(cfg config.IstioProxyConfig) (v1.PodList, error) {
+	retryOpts := retryOptions(cfg)
+	candidates := v1.PodList{Items: []v1.Pod{}}
+	seen := make(map[string]bool)
+	addCandidates := func(pods v1.PodList, reason string) {
+		for _, p := range pods.Items {
+			key := p.Namespace + "/" + p.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			cfg.Log.Debugf("Pod %s is a proxy reset candidate: %s", key, reason)
+			candidates.Items = append(candidates.Items, p)
+		}
+	}
+
+	if cfg.IsUpdate {
+		_, podsWithoutAnnotation, err := i.podsWithDifferentImage(cfg, retryOpts)
+		if err != nil {
+			return v1.PodList{}, err
+		}
+		addCandidates(podsWithoutAnnotation, ResetReasonDifferentImage)
+	}
+
+	podsWithCNIChange, err := i.podsWithCNIChange(cfg, retryOpts)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	addCandidates(podsWithCNIChange, ResetReasonCNIChange)
+
+	podsWithoutSidecar, err := i.podsWithoutSidecar(cfg, retryOpts)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	addCandidates(podsWithoutSidecar, ResetReasonMissingSidecar)
+	cfg.Log.Debugf("Found %d proxy reset candidates", len(candidates.Items))
+
+	return candidates, nil
+}
+
+func (i *DefaultIstioProxyReset) PodsWithDifferentImage(cfg config.IstioProxyConfig) (v1.PodList, error) {
+	_, podsWithoutAnnotation, err := i.podsWithDifferentImage(cfg, retryOptions(cfg))
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	return podsWithoutAnnotation, nil
+}
+
+// podsWithDifferentImage returns the pods in scope which run an istio proxy image different from the expected one, together
+// with those of them which are not annotated with a reset warning and so are reset.
+func (i *DefaultIstioProxyReset) podsWithDifferentImage(cfg config.IstioProxyConfig, retryOpts []retry.Option) (v1.PodList, v1.PodList, error) {
+	pods, err := i.gatherer.GetAllPodsWithDifferentImage(cfg.Kubeclient, retryOpts, expectedImage(cfg), cfg.IncludeTerminatingPods)
+	if err != nil {
+		return v1.PodList{}, v1.PodList{}, err
+	}
+	pods = keepPodsInScope(pods, cfg)
+	return pods, data.RemoveAnnotatedPods(pods, pod.AnnotationResetWarningKey), nil
+}
+
+// podsWithCNIChange returns the pods in scope which need to be restarted to roll out the CNI plugin change.
+func (i *DefaultIstioProxyReset) podsWithCNIChange(cfg config.IstioProxyConfig, retryOpts []retry.Option) (v1.PodList, error) {
+	pods, err := i.gatherer.GetPodsForCNIChange(cfg.Kubeclient, retryOpts, cfg.CNIEnabled, cfg.IncludeTerminatingPods)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	return keepPodsInScope(pods, cfg), nil
+}
+
+// podsWithoutSidecar returns the pods in scope which require a sidecar but do not have one.
+func (i *DefaultIstioProxyReset) podsWithoutSidecar(cfg config.IstioProxyConfig, retryOpts []retry.Option) (v1.PodList, error) {
+	pods, err := i.gatherer.GetPodsWithoutSidecar(cfg.Kubeclient, retryOpts, cfg.SidecarInjectionByDefaultEnabled, cfg.IncludeTerminatingPods)
+	if err != nil {
+		return v1.PodList{}, err
+	}
+	return keepPodsInScope(pods, cfg), nil
+}
+
+func expectedImage(cfg config.IstioProxyConfig) data.ExpectedImage {
+	return data.ExpectedImage{
+		Prefix:     cfg.ImagePrefix,
+		Version:    cfg.ImageVersion,
+		Digest:     cfg.ImageDigest,
+		Comparison: cfg.ImageComparison,
+	}
+}
+
+func retryOptions(cfg config.IstioProxyConfig) []retry.Option {
+	return []retry.Option{
+		retry.Delay(cfg.DelayBetweenRetries),
+		retry.Attempts(uint(cfg.RetriesCount)),
+		retry.DelayType(retry.FixedDelay),
+	}
+}
+
+// keepPodsInScope keeps only the pods in the namespace and the namespaces the reset is limited to by the configuration.
+func keepPodsInScope(pods v1.PodList, cfg config.IstioProxyConfig) v1.PodList {
+	return data.KeepPodsInNamespaces(data.KeepPodsInNamespace(pods, cfg.Namespace), cfg.Namespaces)
+}
+
+// resetPods resets the given pods namespace by namespace, processing at most cfg.MaxConcurrentNamespaces namespaces at once.
+// The pods of a namespace are counted as restarted or failed in progress depending on the result of their reset.
+func (i *DefaultIstioProxyReset) resetPods(cfg config.IstioProxyConfig, retryOpts []retry.Option, waitOpts pod.WaitOptions, pods v1.PodList, progress *resetProgress) error {
+	maxConcurrentNamespaces := cfg.MaxConcurrentNamespaces
+	if maxConcurrentNamespaces < 1 {
+		maxConcurrentNamespaces = 1
+	}
+
+	pods, err := i.handleJobOwnedPods(cfg, retryOpts, pods, progress)
+	if err != nil {
+		return err
+	}
+
+	namespaces, podsByNamespace := groupPodsByNamespace(pods)
+	if len(cfg.NamespacePriority) > 0 {
+		sortNamespacesByPriority(namespaces, cfg.NamespacePriority, cfg.UnlistedNamespacesPriority)
+		cfg.Log.Debugf("Resetting namespaces in prioritized order: %v", namespaces)
+	}
+	cfg.Log.Debugf("Resetting pods in %d namespaces with at most %d namespaces in parallel", len(namespaces), maxConcurrentNamespaces)
+
+	g := errgroup.Group{}
+	g.SetLimit(maxConcurrentNamespaces)
+	for _, namespace := range namespaces {
+		namespacePods := podsByNamespace[namespace]
+		g.Go(func() error {
+			err := i.action.Reset(cfg.Context, cfg.Kubeclient, retryOpts, namespacePods, cfg.Log, cfg.Debug, waitOpts)
+			if err != nil {
+				progress.failed(namespacePods)
+				return err
+			}
+			progress.restarted(namespacePods)
+			return nil
+		})
+	}
+
+	return g.Wait()
+}
+
+func groupPodsByNamespace(pods v1.PodList) ([]string, map[string]v1.PodList) {
+	podsByNamespace := make(map[string]v1.PodList)
+	for _, p := range pods.Items {
+		namespacePods, ok := podsByNamespace[p.Namespace]
+		if !ok {
+			namespacePods = v1.PodList{TypeMeta: pods.TypeMeta, ListMeta: *pods.ListMeta.DeepCopy()}
+		}
+		namespacePods.Items = append(namespacePods.Items, p)
+		podsByNamespace[p.Namespace] = namespacePods
+	}
+
+	namespaces := make([]string, 0, len(podsByNamespace))
+	for namespace := range podsByNamespace {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	return namespaces, podsByNamespace
+}
+
+// sortNamespacesByPriority orders namespaces by their position in priority, namespaces with the same position stay in alphabetical order.
+// Namespaces not listed in priority are put at unlistedPriority, or after all listed namespaces if it is nil.
+func sortNamespacesByPriority(namespaces []string, priority []string, unlistedPriority *int) {
+	positions := make(map[string]int, len(priority))
+	for position, namespace := range priority {
+		positions[namespace] = position
+	}
+	unlistedPosition := len(priority)
+	if unlistedPriority != nil {
+		unlistedPosition = *unlistedPriority
+	}
+
+	positionOf := func(namespace string) int {
+		if position, ok := positions[namespace]; ok {
+			return position
+		}
+		return unlistedPosition
+	}
+
+	sort.SliceStable(namespaces, func(i, j int) bool {
+		return positionOf(namespaces[i]) < positionOf(namespaces[j])
+	})
+}
+
+// handleJobOwnedPods applies cfg.JobPodsHandling to the pods owned by Jobs and returns the pods which still need to be reset.
+func (i *DefaultIstioProxyReset) handleJobOwnedPods(cfg config.IstioProxyConfig, retryOpts []retry.Option, pods v1.PodList, progress *resetProgress) (v1.PodList, error) {
+	jobOwnedPods, otherPods := data.SplitJobOwnedPods(pods)
+	if len(jobOwnedPods.Items) == 0 {
+		return pods, nil
+	}
+
+	switch cfg.JobPodsHandling {
+	case data.JobPodsHandlingSkip:
+		cfg.Log.Infof("Skipping proxy reset for %d pods owned by Jobs", len(jobOwnedPods.Items))
+		progress.skipped(jobOwnedPods)
+		return otherPods, nil
+	case data.JobPodsHandlingRestart:
+		for _, jobPod := range jobOwnedPods.Items {
+			err := deletePod(cfg, retryOpts, jobPod)
+			if err != nil {
+				progress.failed(v1.PodList{Items: []v1.Pod{jobPod}})
+				return v1.PodList{}, err
+			}
+			progress.restarted(v1.PodList{Items: []v1.Pod{jobPod}})
+		}
+		cfg.Log.Infof("Restarted %d pods owned by Jobs", len(jobOwnedPods.Items))
+		return otherPods, nil
+	case data.JobPodsHandlingFail:
+		var names []string
+		for _, jobPod := range jobOwnedPods.Items {
+			names = append(names, fmt.Sprintf("%s/%s", jobPod.Namespace, jobPod.Name))
+		}
+		progress.failed(jobOwnedPods)
+		return v1.PodList{}, fmt.Errorf("Found %d pods owned by Jobs which require proxy reset: %s", len(names), strings.Join(names, ", "))
+	default:
+		return pods, nil
+	}
+}
+
+func deletePod(cfg config.IstioProxyConfig, retryOpts []retry.Option, jobPod v1.Pod) error {
+	cfg.Log.Debugf("Deleting pod %s/%s owned by Job", jobPod.Namespace, jobPod.Name)
+	if cfg.Debug {
+		return nil
+	}
+
+	return retry.Do(func() error {
+		return cfg.Kubeclient.CoreV1().Pods(jobPod.Namespace).Delete(cfg.Context, jobPod.Name, metav1.DeleteOptions{})
+	}, retryOpts...)
 }
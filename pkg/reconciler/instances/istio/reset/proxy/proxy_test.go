@@ -1,13 +1,25 @@
 package proxy
 
 import (
+	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	log "github.com/kyma-incubator/reconciler/pkg/logger"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/config"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data"
 	datamocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/data/mocks"
 	podresetmocks "github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/reset/pod/reset/mocks"
 	"github.com/stretchr/testify/mock"
@@ -28,11 +40,10 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 	t.Run("should not return an error when no pods are present on the cluster", func(t *testing.T) {
 		// given
 		gatherer := datamocks.Gatherer{}
-		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{}, nil)
-		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
-			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{})
-		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{}, nil)
-		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
 
 		action := podresetmocks.Action{}
 		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
@@ -40,23 +51,21 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
 
 		// when
-		err := istioProxyReset.Run(cfg)
+		_, err := istioProxyReset.Run(cfg)
 
 		// then
 		require.NoError(t, err)
-		gatherer.AssertNumberOfCalls(t, "GetAllPods", 1)
-		gatherer.AssertNumberOfCalls(t, "GetPodsWithDifferentImage", 1)
+		gatherer.AssertNumberOfCalls(t, "GetAllPodsWithDifferentImage", 1)
 		action.AssertNumberOfCalls(t, "Reset", 0)
 	})
 
 	t.Run("should not return an error when pods are present on the cluster", func(t *testing.T) {
 		// given
 		gatherer := datamocks.Gatherer{}
-		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}}}, nil)
-		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
-			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{Items: []v1.Pod{{}}})
-		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{{}}}, nil)
-		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns"}}}}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns"}}}}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
 
 		action := podresetmocks.Action{}
 		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
@@ -64,24 +73,101 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
 
 		// when
-		err := istioProxyReset.Run(cfg)
+		summary, err := istioProxyReset.Run(cfg)
 
 		// then
 		require.NoError(t, err)
-		gatherer.AssertNumberOfCalls(t, "GetAllPods", 1)
-		gatherer.AssertNumberOfCalls(t, "GetPodsWithDifferentImage", 1)
+		require.Equal(t, ResetSummary{PodsConsidered: 2, PodsRestarted: 2}, summary)
+		gatherer.AssertNumberOfCalls(t, "GetAllPodsWithDifferentImage", 1)
 		action.AssertNumberOfCalls(t, "Reset", 2)
 	})
 
-	t.Run("should return an error when GetAllPods returns an error", func(t *testing.T) {
+	t.Run("should count a pod found for several reasons once", func(t *testing.T) {
 		// given
-		expectedError := errors.New("GetAllPods error")
+		resetPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns"}}
 		gatherer := datamocks.Gatherer{}
-		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(nil, expectedError)
-		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
-			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{})
-		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{}, nil)
-		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{resetPod}}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{resetPod}}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{resetPod}}, nil)
+
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(nil)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		summary, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ResetSummary{PodsConsidered: 1, PodsRestarted: 1}, summary)
+	})
+
+	t.Run("should count the pods annotated with a reset warning as skipped", func(t *testing.T) {
+		// given
+		annotatedPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "annotated-pod", Namespace: "ns", Annotations: map[string]string{pod.AnnotationResetWarningKey: "warning"}}}
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{annotatedPod, {ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns"}}}}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(nil)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		summary, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ResetSummary{PodsConsidered: 2, PodsRestarted: 1, PodsSkipped: 1}, summary)
+	})
+
+	t.Run("should report the partial progress when the reset of a namespace failed", func(t *testing.T) {
+		// given
+		pods := v1.PodList{Items: []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Namespace: "ns2"}},
+		}}
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(pods, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+
+		inNamespace := func(namespace string) interface{} {
+			return mock.MatchedBy(func(pods v1.PodList) bool {
+				return len(pods.Items) > 0 && pods.Items[0].Namespace == namespace
+			})
+		}
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), inNamespace("ns1"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(nil)
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), inNamespace("ns2"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(errors.New("reset error"))
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
+
+		// when
+		summary, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.EqualError(t, err, "reset error")
+		require.Equal(t, ResetSummary{PodsConsidered: 3, PodsRestarted: 2, PodsFailed: 1}, summary)
+		gatherer.AssertNumberOfCalls(t, "GetPodsForCNIChange", 0)
+	})
+
+	t.Run("should return an error when GetAllPodsWithDifferentImage returns an error", func(t *testing.T) {
+		// given
+		expectedError := errors.New("GetAllPodsWithDifferentImage error")
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{}, expectedError)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
 
 		action := podresetmocks.Action{}
 		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
@@ -89,12 +175,11 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		istioProxyReset := DefaultIstioProxyReset{&gatherer, &action}
 
 		// when
-		err := istioProxyReset.Run(cfg)
+		_, err := istioProxyReset.Run(cfg)
 
 		// then
 		require.ErrorIs(t, err, expectedError)
-		gatherer.AssertNumberOfCalls(t, "GetAllPods", 1)
-		gatherer.AssertNumberOfCalls(t, "GetPodsWithDifferentImage", 0)
+		gatherer.AssertNumberOfCalls(t, "GetAllPodsWithDifferentImage", 1)
 		action.AssertNumberOfCalls(t, "Reset", 0)
 	})
 
@@ -103,11 +188,10 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		cfg.CNIEnabled = true
 		cfg.IsUpdate = false
 		gatherer := datamocks.Gatherer{}
-		gatherer.On("GetAllPods", mock.Anything, mock.AnythingOfType("[]retry.Option")).Return(&v1.PodList{Items: []v1.Pod{{}}}, nil)
-		gatherer.On("GetPodsWithDifferentImage", mock.AnythingOfType("v1.PodList"),
-			mock.AnythingOfType("data.ExpectedImage")).Return(v1.PodList{})
-		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{}, nil)
-		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{{}}}, nil)
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{{}}}, nil)
 
 		action := podresetmocks.Action{}
 		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
@@ -115,13 +199,594 @@ func Test_IstioProxyReset_Run(t *testing.T) {
 		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &action)
 
 		// when
-		err := istioProxyReset.Run(cfg)
+		_, err := istioProxyReset.Run(cfg)
 
 		// then
 		require.NoError(t, err)
-		gatherer.AssertNumberOfCalls(t, "GetAllPods", 0)
-		gatherer.AssertNumberOfCalls(t, "GetPodsWithDifferentImage", 0)
+		gatherer.AssertNumberOfCalls(t, "GetAllPodsWithDifferentImage", 0)
 		gatherer.AssertNumberOfCalls(t, "GetPodsForCNIChange", 1)
 		action.AssertNumberOfCalls(t, "Reset", 1)
 	})
 }
+
+func Test_IstioProxyReset_Run_MaxConcurrentNamespaces(t *testing.T) {
+	podsInNamespaces := v1.PodList{Items: []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Namespace: "ns2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod4", Namespace: "ns3"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod5", Namespace: "ns4"}},
+	}}
+
+	newGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(podsInNamespaces, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		return &gatherer
+	}
+
+	t.Run("should reset namespaces sequentially by default", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, int32(1), action.maxConcurrent)
+		require.ElementsMatch(t, []string{"ns1", "ns2", "ns3", "ns4"}, action.namespaces)
+	})
+
+	t.Run("should not reset more namespaces in parallel than configured", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient:              fake.NewSimpleClientset(),
+			Log:                     log.NewLogger(true),
+			IsUpdate:                true,
+			MaxConcurrentNamespaces: 2,
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.LessOrEqual(t, action.maxConcurrent, int32(2))
+		require.ElementsMatch(t, []string{"ns1", "ns2", "ns3", "ns4"}, action.namespaces)
+	})
+}
+
+func Test_IstioProxyReset_Run_NamespacePriority(t *testing.T) {
+	podsInNamespaces := v1.PodList{Items: []v1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "kyma-system"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Namespace: "ns2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod4", Namespace: "ns1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod5", Namespace: "critical"}},
+	}}
+
+	newGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(podsInNamespaces, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		return &gatherer
+	}
+
+	t.Run("should reset namespaces in alphabetical order by default", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"critical", "default", "kyma-system", "ns1", "ns2"}, action.namespaces)
+	})
+
+	t.Run("should reset unlisted namespaces after all listed namespaces by default", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient:        fake.NewSimpleClientset(),
+			Log:               log.NewLogger(true),
+			IsUpdate:          true,
+			NamespacePriority: []string{"kyma-system", "critical"},
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"kyma-system", "critical", "default", "ns1", "ns2"}, action.namespaces)
+	})
+
+	t.Run("should reset unlisted namespaces first and critical namespaces last", func(t *testing.T) {
+		// given
+		unlistedNamespacesPriority := -1
+		cfg := config.IstioProxyConfig{
+			Kubeclient:                 fake.NewSimpleClientset(),
+			Log:                        log.NewLogger(true),
+			IsUpdate:                   true,
+			NamespacePriority:          []string{"kyma-system", "critical"},
+			UnlistedNamespacesPriority: &unlistedNamespacesPriority,
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"default", "ns1", "ns2", "kyma-system", "critical"}, action.namespaces)
+	})
+
+	t.Run("should reset unlisted namespaces in the configured bucket", func(t *testing.T) {
+		// given
+		unlistedNamespacesPriority := 2
+		cfg := config.IstioProxyConfig{
+			Kubeclient:                 fake.NewSimpleClientset(),
+			Log:                        log.NewLogger(true),
+			IsUpdate:                   true,
+			NamespacePriority:          []string{"critical", "kyma-system"},
+			UnlistedNamespacesPriority: &unlistedNamespacesPriority,
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"critical", "kyma-system", "default", "ns1", "ns2"}, action.namespaces)
+	})
+}
+
+func Test_IstioProxyReset_Run_JobPodsHandling(t *testing.T) {
+	jobPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-pod", Namespace: "ns", OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "job"}}}}
+	deploymentPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "deployment-pod", Namespace: "ns", OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "replicaset"}}}}
+	candidates := v1.PodList{Items: []v1.Pod{jobPod, deploymentPod}}
+
+	newGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(candidates, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, nil)
+		return &gatherer
+	}
+	newAction := func() *podresetmocks.Action {
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(nil)
+		return &action
+	}
+	podNames := func(pods v1.PodList) []string {
+		var names []string
+		for _, p := range pods.Items {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+	newCfg := func(handling data.JobPodsHandling) config.IstioProxyConfig {
+		return config.IstioProxyConfig{
+			Context:         context.Background(),
+			Kubeclient:      fake.NewSimpleClientset(jobPod.DeepCopy(), deploymentPod.DeepCopy()),
+			Log:             log.NewLogger(true),
+			IsUpdate:        true,
+			JobPodsHandling: handling,
+		}
+	}
+
+	t.Run("should pass Job owned pods to the reset action by default", func(t *testing.T) {
+		// given
+		action := newAction()
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(newCfg(""))
+
+		// then
+		require.NoError(t, err)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+		require.ElementsMatch(t, []string{"job-pod", "deployment-pod"}, podNames(action.Calls[0].Arguments.Get(3).(v1.PodList)))
+	})
+
+	t.Run("should skip Job owned pods", func(t *testing.T) {
+		// given
+		action := newAction()
+		cfg := newCfg(data.JobPodsHandlingSkip)
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		summary, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ResetSummary{PodsConsidered: 2, PodsRestarted: 1, PodsSkipped: 1}, summary)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+		require.Equal(t, []string{"deployment-pod"}, podNames(action.Calls[0].Arguments.Get(3).(v1.PodList)))
+		_, err = cfg.Kubeclient.CoreV1().Pods("ns").Get(context.Background(), "job-pod", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("should restart Job owned pods by deleting them", func(t *testing.T) {
+		// given
+		action := newAction()
+		cfg := newCfg(data.JobPodsHandlingRestart)
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		summary, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, ResetSummary{PodsConsidered: 2, PodsRestarted: 2}, summary)
+		require.Equal(t, []string{"deployment-pod"}, podNames(action.Calls[0].Arguments.Get(3).(v1.PodList)))
+		_, err = cfg.Kubeclient.CoreV1().Pods("ns").Get(context.Background(), "job-pod", metav1.GetOptions{})
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+
+	t.Run("should fail when Job owned pods require reset", func(t *testing.T) {
+		// given
+		action := newAction()
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		summary, err := istioProxyReset.Run(newCfg(data.JobPodsHandlingFail))
+
+		// then
+		require.EqualError(t, err, "Found 1 pods owned by Jobs which require proxy reset: ns/job-pod")
+		require.Equal(t, ResetSummary{PodsConsidered: 2, PodsFailed: 1}, summary)
+		action.AssertNumberOfCalls(t, "Reset", 0)
+	})
+}
+
+func Test_IstioProxyReset_Run_TerminatingPods(t *testing.T) {
+	newPod := func(name string, terminating bool) *v1.Pod {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "ns",
+				Annotations: map[string]string{"sidecar.istio.io/status": `{"containers":["istio-proxy"]}`},
+			},
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{{Name: "istio-proxy", Image: "istio/proxyv2:1.10.0"}},
+			},
+			Status: v1.PodStatus{Phase: v1.PodRunning},
+		}
+		if terminating {
+			deletionTimestamp := metav1.Now()
+			pod.DeletionTimestamp = &deletionTimestamp
+			pod.Finalizers = []string{"test"}
+		}
+		return &pod
+	}
+	newConfig := func(includeTerminatingPods bool) config.IstioProxyConfig {
+		return config.IstioProxyConfig{
+			Kubeclient:             fake.NewSimpleClientset(newPod("terminating-pod", true), newPod("running-pod", false)),
+			Log:                    log.NewLogger(true),
+			IsUpdate:               true,
+			ImagePrefix:            "istio/proxyv2",
+			ImageVersion:           "1.10.1",
+			IncludeTerminatingPods: includeTerminatingPods,
+		}
+	}
+	newAction := func() *podresetmocks.Action {
+		action := podresetmocks.Action{}
+		action.On("Reset", mock.Anything, mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.AnythingOfType("v1.PodList"), mock.AnythingOfType("*zap.SugaredLogger"), mock.AnythingOfType("bool"), mock.AnythingOfType("pod.WaitOptions")).
+			Return(nil)
+		return &action
+	}
+	podNames := func(pods v1.PodList) []string {
+		var names []string
+		for _, p := range pods.Items {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	t.Run("should skip terminating pods by default", func(t *testing.T) {
+		// given
+		action := newAction()
+		istioProxyReset := NewDefaultIstioProxyReset(data.NewDefaultGatherer(), action)
+
+		// when
+		summary, err := istioProxyReset.Run(newConfig(false))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 1, summary.PodsConsidered)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+		require.Equal(t, []string{"running-pod"}, podNames(action.Calls[0].Arguments.Get(3).(v1.PodList)))
+	})
+
+	t.Run("should reset terminating pods when configured", func(t *testing.T) {
+		// given
+		action := newAction()
+		istioProxyReset := NewDefaultIstioProxyReset(data.NewDefaultGatherer(), action)
+
+		// when
+		summary, err := istioProxyReset.Run(newConfig(true))
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, 2, summary.PodsConsidered)
+		action.AssertNumberOfCalls(t, "Reset", 1)
+		require.ElementsMatch(t, []string{"terminating-pod", "running-pod"}, podNames(action.Calls[0].Arguments.Get(3).(v1.PodList)))
+	})
+}
+
+func Test_IstioProxyReset_Run_Namespace(t *testing.T) {
+	podInTarget := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "target-pod", Namespace: "target"}}
+	podWithoutSidecarInTarget := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "no-sidecar-pod", Namespace: "target"}}
+	podInOther := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-pod", Namespace: "other"}}
+	podWithoutSidecarInOther := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-no-sidecar-pod", Namespace: "other"}}
+
+	newGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{podInTarget, podInOther}}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{podWithoutSidecarInOther, podWithoutSidecarInTarget}}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{podInOther}}, nil)
+		return &gatherer
+	}
+
+	t.Run("should leave pods outside of the configured namespace untouched", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+			Namespace:  "target",
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []string{"target", "target"}, action.namespaces)
+	})
+
+	t.Run("should reset pods in all namespaces when no namespace is configured", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"other", "target", "other", "other", "target"}, action.namespaces)
+	})
+}
+
+func Test_IstioProxyReset_Run_Namespaces(t *testing.T) {
+	podInTarget := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "target-pod", Namespace: "target"}}
+	podInAnother := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "another-pod", Namespace: "another"}}
+	podInOther := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-pod", Namespace: "other"}}
+	podWithoutSidecarInOther := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-no-sidecar-pod", Namespace: "other"}}
+
+	gatherer := datamocks.Gatherer{}
+	gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+		mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{podInTarget, podInOther, podInAnother}}, nil)
+	gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{podWithoutSidecarInOther}}, nil)
+	gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{podInOther}}, nil)
+
+	t.Run("should only reset pods in the allowlisted namespaces", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+			Namespaces: []string{"target", "another"},
+		}
+		action := &concurrencyTrackingAction{}
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, action)
+
+		// when
+		_, err := istioProxyReset.Run(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"another", "target"}, action.namespaces)
+	})
+}
+
+func Test_IstioProxyReset_Candidates(t *testing.T) {
+	podInTarget := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "target-pod", Namespace: "target"}}
+	podInOther := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-pod", Namespace: "other"}}
+	podWithoutSidecarInTarget := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "no-sidecar-pod", Namespace: "target"}}
+	annotatedPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "annotated-pod", Namespace: "target", Annotations: map[string]string{pod.AnnotationResetWarningKey: "warning"}}}
+
+	newGatherer := func() *datamocks.Gatherer {
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), mock.Anything).Return(v1.PodList{Items: []v1.Pod{podInTarget, annotatedPod, podInOther}}, nil)
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{podInOther}}, nil)
+		gatherer.On("GetPodsWithoutSidecar", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{Items: []v1.Pod{podWithoutSidecarInTarget, podInTarget}}, nil)
+		return &gatherer
+	}
+
+	t.Run("should return the pods of all reset reasons once without the annotated pods", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+		}
+		action := podresetmocks.Action{}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), &action)
+
+		// when
+		candidates, err := istioProxyReset.Candidates(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []v1.Pod{podInTarget, podInOther, podWithoutSidecarInTarget}, candidates.Items)
+		action.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return only the pods in the configured namespaces", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			IsUpdate:   true,
+			Namespaces: []string{"other"},
+		}
+		istioProxyReset := NewDefaultIstioProxyReset(newGatherer(), &podresetmocks.Action{})
+
+		// when
+		candidates, err := istioProxyReset.Candidates(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []v1.Pod{podInOther}, candidates.Items)
+	})
+
+	t.Run("should pass the terminating pods option to the gatherer", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient:             fake.NewSimpleClientset(),
+			Log:                    log.NewLogger(true),
+			IsUpdate:               true,
+			IncludeTerminatingPods: true,
+		}
+		gatherer := newGatherer()
+		istioProxyReset := NewDefaultIstioProxyReset(gatherer, &podresetmocks.Action{})
+
+		// when
+		_, err := istioProxyReset.Candidates(cfg)
+
+		// then
+		require.NoError(t, err)
+		gatherer.AssertCalled(t, "GetAllPodsWithDifferentImage", mock.Anything, mock.Anything, mock.Anything, true)
+		gatherer.AssertCalled(t, "GetPodsForCNIChange", mock.Anything, mock.Anything, mock.Anything, true)
+		gatherer.AssertCalled(t, "GetPodsWithoutSidecar", mock.Anything, mock.Anything, mock.Anything, true)
+	})
+
+	t.Run("should return an error when the pods could not be gathered", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+		}
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetPodsForCNIChange", mock.Anything, mock.AnythingOfType("[]retry.Option"), mock.Anything, mock.Anything).Return(v1.PodList{}, errors.New("CNI error"))
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &podresetmocks.Action{})
+
+		// when
+		_, err := istioProxyReset.Candidates(cfg)
+
+		// then
+		require.EqualError(t, err, "CNI error")
+		gatherer.AssertNotCalled(t, "GetAllPodsWithDifferentImage", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func Test_IstioProxyReset_PodsWithDifferentImage(t *testing.T) {
+	podInTarget := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "target-pod", Namespace: "target"}}
+	podInOther := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-pod", Namespace: "other"}}
+	annotatedPod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "annotated-pod", Namespace: "target", Annotations: map[string]string{pod.AnnotationResetWarningKey: "warning"}}}
+
+	t.Run("should return the pods in scope with a different image without the annotated pods", func(t *testing.T) {
+		// given
+		cfg := config.IstioProxyConfig{
+			Kubeclient: fake.NewSimpleClientset(),
+			Log:        log.NewLogger(true),
+			Namespace:  "target",
+		}
+		gatherer := datamocks.Gatherer{}
+		gatherer.On("GetAllPodsWithDifferentImage", mock.Anything, mock.AnythingOfType("[]retry.Option"),
+			mock.AnythingOfType("data.ExpectedImage"), false).Return(v1.PodList{Items: []v1.Pod{podInTarget, annotatedPod, podInOther}}, nil)
+		istioProxyReset := NewDefaultIstioProxyReset(&gatherer, &podresetmocks.Action{})
+
+		// when
+		pods, err := istioProxyReset.PodsWithDifferentImage(cfg)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, []v1.Pod{podInTarget}, pods.Items)
+		gatherer.AssertNotCalled(t, "GetAllPods", mock.Anything, mock.Anything)
+	})
+}
+
+// concurrencyTrackingAction records the highest number of Reset calls running at the same time.
+type concurrencyTrackingAction struct {
+	mu            sync.Mutex
+	running       int32
+	maxConcurrent int32
+	namespaces    []string
+}
+
+func (a *concurrencyTrackingAction) Reset(_ context.Context, _ kubernetes.Interface, _ []retry.Option, podsList v1.PodList, _ *zap.SugaredLogger, _ bool, _ pod.WaitOptions) error {
+	running := atomic.AddInt32(&a.running, 1)
+	defer atomic.AddInt32(&a.running, -1)
+
+	a.mu.Lock()
+	if running > a.maxConcurrent {
+		a.maxConcurrent = running
+	}
+	a.namespaces = append(a.namespaces, podsList.Items[0].Namespace)
+	a.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+	return nil
+}
+
+func Test_groupPodsByNamespace(t *testing.T) {
+	// given
+	pods := v1.PodList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{ResourceVersion: "42"},
+		Items: []v1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod1", Namespace: "ns2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod2", Namespace: "ns1"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "pod3", Namespace: "ns2"}},
+		},
+	}
+
+	// when
+	namespaces, podsByNamespace := groupPodsByNamespace(pods)
+
+	// then
+	require.Equal(t, []string{"ns1", "ns2"}, namespaces)
+	require.Len(t, podsByNamespace["ns1"].Items, 1)
+	require.Len(t, podsByNamespace["ns2"].Items, 2)
+	require.Equal(t, "pod3", podsByNamespace["ns2"].Items[1].Name)
+	require.Equal(t, pods.TypeMeta, podsByNamespace["ns2"].TypeMeta)
+	require.Equal(t, pods.ListMeta, podsByNamespace["ns2"].ListMeta)
+}
@@ -0,0 +1,40 @@
+package istio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/instances/istio/actions"
+	"github.com/kyma-incubator/reconciler/pkg/reconciler/service"
+	"github.com/pkg/errors"
+)
+
+const revisionConfigKey = "istio.revision"
+
+// istioRevision returns the Istio revision configured for the task. An empty revision means an in-place install.
+func istioRevision(task *reconciler.Task) string {
+	value, ok := task.Configuration[revisionConfigKey]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// ensureNoConflictingRevisions prevents reconciling an ambiguous installation: if several istiod revisions, e.g. an in-place
+// installation and a canary revision, are on the cluster, the revision to reconcile has to be requested explicitly.
+func ensureNoConflictingRevisions(context *service.ActionContext, performer actions.IstioPerformer) error {
+	if istioRevision(context.Task) != "" {
+		return nil
+	}
+
+	revisions, err := performer.GetIstiodRevisions(context.Context, context.KubeClient, istioSystemNamespace(context.Task), context.Logger)
+	if err != nil {
+		return errors.Wrap(err, "Could not detect the Istio revisions on the cluster")
+	}
+	if len(revisions) > 1 {
+		return fmt.Errorf("Detected conflicting Istio revisions on the cluster: %s, set %s to select the revision to reconcile",
+			strings.Join(revisions, ", "), revisionConfigKey)
+	}
+	return nil
+}
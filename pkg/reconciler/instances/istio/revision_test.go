@@ -0,0 +1,27 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/kyma-incubator/reconciler/pkg/reconciler"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_istioRevision(t *testing.T) {
+
+	t.Run("should return empty revision when it is not configured", func(t *testing.T) {
+		// when
+		revision := istioRevision(&reconciler.Task{})
+
+		// then
+		require.Empty(t, revision)
+	})
+
+	t.Run("should return configured revision", func(t *testing.T) {
+		// when
+		revision := istioRevision(&reconciler.Task{Configuration: map[string]interface{}{"istio.revision": "canary"}})
+
+		// then
+		require.Equal(t, "canary", revision)
+	})
+}